@@ -11,6 +11,9 @@ import (
 
 	"github.com/danmaciel/api/config"
 	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/pagamento"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 
@@ -30,6 +33,16 @@ import (
 
 // @host localhost:8080
 // @BasePath /api/v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Token JWT no formato "Bearer <token>". Gere um token local com `make token`.
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key de integração, criada em POST /api-keys
 func main() {
 	// Load configuration
 	cfg := config.Load()
@@ -41,24 +54,94 @@ func main() {
 		log.Fatalf("Falha ao inicializar o banco de dados: %v", err)
 	}
 
+	// Initialize authentication
+	jwt, err := config.NewJWT(&cfg.Auth)
+	if err != nil {
+		log.Fatalf("Falha ao configurar a autenticação JWT: %v", err)
+	}
+
 	// Initialize layers (Dependency Injection)
 	// Repositories
 	clienteRepo := repository.NewClienteRepositorySQLite(db)
 	produtoRepo := repository.NewProdutoRepositorySQLite(db)
 	pedidoRepo := repository.NewPedidoRepositorySQLite(db)
+	apiKeyRepo := repository.NewAPIKeyRepositorySQLite(db)
+	idempotencyRepo := repository.NewIdempotencyRepositorySQLite(db)
+	searchRepo := repository.NewSearchRepositorySQLite(db)
+	cupomRepo := repository.NewCupomRepositorySQLite(db)
+	regraImpostoRepo := repository.NewRegraImpostoRepositorySQLite(db)
+	enderecoRepo := repository.NewEnderecoRepositorySQLite(db)
+	contatoRepo := repository.NewContatoRepositorySQLite(db)
+	freteRepo := repository.NewFreteRepositorySQLite(db)
+	pagamentoRepo := repository.NewPagamentoRepositorySQLite(db)
+	auditRepo := repository.NewAuditRepositorySQLite(db)
+
+	// Cálculo de frete pela tabela de zonas e faixas de peso
+	freteCalculator := frete.NewTabela(freteRepo)
+
+	// Provedor de pagamentos em processo; os webhooks são assinados com o segredo configurado
+	if cfg.Pagamentos.WebhookSecret == "" {
+		log.Println("Aviso: PAYMENT_WEBHOOK_SECRET não configurado, webhooks de pagamento serão recusados")
+	}
+	paymentGateway := pagamento.NewFake(cfg.Pagamentos.WebhookSecret)
 
 	// Services
-	clienteService := service.NewClienteService(clienteRepo)
-	produtoService := service.NewProdutoService(produtoRepo)
-	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo)
+	clienteService := service.NewClienteService(clienteRepo, auditRepo)
+	produtoService := service.NewProdutoService(produtoRepo, auditRepo)
+	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, cupomRepo, regraImpostoRepo, enderecoRepo, freteCalculator, auditRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	searchService := service.NewSearchService(searchRepo)
+	cupomService := service.NewCupomService(cupomRepo)
+	regraImpostoService := service.NewRegraImpostoService(regraImpostoRepo)
+	enderecoService := service.NewEnderecoService(enderecoRepo, clienteRepo)
+	contatoService := service.NewContatoService(contatoRepo, clienteRepo)
+	freteService := service.NewFreteService(freteRepo, produtoRepo, freteCalculator)
+	pagamentoService := service.NewPagamentoService(pagamentoRepo, pedidoRepo, paymentGateway, auditRepo)
+	devolucaoService := service.NewDevolucaoService(pedidoRepo, produtoRepo, cupomRepo, pagamentoRepo, paymentGateway, auditRepo)
+	auditService := service.NewAuditService(auditRepo)
 
 	// Controllers
 	clienteController := controller.NewClienteController(clienteService)
 	produtoController := controller.NewProdutoController(produtoService)
 	pedidoController := controller.NewPedidoController(pedidoService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	searchController := controller.NewSearchController(searchService)
+	cupomController := controller.NewCupomController(cupomService)
+	regraImpostoController := controller.NewRegraImpostoController(regraImpostoService)
+	enderecoController := controller.NewEnderecoController(enderecoService)
+	contatoController := controller.NewContatoController(contatoService)
+	freteController := controller.NewFreteController(freteService)
+	pagamentoController := controller.NewPagamentoController(pagamentoService)
+	devolucaoController := controller.NewDevolucaoController(devolucaoService)
+	auditController := controller.NewAuditController(auditService)
 
 	// Setup router
-	router := controller.SetupRouter(clienteController, produtoController, pedidoController)
+	controllers := controller.Controllers{
+		Cliente:      clienteController,
+		Produto:      produtoController,
+		Pedido:       pedidoController,
+		APIKey:       apiKeyController,
+		Search:       searchController,
+		Cupom:        cupomController,
+		RegraImposto: regraImpostoController,
+		Endereco:     enderecoController,
+		Frete:        freteController,
+		Pagamento:    pagamentoController,
+		Devolucao:    devolucaoController,
+		Contato:      contatoController,
+		Audit:        auditController,
+	}
+	router := controller.SetupRouter(controllers,
+		middleware.APIKey(apiKeyService),
+		middleware.Authenticate(jwt),
+		middleware.Idempotency(idempotencyRepo, cfg.Idempotency.TTL),
+	)
+
+	// Remove periodicamente as chaves de idempotência expiradas
+	go purgeExpiredIdempotencyKeys(idempotencyRepo)
+
+	// Concilia periodicamente as cobranças com o provedor de pagamentos
+	go conciliarPagamentos(pagamentoService, cfg.Pagamentos.ConciliacaoIntervalo)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -93,3 +176,30 @@ func main() {
 
 	log.Println("Servidor encerrado com sucesso")
 }
+
+// purgeExpiredIdempotencyKeys remove a cada hora as chaves de idempotência expiradas
+func purgeExpiredIdempotencyKeys(repo repository.IdempotencyRepository) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n, err := repo.DeleteExpired(context.Background(), time.Now()); err != nil {
+			log.Printf("Falha ao remover chaves de idempotência expiradas: %v", err)
+		} else if n > 0 {
+			log.Printf("%d chaves de idempotência expiradas removidas", n)
+		}
+	}
+}
+
+// conciliarPagamentos resolve a cada intervalo as cobranças que ficaram sem
+// resposta do provedor há pelo menos um intervalo e quita os pedidos pagos
+func conciliarPagamentos(svc service.PagamentoService, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := svc.Conciliar(context.Background(), time.Now().Add(-intervalo)); err != nil {
+			log.Printf("Falha ao conciliar pagamentos: %v", err)
+		}
+	}
+}
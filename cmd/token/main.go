@@ -0,0 +1,42 @@
+// Command token emite um JWT local para desenvolvimento e testes, usando a
+// mesma configuração (JWT_*) da API.
+//
+//	go run ./cmd/token -sub maria -roles operador -ttl 8h
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/danmaciel/api/config"
+	"github.com/danmaciel/api/internal/auth"
+)
+
+func main() {
+	cfg := config.Load()
+
+	subject := flag.String("sub", "dev", "identificador do usuário (claim sub)")
+	roles := flag.String("roles", auth.RoleAdmin, "papéis separados por vírgula: admin, operador, leitura")
+	ttl := flag.Duration("ttl", cfg.Auth.TokenTTL, "validade do token")
+	flag.Parse()
+
+	jwt, err := config.NewJWT(&cfg.Auth)
+	if err != nil {
+		log.Fatalf("Falha ao configurar a autenticação JWT: %v", err)
+	}
+
+	var papeis []string
+	for _, r := range strings.Split(*roles, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			papeis = append(papeis, r)
+		}
+	}
+
+	token, err := jwt.Issue(*subject, papeis, *ttl)
+	if err != nil {
+		log.Fatalf("Falha ao emitir o token: %v", err)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danmaciel/api/internal/auth"
+)
+
+// cria o emissor/validador de JWT conforme o algoritmo configurado
+func NewJWT(cfg *AuthConfig) (*auth.JWT, error) {
+	switch cfg.Algorithm {
+	case auth.AlgHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("JWT_SECRET é obrigatório para HS256")
+		}
+		return auth.NewHS256([]byte(cfg.Secret), cfg.Issuer)
+	case auth.AlgRS256:
+		return newRS256(cfg)
+	default:
+		return nil, fmt.Errorf("algoritmo JWT não suportado: %s", cfg.Algorithm)
+	}
+}
+
+// carrega as chaves RSA dos arquivos PEM configurados
+func newRS256(cfg *AuthConfig) (*auth.JWT, error) {
+	if cfg.PublicKeyFile == "" && cfg.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("JWT_PUBLIC_KEY_FILE ou JWT_PRIVATE_KEY_FILE é obrigatório para RS256")
+	}
+
+	pub, err := readKey(cfg.PublicKeyFile, auth.ParseRSAPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao carregar a chave pública: %w", err)
+	}
+	priv, err := readKey(cfg.PrivateKeyFile, auth.ParseRSAPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao carregar a chave privada: %w", err)
+	}
+	return auth.NewRS256(pub, priv, cfg.Issuer)
+}
+
+// lê e interpreta um arquivo de chave; caminho vazio retorna nil
+func readKey[T any](path string, parse func([]byte) (*T, error)) (*T, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 // configuração principal da aplicação
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Auth        AuthConfig
+	Idempotency IdempotencyConfig
+	Pagamentos  PagamentosConfig
 }
 
 // configuração do servidor
@@ -24,6 +28,31 @@ type DatabaseConfig struct {
 	FilePath string
 }
 
+// Configuração da autenticação JWT. Com HS256 os tokens são assinados com
+// Secret; com RS256 a chave pública valida e a privada (opcional) emite tokens.
+type AuthConfig struct {
+	Algorithm      string
+	Secret         string
+	PublicKeyFile  string
+	PrivateKeyFile string
+	Issuer         string
+	TokenTTL       time.Duration
+}
+
+// Configuração das chaves de idempotência dos POSTs
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// Configuração do provedor de pagamentos. WebhookSecret assina as
+// notificações do provedor; sem ele, todo webhook é recusado.
+// ConciliacaoIntervalo é o intervalo da conciliação com o provedor e também
+// a idade mínima de uma cobrança sem resposta para ser conciliada.
+type PagamentosConfig struct {
+	WebhookSecret        string
+	ConciliacaoIntervalo time.Duration
+}
+
 // carrega as configurações do ambiente ou usa valores padrão
 func Load() *Config {
 	return &Config{
@@ -35,6 +64,21 @@ func Load() *Config {
 			Driver:   getEnv("DB_DRIVER", "sqlite"),
 			FilePath: getEnv("DB_FILE_PATH", "./database/api.db"),
 		},
+		Auth: AuthConfig{
+			Algorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+			Secret:         getEnv("JWT_SECRET", ""),
+			PublicKeyFile:  getEnv("JWT_PUBLIC_KEY_FILE", ""),
+			PrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			Issuer:         getEnv("JWT_ISSUER", "cliente-api"),
+			TokenTTL:       getEnvAsDuration("JWT_TTL", time.Hour),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		Pagamentos: PagamentosConfig{
+			WebhookSecret:        getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			ConciliacaoIntervalo: getEnvAsDuration("PAYMENT_RECONCILE_INTERVAL", 5*time.Minute),
+		},
 	}
 }
 
@@ -56,6 +100,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// helper que ajuda a retornar durações de ambiente (ex: "30m") com default
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 // Helper que retorna um print com informações do servidor
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -20,24 +21,89 @@ func InitDatabase(cfg *DatabaseConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("falha ao criar diretório do banco de dados: %w", err)
 	}
 
-	// abre a conexão com o banco de dados
-	db, err := gorm.Open(sqlite.Open(cfg.FilePath), &gorm.Config{
+	// abre a conexão com o banco de dados; _foreign_keys liga as chaves
+	// estrangeiras (PRAGMA foreign_keys=ON) em cada conexão do pool
+	db, err := gorm.Open(sqlite.Open("file:"+cfg.FilePath+"?_foreign_keys=on"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("falha ao conectar ao banco de dados: %w", err)
 	}
 
-	// execução de migrations automáticas
-	if err := db.AutoMigrate(
+	// execução de migrations automáticas, precedidas das migrations de dados pendentes
+	if err := migrateWithoutForeignKeys(db); err != nil {
+		return nil, fmt.Errorf("falha ao executar a migration: %w", err)
+	}
+
+	log.Println("Banco de dados inicializado com sucesso")
+	return db, nil
+}
+
+// migrateWithoutForeignKeys executa as migrations com as chaves estrangeiras
+// desligadas: nem as migrations de dados nem o AutoMigrate do SQLite, que
+// altera colunas recriando a tabela (DROP TABLE + RENAME), podem apagar em
+// cascata os endereços dos clientes ou falhar nos pedidos. O PRAGMA vale por
+// conexão, então tudo roda em uma conexão reservada, que volta ao pool com as
+// chaves ligadas.
+func migrateWithoutForeignKeys(db *gorm.DB) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		conn = conn.Session(&gorm.Session{})
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+
+		if err := runMigrations(conn, autoMigrate); err != nil {
+			return err
+		}
+
+		// registros órfãos de antes das chaves ligadas não impedem a
+		// inicialização, mas passam a falhar quando alterados
+		var orfaos []map[string]interface{}
+		if err := conn.Raw("PRAGMA foreign_key_check").Scan(&orfaos).Error; err != nil {
+			return err
+		}
+		if len(orfaos) > 0 {
+			log.Printf("Aviso: %d registros apontam para registros inexistentes (PRAGMA foreign_key_check)", len(orfaos))
+		}
+		return nil
+	})
+}
+
+// autoMigrate cria ou atualiza as tabelas de todas as entidades e o índice da
+// busca textual
+func autoMigrate(db *gorm.DB) error {
+	err := db.AutoMigrate(
 		&model.Cliente{},
+		&model.Endereco{},
+		&model.Contato{},
 		&model.Produto{},
 		&model.Pedido{},
 		&model.PedidoProduto{},
-	); err != nil {
-		return nil, fmt.Errorf("falha ao executar a migration: %w", err)
+		&model.PedidoStatusHistorico{},
+		&model.PedidoDevolucao{},
+		&model.PedidoDevolucaoItem{},
+		&model.Cupom{},
+		&model.PedidoDesconto{},
+		&model.RegraImposto{},
+		&model.PedidoItemImposto{},
+		&model.FreteZona{},
+		&model.FreteFaixa{},
+		&model.Pagamento{},
+		&model.PagamentoEstorno{},
+		&model.APIKey{},
+		&model.IdempotencyKey{},
+		&model.AuditLog{},
+		&model.AuditCadeia{},
+	)
+	if err != nil {
+		return err
 	}
 
-	log.Println("Banco de dados inicializado com sucesso")
-	return db, nil
+	// sem FTS5 a API funciona normalmente, mas /search responde 503
+	if !repository.FTS5Available(db) {
+		log.Println("Aviso: SQLite sem FTS5, busca textual desativada (compile com -tags sqlite_fts5)")
+		return nil
+	}
+	return repository.CreateSearchIndex(db)
 }
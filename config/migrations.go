@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigration registra as migrations de dados já aplicadas ao banco
+type schemaMigration struct {
+	Versao    string `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migration é uma alteração de dados que o AutoMigrate não consegue fazer
+// sozinho. before roda antes do AutoMigrate, enquanto as colunas ainda têm o
+// formato antigo; after roda depois dele, com o schema já atualizado.
+type migration struct {
+	versao string
+	before func(tx *gorm.DB) error
+	after  func(tx *gorm.DB) error
+}
+
+// migrations lista as migrations de dados em ordem de aplicação
+var migrations = []migration{
+	{versao: "0001_valores_em_centavos", before: converterValoresParaCentavos},
+	{versao: "0002_subtotal_dos_pedidos", after: preencherSubtotalDosPedidos},
+	{versao: "0003_documentos_dos_clientes", before: removerIndiceCPF},
+	{versao: "0004_razao_social_dos_clientes_pj", after: moverNomeParaRazaoSocial},
+	{versao: "0005_unicos_ignoram_excluidos", before: removerIndicesUnicos},
+}
+
+// runMigrations executa as migrations pendentes em torno do AutoMigrate. Em um
+// banco novo não há dados a converter, então as migrations são apenas marcadas
+// como aplicadas.
+func runMigrations(db *gorm.DB, autoMigrate func(db *gorm.DB) error) error {
+	bancoNovo := !db.Migrator().HasTable("produtos")
+
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	var aplicadas []string
+	if err := db.Model(&schemaMigration{}).Pluck("versao", &aplicadas).Error; err != nil {
+		return err
+	}
+	jaAplicada := make(map[string]bool, len(aplicadas))
+	for _, v := range aplicadas {
+		jaAplicada[v] = true
+	}
+
+	var pendentes []migration
+	for _, m := range migrations {
+		if !jaAplicada[m.versao] {
+			pendentes = append(pendentes, m)
+		}
+	}
+
+	if !bancoNovo {
+		for _, m := range pendentes {
+			if m.before == nil {
+				continue
+			}
+			if err := db.Transaction(m.before); err != nil {
+				return fmt.Errorf("migration %s: %w", m.versao, err)
+			}
+		}
+	}
+
+	if err := autoMigrate(db); err != nil {
+		return err
+	}
+
+	for _, m := range pendentes {
+		if !bancoNovo && m.after != nil {
+			if err := db.Transaction(m.after); err != nil {
+				return fmt.Errorf("migration %s: %w", m.versao, err)
+			}
+		}
+		if err := db.Create(&schemaMigration{Versao: m.versao, AppliedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// converterValoresParaCentavos converte preços e totais gravados como decimal
+// (reais) para inteiros em centavos
+func converterValoresParaCentavos(tx *gorm.DB) error {
+	colunas := map[string][]string{
+		"produtos":        {"preco"},
+		"pedidos":         {"valor_total"},
+		"pedido_produtos": {"preco_unitario", "subtotal"},
+	}
+
+	for tabela, cols := range colunas {
+		if !tx.Migrator().HasTable(tabela) {
+			continue
+		}
+		for _, col := range cols {
+			sql := fmt.Sprintf("UPDATE %s SET %s = CAST(ROUND(%s * 100) AS INTEGER)", tabela, col, col)
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// preencherSubtotalDosPedidos copia o valor total para o subtotal dos pedidos
+// anteriores aos cupons, que não tinham descontos
+func preencherSubtotalDosPedidos(tx *gorm.DB) error {
+	return tx.Exec("UPDATE pedidos SET subtotal = valor_total WHERE subtotal = 0").Error
+}
+
+// removerIndiceCPF remove o índice único do CPF, que passa a valer só para os
+// CPFs preenchidos: clientes PJ ficam com o CPF vazio. O AutoMigrate recria o
+// índice com a nova condição.
+func removerIndiceCPF(tx *gorm.DB) error {
+	return tx.Exec("DROP INDEX IF EXISTS idx_clientes_cpf").Error
+}
+
+// moverNomeParaRazaoSocial leva o nome dos clientes PJ já cadastrados para a
+// razão social, o campo que os identifica agora; o nome fica só para PF
+func moverNomeParaRazaoSocial(tx *gorm.DB) error {
+	return tx.Exec("UPDATE clientes SET razao_social = nome, nome = '' WHERE tipo = 'PJ' AND razao_social = ''").Error
+}
+
+// indicesUnicos são os índices únicos que passam a ignorar os registros
+// excluídos (soft delete), para que um registro excluído não impeça recadastrar
+// o mesmo email, documento, SKU ou código de cupom
+var indicesUnicos = []string{
+	"idx_clientes_email",
+	"idx_clientes_cpf",
+	"idx_clientes_cnpj",
+	"idx_produtos_sku",
+	"idx_cupons_codigo",
+}
+
+// removerIndicesUnicos remove os índices únicos antigos; o AutoMigrate os
+// recria com a condição deleted_at IS NULL
+func removerIndicesUnicos(tx *gorm.DB) error {
+	for _, indice := range indicesUnicos {
+		if err := tx.Exec("DROP INDEX IF EXISTS " + indice).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,36 +23,82 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/clientes": {
+        "/api-keys": {
             "get": {
-                "description": "Retrieve all clientes from the database",
+                "description": "List api keys, including revoked ones, without their secrets. Requires the admin role",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clientes"
+                    "api-keys"
+                ],
+                "summary": "List api keys",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all clientes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ClienteResponse"
-                            }
+                            "$ref": "#/definitions/dto.APIKeyPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "post": {
-                "description": "Create a new cliente with the provided information",
+                "description": "Create an api key for machine-to-machine integrations. The key is returned only in this response; only its hash is stored. Requires the admin role",
                 "consumes": [
                     "application/json"
                 ],
@@ -60,154 +106,384 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clientes"
+                    "api-keys"
                 ],
-                "summary": "Create a new cliente",
+                "summary": "Create an api key",
                 "parameters": [
                     {
-                        "description": "Cliente data",
-                        "name": "cliente",
+                        "description": "API key data",
+                        "name": "api_key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateClienteRequest"
+                            "$ref": "#/definitions/dto.CreateAPIKeyRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.ClienteResponse"
+                            "$ref": "#/definitions/dto.APIKeyCreatedResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/clientes/count": {
+        "/api-keys/{id}": {
+            "delete": {
+                "description": "Revoke an api key. Revoked keys are rejected immediately and stay listed for auditing. Requires the admin role",
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an api key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/audit": {
             "get": {
-                "description": "Get the total number of clientes",
+                "description": "List the audit log of cliente, produto and pedido writes in the order they were recorded. Each entry has the actor, the request ID and the changed fields. Requires the admin role",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clientes"
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity (cliente, produto or pedido)",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID; requires entity",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entries recorded at or after this instant (RFC 3339 or 2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entries recorded at or before this instant; a date without time includes the whole day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count clientes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CountResponse"
+                            "$ref": "#/definitions/dto.AuditLogPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/clientes/nome/{name}": {
+        "/audit/verify": {
             "get": {
-                "description": "Retrieve clientes matching the specified name (partial match)",
+                "description": "Walk the hash chain of the audit log and report the first entry that was changed, or that follows a removed entry. Requires the admin role",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clientes"
-                ],
-                "summary": "Get clientes by name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Cliente name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    }
+                    "audit"
                 ],
+                "summary": "Verify the audit log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ClienteResponse"
-                            }
+                            "$ref": "#/definitions/dto.AuditVerifyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/clientes/{id}": {
+        "/clientes": {
             "get": {
-                "description": "Retrieve a specific cliente by ID",
+                "description": "Retrieve all clientes from the database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clientes"
                 ],
-                "summary": "Get cliente by ID",
+                "summary": "Get all clientes",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cliente ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ClienteResponse"
+                            "$ref": "#/definitions/dto.ClientePageResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
-            "put": {
-                "description": "Update an existing cliente",
+            "post": {
+                "description": "Create a new cliente with the provided information",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,28 +493,27 @@ const docTemplate = `{
                 "tags": [
                     "clientes"
                 ],
-                "summary": "Update cliente",
+                "summary": "Create a new cliente",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Cliente ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Cliente data",
                         "name": "cliente",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateClienteRequest"
+                            "$ref": "#/definitions/dto.CreateClienteRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/dto.ClienteResponse"
                         }
@@ -246,292 +521,292 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete a cliente by ID",
-                "tags": [
-                    "clientes"
-                ],
-                "summary": "Delete cliente",
-                "parameters": [
+                },
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Cliente ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
+                        "BearerAuth": []
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
+                ]
             }
         },
-        "/pedidos": {
+        "/clientes/count": {
             "get": {
-                "description": "Retrieve all pedidos from the database",
+                "description": "Get the total number of clientes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "clientes"
                 ],
-                "summary": "Get all pedidos",
+                "summary": "Count clientes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.PedidoResponse"
-                            }
+                            "$ref": "#/definitions/dto.CountResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new pedido with the provided information",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pedidos"
-                ],
-                "summary": "Create a new pedido",
-                "parameters": [
-                    {
-                        "description": "Pedido data",
-                        "name": "pedido",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreatePedidoRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.PedidoResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/pedidos/cliente/{cliente_id}": {
+        "/clientes/nome/{name}": {
             "get": {
-                "description": "Retrieve all pedidos for a specific cliente",
+                "description": "Retrieve clientes matching the specified name (partial match)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "clientes"
                 ],
-                "summary": "Get pedidos by cliente ID",
+                "summary": "Get clientes by name",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Cliente ID",
-                        "name": "cliente_id",
+                        "type": "string",
+                        "description": "Cliente name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.PedidoResponse"
-                            }
+                            "$ref": "#/definitions/dto.ClientePageResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
-                    }
-                }
-            }
-        },
-        "/pedidos/count": {
-            "get": {
-                "description": "Get the total number of pedidos",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pedidos"
-                ],
-                "summary": "Count pedidos",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/dto.CountResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
-            }
-        },
-        "/pedidos/status/{status}": {
-            "get": {
-                "description": "Retrieve pedidos by status (pendente, pago, enviado, entregue, cancelado)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pedidos"
-                ],
-                "summary": "Get pedidos by status",
-                "parameters": [
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Pedido status",
-                        "name": "status",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.PedidoResponse"
-                            }
-                        }
+                        "BearerAuth": []
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
+                ]
             }
         },
-        "/pedidos/{id}": {
+        "/clientes/{id}": {
             "get": {
-                "description": "Retrieve a specific pedido by ID",
+                "description": "Retrieve a specific cliente by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "clientes"
                 ],
-                "summary": "Get pedido by ID",
+                "summary": "Get cliente by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pedido ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.PedidoResponse"
+                            "$ref": "#/definitions/dto.ClienteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update the status of an existing pedido",
+                "description": "Replace all fields of an existing cliente; omitted optional fields are cleared",
                 "consumes": [
                     "application/json"
                 ],
@@ -539,184 +814,328 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "clientes"
                 ],
-                "summary": "Update pedido status",
+                "summary": "Replace cliente",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pedido ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Pedido status update",
-                        "name": "pedido",
+                        "description": "Complete cliente representation",
+                        "name": "cliente",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePedidoRequest"
+                            "$ref": "#/definitions/dto.UpdateClienteRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.PedidoResponse"
+                            "$ref": "#/definitions/dto.ClienteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a pedido by ID",
+                "description": "Delete a cliente by ID. With purge=true the cliente is permanently removed with its enderecos and contatos, whether soft-deleted or not. A cliente referenced by pedidos cannot be deleted and returns 409 with the number of blocking pedidos in dependents; with cascade=deactivate such a cliente is anonymized: its personal data is erased and its enderecos and contatos removed, keeping the record referenced by the pedidos, while a cliente without pedidos is deleted as usual",
                 "tags": [
-                    "pedidos"
+                    "clientes"
                 ],
-                "summary": "Delete pedido",
+                "summary": "Delete cliente",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pedido ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
+                    {
+                        "type": "boolean",
+                        "description": "Permanently remove the cliente instead of soft-deleting it",
+                        "name": "purge",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "deactivate"
+                        ],
+                        "type": "string",
+                        "description": "What to do when pedidos reference the cliente",
+                        "name": "cascade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
-                    }
-                }
-            }
-        },
-        "/produtos": {
-            "get": {
-                "description": "Retrieve all produtos from the database",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "produtos"
-                ],
-                "summary": "Get all produtos",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ProdutoResponse"
-                            }
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
-            "post": {
-                "description": "Create a new produto with the provided information",
+            "patch": {
+                "description": "Apply a JSON Merge Patch (RFC 7396) to an existing cliente: only the fields present are changed and null clears optional fields",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "clientes"
                 ],
-                "summary": "Create a new produto",
+                "summary": "Partially update cliente",
                 "parameters": [
                     {
-                        "description": "Produto data",
-                        "name": "produto",
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "cliente",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateProdutoRequest"
+                            "$ref": "#/definitions/dto.UpdateClienteRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProdutoResponse"
+                            "$ref": "#/definitions/dto.ClienteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/produtos/categoria/{categoria}": {
+        "/clientes/{id}/contatos": {
             "get": {
-                "description": "Retrieve produtos by categoria",
+                "description": "Retrieve the contacts of a cliente in creation order, optionally only those with a role",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "contatos"
                 ],
-                "summary": "Get produtos by categoria",
+                "summary": "Get contatos of a cliente",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Produto categoria",
-                        "name": "categoria",
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "compras",
+                            "financeiro",
+                            "fiscal",
+                            "logistica",
+                            "outro"
+                        ],
+                        "type": "string",
+                        "description": "Only contacts with this role",
+                        "name": "papel",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -725,131 +1144,230 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.ProdutoResponse"
+                                "$ref": "#/definitions/dto.ContatoResponse"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
-                    }
-                }
-            }
-        },
-        "/produtos/count": {
-            "get": {
-                "description": "Get the total number of produtos",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "produtos"
-                ],
-                "summary": "Count produtos",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.CountResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
-            }
-        },
-        "/produtos/nome/{name}": {
-            "get": {
-                "description": "Retrieve produtos matching the specified name (partial match)",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Add a contact person with a role (compras, financeiro, fiscal, logistica or outro) to a PJ cliente. The contact needs an email or a telefone. PF clientes return 422",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "contatos"
                 ],
-                "summary": "Get produtos by name",
+                "summary": "Create a contato for a cliente",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Produto name",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Contato data",
+                        "name": "contato",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateContatoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ProdutoResponse"
-                            }
+                            "$ref": "#/definitions/dto.ContatoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/produtos/{id}": {
+        "/clientes/{id}/contatos/{contato_id}": {
             "get": {
-                "description": "Retrieve a specific produto by ID",
+                "description": "Retrieve a specific contact of a cliente",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "contatos"
                 ],
-                "summary": "Get produto by ID",
+                "summary": "Get contato by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Produto ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Contato ID",
+                        "name": "contato_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProdutoResponse"
+                            "$ref": "#/definitions/dto.ContatoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update an existing produto",
+                "description": "Replace all fields of a contact of a PJ cliente. Omitted optional fields are cleared",
                 "consumes": [
                     "application/json"
                 ],
@@ -857,67 +1375,137 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "contatos"
                 ],
-                "summary": "Update produto",
+                "summary": "Replace contato",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Produto ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Produto data",
-                        "name": "produto",
+                        "type": "integer",
+                        "description": "Contato ID",
+                        "name": "contato_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete contato representation",
+                        "name": "contato",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateProdutoRequest"
+                            "$ref": "#/definitions/dto.UpdateContatoRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProdutoResponse"
+                            "$ref": "#/definitions/dto.ContatoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a produto by ID",
+                "description": "Delete a contact of a cliente. Requires the admin role",
                 "tags": [
-                    "produtos"
+                    "contatos"
                 ],
-                "summary": "Delete produto",
+                "summary": "Delete contato",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Produto ID",
+                        "description": "Cliente ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Contato ID",
+                        "name": "contato_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -927,291 +1515,6843 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.ProblemDetails"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "dto.ClienteResponse": {
-            "type": "object",
-            "properties": {
-                "cpf": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
                 },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "nome": {
-                    "type": "string"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/clientes/{id}/enderecos": {
+            "get": {
+                "description": "Retrieve all addresses of a cliente, the default one first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Get enderecos of a cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.EnderecoResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
                 },
-                "telefone": {
-                    "type": "string"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Add a shipping address to a cliente. The CEP is stored as 8 digits. The first address becomes the default one, and padrao=true moves the default to the new address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Create an endereco for a cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Endereco data",
+                        "name": "endereco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateEnderecoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnderecoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
-                }
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "dto.CountResponse": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
+        "/clientes/{id}/enderecos/{endereco_id}": {
+            "get": {
+                "description": "Retrieve a specific address of a cliente",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Get endereco by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Endereco ID",
+                        "name": "endereco_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnderecoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace all fields of an address. Pedidos already placed keep their own copy of the address. The default address can only stop being the default by marking another one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Replace endereco",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Endereco ID",
+                        "name": "endereco_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete endereco representation",
+                        "name": "endereco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateEnderecoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnderecoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete an address of a cliente. Deleting the default address promotes the oldest remaining one. Requires the admin role",
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Delete endereco",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Endereco ID",
+                        "name": "endereco_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/clientes/{id}/restore": {
+            "post": {
+                "description": "Undo the deletion of a soft-deleted cliente. Returns 409 when the cliente is not deleted or its email or documents were registered again for another cliente. Requires the admin role",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Restore cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClienteResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/cupons": {
+            "get": {
+                "description": "Retrieve all cupons with their usage counters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Get all cupons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[ativo]=true or filter[tipo]=percentual (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,codigo",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,codigo",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CupomPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a discount cupom (percentual, valor_fixo or frete_gratis). The codigo is stored in upper case. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Create a new cupom",
+                "parameters": [
+                    {
+                        "description": "Cupom data",
+                        "name": "cupom",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCupomRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CupomResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/cupons/{id}": {
+            "get": {
+                "description": "Retrieve a specific cupom by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Get cupom by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cupom ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CupomResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace all fields of an existing cupom except the usage counter. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Replace cupom",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cupom ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete cupom representation",
+                        "name": "cupom",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCupomRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CupomResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a cupom by ID. Pedidos that used it keep their discount lines. With purge=true the cupom is permanently removed, whether soft-deleted or not. Requires the admin role",
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Delete cupom",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cupom ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Permanently remove the cupom instead of soft-deleting it",
+                        "name": "purge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/cupons/{id}/restore": {
+            "post": {
+                "description": "Undo the deletion of a soft-deleted cupom. Returns 409 when the cupom is not deleted or its code was registered again for another cupom. Requires the admin role",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cupons"
+                ],
+                "summary": "Restore cupom",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cupom ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CupomResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/frete/cotacao": {
+            "post": {
+                "description": "Quote the shipping options for the given items to a CEP. The taxable weight of each item is the larger of its real weight and its cubed weight (A x L x C / 6). Options come from the active zonas covering the CEP, cheapest first; an empty list means the CEP or the weight is not served",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Quote shipping",
+                "parameters": [
+                    {
+                        "description": "CEP and items to ship",
+                        "name": "cotacao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CotacaoFreteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CotacaoFreteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/frete/zonas": {
+            "get": {
+                "description": "Retrieve all zonas of the shipping table with their weight brackets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Get all shipping zonas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[servico]=Expresso or filter[ativo]=true (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. cep_inicio,servico",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome,faixas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FreteZonaPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a zona of the shipping table: a CEP range (both ends included) served by a servico, with a price per weight bracket. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Create a shipping zona",
+                "parameters": [
+                    {
+                        "description": "Zona data",
+                        "name": "zona",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateFreteZonaRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FreteZonaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/frete/zonas/{id}": {
+            "get": {
+                "description": "Retrieve a specific zona of the shipping table by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Get shipping zona by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Zona ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FreteZonaResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace all fields of an existing zona, including its weight brackets. Pedidos already created keep their frete. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Replace shipping zona",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Zona ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete zona representation",
+                        "name": "zona",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateFreteZonaRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FreteZonaResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a zona of the shipping table by ID. Pedidos already created keep their frete. Requires the admin role",
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Delete shipping zona",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Zona ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/impostos/regras": {
+            "get": {
+                "description": "Retrieve all tax rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impostos"
+                ],
+                "summary": "Get all tax rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[imposto]=ICMS or filter[uf]=SP (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. imposto,-uf",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,imposto,aliquota",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegraImpostoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a tax rule for an imposto (e.g. ICMS, IPI) by product categoria and destination UF; empty categoria or uf match all. Only one rule per imposto, categoria and uf is allowed. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impostos"
+                ],
+                "summary": "Create a new tax rule",
+                "parameters": [
+                    {
+                        "description": "Tax rule data",
+                        "name": "regra",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRegraImpostoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegraImpostoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/impostos/regras/{id}": {
+            "get": {
+                "description": "Retrieve a specific tax rule by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impostos"
+                ],
+                "summary": "Get tax rule by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegraImpostoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace all fields of an existing tax rule. Pedidos already created keep their taxes. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impostos"
+                ],
+                "summary": "Replace tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete tax rule representation",
+                        "name": "regra",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRegraImpostoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegraImpostoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a tax rule by ID. Pedidos already created keep their tax lines. Requires the admin role",
+                "tags": [
+                    "impostos"
+                ],
+                "summary": "Delete tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos": {
+            "get": {
+                "description": "Retrieve all pedidos from the database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Get all pedidos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new pedido with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Create a new pedido",
+                "parameters": [
+                    {
+                        "description": "Pedido data",
+                        "name": "pedido",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePedidoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/cliente/{cliente_id}": {
+            "get": {
+                "description": "Retrieve all pedidos for a specific cliente",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Get pedidos by cliente ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "cliente_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/count": {
+            "get": {
+                "description": "Get the total number of pedidos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Count pedidos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CountResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/status/{status}": {
+            "get": {
+                "description": "Retrieve pedidos by status (pendente, pago, enviado, entregue, cancelado)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Get pedidos by status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pedido status",
+                        "name": "status",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}": {
+            "get": {
+                "description": "Retrieve a specific pedido by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Get pedido by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update the status of an existing pedido. Pedidos normally become pago when their pagamentos are confirmed; setting pago by hand is a manual settlement and requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Update pedido status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pedido status update",
+                        "name": "pedido",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePedidoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a pendente or cancelado pedido with no pendente or confirmado pagamentos. Any other pedido returns 409; cancel it with POST /pedidos/{id}/cancelamento",
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Delete pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/cancelamento": {
+            "post": {
+                "description": "Cancel a pendente or pago pedido with a reason. Items not yet returned go back to stock, coupon uses are released and every confirmed payment is refunded through the provider. Payments confirmed after the cancellation are refunded as they arrive",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Cancel a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation reason",
+                        "name": "cancelamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelamentoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/devolucoes": {
+            "post": {
+                "description": "Return quantities of items of a pago, enviado or entregue pedido. The returned units go back to stock and each line gets its refund: the item value net of its share of the discount, plus exclusive taxes, prorated by unit. The refund is charged back on the confirmed payments; reembolsado is what was actually refunded. The original order lines are kept and the return is attached to the pedido",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Return items of a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Returned items",
+                        "name": "devolucao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateDevolucaoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the pedido version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DevolucaoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/historico": {
+            "get": {
+                "description": "Retrieve every status change of a pedido, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Get pedido status history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PedidoStatusHistoricoResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/itens": {
+            "post": {
+                "description": "Add a produto to a pendente pedido. The produto must be active and have stock, which is reserved; a produto already in the pedido adds to its line. Subtotal, discounts, taxes, freight and total are recalculated, keeping the unit prices of the existing lines",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Add an item to a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateItemPedidoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/itens/{item_id}": {
+            "put": {
+                "description": "Change the quantity of an item of a pendente pedido. An increase reserves stock and requires an active produto; a decrease returns the difference to stock. The pedido values are recalculated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Change the quantity of a pedido item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New quantity",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateItemPedidoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove an item of a pendente pedido, returning its quantity to stock, and respond with the recalculated pedido. The last item cannot be removed; cancel the pedido instead",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Remove an item from a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/pagamentos": {
+            "get": {
+                "description": "Retrieve the payments of a pedido with their refunds and the reconciliation against valor_total: pago (confirmed minus refunds), em_cobranca (pending) and saldo (still to pay)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Get pagamentos of a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PagamentosPedidoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Charge a pendente pedido through the payment provider. Without valor the whole remaining balance is charged; a smaller valor records a partial payment. Cartão payments are confirmed at once, pix and boleto wait for the provider webhook. The pedido becomes pago when the confirmed payments cover its valor_total",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Charge a pedido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pagamento data",
+                        "name": "pagamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePagamentoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PagamentoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/pedidos/{id}/pagamentos/{pagamento_id}/estornos": {
+            "post": {
+                "description": "Refund part or all of a confirmed payment through the provider. Without valor everything not yet refunded is returned; the payment becomes estornado when fully refunded. The pedido status is not changed. Requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Refund a pagamento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pedido ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagamento ID",
+                        "name": "pagamento_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Estorno data",
+                        "name": "estorno",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateEstornoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PagamentoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos": {
+            "get": {
+                "description": "Retrieve all produtos from the database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Get all produtos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new produto with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Create a new produto",
+                "parameters": [
+                    {
+                        "description": "Produto data",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateProdutoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request; repeats within the TTL replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos/categoria/{categoria}": {
+            "get": {
+                "description": "Retrieve produtos by categoria",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Get produtos by categoria",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Produto categoria",
+                        "name": "categoria",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos/count": {
+            "get": {
+                "description": "Get the total number of produtos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Count produtos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CountResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos/nome/{name}": {
+            "get": {
+                "description": "Retrieve produtos matching the specified name (partial match)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Get produtos by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Produto name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for cursor pagination (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for offset pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size for offset pagination (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)",
+                        "name": "filter[campo][op]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated response fields to return, e.g. id,nome",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos/{id}": {
+            "get": {
+                "description": "Retrieve a specific produto by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Get produto by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; a match returns 304 without body",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace all fields of an existing produto; omitted optional fields are cleared. Changing the price requires the admin role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Replace produto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Complete produto representation",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProdutoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a produto by ID. With purge=true the produto is permanently removed, whether soft-deleted or not. A produto referenced by pedidos cannot be deleted and returns 409 with the number of blocking pedidos in dependents; with cascade=deactivate such a produto is deactivated (ativo=false), which prevents new pedidos with it, while a produto without pedidos is deleted as usual",
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Delete produto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Permanently remove the produto instead of soft-deleting it",
+                        "name": "purge",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "deactivate"
+                        ],
+                        "type": "string",
+                        "description": "What to do when pedidos reference the produto",
+                        "name": "cascade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Apply a JSON Merge Patch (RFC 7396) to an existing produto: only the fields present are changed and null clears optional fields. Changing the price requires the admin role",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Partially update produto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProdutoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed; a mismatch returns 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/produtos/{id}/restore": {
+            "post": {
+                "description": "Undo the deletion of a soft-deleted produto. Returns 409 when the produto is not deleted or its SKU was registered again for another produto. Requires the admin role",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Restore produto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProdutoResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Full-text search ranked by relevance across produtos (nome, descricao, sku, categoria) and clientes (nome, email, cpf, telefone). Case and accents are ignored and every term also matches as a prefix, so partial words work for autocomplete. Only the types the caller can read are searched",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search produtos and clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to search, e.g. joao or cafe ele",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated types to search (produtos, clientes); defaults to all readable types",
+                        "name": "tipos",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/pagamentos": {
+            "post": {
+                "description": "Notification from the payment provider confirming or failing a pending payment. It is authenticated by the HMAC-SHA256 signature of the body in X-Webhook-Signature, not by a token. Repeated notifications are accepted without effect",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Payment provider webhook",
+                "parameters": [
+                    {
+                        "description": "Provider event with referencia and status (confirmado or falhou)",
+                        "name": "evento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the body with the webhook secret",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKeyCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "criado_por": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "ak_1a2b3c4d_Zm9vYmFyYmF6..."
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "prefixo": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APIKeyPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.APIKeyResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "criado_por": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "prefixo": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AuditLogPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditLogResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "acao": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "ator": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object"
+                },
+                "entidade": {
+                    "type": "string",
+                    "example": "produto"
+                },
+                "entidade_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "hash": {
+                    "type": "string",
+                    "example": "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+                },
+                "hash_anterior": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "host/abc123-000001"
+                }
+            }
+        },
+        "dto.AuditVerifyResponse": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "type": "integer",
+                    "example": 57
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 128
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.CancelamentoRequest": {
+            "type": "object",
+            "required": [
+                "motivo"
+            ],
+            "properties": {
+                "motivo": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Cliente desistiu da compra"
+                }
+            }
+        },
+        "dto.ClientePageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClienteResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ClienteResponse": {
+            "type": "object",
+            "properties": {
+                "cnpj": {
+                    "type": "string"
+                },
+                "cpf": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inscricao_estadual": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "nome_fantasia": {
+                    "type": "string"
+                },
+                "razao_social": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string",
+                    "example": "PF"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ContatoResponse": {
+            "type": "object",
+            "properties": {
+                "cargo": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "papel": {
+                    "type": "string",
+                    "example": "financeiro"
+                },
+                "telefone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CotacaoFreteRequest": {
+            "type": "object",
+            "required": [
+                "cep",
+                "itens"
+            ],
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "itens": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CreateItemPedidoRequest"
+                    }
+                }
+            }
+        },
+        "dto.CotacaoFreteResponse": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310100"
+                },
+                "opcoes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OpcaoFreteResponse"
+                    }
+                },
+                "peso_gramas": {
+                    "type": "integer",
+                    "example": 2500
+                }
+            }
+        },
+        "dto.CountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "nome",
+                "scopes"
+            ],
+            "properties": {
+                "nome": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Conector ERP"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pedidos:write",
+                        "produtos:read"
+                    ]
+                }
+            }
+        },
+        "dto.CreateClienteRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "cnpj": {
+                    "type": "string",
+                    "example": "11.222.333/0001-81"
+                },
+                "cpf": {
+                    "type": "string",
+                    "example": "529.982.247-25"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "inscricao_estadual": {
+                    "type": "string",
+                    "example": "110.042.490.114"
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Maria Silva"
+                },
+                "nome_fantasia": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Acme"
+                },
+                "razao_social": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 3,
+                    "example": "Acme Comércio Ltda"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 15,
+                    "minLength": 10
+                },
+                "tipo": {
+                    "type": "string",
+                    "enum": [
+                        "PF",
+                        "PJ"
+                    ],
+                    "example": "PF"
+                }
+            }
+        },
+        "dto.CreateContatoRequest": {
+            "type": "object",
+            "required": [
+                "nome",
+                "papel"
+            ],
+            "properties": {
+                "cargo": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Gerente financeira"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ana@acme.com.br"
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Ana Souza"
+                },
+                "papel": {
+                    "type": "string",
+                    "enum": [
+                        "compras",
+                        "financeiro",
+                        "fiscal",
+                        "logistica",
+                        "outro"
+                    ],
+                    "example": "financeiro"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 15,
+                    "minLength": 10,
+                    "example": "11987654321"
+                }
+            }
+        },
+        "dto.CreateCupomRequest": {
+            "type": "object",
+            "required": [
+                "categorias",
+                "codigo",
+                "produto_ids",
+                "tipo"
+            ],
+            "properties": {
+                "ativo": {
+                    "description": "pointer para permitir false explícito",
+                    "type": "boolean"
+                },
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "codigo": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3,
+                    "example": "BEMVINDO10"
+                },
+                "descricao": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "10% na primeira compra"
+                },
+                "limite_por_cliente": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "limite_uso": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "percentual": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 10
+                },
+                "produto_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tipo": {
+                    "type": "string",
+                    "enum": [
+                        "percentual",
+                        "valor_fixo",
+                        "frete_gratis"
+                    ],
+                    "example": "percentual"
+                },
+                "valido_ate": {
+                    "type": "string"
+                },
+                "valido_de": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "0.00"
+                },
+                "valor_minimo": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "100.00"
+                }
+            }
+        },
+        "dto.CreateDevolucaoRequest": {
+            "type": "object",
+            "required": [
+                "itens"
+            ],
+            "properties": {
+                "itens": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.DevolucaoItemRequest"
+                    }
+                },
+                "motivo": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Produto com defeito"
+                }
+            }
+        },
+        "dto.CreateEnderecoRequest": {
+            "type": "object",
+            "required": [
+                "bairro",
+                "cep",
+                "cidade",
+                "logradouro",
+                "numero",
+                "uf"
+            ],
+            "properties": {
+                "apelido": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Casa"
+                },
+                "bairro": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Bela Vista"
+                },
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "cidade": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "São Paulo"
+                },
+                "complemento": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Apto 42"
+                },
+                "logradouro": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Avenida Paulista"
+                },
+                "numero": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "1000"
+                },
+                "padrao": {
+                    "type": "boolean"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "dto.CreateEstornoRequest": {
+            "type": "object",
+            "properties": {
+                "motivo": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Item devolvido"
+                },
+                "valor": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "50.00"
+                }
+            }
+        },
+        "dto.CreateFreteZonaRequest": {
+            "type": "object",
+            "required": [
+                "cep_fim",
+                "cep_inicio",
+                "faixas",
+                "nome",
+                "servico"
+            ],
+            "properties": {
+                "ativo": {
+                    "description": "pointer para permitir false explícito",
+                    "type": "boolean"
+                },
+                "cep_fim": {
+                    "type": "string",
+                    "example": "05999-999"
+                },
+                "cep_inicio": {
+                    "type": "string",
+                    "example": "01000-000"
+                },
+                "faixas": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.FreteFaixaRequest"
+                    }
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Capital SP"
+                },
+                "prazo_dias": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "servico": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Expresso"
+                }
+            }
+        },
+        "dto.CreateItemPedidoRequest": {
+            "type": "object",
+            "required": [
+                "produto_id",
+                "quantidade"
+            ],
+            "properties": {
+                "produto_id": {
+                    "type": "integer"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreatePagamentoRequest": {
+            "type": "object",
+            "required": [
+                "metodo"
+            ],
+            "properties": {
+                "metodo": {
+                    "type": "string",
+                    "enum": [
+                        "pix",
+                        "boleto",
+                        "cartao"
+                    ],
+                    "example": "pix"
+                },
+                "valor": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "150.00"
+                }
+            }
+        },
+        "dto.CreatePedidoRequest": {
+            "type": "object",
+            "required": [
+                "cliente_id",
+                "itens"
+            ],
+            "properties": {
+                "cliente_id": {
+                    "type": "integer"
+                },
+                "cupom": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "BEMVINDO10"
+                },
+                "endereco_id": {
+                    "description": "EnderecoID é o endereço de entrega; sem ele vale o endereço padrão do cliente, se houver",
+                    "type": "integer",
+                    "example": 1
+                },
+                "frete_servico": {
+                    "description": "FreteServico escolhe o serviço de entrega; sem ele vale a opção mais barata",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Expresso"
+                },
+                "itens": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CreateItemPedidoRequest"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pendente"
+                    ]
+                },
+                "uf_destino": {
+                    "description": "UFDestino escolhe as regras de imposto por UF; sem ela valem apenas as regras sem UF",
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "dto.CreateProdutoRequest": {
+            "type": "object",
+            "required": [
+                "nome",
+                "preco",
+                "sku"
+            ],
+            "properties": {
+                "altura_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "ativo": {
+                    "description": "pointer para permitir false explícito",
+                    "type": "boolean"
+                },
+                "categoria": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "comprimento_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "descricao": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "estoque": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "largura_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 40
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "peso_gramas": {
+                    "description": "Peso e dimensões da embalagem, usados no cálculo do frete",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2500
+                },
+                "preco": {
+                    "type": "string",
+                    "example": "2999.99"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.CreateRegraImpostoRequest": {
+            "type": "object",
+            "required": [
+                "imposto",
+                "modo"
+            ],
+            "properties": {
+                "aliquota": {
+                    "type": "string",
+                    "example": "18.00"
+                },
+                "ativo": {
+                    "description": "pointer para permitir false explícito",
+                    "type": "boolean"
+                },
+                "categoria": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Eletrônicos"
+                },
+                "descricao": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "ICMS interno de SP"
+                },
+                "imposto": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "ICMS"
+                },
+                "modo": {
+                    "type": "string",
+                    "enum": [
+                        "inclusivo",
+                        "exclusivo"
+                    ],
+                    "example": "inclusivo"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "dto.CupomPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CupomResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CupomResponse": {
+            "type": "object",
+            "properties": {
+                "ativo": {
+                    "type": "boolean"
+                },
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "codigo": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "limite_por_cliente": {
+                    "type": "integer"
+                },
+                "limite_uso": {
+                    "type": "integer"
+                },
+                "percentual": {
+                    "type": "integer"
+                },
+                "produto_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usos": {
+                    "type": "integer"
+                },
+                "valido_ate": {
+                    "type": "string"
+                },
+                "valido_de": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "valor_minimo": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DescontoResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "5999.98"
+                },
+                "codigo": {
+                    "type": "string"
+                },
+                "cupom_id": {
+                    "type": "integer"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "599.99"
+                }
+            }
+        },
+        "dto.DevolucaoItemRequest": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "quantidade"
+            ],
+            "properties": {
+                "item_id": {
+                    "description": "ItemID é o id do item do pedido (itens[].id), não o do produto",
+                    "type": "integer",
+                    "example": 1
+                },
+                "quantidade": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.DevolucaoItemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "produto_id": {
+                    "type": "integer"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "49.90"
+                }
+            }
+        },
+        "dto.DevolucaoResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DevolucaoItemResponse"
+                    }
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "pedido_id": {
+                    "type": "integer"
+                },
+                "reembolsado": {
+                    "type": "string",
+                    "example": "49.90"
+                },
+                "usuario": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "49.90"
+                }
+            }
+        },
+        "dto.EnderecoEntregaResponse": {
+            "type": "object",
+            "properties": {
+                "bairro": {
+                    "type": "string"
+                },
+                "cep": {
+                    "type": "string",
+                    "example": "01310100"
+                },
+                "cidade": {
+                    "type": "string"
+                },
+                "complemento": {
+                    "type": "string"
+                },
+                "logradouro": {
+                    "type": "string"
+                },
+                "numero": {
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EnderecoResponse": {
+            "type": "object",
+            "properties": {
+                "apelido": {
+                    "type": "string"
+                },
+                "bairro": {
+                    "type": "string"
+                },
+                "cep": {
+                    "type": "string",
+                    "example": "01310100"
+                },
+                "cidade": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "integer"
+                },
+                "complemento": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "logradouro": {
+                    "type": "string"
+                },
+                "numero": {
+                    "type": "string"
+                },
+                "padrao": {
+                    "type": "boolean"
+                },
+                "uf": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.EstornoResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "referencia": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "concluido"
+                },
+                "usuario": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "50.00"
+                }
+            }
+        },
+        "dto.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "cpf"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "deve ter exatamente 11 caracteres"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "len"
+                }
+            }
+        },
+        "dto.FreteFaixaRequest": {
+            "type": "object",
+            "required": [
+                "peso_maximo_gramas"
+            ],
+            "properties": {
+                "peso_maximo_gramas": {
+                    "type": "integer",
+                    "example": 5000
+                },
+                "valor": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "25.90"
+                }
+            }
+        },
+        "dto.FreteFaixaResponse": {
+            "type": "object",
+            "properties": {
+                "peso_maximo_gramas": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "25.90"
+                }
+            }
+        },
+        "dto.FreteZonaPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FreteZonaResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.FreteZonaResponse": {
+            "type": "object",
+            "properties": {
+                "ativo": {
+                    "type": "boolean"
+                },
+                "cep_fim": {
+                    "type": "string",
+                    "example": "05999999"
+                },
+                "cep_inicio": {
+                    "type": "string",
+                    "example": "01000000"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "faixas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FreteFaixaResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "prazo_dias": {
+                    "type": "integer"
+                },
+                "servico": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ImpostoItemResponse": {
+            "type": "object",
+            "properties": {
+                "aliquota": {
+                    "type": "string",
+                    "example": "18.00"
+                },
+                "base": {
+                    "type": "string",
+                    "example": "5999.98"
+                },
+                "imposto": {
+                    "type": "string",
+                    "example": "ICMS"
+                },
+                "modo": {
+                    "type": "string",
+                    "example": "inclusivo"
+                },
+                "regra_id": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "1079.99"
+                }
+            }
+        },
+        "dto.ImpostoResumoResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "5999.98"
+                },
+                "imposto": {
+                    "type": "string",
+                    "example": "ICMS"
+                },
+                "modo": {
+                    "type": "string",
+                    "example": "inclusivo"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "1079.99"
+                }
+            }
+        },
+        "dto.ItemPedidoResponse": {
+            "type": "object",
+            "properties": {
+                "desconto": {
+                    "description": "Desconto é a parte do desconto do pedido rateada para o item",
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imposto": {
+                    "type": "string",
+                    "example": "1079.99"
+                },
+                "impostos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImpostoItemResponse"
+                    }
+                },
+                "preco_unitario": {
+                    "type": "string",
+                    "example": "2999.99"
+                },
+                "produto": {
+                    "$ref": "#/definitions/dto.ProdutoResponse"
+                },
+                "produto_id": {
+                    "type": "integer"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "quantidade_devolvida": {
+                    "description": "QuantidadeDevolvida soma as devoluções do item; Quantidade não muda",
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "5999.98"
+                }
+            }
+        },
+        "dto.OpcaoFreteResponse": {
+            "type": "object",
+            "properties": {
+                "prazo_dias": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "servico": {
+                    "type": "string",
+                    "example": "Expresso"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "25.90"
+                }
+            }
+        },
+        "dto.PagamentoResponse": {
+            "type": "object",
+            "properties": {
+                "confirmado_em": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "estornado": {
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "estornos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EstornoResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "metodo": {
+                    "type": "string",
+                    "example": "pix"
+                },
+                "pedido_id": {
+                    "type": "integer"
+                },
+                "provedor": {
+                    "type": "string",
+                    "example": "fake"
+                },
+                "referencia": {
+                    "type": "string",
+                    "example": "fake_pay_4f1c2a9b8e7d6c5b4a3f2e1d"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pendente"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string",
+                    "example": "150.00"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PagamentosPedidoResponse": {
+            "type": "object",
+            "properties": {
+                "em_cobranca": {
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "pagamentos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PagamentoResponse"
+                    }
+                },
+                "pago": {
+                    "type": "string",
+                    "example": "150.00"
+                },
+                "pedido_id": {
+                    "type": "integer"
+                },
+                "saldo": {
+                    "type": "string",
+                    "example": "150.00"
+                },
+                "valor_total": {
+                    "type": "string",
+                    "example": "300.00"
+                }
+            }
+        },
+        "dto.PedidoPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PedidoResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PedidoResponse": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "$ref": "#/definitions/dto.ClienteResponse"
+                },
+                "cliente_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data_pedido": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "desconto": {
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "descontos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DescontoResponse"
+                    }
+                },
+                "devolucoes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DevolucaoResponse"
+                    }
+                },
+                "endereco_id": {
+                    "type": "integer"
+                },
+                "entrega": {
+                    "$ref": "#/definitions/dto.EnderecoEntregaResponse"
+                },
+                "frete": {
+                    "type": "string",
+                    "example": "25.90"
+                },
+                "frete_prazo_dias": {
+                    "type": "integer"
+                },
+                "frete_servico": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imposto": {
+                    "type": "string",
+                    "example": "1079.99"
+                },
+                "impostos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImpostoResumoResponse"
+                    }
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ItemPedidoResponse"
+                    }
+                },
+                "motivo_cancelamento": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "5999.98"
+                },
+                "uf_destino": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valor_total": {
+                    "type": "string",
+                    "example": "5999.98"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PedidoStatusHistoricoResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pedido_id": {
+                    "type": "integer"
+                },
+                "status_anterior": {
+                    "type": "string"
+                },
+                "status_novo": {
+                    "type": "string"
+                },
+                "usuario": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "dependents": {
+                    "description": "Dependents conta, por tipo, os registros que impedem a exclusão do recurso",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    },
+                    "example": {
+                        "pedidos": 3
+                    }
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "dados do cliente inválidos"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "host/abc123-000001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Requisição inválida"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/validation-error"
+                }
+            }
+        },
+        "dto.ProdutoPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProdutoResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ProdutoResponse": {
+            "type": "object",
+            "properties": {
+                "altura_cm": {
+                    "type": "integer"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "categoria": {
+                    "type": "string"
+                },
+                "comprimento_cm": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "estoque": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "largura_cm": {
+                    "type": "integer"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "peso_gramas": {
+                    "description": "Peso e dimensões da embalagem, usados no cálculo do frete",
+                    "type": "integer"
+                },
+                "preco": {
+                    "type": "string",
+                    "example": "2999.99"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RegraImpostoPageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RegraImpostoResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RegraImpostoResponse": {
+            "type": "object",
+            "properties": {
+                "aliquota": {
+                    "type": "string",
+                    "example": "18.00"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "categoria": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imposto": {
+                    "type": "string"
+                },
+                "modo": {
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SearchResult"
+                    }
+                },
+                "query": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SearchResult": {
+            "type": "object",
+            "properties": {
+                "href": {
+                    "type": "string",
+                    "example": "/api/v1/produtos/1"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "type": "number",
+                    "example": 4.21
+                },
+                "tipo": {
+                    "type": "string",
+                    "example": "produtos"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eCafe\u003c/mark\u003eteira Elétrica"
+                },
+                "trecho": {
+                    "type": "string",
+                    "example": "…prepara até 30 \u003cmark\u003ecafe\u003c/mark\u003ezinhos…"
                 }
             }
         },
-        "dto.CreateClienteRequest": {
+        "dto.UpdateClienteRequest": {
             "type": "object",
             "required": [
-                "cpf",
-                "email",
-                "nome"
+                "email"
             ],
             "properties": {
+                "cnpj": {
+                    "type": "string",
+                    "example": "11.222.333/0001-81"
+                },
                 "cpf": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "529.982.247-25"
                 },
                 "email": {
                     "type": "string"
                 },
+                "inscricao_estadual": {
+                    "type": "string",
+                    "example": "110.042.490.114"
+                },
                 "nome": {
                     "type": "string",
                     "maxLength": 100,
-                    "minLength": 3
+                    "minLength": 3,
+                    "example": "Maria Silva"
+                },
+                "nome_fantasia": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Acme"
+                },
+                "razao_social": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 3,
+                    "example": "Acme Comércio Ltda"
                 },
                 "telefone": {
                     "type": "string",
                     "maxLength": 15,
                     "minLength": 10
-                }
-            }
-        },
-        "dto.CreateItemPedidoRequest": {
-            "type": "object",
-            "required": [
-                "produto_id",
-                "quantidade"
-            ],
-            "properties": {
-                "produto_id": {
-                    "type": "integer"
                 },
-                "quantidade": {
-                    "type": "integer"
+                "tipo": {
+                    "type": "string",
+                    "enum": [
+                        "PF",
+                        "PJ"
+                    ],
+                    "example": "PF"
                 }
             }
         },
-        "dto.CreatePedidoRequest": {
+        "dto.UpdateContatoRequest": {
             "type": "object",
             "required": [
-                "cliente_id",
-                "itens"
+                "nome",
+                "papel"
             ],
             "properties": {
-                "cliente_id": {
-                    "type": "integer"
+                "cargo": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Gerente financeira"
                 },
-                "itens": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/dto.CreateItemPedidoRequest"
-                    }
+                "email": {
+                    "type": "string",
+                    "example": "ana@acme.com.br"
                 },
-                "status": {
+                "nome": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Ana Souza"
+                },
+                "papel": {
                     "type": "string",
                     "enum": [
-                        "pendente",
-                        "pago",
-                        "enviado",
-                        "entregue",
-                        "cancelado"
-                    ]
+                        "compras",
+                        "financeiro",
+                        "fiscal",
+                        "logistica",
+                        "outro"
+                    ],
+                    "example": "financeiro"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 15,
+                    "minLength": 10,
+                    "example": "11987654321"
                 }
             }
         },
-        "dto.CreateProdutoRequest": {
+        "dto.UpdateCupomRequest": {
             "type": "object",
             "required": [
-                "nome",
-                "preco",
-                "sku"
+                "ativo",
+                "categorias",
+                "codigo",
+                "produto_ids",
+                "tipo"
             ],
             "properties": {
                 "ativo": {
-                    "description": "pointer para permitir false explícito",
                     "type": "boolean"
                 },
-                "categoria": {
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "codigo": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 50,
+                    "minLength": 3,
+                    "example": "BEMVINDO10"
                 },
                 "descricao": {
                     "type": "string",
-                    "maxLength": 1000
+                    "maxLength": 200,
+                    "example": "10% na primeira compra"
                 },
-                "estoque": {
+                "limite_por_cliente": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "limite_uso": {
                     "type": "integer",
                     "minimum": 0
                 },
-                "nome": {
-                    "type": "string",
-                    "maxLength": 200,
-                    "minLength": 3
+                "percentual": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 10
                 },
-                "preco": {
-                    "type": "number"
+                "produto_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "sku": {
+                "tipo": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
-                }
-            }
-        },
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+                    "enum": [
+                        "percentual",
+                        "valor_fixo",
+                        "frete_gratis"
+                    ],
+                    "example": "percentual"
                 },
-                "message": {
+                "valido_ate": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.ItemPedidoResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "preco_unitario": {
-                    "type": "number"
-                },
-                "produto": {
-                    "$ref": "#/definitions/dto.ProdutoResponse"
                 },
-                "produto_id": {
-                    "type": "integer"
+                "valido_de": {
+                    "type": "string"
                 },
-                "quantidade": {
-                    "type": "integer"
+                "valor": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "0.00"
                 },
-                "subtotal": {
-                    "type": "number"
+                "valor_minimo": {
+                    "type": "string",
+                    "minLength": 0,
+                    "example": "100.00"
                 }
             }
         },
-        "dto.PedidoResponse": {
+        "dto.UpdateEnderecoRequest": {
             "type": "object",
+            "required": [
+                "bairro",
+                "cep",
+                "cidade",
+                "logradouro",
+                "numero",
+                "padrao",
+                "uf"
+            ],
             "properties": {
-                "cliente": {
-                    "$ref": "#/definitions/dto.ClienteResponse"
+                "apelido": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Casa"
                 },
-                "cliente_id": {
-                    "type": "integer"
+                "bairro": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Bela Vista"
                 },
-                "created_at": {
-                    "type": "string"
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
                 },
-                "data_pedido": {
-                    "type": "string"
+                "cidade": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "São Paulo"
                 },
-                "id": {
-                    "type": "integer"
+                "complemento": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Apto 42"
                 },
-                "itens": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.ItemPedidoResponse"
-                    }
+                "logradouro": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Avenida Paulista"
                 },
-                "status": {
-                    "type": "string"
+                "numero": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "1000"
                 },
-                "updated_at": {
-                    "type": "string"
+                "padrao": {
+                    "type": "boolean"
                 },
-                "valor_total": {
-                    "type": "number"
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
                 }
             }
         },
-        "dto.ProdutoResponse": {
+        "dto.UpdateFreteZonaRequest": {
             "type": "object",
+            "required": [
+                "ativo",
+                "cep_fim",
+                "cep_inicio",
+                "faixas",
+                "nome",
+                "servico"
+            ],
             "properties": {
                 "ativo": {
                     "type": "boolean"
                 },
-                "categoria": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "descricao": {
-                    "type": "string"
+                "cep_fim": {
+                    "type": "string",
+                    "example": "05999-999"
                 },
-                "estoque": {
-                    "type": "integer"
+                "cep_inicio": {
+                    "type": "string",
+                    "example": "01000-000"
                 },
-                "id": {
-                    "type": "integer"
+                "faixas": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.FreteFaixaRequest"
+                    }
                 },
                 "nome": {
-                    "type": "string"
-                },
-                "preco": {
-                    "type": "number"
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Capital SP"
                 },
-                "sku": {
-                    "type": "string"
+                "prazo_dias": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
                 },
-                "updated_at": {
-                    "type": "string"
+                "servico": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Expresso"
                 }
             }
         },
-        "dto.UpdateClienteRequest": {
+        "dto.UpdateItemPedidoRequest": {
             "type": "object",
+            "required": [
+                "quantidade"
+            ],
             "properties": {
-                "cpf": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "nome": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
-                },
-                "telefone": {
-                    "type": "string",
-                    "maxLength": 15,
-                    "minLength": 10
+                "quantidade": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
@@ -1235,7 +8375,19 @@ const docTemplate = `{
         },
         "dto.UpdateProdutoRequest": {
             "type": "object",
+            "required": [
+                "ativo",
+                "estoque",
+                "nome",
+                "preco",
+                "sku"
+            ],
             "properties": {
+                "altura_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
                 "ativo": {
                     "type": "boolean"
                 },
@@ -1243,6 +8395,11 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 100
                 },
+                "comprimento_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
                 "descricao": {
                     "type": "string",
                     "maxLength": 1000
@@ -1251,13 +8408,25 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 0
                 },
+                "largura_cm": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 40
+                },
                 "nome": {
                     "type": "string",
                     "maxLength": 200,
                     "minLength": 3
                 },
+                "peso_gramas": {
+                    "description": "Peso e dimensões omitidos são zerados, como os demais campos opcionais",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2500
+                },
                 "preco": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "2999.99"
                 },
                 "sku": {
                     "type": "string",
@@ -1265,6 +8434,64 @@ const docTemplate = `{
                     "minLength": 3
                 }
             }
+        },
+        "dto.UpdateRegraImpostoRequest": {
+            "type": "object",
+            "required": [
+                "ativo",
+                "imposto",
+                "modo"
+            ],
+            "properties": {
+                "aliquota": {
+                    "type": "string",
+                    "example": "18.00"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "categoria": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Eletrônicos"
+                },
+                "descricao": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "ICMS interno de SP"
+                },
+                "imposto": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "ICMS"
+                },
+                "modo": {
+                    "type": "string",
+                    "enum": [
+                        "inclusivo",
+                        "exclusivo"
+                    ],
+                    "example": "inclusivo"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key de integração, criada em POST /api-keys",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Token JWT no formato \"Bearer \u003ctoken\u003e\". Gere um token local com ` + "`" + `make token` + "`" + `.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
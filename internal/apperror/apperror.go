@@ -0,0 +1,89 @@
+package apperror
+
+import "errors"
+
+// Categorias de erro de domínio. Use errors.Is para identificar a categoria de
+// um erro retornado por repositórios e serviços.
+var (
+	// ErrNotFound indica que o recurso procurado não existe
+	ErrNotFound = errors.New("não encontrado")
+	// ErrConflict indica que a operação conflita com o estado atual do recurso
+	ErrConflict = errors.New("conflito")
+	// ErrValidation indica dados de entrada inválidos
+	ErrValidation = errors.New("dados inválidos")
+	// ErrBusinessRule indica uma operação válida que viola uma regra de negócio
+	ErrBusinessRule = errors.New("regra de negócio violada")
+	// ErrForbidden indica que quem fez a requisição não tem permissão para a operação
+	ErrForbidden = errors.New("acesso negado")
+	// ErrPreconditionFailed indica que a versão informada pelo cliente (If-Match) não é a atual
+	ErrPreconditionFailed = errors.New("pré-condição falhou")
+	// ErrUnavailable indica um recurso do servidor temporária ou permanentemente indisponível
+	ErrUnavailable = errors.New("indisponível")
+)
+
+// Error é um erro de domínio com categoria, mensagem e causa opcional
+type Error struct {
+	kind    error
+	Message string
+	Err     error
+	// Dependents conta, por tipo, os registros que impedem a operação (ver InUse)
+	Dependents map[string]int64
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap expõe a categoria e a causa, permitindo errors.Is com as categorias
+// e errors.As com o erro original (por exemplo validator.ValidationErrors)
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.kind, e.Err}
+	}
+	return []error{e.kind}
+}
+
+// NotFound cria um erro de recurso inexistente
+func NotFound(message string) *Error {
+	return &Error{kind: ErrNotFound, Message: message}
+}
+
+// Conflict cria um erro de conflito com o estado atual do recurso
+func Conflict(message string) *Error {
+	return &Error{kind: ErrConflict, Message: message}
+}
+
+// InUse cria um conflito para a exclusão de um recurso do qual outros
+// registros ainda dependem; dependents conta esses registros por tipo, por
+// exemplo {"pedidos": 3}
+func InUse(message string, dependents map[string]int64) *Error {
+	return &Error{kind: ErrConflict, Message: message, Dependents: dependents}
+}
+
+// Validation cria um erro de dados de entrada inválidos a partir da causa
+func Validation(message string, err error) *Error {
+	return &Error{kind: ErrValidation, Message: message, Err: err}
+}
+
+// BusinessRule cria um erro de violação de regra de negócio
+func BusinessRule(message string) *Error {
+	return &Error{kind: ErrBusinessRule, Message: message}
+}
+
+// Forbidden cria um erro de falta de permissão para a operação
+func Forbidden(message string) *Error {
+	return &Error{kind: ErrForbidden, Message: message}
+}
+
+// PreconditionFailed cria um erro de versão desatualizada informada pelo cliente
+func PreconditionFailed(message string) *Error {
+	return &Error{kind: ErrPreconditionFailed, Message: message}
+}
+
+// Unavailable cria um erro de funcionalidade indisponível no servidor
+func Unavailable(message string) *Error {
+	return &Error{kind: ErrUnavailable, Message: message}
+}
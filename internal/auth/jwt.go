@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Algoritmos de assinatura suportados
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// clockSkew é a tolerância aplicada na validação de exp e nbf
+const clockSkew = 30 * time.Second
+
+var (
+	// ErrTokenInvalido indica um token malformado, com assinatura inválida ou de outro emissor
+	ErrTokenInvalido = errors.New("token inválido")
+	// ErrTokenExpirado indica um token fora do período de validade
+	ErrTokenExpirado = errors.New("token expirado")
+	// ErrAPIKeyInvalida indica uma API key inexistente, malformada ou revogada
+	ErrAPIKeyInvalida = errors.New("API key inválida")
+)
+
+// Claims são as informações carregadas no payload do token
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Roles     []string `json:"roles"`
+	Issuer    string   `json:"iss,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// JWT emite e valida tokens JWT assinados com HS256 ou RS256. O algoritmo é
+// fixado na criação: tokens com outro alg no cabeçalho são rejeitados.
+type JWT struct {
+	alg        string
+	issuer     string
+	secret     []byte
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+	now        func() time.Time
+}
+
+// NewHS256 cria um JWT assinado com HMAC-SHA256 e o segredo informado
+func NewHS256(secret []byte, issuer string) (*JWT, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("o segredo HS256 deve ter pelo menos 32 bytes")
+	}
+	return &JWT{alg: AlgHS256, issuer: issuer, secret: secret, now: time.Now}, nil
+}
+
+// NewRS256 cria um JWT assinado com RSA-SHA256. A chave privada é opcional e
+// só é necessária para emitir tokens.
+func NewRS256(publicKey *rsa.PublicKey, privateKey *rsa.PrivateKey, issuer string) (*JWT, error) {
+	if publicKey == nil {
+		if privateKey == nil {
+			return nil, errors.New("RS256 exige a chave pública")
+		}
+		publicKey = &privateKey.PublicKey
+	}
+	return &JWT{alg: AlgRS256, issuer: issuer, publicKey: publicKey, privateKey: privateKey, now: time.Now}, nil
+}
+
+// Issue emite um token para o subject com os papéis e a validade informados
+func (j *JWT) Issue(subject string, roles []string, ttl time.Duration) (string, error) {
+	now := j.now()
+	claims := Claims{
+		Subject:   subject,
+		Roles:     roles,
+		Issuer:    j.issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	h, err := encodeSegment(header{Alg: j.alg, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := h + "." + payload
+	signature, err := j.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify valida assinatura, emissor e validade do token e retorna o principal
+func (j *JWT) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenInvalido
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != j.alg {
+		return nil, ErrTokenInvalido
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !j.verifySignature([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrTokenInvalido
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" || claims.ExpiresAt == 0 {
+		return nil, ErrTokenInvalido
+	}
+	if j.issuer != "" && claims.Issuer != j.issuer {
+		return nil, ErrTokenInvalido
+	}
+
+	now := j.now()
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, ErrTokenExpirado
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrTokenInvalido
+	}
+
+	return &Principal{Subject: claims.Subject, Roles: claims.Roles}, nil
+}
+
+func (j *JWT) sign(input []byte) ([]byte, error) {
+	switch j.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, j.secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case AlgRS256:
+		if j.privateKey == nil {
+			return nil, errors.New("chave privada RS256 não configurada")
+		}
+		digest := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(rand.Reader, j.privateKey, crypto.SHA256, digest[:])
+	}
+	return nil, fmt.Errorf("algoritmo não suportado: %s", j.alg)
+}
+
+func (j *JWT) verifySignature(input, signature []byte) bool {
+	switch j.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, j.secret)
+		mac.Write(input)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgRS256:
+		digest := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(j.publicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ParseRSAPublicKey lê uma chave pública RSA em PEM (PKIX ou PKCS#1)
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("chave pública PEM inválida")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("a chave pública não é RSA")
+	}
+	return rsaKey, nil
+}
+
+// ParseRSAPrivateKey lê uma chave privada RSA em PEM (PKCS#8 ou PKCS#1)
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("chave privada PEM inválida")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("a chave privada não é RSA")
+	}
+	return rsaKey, nil
+}
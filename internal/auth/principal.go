@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+)
+
+// ActorAnonimo identifica operações feitas sem um usuário autenticado
+const ActorAnonimo = "anonimo"
+
+// Papéis de acesso. Cada papel inclui as permissões dos papéis abaixo dele:
+// admin ⊃ operador ⊃ leitura.
+const (
+	RoleAdmin    = "admin"
+	RoleOperador = "operador"
+	RoleLeitura  = "leitura"
+)
+
+// papeisIncluidos lista, para cada papel, os papéis que ele também concede
+var papeisIncluidos = map[string][]string{
+	RoleAdmin:    {RoleAdmin, RoleOperador, RoleLeitura},
+	RoleOperador: {RoleOperador, RoleLeitura},
+	RoleLeitura:  {RoleLeitura},
+}
+
+// Recursos da API usados nos escopos de API keys
+const (
+	RecursoClientes = "clientes"
+	RecursoProdutos = "produtos"
+	RecursoPedidos  = "pedidos"
+	RecursoCupons   = "cupons"
+	RecursoImpostos = "impostos"
+	RecursoFrete    = "frete"
+)
+
+// Recursos lista os recursos que podem aparecer em um escopo
+var Recursos = []string{RecursoClientes, RecursoProdutos, RecursoPedidos, RecursoCupons, RecursoImpostos, RecursoFrete}
+
+// Ações de um escopo "<recurso>:<ação>". Cada ação corresponde a um papel
+// (read → leitura, write → operador, admin → admin) e também é cumulativa.
+const (
+	AcaoRead  = "read"
+	AcaoWrite = "write"
+	AcaoAdmin = "admin"
+)
+
+// acaoDoPapel associa cada papel à ação de escopo equivalente
+var acaoDoPapel = map[string]string{
+	RoleLeitura:  AcaoRead,
+	RoleOperador: AcaoWrite,
+	RoleAdmin:    AcaoAdmin,
+}
+
+// papelDaAcao é o inverso de acaoDoPapel
+var papelDaAcao = map[string]string{
+	AcaoRead:  RoleLeitura,
+	AcaoWrite: RoleOperador,
+	AcaoAdmin: RoleAdmin,
+}
+
+// Principal representa quem está realizando a requisição. Usuários (JWT)
+// possuem papéis; API keys possuem escopos por recurso.
+type Principal struct {
+	Subject string
+	Roles   []string
+	Scopes  []string
+}
+
+// HasRole indica se o principal possui o papel, diretamente ou por um papel superior
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		for _, incluido := range papeisIncluidos[r] {
+			if incluido == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Can indica se o principal pode executar, no recurso, uma operação que exige
+// o papel informado: pelo papel diretamente ou por um escopo equivalente
+func (p *Principal) Can(recurso, role string) bool {
+	if p.HasRole(role) {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		r, acao, ok := strings.Cut(scope, ":")
+		if !ok || r != recurso {
+			continue
+		}
+		for _, incluido := range papeisIncluidos[papelDaAcao[acao]] {
+			if incluido == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidScope indica se o escopo tem o formato "<recurso>:<ação>" com recurso e ação conhecidos
+func ValidScope(scope string) bool {
+	recurso, acao, ok := strings.Cut(scope, ":")
+	if !ok {
+		return false
+	}
+	if _, ok := papelDaAcao[acao]; !ok {
+		return false
+	}
+	for _, r := range Recursos {
+		if r == recurso {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal retorna um contexto carregando o principal informado
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext recupera o principal do contexto, se houver
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Actor retorna o identificador de quem executa a operação, usado em registros históricos
+func Actor(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok && p.Subject != "" {
+		return p.Subject
+	}
+	return ActorAnonimo
+}
+
+// RequireRole verifica se o principal do contexto possui o papel informado.
+// Sem principal no contexto o acesso é negado.
+func RequireRole(ctx context.Context, role string) error {
+	if p, ok := FromContext(ctx); ok && p.HasRole(role) {
+		return nil
+	}
+	return apperror.Forbidden("operação exige o papel " + role)
+}
+
+// Authorize verifica se o principal do contexto pode executar no recurso uma
+// operação que exige o papel informado (ver Principal.Can)
+func Authorize(ctx context.Context, recurso, role string) error {
+	if p, ok := FromContext(ctx); ok && p.Can(recurso, role) {
+		return nil
+	}
+	return apperror.Forbidden("operação exige o papel " + role + " ou o escopo " + recurso + ":" + acaoDoPapel[role])
+}
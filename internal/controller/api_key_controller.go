@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyController struct {
+	service service.APIKeyService
+}
+
+// NewAPIKeyController creates a new controller instance
+func NewAPIKeyController(service service.APIKeyService) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+// Create godoc
+// @Summary Create an api key
+// @Description Create an api key for machine-to-machine integrations. The key is returned only in this response; only its hash is stored. Requires the admin role
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param api_key body dto.CreateAPIKeyRequest true "API key data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.APIKeyCreatedResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (c *APIKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindAll godoc
+// @Summary List api keys
+// @Description List api keys, including revoked ones, without their secrets. Requires the admin role
+// @Tags api-keys
+// @Produce json
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Success 200 {object} dto.APIKeyPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (c *APIKeyController) FindAll(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	response, err := c.service.FindAll(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	setLinkHeader(w, r, response)
+	respondJSON(w, http.StatusOK, response)
+}
+
+// Revoke godoc
+// @Summary Revoke an api key
+// @Description Revoke an api key. Revoked keys are rejected immediately and stay listed for auditing. Requires the admin role
+// @Tags api-keys
+// @Param id path int true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (c *APIKeyController) Revoke(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	if err := c.service.Revoke(r.Context(), uint(id)); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
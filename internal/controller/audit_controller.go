@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+)
+
+type AuditController struct {
+	service service.AuditService
+}
+
+// NewAuditController creates a new controller instance
+func NewAuditController(service service.AuditService) *AuditController {
+	return &AuditController{service: service}
+}
+
+// FindAll godoc
+// @Summary List audit log entries
+// @Description List the audit log of cliente, produto and pedido writes in the order they were recorded. Each entry has the actor, the request ID and the changed fields. Requires the admin role
+// @Tags audit
+// @Produce json
+// @Param entity query string false "Entity (cliente, produto or pedido)"
+// @Param id query int false "Entity ID; requires entity"
+// @Param from query string false "Entries recorded at or after this instant (RFC 3339 or 2006-01-02)"
+// @Param to query string false "Entries recorded at or before this instant; a date without time includes the whole day"
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Success 200 {object} dto.AuditLogPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Router /audit [get]
+func (c *AuditController) FindAll(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	query := r.URL.Query()
+	filtro := dto.AuditFilter{
+		Entity: query.Get("entity"),
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+	}
+	if idStr := query.Get("id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			respondError(w, r, apperror.Validation("id inválido", err))
+			return
+		}
+		filtro.ID = uint(id)
+	}
+
+	response, err := c.service.FindAll(r.Context(), filtro, page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	setLinkHeader(w, r, response)
+	respondJSON(w, http.StatusOK, response)
+}
+
+// Verify godoc
+// @Summary Verify the audit log
+// @Description Walk the hash chain of the audit log and report the first entry that was changed, or that follows a removed entry. Requires the admin role
+// @Tags audit
+// @Produce json
+// @Success 200 {object} dto.AuditVerifyResponse
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Router /audit/verify [get]
+func (c *AuditController) Verify(w http.ResponseWriter, r *http.Request) {
+	response, err := c.service.Verify(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
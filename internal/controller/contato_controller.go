@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ContatoController struct {
+	service service.ContatoService
+}
+
+// NewContatoController creates a new controller instance
+func NewContatoController(service service.ContatoService) *ContatoController {
+	return &ContatoController{service: service}
+}
+
+// Create godoc
+// @Summary Create a contato for a cliente
+// @Description Add a contact person with a role (compras, financeiro, fiscal, logistica or outro) to a PJ cliente. The contact needs an email or a telefone. PF clientes return 422
+// @Tags contatos
+// @Accept json
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param contato body dto.CreateContatoRequest true "Contato data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.ContatoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/contatos [post]
+func (c *ContatoController) Create(w http.ResponseWriter, r *http.Request) {
+	clienteID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CreateContatoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), uint(clienteID), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindByClienteID godoc
+// @Summary Get contatos of a cliente
+// @Description Retrieve the contacts of a cliente in creation order, optionally only those with a role
+// @Tags contatos
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param papel query string false "Only contacts with this role" Enums(compras, financeiro, fiscal, logistica, outro)
+// @Success 200 {array} dto.ContatoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/contatos [get]
+func (c *ContatoController) FindByClienteID(w http.ResponseWriter, r *http.Request) {
+	clienteID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindByClienteID(r.Context(), uint(clienteID), r.URL.Query().Get("papel"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// FindByID godoc
+// @Summary Get contato by ID
+// @Description Retrieve a specific contact of a cliente
+// @Tags contatos
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param contato_id path int true "Contato ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
+// @Success 200 {object} dto.ContatoResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/contatos/{contato_id} [get]
+func (c *ContatoController) FindByID(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := contatoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := c.service.FindByID(r.Context(), clienteID, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Update godoc
+// @Summary Replace contato
+// @Description Replace all fields of a contact of a PJ cliente. Omitted optional fields are cleared
+// @Tags contatos
+// @Accept json
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param contato_id path int true "Contato ID"
+// @Param contato body dto.UpdateContatoRequest true "Complete contato representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.ContatoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/contatos/{contato_id} [put]
+func (c *ContatoController) Update(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := contatoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var req dto.UpdateContatoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Update(ifMatchContext(r), clienteID, id, &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Delete godoc
+// @Summary Delete contato
+// @Description Delete a contact of a cliente. Requires the admin role
+// @Tags contatos
+// @Param id path int true "Cliente ID"
+// @Param contato_id path int true "Contato ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/contatos/{contato_id} [delete]
+func (c *ContatoController) Delete(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := contatoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := c.service.Delete(ifMatchContext(r), clienteID, id); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// contatoPath lê o id do cliente e o do contato da rota aninhada
+func contatoPath(r *http.Request) (clienteID, id uint, err error) {
+	c, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("id inválido", err)
+	}
+	e, err := strconv.ParseUint(chi.URLParam(r, "contato_id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("contato_id inválido", err)
+	}
+	return uint(c), uint(e), nil
+}
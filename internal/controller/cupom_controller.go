@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type CupomController struct {
+	service service.CupomService
+}
+
+// NewCupomController creates a new controller instance
+func NewCupomController(service service.CupomService) *CupomController {
+	return &CupomController{service: service}
+}
+
+// Create godoc
+// @Summary Create a new cupom
+// @Description Create a discount cupom (percentual, valor_fixo or frete_gratis). The codigo is stored in upper case. Requires the admin role
+// @Tags cupons
+// @Accept json
+// @Produce json
+// @Param cupom body dto.CreateCupomRequest true "Cupom data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.CupomResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons [post]
+func (c *CupomController) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCupomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindAll godoc
+// @Summary Get all cupons
+// @Description Retrieve all cupons with their usage counters
+// @Tags cupons
+// @Produce json
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[ativo]=true or filter[tipo]=percentual (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,codigo"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,codigo"
+// @Success 200 {object} dto.CupomPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons [get]
+func (c *CupomController) FindAll(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.CupomResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	response, err := c.service.FindAll(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, response, fields)
+}
+
+// FindByID godoc
+// @Summary Get cupom by ID
+// @Description Retrieve a specific cupom by ID
+// @Tags cupons
+// @Produce json
+// @Param id path int true "Cupom ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
+// @Success 200 {object} dto.CupomResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons/{id} [get]
+func (c *CupomController) FindByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindByID(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Update godoc
+// @Summary Replace cupom
+// @Description Replace all fields of an existing cupom except the usage counter. Requires the admin role
+// @Tags cupons
+// @Accept json
+// @Produce json
+// @Param id path int true "Cupom ID"
+// @Param cupom body dto.UpdateCupomRequest true "Complete cupom representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.CupomResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons/{id} [put]
+func (c *CupomController) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.UpdateCupomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Update(ifMatchContext(r), uint(id), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Delete godoc
+// @Summary Delete cupom
+// @Description Delete a cupom by ID. Pedidos that used it keep their discount lines. With purge=true the cupom is permanently removed, whether soft-deleted or not. Requires the admin role
+// @Tags cupons
+// @Param id path int true "Cupom ID"
+// @Param purge query bool false "Permanently remove the cupom instead of soft-deleting it"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons/{id} [delete]
+func (c *CupomController) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	purge, err := parsePurge(r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	if purge {
+		err = c.service.Purge(r.Context(), uint(id))
+	} else {
+		err = c.service.Delete(ifMatchContext(r), uint(id))
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore godoc
+// @Summary Restore cupom
+// @Description Undo the deletion of a soft-deleted cupom. Returns 409 when the cupom is not deleted or its code was registered again for another cupom. Requires the admin role
+// @Tags cupons
+// @Produce json
+// @Param id path int true "Cupom ID"
+// @Success 200 {object} dto.CupomResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cupons/{id}/restore [post]
+func (c *CupomController) Restore(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.Restore(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type DevolucaoController struct {
+	service service.DevolucaoService
+}
+
+// NewDevolucaoController creates a new controller instance
+func NewDevolucaoController(service service.DevolucaoService) *DevolucaoController {
+	return &DevolucaoController{service: service}
+}
+
+// Cancelar godoc
+// @Summary Cancel a pedido
+// @Description Cancel a pendente or pago pedido with a reason. Items not yet returned go back to stock, coupon uses are released and every confirmed payment is refunded through the provider. Payments confirmed after the cancellation are refunded as they arrive
+// @Tags pedidos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param cancelamento body dto.CancelamentoRequest true "Cancellation reason"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 200 {object} dto.PedidoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/cancelamento [post]
+func (c *DevolucaoController) Cancelar(w http.ResponseWriter, r *http.Request) {
+	pedidoID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CancelamentoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Cancelar(ifMatchContext(r), uint(pedidoID), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Devolver godoc
+// @Summary Return items of a pedido
+// @Description Return quantities of items of a pago, enviado or entregue pedido. The returned units go back to stock and each line gets its refund: the item value net of its share of the discount, plus exclusive taxes, prorated by unit. The refund is charged back on the confirmed payments; reembolsado is what was actually refunded. The original order lines are kept and the return is attached to the pedido
+// @Tags pedidos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param devolucao body dto.CreateDevolucaoRequest true "Returned items"
+// @Param If-Match header string false "ETag of the pedido version being changed; a mismatch returns 412"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.DevolucaoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/devolucoes [post]
+func (c *DevolucaoController) Devolver(w http.ResponseWriter, r *http.Request) {
+	pedidoID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CreateDevolucaoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Devolver(ifMatchContext(r), uint(pedidoID), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type EnderecoController struct {
+	service service.EnderecoService
+}
+
+// NewEnderecoController creates a new controller instance
+func NewEnderecoController(service service.EnderecoService) *EnderecoController {
+	return &EnderecoController{service: service}
+}
+
+// Create godoc
+// @Summary Create an endereco for a cliente
+// @Description Add a shipping address to a cliente. The CEP is stored as 8 digits. The first address becomes the default one, and padrao=true moves the default to the new address
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param endereco body dto.CreateEnderecoRequest true "Endereco data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.EnderecoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/enderecos [post]
+func (c *EnderecoController) Create(w http.ResponseWriter, r *http.Request) {
+	clienteID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CreateEnderecoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), uint(clienteID), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindByClienteID godoc
+// @Summary Get enderecos of a cliente
+// @Description Retrieve all addresses of a cliente, the default one first
+// @Tags enderecos
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Success 200 {array} dto.EnderecoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/enderecos [get]
+func (c *EnderecoController) FindByClienteID(w http.ResponseWriter, r *http.Request) {
+	clienteID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindByClienteID(r.Context(), uint(clienteID))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// FindByID godoc
+// @Summary Get endereco by ID
+// @Description Retrieve a specific address of a cliente
+// @Tags enderecos
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param endereco_id path int true "Endereco ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
+// @Success 200 {object} dto.EnderecoResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/enderecos/{endereco_id} [get]
+func (c *EnderecoController) FindByID(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := enderecoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := c.service.FindByID(r.Context(), clienteID, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Update godoc
+// @Summary Replace endereco
+// @Description Replace all fields of an address. Pedidos already placed keep their own copy of the address. The default address can only stop being the default by marking another one
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param id path int true "Cliente ID"
+// @Param endereco_id path int true "Endereco ID"
+// @Param endereco body dto.UpdateEnderecoRequest true "Complete endereco representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.EnderecoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/enderecos/{endereco_id} [put]
+func (c *EnderecoController) Update(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := enderecoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var req dto.UpdateEnderecoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Update(ifMatchContext(r), clienteID, id, &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Delete godoc
+// @Summary Delete endereco
+// @Description Delete an address of a cliente. Deleting the default address promotes the oldest remaining one. Requires the admin role
+// @Tags enderecos
+// @Param id path int true "Cliente ID"
+// @Param endereco_id path int true "Endereco ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /clientes/{id}/enderecos/{endereco_id} [delete]
+func (c *EnderecoController) Delete(w http.ResponseWriter, r *http.Request) {
+	clienteID, id, err := enderecoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := c.service.Delete(ifMatchContext(r), clienteID, id); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// enderecoPath lê o id do cliente e o do endereço da rota aninhada
+func enderecoPath(r *http.Request) (clienteID, id uint, err error) {
+	c, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("id inválido", err)
+	}
+	e, err := strconv.ParseUint(chi.URLParam(r, "endereco_id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("endereco_id inválido", err)
+	}
+	return uint(c), uint(e), nil
+}
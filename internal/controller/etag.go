@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danmaciel/api/internal/etag"
+)
+
+// ifMatchContext retorna o contexto da requisição carregando a pré-condição If-Match
+func ifMatchContext(r *http.Request) context.Context {
+	return etag.WithIfMatch(r.Context(), r.Header.Get("If-Match"))
+}
+
+// respondVersioned escreve o recurso com o cabeçalho ETag da sua versão. Em
+// leituras cujo If-None-Match corresponde à versão atual responde 304 sem corpo.
+func respondVersioned(w http.ResponseWriter, r *http.Request, status int, versao uint, data interface{}) {
+	w.Header().Set("ETag", etag.Format(versao))
+	if inm := r.Header.Get("If-None-Match"); r.Method == http.MethodGet && inm != "" && etag.WeakMatches(inm, versao) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	respondJSON(w, status, data)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// parseFields lê ?fields=id,nome e valida cada campo contra as propriedades
+// JSON de T. Sem o parâmetro todos os campos são retornados.
+func parseFields[T any](param string) ([]string, error) {
+	if param == "" {
+		return nil, nil
+	}
+
+	validos := jsonFields(reflect.TypeFor[T]())
+	var fields []string
+	for _, campo := range strings.Split(param, ",") {
+		campo = strings.TrimSpace(campo)
+		if !validos[campo] {
+			return nil, fmt.Errorf("fields contém um campo desconhecido: %q", campo)
+		}
+		fields = append(fields, campo)
+	}
+	return fields, nil
+}
+
+// jsonFields retorna os nomes das propriedades JSON de um struct
+func jsonFields(t reflect.Type) map[string]bool {
+	campos := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		nome, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if nome != "" && nome != "-" {
+			campos[nome] = true
+		}
+	}
+	return campos
+}
+
+// respondPage escreve a página com o header Link. Com fields informado cada
+// item traz apenas os campos pedidos.
+func respondPage[T any](w http.ResponseWriter, r *http.Request, page *dto.PageResponse[T], fields []string) {
+	setLinkHeader(w, r, page)
+	if len(fields) == 0 {
+		respondJSON(w, http.StatusOK, page)
+		return
+	}
+
+	data := make([]map[string]json.RawMessage, len(page.Data))
+	for i, item := range page.Data {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		var completo map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &completo); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		data[i] = make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := completo[f]; ok {
+				data[i][f] = v
+			}
+		}
+	}
+
+	respondJSON(w, http.StatusOK, dto.PageResponse[map[string]json.RawMessage]{
+		Data:       data,
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Page:       page.Page,
+		NextCursor: page.NextCursor,
+		NextPage:   page.NextPage,
+	})
+}
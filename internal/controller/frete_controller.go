@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type FreteController struct {
+	service service.FreteService
+}
+
+// NewFreteController creates a new controller instance
+func NewFreteController(service service.FreteService) *FreteController {
+	return &FreteController{service: service}
+}
+
+// Cotar godoc
+// @Summary Quote shipping
+// @Description Quote the shipping options for the given items to a CEP. The taxable weight of each item is the larger of its real weight and its cubed weight (A x L x C / 6). Options come from the active zonas covering the CEP, cheapest first; an empty list means the CEP or the weight is not served
+// @Tags frete
+// @Accept json
+// @Produce json
+// @Param cotacao body dto.CotacaoFreteRequest true "CEP and items to ship"
+// @Success 200 {object} dto.CotacaoFreteResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/cotacao [post]
+func (c *FreteController) Cotar(w http.ResponseWriter, r *http.Request) {
+	var req dto.CotacaoFreteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Cotar(r.Context(), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// CreateZona godoc
+// @Summary Create a shipping zona
+// @Description Create a zona of the shipping table: a CEP range (both ends included) served by a servico, with a price per weight bracket. Requires the admin role
+// @Tags frete
+// @Accept json
+// @Produce json
+// @Param zona body dto.CreateFreteZonaRequest true "Zona data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.FreteZonaResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/zonas [post]
+func (c *FreteController) CreateZona(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateFreteZonaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.CreateZona(r.Context(), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindAllZonas godoc
+// @Summary Get all shipping zonas
+// @Description Retrieve all zonas of the shipping table with their weight brackets
+// @Tags frete
+// @Produce json
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[servico]=Expresso or filter[ativo]=true (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. cep_inicio,servico"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome,faixas"
+// @Success 200 {object} dto.FreteZonaPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/zonas [get]
+func (c *FreteController) FindAllZonas(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.FreteZonaResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	response, err := c.service.FindAllZonas(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, response, fields)
+}
+
+// FindZonaByID godoc
+// @Summary Get shipping zona by ID
+// @Description Retrieve a specific zona of the shipping table by ID
+// @Tags frete
+// @Produce json
+// @Param id path int true "Zona ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
+// @Success 200 {object} dto.FreteZonaResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/zonas/{id} [get]
+func (c *FreteController) FindZonaByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindZonaByID(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// UpdateZona godoc
+// @Summary Replace shipping zona
+// @Description Replace all fields of an existing zona, including its weight brackets. Pedidos already created keep their frete. Requires the admin role
+// @Tags frete
+// @Accept json
+// @Produce json
+// @Param id path int true "Zona ID"
+// @Param zona body dto.UpdateFreteZonaRequest true "Complete zona representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.FreteZonaResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/zonas/{id} [put]
+func (c *FreteController) UpdateZona(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.UpdateFreteZonaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.UpdateZona(ifMatchContext(r), uint(id), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// DeleteZona godoc
+// @Summary Delete shipping zona
+// @Description Delete a zona of the shipping table by ID. Pedidos already created keep their frete. Requires the admin role
+// @Tags frete
+// @Param id path int true "Zona ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /frete/zonas/{id} [delete]
+func (c *FreteController) DeleteZona(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	if err := c.service.DeleteZona(ifMatchContext(r), uint(id)); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/mergepatch"
+	"github.com/danmaciel/api/internal/problem"
+)
+
+// readMergePatch lê o corpo de um PATCH. Apenas application/merge-patch+json
+// é aceito; outros formatos recebem 415 com o cabeçalho Accept-Patch.
+func readMergePatch(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergepatch.ContentType {
+		w.Header().Set("Accept-Patch", mergepatch.ContentType)
+		problem.Write(w, r, problem.New(http.StatusUnsupportedMediaType, "PATCH exige o Content-Type "+mergepatch.ContentType))
+		return nil, false
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return nil, false
+	}
+	return patch, true
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxWebhookBytes limita o corpo das notificações do provedor
+const maxWebhookBytes = 64 << 10
+
+type PagamentoController struct {
+	service service.PagamentoService
+}
+
+// NewPagamentoController creates a new controller instance
+func NewPagamentoController(service service.PagamentoService) *PagamentoController {
+	return &PagamentoController{service: service}
+}
+
+// Create godoc
+// @Summary Charge a pedido
+// @Description Charge a pendente pedido through the payment provider. Without valor the whole remaining balance is charged; a smaller valor records a partial payment. Cartão payments are confirmed at once, pix and boleto wait for the provider webhook. The pedido becomes pago when the confirmed payments cover its valor_total
+// @Tags pagamentos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param pagamento body dto.CreatePagamentoRequest true "Pagamento data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.PagamentoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/pagamentos [post]
+func (c *PagamentoController) Create(w http.ResponseWriter, r *http.Request) {
+	pedidoID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CreatePagamentoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), uint(pedidoID), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindByPedidoID godoc
+// @Summary Get pagamentos of a pedido
+// @Description Retrieve the payments of a pedido with their refunds and the reconciliation against valor_total: pago (confirmed minus refunds), em_cobranca (pending) and saldo (still to pay)
+// @Tags pagamentos
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Success 200 {object} dto.PagamentosPedidoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/pagamentos [get]
+func (c *PagamentoController) FindByPedidoID(w http.ResponseWriter, r *http.Request) {
+	pedidoID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindByPedidoID(r.Context(), uint(pedidoID))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// Estornar godoc
+// @Summary Refund a pagamento
+// @Description Refund part or all of a confirmed payment through the provider. Without valor everything not yet refunded is returned; the payment becomes estornado when fully refunded. The pedido status is not changed. Requires the admin role
+// @Tags pagamentos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param pagamento_id path int true "Pagamento ID"
+// @Param estorno body dto.CreateEstornoRequest true "Estorno data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.PagamentoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/pagamentos/{pagamento_id}/estornos [post]
+func (c *PagamentoController) Estornar(w http.ResponseWriter, r *http.Request) {
+	pedidoID, pagamentoID, err := pagamentoPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var req dto.CreateEstornoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Estornar(r.Context(), pedidoID, pagamentoID, &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// Webhook godoc
+// @Summary Payment provider webhook
+// @Description Notification from the payment provider confirming or failing a pending payment. It is authenticated by the HMAC-SHA256 signature of the body in X-Webhook-Signature, not by a token. Repeated notifications are accepted without effect
+// @Tags pagamentos
+// @Accept json
+// @Param evento body object true "Provider event with referencia and status (confirmado or falhou)"
+// @Param X-Webhook-Signature header string true "Hex HMAC-SHA256 of the body with the webhook secret"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Router /webhooks/pagamentos [post]
+func (c *PagamentoController) Webhook(w http.ResponseWriter, r *http.Request) {
+	// a assinatura é calculada sobre o corpo exato recebido
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	if err := c.service.Webhook(r.Context(), payload, r.Header.Get(pagamento.WebhookSignatureHeader)); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pagamentoPath lê o id do pedido e o do pagamento da rota aninhada
+func pagamentoPath(r *http.Request) (pedidoID, id uint, err error) {
+	p, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("id inválido", err)
+	}
+	g, err := strconv.ParseUint(chi.URLParam(r, "pagamento_id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("pagamento_id inválido", err)
+	}
+	return uint(p), uint(g), nil
+}
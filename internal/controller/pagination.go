@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// filterParam reconhece filter[campo] e filter[campo][op]
+var filterParam = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// parseListRequest lê os parâmetros de uma listagem: paginação, filtros,
+// ordenação, os registros excluídos pedidos em ?deleted= e os campos pedidos
+// em ?fields=, validados contra as propriedades JSON de T
+func parseListRequest[T any](r *http.Request) (dto.PageRequest, []string, error) {
+	req, err := parsePageRequest(r)
+	if err != nil {
+		return req, nil, err
+	}
+	if req.Deleted, err = parseDeleted(r.URL.Query().Get("deleted")); err != nil {
+		return req, nil, err
+	}
+	fields, err := parseFields[T](r.URL.Query().Get("fields"))
+	return req, fields, err
+}
+
+// parseDeleted lê ?deleted=only (apenas os registros excluídos) ou
+// ?deleted=include (ativos e excluídos)
+func parseDeleted(param string) (string, error) {
+	switch param {
+	case "", dto.DeletedOnly, dto.DeletedInclude:
+		return param, nil
+	}
+	return "", fmt.Errorf("deleted deve ser %s ou %s", dto.DeletedOnly, dto.DeletedInclude)
+}
+
+// parsePageRequest lê os parâmetros de paginação da query string:
+// ?limit=&cursor= para paginação por cursor ou ?page=&page_size= por página.
+// Também lê os filtros ?filter[campo][op]=valor e a ordenação ?sort=-campo,campo.
+func parsePageRequest(r *http.Request) (dto.PageRequest, error) {
+	query := r.URL.Query()
+	var req dto.PageRequest
+
+	filters, err := parseFilters(query)
+	if err != nil {
+		return req, err
+	}
+	req.Filters = filters
+
+	sort, err := parseSort(query.Get("sort"))
+	if err != nil {
+		return req, err
+	}
+	req.Sort = sort
+
+	if page := query.Get("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return req, errors.New("page deve ser um inteiro maior que zero")
+		}
+		req.Page = n
+
+		if size := query.Get("page_size"); size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil || n < 1 {
+				return req, errors.New("page_size deve ser um inteiro maior que zero")
+			}
+			req.Limit = n
+		}
+
+		if query.Get("cursor") != "" {
+			return req, errors.New("cursor não pode ser combinado com page")
+		}
+		return req, nil
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return req, errors.New("limit deve ser um inteiro maior que zero")
+		}
+		req.Limit = n
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		c, err := dto.DecodeCursor(cursor)
+		if err != nil {
+			return req, err
+		}
+		// o cursor só vale para a mesma ordenação em que foi gerado
+		if c.Sort != dto.FormatSort(req.Sort) {
+			return req, errors.New("cursor gerado para outra ordenação")
+		}
+		req.AfterID = c.ID
+		req.AfterKeys = c.Keys
+	}
+
+	return req, nil
+}
+
+// parseFilters lê os filtros filter[campo]=valor (igualdade) e
+// filter[campo][op]=valor. Parâmetros repetidos geram filtros combinados com AND.
+func parseFilters(query url.Values) ([]dto.FilterParam, error) {
+	var filters []dto.FilterParam
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter") {
+			continue
+		}
+		m := filterParam.FindStringSubmatch(key)
+		if m == nil {
+			return nil, fmt.Errorf("filtro malformado: %s (use filter[campo][op]=valor)", key)
+		}
+		op := m[2]
+		if op == "" {
+			op = "eq"
+		}
+		for _, v := range values {
+			filters = append(filters, dto.FilterParam{Field: m[1], Op: op, Value: v})
+		}
+	}
+
+	// a ordem de um map não é estável; ordena para gerar sempre a mesma query
+	slices.SortFunc(filters, func(a, b dto.FilterParam) int {
+		return cmp.Or(cmp.Compare(a.Field, b.Field), cmp.Compare(a.Op, b.Op), cmp.Compare(a.Value, b.Value))
+	})
+	return filters, nil
+}
+
+// parseSort lê ?sort=-created_at,nome; o prefixo "-" indica ordem decrescente
+func parseSort(param string) ([]dto.SortParam, error) {
+	if param == "" {
+		return nil, nil
+	}
+
+	var sort []dto.SortParam
+	vistos := map[string]bool{}
+	for _, campo := range strings.Split(param, ",") {
+		campo = strings.TrimSpace(campo)
+		desc := strings.HasPrefix(campo, "-")
+		campo = strings.TrimPrefix(campo, "-")
+		if campo == "" {
+			return nil, errors.New("sort contém um campo vazio")
+		}
+		if vistos[campo] {
+			return nil, fmt.Errorf("sort repete o campo %s", campo)
+		}
+		vistos[campo] = true
+		sort = append(sort, dto.SortParam{Field: campo, Desc: desc})
+	}
+	return sort, nil
+}
+
+// setLinkHeader publica no header Link (RFC 8288) as URLs das páginas vizinhas
+func setLinkHeader[T any](w http.ResponseWriter, r *http.Request, page *dto.PageResponse[T]) {
+	var links []string
+
+	if page.Page > 0 {
+		links = append(links, pageLink(r, "first", map[string]string{"page": "1", "page_size": strconv.Itoa(page.Limit)}))
+		if page.Page > 1 {
+			links = append(links, pageLink(r, "prev", map[string]string{"page": strconv.Itoa(page.Page - 1), "page_size": strconv.Itoa(page.Limit)}))
+		}
+		if page.NextPage > 0 {
+			links = append(links, pageLink(r, "next", map[string]string{"page": strconv.Itoa(page.NextPage), "page_size": strconv.Itoa(page.Limit)}))
+		}
+	} else {
+		links = append(links, pageLink(r, "first", map[string]string{"limit": strconv.Itoa(page.Limit), "cursor": ""}))
+		if page.NextCursor != "" {
+			links = append(links, pageLink(r, "next", map[string]string{"limit": strconv.Itoa(page.Limit), "cursor": page.NextCursor}))
+		}
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// pageLink monta um link para a URL atual substituindo os parâmetros informados;
+// parâmetros com valor vazio são removidos
+func pageLink(r *http.Request, rel string, params map[string]string) string {
+	query := r.URL.Query()
+	for k, v := range params {
+		if v == "" {
+			query.Del(k)
+			continue
+		}
+		query.Set(k, v)
+	}
+
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
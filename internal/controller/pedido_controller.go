@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/dto"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -26,24 +27,31 @@ func NewPedidoController(service service.PedidoService) *PedidoController {
 // @Accept json
 // @Produce json
 // @Param pedido body dto.CreatePedidoRequest true "Pedido data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
 // @Success 201 {object} dto.PedidoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos [post]
 func (c *PedidoController) Create(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreatePedidoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		c.respondError(w, http.StatusBadRequest, "Corpo da requisição inválido", err.Error())
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
 		return
 	}
 
 	response, err := c.service.Create(r.Context(), &req)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao criar pedido", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusCreated, response)
+	respondJSON(w, http.StatusCreated, response)
 }
 
 // FindAll godoc
@@ -51,17 +59,36 @@ func (c *PedidoController) Create(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve all pedidos from the database
 // @Tags pedidos
 // @Produce json
-// @Success 200 {array} dto.PedidoResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.PedidoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos [get]
 func (c *PedidoController) FindAll(w http.ResponseWriter, r *http.Request) {
-	responses, err := c.service.FindAll(r.Context())
+	page, fields, err := parseListRequest[dto.PedidoResponse](r)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar pedidos", err.Error())
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	response, err := c.service.FindAll(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, response, fields)
 }
 
 // FindByID godoc
@@ -70,30 +97,33 @@ func (c *PedidoController) FindAll(w http.ResponseWriter, r *http.Request) {
 // @Tags pedidos
 // @Produce json
 // @Param id path int true "Pedido ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
 // @Success 200 {object} dto.PedidoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/{id} [get]
 func (c *PedidoController) FindByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
 	response, err := c.service.FindByID(r.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "pedido not found" {
-			c.respondError(w, http.StatusNotFound, "Pedido nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar pedido", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, response)
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
 }
 
 // FindByClienteID godoc
@@ -102,25 +132,43 @@ func (c *PedidoController) FindByID(w http.ResponseWriter, r *http.Request) {
 // @Tags pedidos
 // @Produce json
 // @Param cliente_id path int true "Cliente ID"
-// @Success 200 {array} dto.PedidoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.PedidoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/cliente/{cliente_id} [get]
 func (c *PedidoController) FindByClienteID(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.PedidoResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
 	clienteIDStr := chi.URLParam(r, "cliente_id")
 	clienteID, err := strconv.ParseUint(clienteIDStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Cliente ID Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("cliente_id inválido", err))
 		return
 	}
 
-	responses, err := c.service.FindByClienteID(r.Context(), uint(clienteID))
+	response, err := c.service.FindByClienteID(r.Context(), uint(clienteID), page)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar pedidos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	respondPage(w, r, response, fields)
 }
 
 // FindByStatus godoc
@@ -129,85 +177,284 @@ func (c *PedidoController) FindByClienteID(w http.ResponseWriter, r *http.Reques
 // @Tags pedidos
 // @Produce json
 // @Param status path string true "Pedido status"
-// @Success 200 {array} dto.PedidoResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.PedidoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/status/{status} [get]
 func (c *PedidoController) FindByStatus(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.PedidoResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
 	status := chi.URLParam(r, "status")
 
-	responses, err := c.service.FindByStatus(r.Context(), status)
+	response, err := c.service.FindByStatus(r.Context(), status, page)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar pedidos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	respondPage(w, r, response, fields)
 }
 
 // UpdateStatus godoc
 // @Summary Update pedido status
-// @Description Update the status of an existing pedido
+// @Description Update the status of an existing pedido. Pedidos normally become pago when their pagamentos are confirmed; setting pago by hand is a manual settlement and requires the admin role
 // @Tags pedidos
 // @Accept json
 // @Produce json
 // @Param id path int true "Pedido ID"
 // @Param pedido body dto.UpdatePedidoRequest true "Pedido status update"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
 // @Success 200 {object} dto.PedidoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/{id} [put]
 func (c *PedidoController) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
 	var req dto.UpdatePedidoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		c.respondError(w, http.StatusBadRequest, "Corpo da requisição inválido", err.Error())
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.UpdateStatus(ifMatchContext(r), uint(id), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// AddItem godoc
+// @Summary Add an item to a pedido
+// @Description Add a produto to a pendente pedido. The produto must be active and have stock, which is reserved; a produto already in the pedido adds to its line. Subtotal, discounts, taxes, freight and total are recalculated, keeping the unit prices of the existing lines
+// @Tags pedidos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param item body dto.CreateItemPedidoRequest true "Item data"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.PedidoResponse
+// @Header 201 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/itens [post]
+func (c *PedidoController) AddItem(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.CreateItemPedidoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
 		return
 	}
 
-	response, err := c.service.UpdateStatus(r.Context(), uint(id), &req)
+	response, err := c.service.AddItem(ifMatchContext(r), uint(id), &req)
 	if err != nil {
-		if err.Error() == "pedido not found" {
-			c.respondError(w, http.StatusNotFound, "Pedido nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao atualizar pedido", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, response)
+	respondVersioned(w, r, http.StatusCreated, response.Versao, response)
+}
+
+// UpdateItem godoc
+// @Summary Change the quantity of a pedido item
+// @Description Change the quantity of an item of a pendente pedido. An increase reserves stock and requires an active produto; a decrease returns the difference to stock. The pedido values are recalculated
+// @Tags pedidos
+// @Accept json
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param item_id path int true "Item ID"
+// @Param item body dto.UpdateItemPedidoRequest true "New quantity"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.PedidoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/itens/{item_id} [put]
+func (c *PedidoController) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := itemPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var req dto.UpdateItemPedidoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.UpdateItem(ifMatchContext(r), id, itemID, &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// RemoveItem godoc
+// @Summary Remove an item from a pedido
+// @Description Remove an item of a pendente pedido, returning its quantity to stock, and respond with the recalculated pedido. The last item cannot be removed; cancel the pedido instead
+// @Tags pedidos
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Param item_id path int true "Item ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.PedidoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/itens/{item_id} [delete]
+func (c *PedidoController) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := itemPath(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := c.service.RemoveItem(ifMatchContext(r), id, itemID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// itemPath lê o id do pedido e o do item da rota aninhada
+func itemPath(r *http.Request) (pedidoID, itemID uint, err error) {
+	p, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("id inválido", err)
+	}
+	i, err := strconv.ParseUint(chi.URLParam(r, "item_id"), 10, 32)
+	if err != nil {
+		return 0, 0, apperror.Validation("item_id inválido", err)
+	}
+	return uint(p), uint(i), nil
+}
+
+// FindHistorico godoc
+// @Summary Get pedido status history
+// @Description Retrieve every status change of a pedido, oldest first
+// @Tags pedidos
+// @Produce json
+// @Param id path int true "Pedido ID"
+// @Success 200 {array} dto.PedidoStatusHistoricoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pedidos/{id}/historico [get]
+func (c *PedidoController) FindHistorico(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	responses, err := c.service.FindHistorico(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, responses)
 }
 
 // Delete godoc
 // @Summary Delete pedido
-// @Description Delete a pedido by ID
+// @Description Delete a pendente or cancelado pedido with no pendente or confirmado pagamentos. Any other pedido returns 409; cancel it with POST /pedidos/{id}/cancelamento
 // @Tags pedidos
 // @Param id path int true "Pedido ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
 // @Success 204 "No Content"
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/{id} [delete]
 func (c *PedidoController) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
-	if err := c.service.Delete(r.Context(), uint(id)); err != nil {
-		if err.Error() == "pedido not found" {
-			c.respondError(w, http.StatusNotFound, "Pedido nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao deletar pedido", err.Error())
+	if err := c.service.Delete(ifMatchContext(r), uint(id)); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -220,30 +467,18 @@ func (c *PedidoController) Delete(w http.ResponseWriter, r *http.Request) {
 // @Tags pedidos
 // @Produce json
 // @Success 200 {object} dto.CountResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pedidos/count [get]
 func (c *PedidoController) Count(w http.ResponseWriter, r *http.Request) {
 	count, err := c.service.Count(r.Context())
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao contar pedidos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, dto.CountResponse{Count: count})
-}
-
-// Helper methods for JSON responses
-func (c *PedidoController) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
-}
-
-func (c *PedidoController) respondError(w http.ResponseWriter, status int, error string, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(dto.ErrorResponse{
-		Error:   error,
-		Message: message,
-	})
+	respondJSON(w, http.StatusOK, dto.CountResponse{Count: count})
 }
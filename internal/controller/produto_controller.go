@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/dto"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -26,24 +27,31 @@ func NewProdutoController(service service.ProdutoService) *ProdutoController {
 // @Accept json
 // @Produce json
 // @Param produto body dto.CreateProdutoRequest true "Produto data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
 // @Success 201 {object} dto.ProdutoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos [post]
 func (c *ProdutoController) Create(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateProdutoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		c.respondError(w, http.StatusBadRequest, "Corpo da requisição inválido", err.Error())
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
 		return
 	}
 
 	response, err := c.service.Create(r.Context(), &req)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao criar produto", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusCreated, response)
+	respondJSON(w, http.StatusCreated, response)
 }
 
 // FindAll godoc
@@ -51,17 +59,36 @@ func (c *ProdutoController) Create(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve all produtos from the database
 // @Tags produtos
 // @Produce json
-// @Success 200 {array} dto.ProdutoResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.ProdutoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos [get]
 func (c *ProdutoController) FindAll(w http.ResponseWriter, r *http.Request) {
-	responses, err := c.service.FindAll(r.Context())
+	page, fields, err := parseListRequest[dto.ProdutoResponse](r)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar produtos", err.Error())
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	response, err := c.service.FindAll(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, response, fields)
 }
 
 // FindByID godoc
@@ -70,30 +97,33 @@ func (c *ProdutoController) FindAll(w http.ResponseWriter, r *http.Request) {
 // @Tags produtos
 // @Produce json
 // @Param id path int true "Produto ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
 // @Success 200 {object} dto.ProdutoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/{id} [get]
 func (c *ProdutoController) FindByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
 	response, err := c.service.FindByID(r.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "produto not found" {
-			c.respondError(w, http.StatusNotFound, "Produto nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar produto", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, response)
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
 }
 
 // FindByName godoc
@@ -102,19 +132,38 @@ func (c *ProdutoController) FindByID(w http.ResponseWriter, r *http.Request) {
 // @Tags produtos
 // @Produce json
 // @Param name path string true "Produto name"
-// @Success 200 {array} dto.ProdutoResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.ProdutoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/nome/{name} [get]
 func (c *ProdutoController) FindByName(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.ProdutoResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
 	nome := chi.URLParam(r, "name")
 
-	responses, err := c.service.FindByName(r.Context(), nome)
+	response, err := c.service.FindByName(r.Context(), nome, page)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar produtos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	respondPage(w, r, response, fields)
 }
 
 // FindByCategoria godoc
@@ -123,121 +172,229 @@ func (c *ProdutoController) FindByName(w http.ResponseWriter, r *http.Request) {
 // @Tags produtos
 // @Produce json
 // @Param categoria path string true "Produto categoria"
-// @Success 200 {array} dto.ProdutoResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[status]=pendente or filter[preco][gte]=10 (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. -created_at,nome"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,nome"
+// @Success 200 {object} dto.ProdutoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/categoria/{categoria} [get]
 func (c *ProdutoController) FindByCategoria(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.ProdutoResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
 	categoria := chi.URLParam(r, "categoria")
 
-	responses, err := c.service.FindByCategoria(r.Context(), categoria)
+	response, err := c.service.FindByCategoria(r.Context(), categoria, page)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao recuperar produtos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, responses)
+	respondPage(w, r, response, fields)
 }
 
 // Update godoc
-// @Summary Update produto
-// @Description Update an existing produto
+// @Summary Replace produto
+// @Description Replace all fields of an existing produto; omitted optional fields are cleared. Changing the price requires the admin role
 // @Tags produtos
 // @Accept json
 // @Produce json
 // @Param id path int true "Produto ID"
-// @Param produto body dto.UpdateProdutoRequest true "Produto data"
+// @Param produto body dto.UpdateProdutoRequest true "Complete produto representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
 // @Success 200 {object} dto.ProdutoResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/{id} [put]
 func (c *ProdutoController) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
 	var req dto.UpdateProdutoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		c.respondError(w, http.StatusBadRequest, "Corpo da requisição inválido", err.Error())
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
 		return
 	}
 
-	response, err := c.service.Update(r.Context(), uint(id), &req)
+	response, err := c.service.Update(ifMatchContext(r), uint(id), &req)
 	if err != nil {
-		if err.Error() == "produto not found" {
-			c.respondError(w, http.StatusNotFound, "Produto nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao atualizar produto", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, response)
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Patch godoc
+// @Summary Partially update produto
+// @Description Apply a JSON Merge Patch (RFC 7396) to an existing produto: only the fields present are changed and null clears optional fields. Changing the price requires the admin role
+// @Tags produtos
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "Produto ID"
+// @Param produto body dto.UpdateProdutoRequest true "Fields to change"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.ProdutoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 415 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /produtos/{id} [patch]
+func (c *ProdutoController) Patch(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	patch, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := c.service.Patch(ifMatchContext(r), uint(id), patch)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
 }
 
 // Delete godoc
 // @Summary Delete produto
-// @Description Delete a produto by ID
+// @Description Delete a produto by ID. With purge=true the produto is permanently removed, whether soft-deleted or not. A produto referenced by pedidos cannot be deleted and returns 409 with the number of blocking pedidos in dependents; with cascade=deactivate such a produto is deactivated (ativo=false), which prevents new pedidos with it, while a produto without pedidos is deleted as usual
 // @Tags produtos
 // @Param id path int true "Produto ID"
+// @Param purge query bool false "Permanently remove the produto instead of soft-deleting it"
+// @Param cascade query string false "What to do when pedidos reference the produto" Enums(deactivate)
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
 // @Success 204 "No Content"
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 404 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/{id} [delete]
 func (c *ProdutoController) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.respondError(w, http.StatusBadRequest, "Id Parametro Invalido", err.Error())
+		respondError(w, r, apperror.Validation("id inválido", err))
 		return
 	}
 
-	if err := c.service.Delete(r.Context(), uint(id)); err != nil {
-		if err.Error() == "produto not found" {
-			c.respondError(w, http.StatusNotFound, "Produto nao encontrado", "")
-			return
-		}
-		c.respondError(w, http.StatusInternalServerError, "Falha ao deletar produto", err.Error())
+	mode, err := parseDeleteMode(r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	switch mode {
+	case deletePurge:
+		err = c.service.Purge(r.Context(), uint(id))
+	case deleteDeactivate:
+		err = c.service.Deactivate(ifMatchContext(r), uint(id))
+	default:
+		err = c.service.Delete(ifMatchContext(r), uint(id))
+	}
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Restore godoc
+// @Summary Restore produto
+// @Description Undo the deletion of a soft-deleted produto. Returns 409 when the produto is not deleted or its SKU was registered again for another produto. Requires the admin role
+// @Tags produtos
+// @Produce json
+// @Param id path int true "Produto ID"
+// @Success 200 {object} dto.ProdutoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /produtos/{id}/restore [post]
+func (c *ProdutoController) Restore(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.Restore(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
 // Count godoc
 // @Summary Count produtos
 // @Description Get the total number of produtos
 // @Tags produtos
 // @Produce json
 // @Success 200 {object} dto.CountResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /produtos/count [get]
 func (c *ProdutoController) Count(w http.ResponseWriter, r *http.Request) {
 	count, err := c.service.Count(r.Context())
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, "Falha ao contar produtos", err.Error())
+		respondError(w, r, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, dto.CountResponse{Count: count})
-}
-
-// Helper methods for JSON responses
-func (c *ProdutoController) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
-}
-
-func (c *ProdutoController) respondError(w http.ResponseWriter, status int, error string, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(dto.ErrorResponse{
-		Error:   error,
-		Message: message,
-	})
+	respondJSON(w, http.StatusOK, dto.CountResponse{Count: count})
 }
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type RegraImpostoController struct {
+	service service.RegraImpostoService
+}
+
+// NewRegraImpostoController creates a new controller instance
+func NewRegraImpostoController(service service.RegraImpostoService) *RegraImpostoController {
+	return &RegraImpostoController{service: service}
+}
+
+// Create godoc
+// @Summary Create a new tax rule
+// @Description Create a tax rule for an imposto (e.g. ICMS, IPI) by product categoria and destination UF; empty categoria or uf match all. Only one rule per imposto, categoria and uf is allowed. Requires the admin role
+// @Tags impostos
+// @Accept json
+// @Produce json
+// @Param regra body dto.CreateRegraImpostoRequest true "Tax rule data"
+// @Param Idempotency-Key header string false "Key to safely retry the request; repeats within the TTL replay the first response"
+// @Success 201 {object} dto.RegraImpostoResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /impostos/regras [post]
+func (c *RegraImpostoController) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRegraImpostoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Create(r.Context(), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// FindAll godoc
+// @Summary Get all tax rules
+// @Description Retrieve all tax rules
+// @Tags impostos
+// @Produce json
+// @Param limit query int false "Page size for cursor pagination (max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param page query int false "Page number for offset pagination"
+// @Param page_size query int false "Page size for offset pagination (max 100)"
+// @Param filter[campo][op] query string false "Filter by a field, e.g. filter[imposto]=ICMS or filter[uf]=SP (ops: eq, ne, gt, gte, lt, lte, in, like)"
+// @Param sort query string false "Comma separated sort fields; prefix with - for descending, e.g. imposto,-uf"
+// @Param deleted query string false "Also list soft-deleted records: only (just the deleted ones) or include (active and deleted)" Enums(only, include)
+// @Param fields query string false "Comma separated response fields to return, e.g. id,imposto,aliquota"
+// @Success 200 {object} dto.RegraImpostoPageResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /impostos/regras [get]
+func (c *RegraImpostoController) FindAll(w http.ResponseWriter, r *http.Request) {
+	page, fields, err := parseListRequest[dto.RegraImpostoResponse](r)
+	if err != nil {
+		respondError(w, r, apperror.Validation("parâmetros de consulta inválidos", err))
+		return
+	}
+
+	response, err := c.service.FindAll(r.Context(), page)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, response, fields)
+}
+
+// FindByID godoc
+// @Summary Get tax rule by ID
+// @Description Retrieve a specific tax rule by ID
+// @Tags impostos
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Param If-None-Match header string false "ETag of a cached copy; a match returns 304 without body"
+// @Success 200 {object} dto.RegraImpostoResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Current version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /impostos/regras/{id} [get]
+func (c *RegraImpostoController) FindByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	response, err := c.service.FindByID(r.Context(), uint(id))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Update godoc
+// @Summary Replace tax rule
+// @Description Replace all fields of an existing tax rule. Pedidos already created keep their taxes. Requires the admin role
+// @Tags impostos
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Param regra body dto.UpdateRegraImpostoRequest true "Complete tax rule representation"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 200 {object} dto.RegraImpostoResponse
+// @Header 200 {string} ETag "New version of the resource"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /impostos/regras/{id} [put]
+func (c *RegraImpostoController) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	var req dto.UpdateRegraImpostoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, apperror.Validation("corpo da requisição inválido", err))
+		return
+	}
+
+	response, err := c.service.Update(ifMatchContext(r), uint(id), &req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondVersioned(w, r, http.StatusOK, response.Versao, response)
+}
+
+// Delete godoc
+// @Summary Delete tax rule
+// @Description Delete a tax rule by ID. Pedidos already created keep their tax lines. Requires the admin role
+// @Tags impostos
+// @Param id path int true "Tax rule ID"
+// @Param If-Match header string false "ETag of the version being changed; a mismatch returns 412"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /impostos/regras/{id} [delete]
+func (c *RegraImpostoController) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		respondError(w, r, apperror.Validation("id inválido", err))
+		return
+	}
+
+	if err := c.service.Delete(ifMatchContext(r), uint(id)); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/problem"
+	"github.com/go-playground/validator/v10"
+)
+
+// respondJSON escreve data como JSON com o status informado
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// respondError traduz o erro em uma resposta problem+json com o status da sua
+// categoria de domínio. Erros de validação listam cada campo inválido. Erros
+// sem categoria são tratados como 500 e o detalhe fica apenas no log.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusFromError(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("erro interno: %v", err)
+		problem.Write(w, r, problem.New(status, ""))
+		return
+	}
+
+	p := problem.New(status, err.Error())
+
+	var validationErrors validator.ValidationErrors
+	var appErr *apperror.Error
+	if errors.As(err, &validationErrors) && errors.As(err, &appErr) {
+		// a mensagem crua do validator é substituída pela lista de campos
+		p.Detail = appErr.Message
+		p.Errors = problem.FieldErrors(validationErrors)
+	}
+	if errors.As(err, &appErr) && appErr.Dependents != nil {
+		p.Dependents = appErr.Dependents
+	}
+
+	problem.Write(w, r, p)
+}
+
+// statusFromError retorna o status HTTP correspondente à categoria do erro
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, apperror.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperror.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperror.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperror.ErrBusinessRule):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, apperror.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,15 +3,51 @@ package controller
 import (
 	"net/http"
 
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/problem"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-// configura o roteador com todas as rotas e middlewares
-func SetupRouter(clienteController *ClienteController, produtoController *ProdutoController, pedidoController *PedidoController) *chi.Mux {
+// Controllers agrupa os controllers expostos pelo roteador
+type Controllers struct {
+	Cliente      *ClienteController
+	Produto      *ProdutoController
+	Pedido       *PedidoController
+	APIKey       *APIKeyController
+	Search       *SearchController
+	Cupom        *CupomController
+	RegraImposto *RegraImpostoController
+	Endereco     *EnderecoController
+	Frete        *FreteController
+	Pagamento    *PagamentoController
+	Devolucao    *DevolucaoController
+	Contato      *ContatoController
+	Audit        *AuditController
+}
+
+// configura o roteador com todas as rotas e middlewares. apiMiddlewares são
+// aplicados em ordem às rotas /api/v1: primeiro os que identificam o principal
+// (middleware.APIKey, middleware.Authenticate) e depois os que dependem dele
+// (middleware.Idempotency).
+func SetupRouter(controllers Controllers, apiMiddlewares ...func(http.Handler) http.Handler) *chi.Mux {
+	clienteController := controllers.Cliente
+	produtoController := controllers.Produto
+	pedidoController := controllers.Pedido
+	apiKeyController := controllers.APIKey
+	searchController := controllers.Search
+	cupomController := controllers.Cupom
+	regraImpostoController := controllers.RegraImposto
+	enderecoController := controllers.Endereco
+	freteController := controllers.Frete
+	pagamentoController := controllers.Pagamento
+	devolucaoController := controllers.Devolucao
+	contatoController := controllers.Contato
+	auditController := controllers.Audit
+
 	r := chi.NewRouter()
 
 	// aplicação de middlewares globais
@@ -23,9 +59,9 @@ func SetupRouter(clienteController *ClienteController, produtoController *Produt
 	// configuração de CORS
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "X-API-Key", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Accept-Patch", "ETag", "Idempotent-Replayed", "Link"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
@@ -35,48 +71,164 @@ func SetupRouter(clienteController *ClienteController, produtoController *Produt
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
+	// papéis exigidos pelas rotas de cada recurso; admin ⊃ operador ⊃ leitura.
+	// API keys são autorizadas pelo escopo equivalente (ex: pedidos:write).
+	permissoes := func(recurso string) (leitura, operador, admin func(http.Handler) http.Handler) {
+		return middleware.Authorize(recurso, auth.RoleLeitura),
+			middleware.Authorize(recurso, auth.RoleOperador),
+			middleware.Authorize(recurso, auth.RoleAdmin)
+	}
+
+	// notificações do provedor de pagamentos: autenticadas pela assinatura do
+	// corpo, não por token, por isso ficam fora dos middlewares da API
+	r.Post("/api/v1/webhooks/pagamentos", pagamentoController.Webhook)
+
 	// rotas da API v1
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(apiMiddlewares...)
+
 		// Rotas de Clientes
 		r.Route("/clientes", func(r chi.Router) {
+			leitura, operador, admin := permissoes(auth.RecursoClientes)
+
 			// IMPORTANT: More specific routes must come before generic ones
-			r.Get("/count", clienteController.Count)           // Must be before /{id}
-			r.Get("/nome/{name}", clienteController.FindByName) // Must be before /{id}
-
-			r.Post("/", clienteController.Create)
-			r.Get("/", clienteController.FindAll)
-			r.Get("/{id}", clienteController.FindByID)
-			r.Put("/{id}", clienteController.Update)
-			r.Delete("/{id}", clienteController.Delete)
+			r.With(leitura).Get("/count", clienteController.Count)           // Must be before /{id}
+			r.With(leitura).Get("/nome/{name}", clienteController.FindByName) // Must be before /{id}
+
+			r.With(operador).Post("/", clienteController.Create)
+			r.With(leitura).Get("/", clienteController.FindAll)
+			r.With(leitura).Get("/{id}", clienteController.FindByID)
+			r.With(operador).Put("/{id}", clienteController.Update)
+			r.With(operador).Patch("/{id}", clienteController.Patch)
+			r.With(admin).Delete("/{id}", clienteController.Delete)
+			r.With(admin).Post("/{id}/restore", clienteController.Restore)
+
+			// Endereços do cliente
+			r.With(operador).Post("/{id}/enderecos", enderecoController.Create)
+			r.With(leitura).Get("/{id}/enderecos", enderecoController.FindByClienteID)
+			r.With(leitura).Get("/{id}/enderecos/{endereco_id}", enderecoController.FindByID)
+			r.With(operador).Put("/{id}/enderecos/{endereco_id}", enderecoController.Update)
+			r.With(admin).Delete("/{id}/enderecos/{endereco_id}", enderecoController.Delete)
+
+			// Contatos de clientes PJ
+			r.With(operador).Post("/{id}/contatos", contatoController.Create)
+			r.With(leitura).Get("/{id}/contatos", contatoController.FindByClienteID)
+			r.With(leitura).Get("/{id}/contatos/{contato_id}", contatoController.FindByID)
+			r.With(operador).Put("/{id}/contatos/{contato_id}", contatoController.Update)
+			r.With(admin).Delete("/{id}/contatos/{contato_id}", contatoController.Delete)
 		})
 
 		// Rotas de Produtos
 		r.Route("/produtos", func(r chi.Router) {
+			leitura, operador, admin := permissoes(auth.RecursoProdutos)
 			// IMPORTANT: More specific routes must come before generic ones
-			r.Get("/count", produtoController.Count)                        // Must be before /{id}
-			r.Get("/nome/{name}", produtoController.FindByName)             // Must be before /{id}
-			r.Get("/categoria/{categoria}", produtoController.FindByCategoria) // Must be before /{id}
-
-			r.Post("/", produtoController.Create)
-			r.Get("/", produtoController.FindAll)
-			r.Get("/{id}", produtoController.FindByID)
-			r.Put("/{id}", produtoController.Update)
-			r.Delete("/{id}", produtoController.Delete)
+			r.With(leitura).Get("/count", produtoController.Count)                           // Must be before /{id}
+			r.With(leitura).Get("/nome/{name}", produtoController.FindByName)                // Must be before /{id}
+			r.With(leitura).Get("/categoria/{categoria}", produtoController.FindByCategoria) // Must be before /{id}
+
+			r.With(operador).Post("/", produtoController.Create)
+			r.With(leitura).Get("/", produtoController.FindAll)
+			r.With(leitura).Get("/{id}", produtoController.FindByID)
+			// alterar o preço exige admin; a verificação fica no serviço
+			r.With(operador).Put("/{id}", produtoController.Update)
+			r.With(operador).Patch("/{id}", produtoController.Patch)
+			r.With(admin).Delete("/{id}", produtoController.Delete)
+			r.With(admin).Post("/{id}/restore", produtoController.Restore)
 		})
 
 		// Rotas de Pedidos
 		r.Route("/pedidos", func(r chi.Router) {
+			leitura, operador, admin := permissoes(auth.RecursoPedidos)
 			// IMPORTANT: More specific routes must come before generic ones
-			r.Get("/count", pedidoController.Count)                          // Must be before /{id}
-			r.Get("/cliente/{cliente_id}", pedidoController.FindByClienteID) // Must be before /{id}
-			r.Get("/status/{status}", pedidoController.FindByStatus)         // Must be before /{id}
-
-			r.Post("/", pedidoController.Create)
-			r.Get("/", pedidoController.FindAll)
-			r.Get("/{id}", pedidoController.FindByID)
-			r.Put("/{id}", pedidoController.UpdateStatus)
-			r.Delete("/{id}", pedidoController.Delete)
+			r.With(leitura).Get("/count", pedidoController.Count)                          // Must be before /{id}
+			r.With(leitura).Get("/cliente/{cliente_id}", pedidoController.FindByClienteID) // Must be before /{id}
+			r.With(leitura).Get("/status/{status}", pedidoController.FindByStatus)         // Must be before /{id}
+
+			r.With(operador).Post("/", pedidoController.Create)
+			r.With(leitura).Get("/", pedidoController.FindAll)
+			r.With(leitura).Get("/{id}", pedidoController.FindByID)
+			// marcar como pago manualmente exige admin; a verificação fica no serviço
+			r.With(operador).Put("/{id}", pedidoController.UpdateStatus)
+			r.With(leitura).Get("/{id}/historico", pedidoController.FindHistorico)
+			r.With(admin).Delete("/{id}", pedidoController.Delete)
+
+			// Itens só mudam enquanto o pedido está pendente
+			r.With(operador).Post("/{id}/itens", pedidoController.AddItem)
+			r.With(operador).Put("/{id}/itens/{item_id}", pedidoController.UpdateItem)
+			r.With(operador).Delete("/{id}/itens/{item_id}", pedidoController.RemoveItem)
+
+			// Cancelamento e devoluções devolvem estoque e estornam os pagamentos
+			r.With(operador).Post("/{id}/cancelamento", devolucaoController.Cancelar)
+			r.With(operador).Post("/{id}/devolucoes", devolucaoController.Devolver)
+
+			// Pagamentos do pedido: estornar é restrito a administradores
+			r.With(operador).Post("/{id}/pagamentos", pagamentoController.Create)
+			r.With(leitura).Get("/{id}/pagamentos", pagamentoController.FindByPedidoID)
+			r.With(admin).Post("/{id}/pagamentos/{pagamento_id}/estornos", pagamentoController.Estornar)
+		})
+
+		// Rotas de Cupons: conceder descontos é restrito a administradores
+		r.Route("/cupons", func(r chi.Router) {
+			leitura, _, admin := permissoes(auth.RecursoCupons)
+
+			r.With(admin).Post("/", cupomController.Create)
+			r.With(leitura).Get("/", cupomController.FindAll)
+			r.With(leitura).Get("/{id}", cupomController.FindByID)
+			r.With(admin).Put("/{id}", cupomController.Update)
+			r.With(admin).Delete("/{id}", cupomController.Delete)
+			r.With(admin).Post("/{id}/restore", cupomController.Restore)
 		})
+
+		// Rotas de regras de imposto: alterar a tributação é restrito a administradores
+		r.Route("/impostos/regras", func(r chi.Router) {
+			leitura, _, admin := permissoes(auth.RecursoImpostos)
+
+			r.With(admin).Post("/", regraImpostoController.Create)
+			r.With(leitura).Get("/", regraImpostoController.FindAll)
+			r.With(leitura).Get("/{id}", regraImpostoController.FindByID)
+			r.With(admin).Put("/{id}", regraImpostoController.Update)
+			r.With(admin).Delete("/{id}", regraImpostoController.Delete)
+		})
+
+		// Rotas de frete: qualquer leitor cota; a tabela de frete é restrita a administradores
+		r.Route("/frete", func(r chi.Router) {
+			leitura, _, admin := permissoes(auth.RecursoFrete)
+
+			r.With(leitura).Post("/cotacao", freteController.Cotar)
+			r.With(admin).Post("/zonas", freteController.CreateZona)
+			r.With(leitura).Get("/zonas", freteController.FindAllZonas)
+			r.With(leitura).Get("/zonas/{id}", freteController.FindZonaByID)
+			r.With(admin).Put("/zonas/{id}", freteController.UpdateZona)
+			r.With(admin).Delete("/zonas/{id}", freteController.DeleteZona)
+		})
+
+		// Busca textual: cada tipo exige leitura no recurso; a verificação fica no serviço
+		r.Get("/search", searchController.Search)
+
+		// Rotas de API keys: apenas usuários admin, nunca outra API key
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(middleware.RequireRole(auth.RoleAdmin))
+
+			r.Post("/", apiKeyController.Create)
+			r.Get("/", apiKeyController.FindAll)
+			r.Delete("/{id}", apiKeyController.Revoke)
+		})
+
+		// Audit log: somente leitura, apenas para usuários admin
+		r.Route("/audit", func(r chi.Router) {
+			r.Use(middleware.RequireRole(auth.RoleAdmin))
+
+			r.Get("/", auditController.FindAll)
+			r.Get("/verify", auditController.Verify)
+		})
+	})
+
+	// rotas e métodos inexistentes também respondem com problem+json
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.New(http.StatusNotFound, "rota não encontrada: "+r.URL.Path))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.New(http.StatusMethodNotAllowed, "método "+r.Method+" não permitido para "+r.URL.Path))
 	})
 
 	// endpoint de Health check
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/service"
+)
+
+type SearchController struct {
+	service service.SearchService
+}
+
+// NewSearchController creates a new controller instance
+func NewSearchController(service service.SearchService) *SearchController {
+	return &SearchController{service: service}
+}
+
+// Search godoc
+// @Summary Search produtos and clientes
+// @Description Full-text search ranked by relevance across produtos (nome, descricao, sku, categoria) and clientes (nome, email, cpf, telefone). Case and accents are ignored and every term also matches as a prefix, so partial words work for autocomplete. Only the types the caller can read are searched
+// @Tags search
+// @Produce json
+// @Param q query string true "Text to search, e.g. joao or cafe ele"
+// @Param tipos query string false "Comma separated types to search (produtos, clientes); defaults to all readable types"
+// @Param limit query int false "Maximum number of results (default 20, max 50)"
+// @Success 200 {object} dto.SearchResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /search [get]
+func (c *SearchController) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.SearchRequest{Q: query.Get("q")}
+
+	if tipos := query.Get("tipos"); tipos != "" {
+		req.Tipos = strings.Split(tipos, ",")
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			respondError(w, r, apperror.Validation("parâmetros de busca inválidos", errors.New("limit deve ser um inteiro maior que zero")))
+			return
+		}
+		req.Limit = n
+	}
+
+	response, err := c.service.Search(r.Context(), req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// parsePurge lê ?purge=true, que troca a exclusão (soft delete) pela remoção
+// definitiva do registro
+func parsePurge(r *http.Request) (bool, error) {
+	param := r.URL.Query().Get("purge")
+	if param == "" {
+		return false, nil
+	}
+	purge, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, errors.New("purge deve ser true ou false")
+	}
+	return purge, nil
+}
+
+// deleteMode é o que o DELETE de um recurso com dependentes faz com o registro
+type deleteMode int
+
+const (
+	// deleteSoft exclui o registro (soft delete)
+	deleteSoft deleteMode = iota
+	// deletePurge remove o registro definitivamente (?purge=true)
+	deletePurge
+	// deleteDeactivate desativa o registro em vez de excluí-lo quando outros
+	// registros dependem dele (?cascade=deactivate)
+	deleteDeactivate
+)
+
+// parseDeleteMode lê ?purge e ?cascade. O único cascade aceito é deactivate,
+// que não pode ser combinado com purge=true.
+func parseDeleteMode(r *http.Request) (deleteMode, error) {
+	purge, err := parsePurge(r)
+	if err != nil {
+		return deleteSoft, err
+	}
+
+	switch r.URL.Query().Get("cascade") {
+	case "":
+	case "deactivate":
+		if purge {
+			return deleteSoft, errors.New("cascade=deactivate não pode ser combinado com purge=true")
+		}
+		return deleteDeactivate, nil
+	default:
+		return deleteSoft, errors.New("cascade deve ser deactivate")
+	}
+
+	if purge {
+		return deletePurge, nil
+	}
+	return deleteSoft, nil
+}
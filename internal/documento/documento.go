@@ -0,0 +1,118 @@
+// Package documento valida e normaliza os documentos de clientes: CPF, para
+// pessoas físicas, e CNPJ e inscrição estadual, para pessoas jurídicas. Os
+// valores são aceitos com ou sem máscara e gravados apenas com os dígitos.
+package documento
+
+import "strings"
+
+// separadores são os caracteres de máscara aceitos na entrada
+const separadores = ".-/ "
+
+// Normalizar remove a máscara do documento, como em 123.456.789-09 ou
+// 12.345.678/0001-95. Outros caracteres são mantidos, para que a validação
+// os recuse.
+func Normalizar(valor string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separadores, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(valor))
+}
+
+// CPFValido confere o tamanho e os dois dígitos verificadores do CPF.
+// Sequências de um mesmo dígito, como 111.111.111-11, são recusadas.
+func CPFValido(valor string) bool {
+	digitos, ok := extrairDigitos(Normalizar(valor), 11)
+	if !ok {
+		return false
+	}
+	return digitos[9] == verificador(digitos[:9], pesosCPF(10)) &&
+		digitos[10] == verificador(digitos[:10], pesosCPF(11))
+}
+
+// CNPJValido confere o tamanho e os dois dígitos verificadores do CNPJ.
+// Sequências de um mesmo dígito são recusadas.
+func CNPJValido(valor string) bool {
+	digitos, ok := extrairDigitos(Normalizar(valor), 14)
+	if !ok {
+		return false
+	}
+	return digitos[12] == verificador(digitos[:12], pesosCNPJ[1:]) &&
+		digitos[13] == verificador(digitos[:13], pesosCNPJ)
+}
+
+// Isento é o valor da inscrição estadual de empresas dispensadas dela
+const Isento = "ISENTO"
+
+// NormalizarInscricaoEstadual remove a máscara da inscrição estadual, como em
+// 110.042.490.114, e grava a isenção sempre em maiúsculas
+func NormalizarInscricaoEstadual(valor string) string {
+	ie := Normalizar(valor)
+	if strings.EqualFold(ie, Isento) {
+		return Isento
+	}
+	return ie
+}
+
+// InscricaoEstadualValida aceita ISENTO ou de 2 a 14 dígitos. O formato e o
+// dígito verificador variam de uma UF para outra e não são conferidos.
+func InscricaoEstadualValida(valor string) bool {
+	ie := NormalizarInscricaoEstadual(valor)
+	if ie == Isento {
+		return true
+	}
+	if len(ie) < 2 || len(ie) > 14 {
+		return false
+	}
+	for _, r := range ie {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// pesosCNPJ são os pesos do segundo dígito verificador; os do primeiro são
+// os mesmos sem o 6 inicial
+var pesosCNPJ = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// pesosCPF retorna os pesos decrescentes a partir de inicial, até 2
+func pesosCPF(inicial int) []int {
+	pesos := make([]int, inicial-1)
+	for i := range pesos {
+		pesos[i] = inicial - i
+	}
+	return pesos
+}
+
+// verificador calcula um dígito verificador pelo módulo 11
+func verificador(digitos, pesos []int) int {
+	soma := 0
+	for i, d := range digitos {
+		soma += d * pesos[i]
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return 0
+	}
+	return 11 - resto
+}
+
+// extrairDigitos converte o documento em dígitos, exigindo o tamanho exato e
+// ao menos dois dígitos diferentes
+func extrairDigitos(valor string, tamanho int) ([]int, bool) {
+	if len(valor) != tamanho {
+		return nil, false
+	}
+	digitos := make([]int, tamanho)
+	repetido := true
+	for i, r := range valor {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		digitos[i] = int(r - '0')
+		repetido = repetido && digitos[i] == digitos[0]
+	}
+	return digitos, !repetido
+}
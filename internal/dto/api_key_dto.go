@@ -0,0 +1,29 @@
+package dto
+
+// CreateAPIKeyRequest represents the request body for creating an api key
+type CreateAPIKeyRequest struct {
+	Nome   string   `json:"nome" validate:"required,min=3,max=100" example:"Conector ERP"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,scope" example:"pedidos:write,produtos:read"`
+}
+
+// APIKeyResponse represents an api key without its secret
+type APIKeyResponse struct {
+	ID         uint     `json:"id"`
+	Nome       string   `json:"nome"`
+	Prefixo    string   `json:"prefixo"`
+	Scopes     []string `json:"scopes"`
+	CriadoPor  string   `json:"criado_por"`
+	LastUsedAt *string  `json:"last_used_at"`
+	RevokedAt  *string  `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// APIKeyCreatedResponse represents a newly created api key. Key is returned
+// only once and cannot be recovered later.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"ak_1a2b3c4d_Zm9vYmFyYmF6..."`
+}
+
+// APIKeyPageResponse representa uma página de API keys
+type APIKeyPageResponse = PageResponse[APIKeyResponse]
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditFilter restringe a listagem do audit log; campos vazios não filtram.
+// From e To aceitam RFC 3339 ou apenas a data (2006-01-02).
+type AuditFilter struct {
+	Entity string
+	ID     uint
+	From   string
+	To     string
+}
+
+// AuditLogResponse represents an audit log entry. Diff lists the changed
+// fields as {"campo": {"antes": ..., "depois": ...}}.
+type AuditLogResponse struct {
+	ID           uint            `json:"id" example:"42"`
+	Entidade     string          `json:"entidade" example:"produto"`
+	EntidadeID   uint            `json:"entidade_id" example:"1"`
+	Acao         string          `json:"acao" example:"update"`
+	Ator         string          `json:"ator" example:"admin@example.com"`
+	RequestID    string          `json:"request_id" example:"host/abc123-000001"`
+	Diff         json.RawMessage `json:"diff" swaggertype:"object"`
+	HashAnterior string          `json:"hash_anterior" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Hash         string          `json:"hash" example:"60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// AuditLogPageResponse representa uma página do audit log
+type AuditLogPageResponse = PageResponse[AuditLogResponse]
+
+// AuditVerifyResponse represents the result of checking the hash chain of the
+// audit log. Entries counts the entries that passed the check; BrokenAt is the
+// ID of the first entry whose hash does not match its content or the previous
+// entry. A chain whose last entries were removed is invalid without BrokenAt.
+type AuditVerifyResponse struct {
+	Valid    bool  `json:"valid" example:"true"`
+	Entries  int64 `json:"entries" example:"128"`
+	BrokenAt *uint `json:"broken_at,omitempty" example:"57"`
+}
@@ -1,30 +1,54 @@
 package dto
 
-// CreateClienteRequest represents the request body for creating a cliente
+// CreateClienteRequest represents the request body for creating a cliente.
+// Tipo defaults to PF, which requires nome and CPF; PJ requires razao_social
+// and CNPJ instead and may have nome_fantasia and inscricao_estadual. Fields
+// of the other type are rejected. Documents are accepted with or without mask
+// and stored as digits only.
 type CreateClienteRequest struct {
-	Nome     string `json:"nome" validate:"required,min=3,max=100"`
-	Email    string `json:"email" validate:"required,email"`
-	CPF      string `json:"cpf" validate:"required,len=11,numeric"`
-	Telefone string `json:"telefone" validate:"omitempty,min=10,max=15"`
+	Tipo              string `json:"tipo" validate:"omitempty,oneof=PF PJ" example:"PF"`
+	Nome              string `json:"nome" validate:"required_unless=Tipo PJ,excluded_if=Tipo PJ,omitempty,min=3,max=100" example:"Maria Silva"`
+	RazaoSocial       string `json:"razao_social" validate:"required_if=Tipo PJ,excluded_unless=Tipo PJ,omitempty,min=3,max=150" example:"Acme Comércio Ltda"`
+	NomeFantasia      string `json:"nome_fantasia" validate:"excluded_unless=Tipo PJ,max=100" example:"Acme"`
+	Email             string `json:"email" validate:"required,email"`
+	CPF               string `json:"cpf" validate:"required_unless=Tipo PJ,excluded_if=Tipo PJ,omitempty,cpf" example:"529.982.247-25"`
+	CNPJ              string `json:"cnpj" validate:"required_if=Tipo PJ,excluded_unless=Tipo PJ,omitempty,cnpj" example:"11.222.333/0001-81"`
+	InscricaoEstadual string `json:"inscricao_estadual" validate:"excluded_unless=Tipo PJ,omitempty,ie" example:"110.042.490.114"`
+	Telefone          string `json:"telefone" validate:"omitempty,min=10,max=15"`
 }
 
-// UpdateClienteRequest represents the request body for updating a cliente
+// UpdateClienteRequest represents the full replacement of a cliente (PUT).
+// Omitted optional fields are cleared; use PATCH to change only some fields.
 type UpdateClienteRequest struct {
-	Nome     string `json:"nome" validate:"omitempty,min=3,max=100"`
-	Email    string `json:"email" validate:"omitempty,email"`
-	CPF      string `json:"cpf" validate:"omitempty,len=11,numeric"`
-	Telefone string `json:"telefone" validate:"omitempty,min=10,max=15"`
+	Tipo              string `json:"tipo" validate:"omitempty,oneof=PF PJ" example:"PF"`
+	Nome              string `json:"nome,omitempty" validate:"required_unless=Tipo PJ,excluded_if=Tipo PJ,omitempty,min=3,max=100" example:"Maria Silva"`
+	RazaoSocial       string `json:"razao_social,omitempty" validate:"required_if=Tipo PJ,excluded_unless=Tipo PJ,omitempty,min=3,max=150" example:"Acme Comércio Ltda"`
+	NomeFantasia      string `json:"nome_fantasia,omitempty" validate:"excluded_unless=Tipo PJ,max=100" example:"Acme"`
+	Email             string `json:"email" validate:"required,email"`
+	CPF               string `json:"cpf,omitempty" validate:"required_unless=Tipo PJ,excluded_if=Tipo PJ,omitempty,cpf" example:"529.982.247-25"`
+	CNPJ              string `json:"cnpj,omitempty" validate:"required_if=Tipo PJ,excluded_unless=Tipo PJ,omitempty,cnpj" example:"11.222.333/0001-81"`
+	InscricaoEstadual string `json:"inscricao_estadual,omitempty" validate:"excluded_unless=Tipo PJ,omitempty,ie" example:"110.042.490.114"`
+	Telefone          string `json:"telefone,omitempty" validate:"omitempty,min=10,max=15"`
 }
 
-// ClienteResponse represents the response for cliente operations
+// ClienteResponse represents the response for cliente operations. Only the
+// fields of the cliente's tipo are present: nome and cpf for PF; razao_social,
+// nome_fantasia, cnpj and inscricao_estadual for PJ.
 type ClienteResponse struct {
-	ID        uint   `json:"id"`
-	Nome      string `json:"nome"`
-	Email     string `json:"email"`
-	CPF       string `json:"cpf"`
-	Telefone  string `json:"telefone"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID                uint   `json:"id"`
+	Tipo              string `json:"tipo" example:"PF"`
+	Nome              string `json:"nome,omitempty"`
+	RazaoSocial       string `json:"razao_social,omitempty"`
+	NomeFantasia      string `json:"nome_fantasia,omitempty"`
+	Email             string `json:"email"`
+	CPF               string `json:"cpf,omitempty"`
+	CNPJ              string `json:"cnpj,omitempty"`
+	InscricaoEstadual string `json:"inscricao_estadual,omitempty"`
+	Telefone          string `json:"telefone"`
+	Versao            uint   `json:"versao"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+	DeletedAt         string `json:"deleted_at,omitempty"`
 }
 
 // CountResponse represents the count response
@@ -32,8 +56,5 @@ type CountResponse struct {
 	Count int64 `json:"count"`
 }
 
-// ErrorResponse represents error responses
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-}
+// ClientePageResponse representa uma página de clientes
+type ClientePageResponse = PageResponse[ClienteResponse]
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateContatoRequest representa a requisição para cadastrar um contato de
+// um cliente PJ. O contato precisa de um email ou de um telefone.
+type CreateContatoRequest struct {
+	Nome     string `json:"nome" validate:"required,min=3,max=100" example:"Ana Souza"`
+	Papel    string `json:"papel" validate:"required,oneof=compras financeiro fiscal logistica outro" example:"financeiro"`
+	Cargo    string `json:"cargo" validate:"max=100" example:"Gerente financeira"`
+	Email    string `json:"email" validate:"required_without=Telefone,omitempty,email" example:"ana@acme.com.br"`
+	Telefone string `json:"telefone" validate:"omitempty,min=10,max=15" example:"11987654321"`
+}
+
+// UpdateContatoRequest representa a substituição completa de um contato (PUT).
+// Campos opcionais omitidos são limpos.
+type UpdateContatoRequest struct {
+	Nome     string `json:"nome" validate:"required,min=3,max=100" example:"Ana Souza"`
+	Papel    string `json:"papel" validate:"required,oneof=compras financeiro fiscal logistica outro" example:"financeiro"`
+	Cargo    string `json:"cargo,omitempty" validate:"max=100" example:"Gerente financeira"`
+	Email    string `json:"email,omitempty" validate:"required_without=Telefone,omitempty,email" example:"ana@acme.com.br"`
+	Telefone string `json:"telefone,omitempty" validate:"omitempty,min=10,max=15" example:"11987654321"`
+}
+
+// ContatoResponse representa a resposta de um contato do cliente
+type ContatoResponse struct {
+	ID        uint      `json:"id"`
+	ClienteID uint      `json:"cliente_id"`
+	Nome      string    `json:"nome"`
+	Papel     string    `json:"papel" example:"financeiro"`
+	Cargo     string    `json:"cargo"`
+	Email     string    `json:"email"`
+	Telefone  string    `json:"telefone"`
+	Versao    uint      `json:"versao"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// CreateCupomRequest representa a requisição para criar um cupom. Percentual
+// vale para cupons percentuais e Valor para cupons de valor fixo. Categorias
+// e produto_ids vazios fazem o cupom valer para todos os itens; limites zerados
+// não restringem o uso.
+type CreateCupomRequest struct {
+	Codigo           string      `json:"codigo" validate:"required,min=3,max=50,codigo" example:"BEMVINDO10"`
+	Descricao        string      `json:"descricao" validate:"max=200" example:"10% na primeira compra"`
+	Tipo             string      `json:"tipo" validate:"required,oneof=percentual valor_fixo frete_gratis" example:"percentual"`
+	Percentual       int         `json:"percentual" validate:"required_if=Tipo percentual,gte=0,lte=100" example:"10"`
+	Valor            money.Money `json:"valor" validate:"required_if=Tipo valor_fixo,gte=0" swaggertype:"string" example:"0.00"`
+	ValorMinimo      money.Money `json:"valor_minimo" validate:"gte=0" swaggertype:"string" example:"100.00"`
+	Categorias       []string    `json:"categorias" validate:"dive,required,max=100"`
+	ProdutoIDs       []uint      `json:"produto_ids" validate:"dive,required"`
+	LimiteUso        int         `json:"limite_uso" validate:"gte=0"`
+	LimitePorCliente int         `json:"limite_por_cliente" validate:"gte=0" example:"1"`
+	ValidoDe         *time.Time  `json:"valido_de"`
+	ValidoAte        *time.Time  `json:"valido_ate"`
+	Ativo            *bool       `json:"ativo"` // pointer para permitir false explícito
+}
+
+// UpdateCupomRequest representa a substituição completa de um cupom (PUT).
+// O contador de usos não é editável.
+type UpdateCupomRequest struct {
+	Codigo           string      `json:"codigo" validate:"required,min=3,max=50,codigo" example:"BEMVINDO10"`
+	Descricao        string      `json:"descricao,omitempty" validate:"max=200" example:"10% na primeira compra"`
+	Tipo             string      `json:"tipo" validate:"required,oneof=percentual valor_fixo frete_gratis" example:"percentual"`
+	Percentual       int         `json:"percentual" validate:"required_if=Tipo percentual,gte=0,lte=100" example:"10"`
+	Valor            money.Money `json:"valor" validate:"required_if=Tipo valor_fixo,gte=0" swaggertype:"string" example:"0.00"`
+	ValorMinimo      money.Money `json:"valor_minimo" validate:"gte=0" swaggertype:"string" example:"100.00"`
+	Categorias       []string    `json:"categorias" validate:"dive,required,max=100"`
+	ProdutoIDs       []uint      `json:"produto_ids" validate:"dive,required"`
+	LimiteUso        int         `json:"limite_uso" validate:"gte=0"`
+	LimitePorCliente int         `json:"limite_por_cliente" validate:"gte=0" example:"1"`
+	ValidoDe         *time.Time  `json:"valido_de"`
+	ValidoAte        *time.Time  `json:"valido_ate"`
+	Ativo            *bool       `json:"ativo" validate:"required"`
+}
+
+// CupomResponse representa a resposta de um cupom
+type CupomResponse struct {
+	ID               uint        `json:"id"`
+	Codigo           string      `json:"codigo"`
+	Descricao        string      `json:"descricao"`
+	Tipo             string      `json:"tipo"`
+	Percentual       int         `json:"percentual"`
+	Valor            money.Money `json:"valor" swaggertype:"string" example:"0.00"`
+	ValorMinimo      money.Money `json:"valor_minimo" swaggertype:"string" example:"100.00"`
+	Categorias       []string    `json:"categorias"`
+	ProdutoIDs       []uint      `json:"produto_ids"`
+	LimiteUso        int         `json:"limite_uso"`
+	LimitePorCliente int         `json:"limite_por_cliente"`
+	Usos             int         `json:"usos"`
+	ValidoDe         *time.Time  `json:"valido_de"`
+	ValidoAte        *time.Time  `json:"valido_ate"`
+	Ativo            bool        `json:"ativo"`
+	Versao           uint        `json:"versao"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+	DeletedAt        *time.Time  `json:"deleted_at,omitempty"`
+}
+
+// CupomPageResponse representa uma página de cupons
+type CupomPageResponse = PageResponse[CupomResponse]
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// CancelamentoRequest representa o cancelamento de um pedido
+type CancelamentoRequest struct {
+	Motivo string `json:"motivo" validate:"required,max=200" example:"Cliente desistiu da compra"`
+}
+
+// CreateDevolucaoRequest representa a devolução de parte dos itens de um pedido
+type CreateDevolucaoRequest struct {
+	Motivo string                 `json:"motivo" validate:"max=200" example:"Produto com defeito"`
+	Itens  []DevolucaoItemRequest `json:"itens" validate:"required,min=1,dive"`
+}
+
+// DevolucaoItemRequest representa a quantidade devolvida de um item do pedido
+type DevolucaoItemRequest struct {
+	// ItemID é o id do item do pedido (itens[].id), não o do produto
+	ItemID     uint `json:"item_id" validate:"required" example:"1"`
+	Quantidade int  `json:"quantidade" validate:"required,gt=0" example:"1"`
+}
+
+// DevolucaoResponse representa uma devolução na resposta
+type DevolucaoResponse struct {
+	ID          uint                    `json:"id"`
+	PedidoID    uint                    `json:"pedido_id"`
+	Motivo      string                  `json:"motivo,omitempty"`
+	Valor       money.Money             `json:"valor" swaggertype:"string" example:"49.90"`
+	Reembolsado money.Money             `json:"reembolsado" swaggertype:"string" example:"49.90"`
+	Usuario     string                  `json:"usuario"`
+	Itens       []DevolucaoItemResponse `json:"itens"`
+	CreatedAt   time.Time               `json:"created_at"`
+}
+
+// DevolucaoItemResponse representa um item devolvido e o seu reembolso
+type DevolucaoItemResponse struct {
+	ID         uint        `json:"id"`
+	ItemID     uint        `json:"item_id"`
+	ProdutoID  uint        `json:"produto_id"`
+	Quantidade int         `json:"quantidade"`
+	Valor      money.Money `json:"valor" swaggertype:"string" example:"49.90"`
+}
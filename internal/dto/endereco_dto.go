@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// CreateEnderecoRequest representa a requisição para cadastrar um endereço do
+// cliente. O CEP aceita 00000-000 ou 00000000 e é gravado só com os dígitos.
+// O primeiro endereço do cliente sempre se torna o padrão.
+type CreateEnderecoRequest struct {
+	Apelido     string `json:"apelido" validate:"max=50" example:"Casa"`
+	CEP         string `json:"cep" validate:"required,cep" example:"01310-100"`
+	Logradouro  string `json:"logradouro" validate:"required,max=200" example:"Avenida Paulista"`
+	Numero      string `json:"numero" validate:"required,max=20" example:"1000"`
+	Complemento string `json:"complemento" validate:"max=100" example:"Apto 42"`
+	Bairro      string `json:"bairro" validate:"required,max=100" example:"Bela Vista"`
+	Cidade      string `json:"cidade" validate:"required,max=100" example:"São Paulo"`
+	UF          string `json:"uf" validate:"required,uf" example:"SP"`
+	Padrao      bool   `json:"padrao"`
+}
+
+// UpdateEnderecoRequest representa a substituição completa de um endereço (PUT).
+// O endereço padrão só deixa de ser padrão quando outro é marcado no lugar.
+type UpdateEnderecoRequest struct {
+	Apelido     string `json:"apelido,omitempty" validate:"max=50" example:"Casa"`
+	CEP         string `json:"cep" validate:"required,cep" example:"01310-100"`
+	Logradouro  string `json:"logradouro" validate:"required,max=200" example:"Avenida Paulista"`
+	Numero      string `json:"numero" validate:"required,max=20" example:"1000"`
+	Complemento string `json:"complemento,omitempty" validate:"max=100" example:"Apto 42"`
+	Bairro      string `json:"bairro" validate:"required,max=100" example:"Bela Vista"`
+	Cidade      string `json:"cidade" validate:"required,max=100" example:"São Paulo"`
+	UF          string `json:"uf" validate:"required,uf" example:"SP"`
+	Padrao      *bool  `json:"padrao" validate:"required"`
+}
+
+// EnderecoResponse representa a resposta de um endereço do cliente
+type EnderecoResponse struct {
+	ID          uint      `json:"id"`
+	ClienteID   uint      `json:"cliente_id"`
+	Apelido     string    `json:"apelido"`
+	CEP         string    `json:"cep" example:"01310100"`
+	Logradouro  string    `json:"logradouro"`
+	Numero      string    `json:"numero"`
+	Complemento string    `json:"complemento"`
+	Bairro      string    `json:"bairro"`
+	Cidade      string    `json:"cidade"`
+	UF          string    `json:"uf"`
+	Padrao      bool      `json:"padrao"`
+	Versao      uint      `json:"versao"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EnderecoEntregaResponse é o endereço de entrega copiado para o pedido
+type EnderecoEntregaResponse struct {
+	CEP         string `json:"cep" example:"01310100"`
+	Logradouro  string `json:"logradouro"`
+	Numero      string `json:"numero"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Cidade      string `json:"cidade"`
+	UF          string `json:"uf"`
+}
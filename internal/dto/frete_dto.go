@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// FreteFaixaRequest é uma faixa de peso da zona: pacotes de até
+// peso_maximo_gramas pagam valor
+type FreteFaixaRequest struct {
+	PesoMaximoGramas int         `json:"peso_maximo_gramas" validate:"required,gt=0" example:"5000"`
+	Valor            money.Money `json:"valor" validate:"gte=0" swaggertype:"string" example:"25.90"`
+}
+
+// CreateFreteZonaRequest representa a requisição para criar uma zona da
+// tabela de frete. Os CEPs aceitam 00000-000 ou 00000000 e a faixa inclui as
+// duas pontas.
+type CreateFreteZonaRequest struct {
+	Nome      string              `json:"nome" validate:"required,max=100" example:"Capital SP"`
+	Servico   string              `json:"servico" validate:"required,max=50" example:"Expresso"`
+	CEPInicio string              `json:"cep_inicio" validate:"required,cep" example:"01000-000"`
+	CEPFim    string              `json:"cep_fim" validate:"required,cep" example:"05999-999"`
+	PrazoDias int                 `json:"prazo_dias" validate:"gte=0" example:"2"`
+	Faixas    []FreteFaixaRequest `json:"faixas" validate:"required,min=1,dive"`
+	Ativo     *bool               `json:"ativo"` // pointer para permitir false explícito
+}
+
+// UpdateFreteZonaRequest representa a substituição completa de uma zona de
+// frete (PUT), inclusive as faixas de peso
+type UpdateFreteZonaRequest struct {
+	Nome      string              `json:"nome" validate:"required,max=100" example:"Capital SP"`
+	Servico   string              `json:"servico" validate:"required,max=50" example:"Expresso"`
+	CEPInicio string              `json:"cep_inicio" validate:"required,cep" example:"01000-000"`
+	CEPFim    string              `json:"cep_fim" validate:"required,cep" example:"05999-999"`
+	PrazoDias int                 `json:"prazo_dias" validate:"gte=0" example:"2"`
+	Faixas    []FreteFaixaRequest `json:"faixas" validate:"required,min=1,dive"`
+	Ativo     *bool               `json:"ativo" validate:"required"`
+}
+
+// FreteFaixaResponse representa uma faixa de peso na resposta
+type FreteFaixaResponse struct {
+	PesoMaximoGramas int         `json:"peso_maximo_gramas"`
+	Valor            money.Money `json:"valor" swaggertype:"string" example:"25.90"`
+}
+
+// FreteZonaResponse representa a resposta de uma zona de frete
+type FreteZonaResponse struct {
+	ID        uint                 `json:"id"`
+	Nome      string               `json:"nome"`
+	Servico   string               `json:"servico"`
+	CEPInicio string               `json:"cep_inicio" example:"01000000"`
+	CEPFim    string               `json:"cep_fim" example:"05999999"`
+	PrazoDias int                  `json:"prazo_dias"`
+	Faixas    []FreteFaixaResponse `json:"faixas"`
+	Ativo     bool                 `json:"ativo"`
+	Versao    uint                 `json:"versao"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	DeletedAt *time.Time           `json:"deleted_at,omitempty"`
+}
+
+// FreteZonaPageResponse representa uma página de zonas de frete
+type FreteZonaPageResponse = PageResponse[FreteZonaResponse]
+
+// CotacaoFreteRequest representa a cotação do frete dos itens para um CEP
+type CotacaoFreteRequest struct {
+	CEP   string                    `json:"cep" validate:"required,cep" example:"01310-100"`
+	Itens []CreateItemPedidoRequest `json:"itens" validate:"required,min=1,dive"`
+}
+
+// OpcaoFreteResponse é um serviço de entrega disponível
+type OpcaoFreteResponse struct {
+	Servico   string      `json:"servico" example:"Expresso"`
+	Valor     money.Money `json:"valor" swaggertype:"string" example:"25.90"`
+	PrazoDias int         `json:"prazo_dias" example:"2"`
+}
+
+// CotacaoFreteResponse traz o peso taxável do pacote e as opções de entrega,
+// da mais barata para a mais cara. Sem opções, o CEP ou o peso não são atendidos.
+type CotacaoFreteResponse struct {
+	CEP        string               `json:"cep" example:"01310100"`
+	PesoGramas int                  `json:"peso_gramas" example:"2500"`
+	Opcoes     []OpcaoFreteResponse `json:"opcoes"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// CreatePagamentoRequest representa a requisição para cobrar um pedido. Sem
+// valor, cobra todo o saldo ainda não pago nem em cobrança; um valor menor
+// registra um pagamento parcial.
+type CreatePagamentoRequest struct {
+	Metodo string      `json:"metodo" validate:"required,oneof=pix boleto cartao" example:"pix"`
+	Valor  money.Money `json:"valor" validate:"gte=0" swaggertype:"string" example:"150.00"`
+}
+
+// CreateEstornoRequest representa a devolução de um pagamento confirmado.
+// Sem valor, estorna tudo o que ainda não foi estornado.
+type CreateEstornoRequest struct {
+	Valor  money.Money `json:"valor" validate:"gte=0" swaggertype:"string" example:"50.00"`
+	Motivo string      `json:"motivo" validate:"max=200" example:"Item devolvido"`
+}
+
+// EstornoResponse representa um estorno na resposta
+type EstornoResponse struct {
+	ID         uint        `json:"id"`
+	Valor      money.Money `json:"valor" swaggertype:"string" example:"50.00"`
+	Status     string      `json:"status" example:"concluido"`
+	Referencia string      `json:"referencia"`
+	Motivo     string      `json:"motivo,omitempty"`
+	Usuario    string      `json:"usuario"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// PagamentoResponse representa a resposta de um pagamento
+type PagamentoResponse struct {
+	ID           uint              `json:"id"`
+	PedidoID     uint              `json:"pedido_id"`
+	Metodo       string            `json:"metodo" example:"pix"`
+	Valor        money.Money       `json:"valor" swaggertype:"string" example:"150.00"`
+	Estornado    money.Money       `json:"estornado" swaggertype:"string" example:"0.00"`
+	Status       string            `json:"status" example:"pendente"`
+	Provedor     string            `json:"provedor" example:"fake"`
+	Referencia   string            `json:"referencia" example:"fake_pay_4f1c2a9b8e7d6c5b4a3f2e1d"`
+	ConfirmadoEm *time.Time        `json:"confirmado_em,omitempty"`
+	Estornos     []EstornoResponse `json:"estornos,omitempty"`
+	Versao       uint              `json:"versao"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// PagamentosPedidoResponse reúne os pagamentos de um pedido e a conciliação
+// com o valor total: pago é a soma confirmada menos os estornos, em_cobranca
+// a soma dos pagamentos pendentes e saldo o que falta pagar
+type PagamentosPedidoResponse struct {
+	PedidoID   uint                `json:"pedido_id"`
+	ValorTotal money.Money         `json:"valor_total" swaggertype:"string" example:"300.00"`
+	Pago       money.Money         `json:"pago" swaggertype:"string" example:"150.00"`
+	EmCobranca money.Money         `json:"em_cobranca" swaggertype:"string" example:"0.00"`
+	Saldo      money.Money         `json:"saldo" swaggertype:"string" example:"150.00"`
+	Pagamentos []PagamentoResponse `json:"pagamentos"`
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrCursorInvalido indica um cursor de paginação malformado
+var ErrCursorInvalido = errors.New("cursor inválido")
+
+// Valores de ?deleted= em uma listagem
+const (
+	// DeletedOnly lista apenas os registros excluídos
+	DeletedOnly = "only"
+	// DeletedInclude lista os registros ativos e os excluídos
+	DeletedInclude = "include"
+)
+
+const (
+	cursorPrefix     = "id:"
+	sortCursorPrefix = "sort:"
+)
+
+// PageRequest representa os parâmetros de paginação de uma listagem.
+// Page > 0 indica paginação por página (?page=&page_size=); caso contrário a
+// paginação é por cursor (?limit=&cursor=). Filters e Sort vêm de
+// ?filter[campo][op]=valor e ?sort=-campo,campo e são validados pelo serviço
+// contra os campos permitidos de cada entidade. Deleted vem de
+// ?deleted=only|include e inclui na listagem os registros excluídos.
+type PageRequest struct {
+	Limit     int
+	AfterID   uint
+	AfterKeys []*string
+	Page      int
+	Filters   []FilterParam
+	Sort      []SortParam
+	Deleted   string
+}
+
+// FilterParam é um filtro da listagem como recebido na query string
+type FilterParam struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// SortParam é um critério de ordenação da listagem; Desc vem do prefixo "-"
+type SortParam struct {
+	Field string
+	Desc  bool
+}
+
+// FormatSort devolve a ordenação no formato do parâmetro sort, por exemplo "-created_at,nome"
+func FormatSort(sort []SortParam) string {
+	campos := make([]string, len(sort))
+	for i, s := range sort {
+		campos[i] = s.Field
+		if s.Desc {
+			campos[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(campos, ",")
+}
+
+// Cursor aponta para depois de um registro da listagem. Em listagens
+// ordenadas guarda também a ordenação e os valores das chaves de ordenação
+// do registro, no formato aceito pelos filtros; chaves nulas (colunas
+// opcionais sem valor) ficam como null.
+type Cursor struct {
+	ID   uint      `json:"id"`
+	Sort string    `json:"sort,omitempty"`
+	Keys []*string `json:"keys,omitempty"`
+}
+
+// PageResponse representa o envelope de uma listagem paginada
+type PageResponse[T any] struct {
+	Data       []T    `json:"data"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	NextPage   int    `json:"next_page,omitempty"`
+}
+
+// EncodeCursor gera o cursor opaco que aponta para depois do registro informado
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+// EncodeSortCursor gera o cursor opaco de uma listagem ordenada
+func EncodeSortCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(append([]byte(sortCursorPrefix), raw...))
+}
+
+// DecodeCursor recupera o conteúdo de um cursor gerado por EncodeCursor ou EncodeSortCursor
+func DecodeCursor(cursor string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrCursorInvalido
+	}
+
+	if rest, ok := strings.CutPrefix(string(raw), sortCursorPrefix); ok {
+		var c Cursor
+		if err := json.Unmarshal([]byte(rest), &c); err != nil || c.ID == 0 || c.Sort == "" {
+			return Cursor{}, ErrCursorInvalido
+		}
+		return c, nil
+	}
+
+	rest, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return Cursor{}, ErrCursorInvalido
+	}
+	id, err := strconv.ParseUint(rest, 10, 32)
+	if err != nil || id == 0 {
+		return Cursor{}, ErrCursorInvalido
+	}
+	return Cursor{ID: uint(id)}, nil
+}
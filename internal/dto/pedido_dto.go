@@ -1,12 +1,23 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
 
 // CreatePedidoRequest representa a requisição para criar um pedido
 type CreatePedidoRequest struct {
 	ClienteID uint                   `json:"cliente_id" validate:"required"`
 	Itens     []CreateItemPedidoRequest `json:"itens" validate:"required,min=1,dive"`
-	Status    string                 `json:"status" validate:"omitempty,oneof=pendente pago enviado entregue cancelado"`
+	Status    string                 `json:"status" validate:"omitempty,oneof=pendente"`
+	Cupom     string                 `json:"cupom" validate:"omitempty,max=50" example:"BEMVINDO10"`
+	// UFDestino escolhe as regras de imposto por UF; sem ela valem apenas as regras sem UF
+	UFDestino string                 `json:"uf_destino" validate:"omitempty,uf" example:"SP"`
+	// EnderecoID é o endereço de entrega; sem ele vale o endereço padrão do cliente, se houver
+	EnderecoID *uint                 `json:"endereco_id" example:"1"`
+	// FreteServico escolhe o serviço de entrega; sem ele vale a opção mais barata
+	FreteServico string              `json:"frete_servico" validate:"omitempty,max=50" example:"Expresso"`
 }
 
 // CreateItemPedidoRequest representa um item no pedido
@@ -15,6 +26,11 @@ type CreateItemPedidoRequest struct {
 	Quantidade int  `json:"quantidade" validate:"required,gt=0"`
 }
 
+// UpdateItemPedidoRequest representa a nova quantidade de um item de um pedido pendente
+type UpdateItemPedidoRequest struct {
+	Quantidade int `json:"quantidade" validate:"required,gt=0" example:"3"`
+}
+
 // UpdatePedidoRequest representa a requisição para atualizar um pedido
 type UpdatePedidoRequest struct {
 	Status string `json:"status" validate:"required,oneof=pendente pago enviado entregue cancelado"`
@@ -26,11 +42,26 @@ type PedidoResponse struct {
 	ClienteID   uint                 `json:"cliente_id"`
 	Cliente     *ClienteResponse     `json:"cliente,omitempty"`
 	Itens       []ItemPedidoResponse `json:"itens"`
-	ValorTotal  float64              `json:"valor_total"`
+	Descontos   []DescontoResponse   `json:"descontos"`
+	Subtotal    money.Money          `json:"subtotal" swaggertype:"string" example:"5999.98"`
+	Desconto    money.Money          `json:"desconto" swaggertype:"string" example:"0.00"`
+	Impostos    []ImpostoResumoResponse `json:"impostos"`
+	Imposto     money.Money          `json:"imposto" swaggertype:"string" example:"1079.99"`
+	ValorTotal  money.Money          `json:"valor_total" swaggertype:"string" example:"5999.98"`
+	UFDestino   string               `json:"uf_destino"`
+	EnderecoID  *uint                `json:"endereco_id"`
+	Entrega     *EnderecoEntregaResponse `json:"entrega"`
+	Frete       money.Money          `json:"frete" swaggertype:"string" example:"25.90"`
+	FreteServico string              `json:"frete_servico"`
+	FretePrazoDias int               `json:"frete_prazo_dias"`
 	Status      string               `json:"status"`
+	MotivoCancelamento string        `json:"motivo_cancelamento,omitempty"`
+	Devolucoes  []DevolucaoResponse  `json:"devolucoes"`
 	DataPedido  time.Time            `json:"data_pedido"`
+	Versao      uint                 `json:"versao"`
 	CreatedAt   time.Time            `json:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at"`
+	DeletedAt   *time.Time           `json:"deleted_at,omitempty"`
 }
 
 // ItemPedidoResponse representa um item do pedido na resposta
@@ -39,6 +70,55 @@ type ItemPedidoResponse struct {
 	ProdutoID     uint             `json:"produto_id"`
 	Produto       *ProdutoResponse `json:"produto,omitempty"`
 	Quantidade    int              `json:"quantidade"`
-	PrecoUnitario float64          `json:"preco_unitario"`
-	Subtotal      float64          `json:"subtotal"`
+	PrecoUnitario money.Money      `json:"preco_unitario" swaggertype:"string" example:"2999.99"`
+	Subtotal      money.Money      `json:"subtotal" swaggertype:"string" example:"5999.98"`
+	Imposto       money.Money      `json:"imposto" swaggertype:"string" example:"1079.99"`
+	Impostos      []ImpostoItemResponse `json:"impostos"`
+	// Desconto é a parte do desconto do pedido rateada para o item
+	Desconto      money.Money      `json:"desconto" swaggertype:"string" example:"0.00"`
+	// QuantidadeDevolvida soma as devoluções do item; Quantidade não muda
+	QuantidadeDevolvida int        `json:"quantidade_devolvida"`
+}
+
+// ImpostoItemResponse representa um imposto calculado sobre um item do pedido
+type ImpostoItemResponse struct {
+	RegraID  *uint          `json:"regra_id"`
+	Imposto  string         `json:"imposto" example:"ICMS"`
+	Aliquota money.Aliquota `json:"aliquota" swaggertype:"string" example:"18.00"`
+	Modo     string         `json:"modo" example:"inclusivo"`
+	Base     money.Money    `json:"base" swaggertype:"string" example:"5999.98"`
+	Valor    money.Money    `json:"valor" swaggertype:"string" example:"1079.99"`
+}
+
+// ImpostoResumoResponse totaliza um imposto de todos os itens do pedido.
+// Impostos exclusivos estão somados ao valor_total; inclusivos já estão nos preços.
+type ImpostoResumoResponse struct {
+	Imposto string      `json:"imposto" example:"ICMS"`
+	Modo    string      `json:"modo" example:"inclusivo"`
+	Base    money.Money `json:"base" swaggertype:"string" example:"5999.98"`
+	Valor   money.Money `json:"valor" swaggertype:"string" example:"1079.99"`
+}
+
+// DescontoResponse representa uma linha de desconto aplicada ao pedido
+type DescontoResponse struct {
+	ID        uint        `json:"id"`
+	CupomID   *uint       `json:"cupom_id"`
+	Codigo    string      `json:"codigo"`
+	Tipo      string      `json:"tipo"`
+	Descricao string      `json:"descricao"`
+	Base      money.Money `json:"base" swaggertype:"string" example:"5999.98"`
+	Valor     money.Money `json:"valor" swaggertype:"string" example:"599.99"`
 }
+
+// PedidoStatusHistoricoResponse representa uma mudança de status na resposta
+type PedidoStatusHistoricoResponse struct {
+	ID             uint      `json:"id"`
+	PedidoID       uint      `json:"pedido_id"`
+	StatusAnterior string    `json:"status_anterior"`
+	StatusNovo     string    `json:"status_novo"`
+	Usuario        string    `json:"usuario"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PedidoPageResponse representa uma página de pedidos
+type PedidoPageResponse = PageResponse[PedidoResponse]
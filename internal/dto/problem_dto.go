@@ -0,0 +1,21 @@
+package dto
+
+// ProblemDetails representa uma resposta de erro no formato RFC 9457
+// (application/problem+json)
+type ProblemDetails struct {
+	Type     string       `json:"type" example:"/problems/validation-error"`
+	Title    string       `json:"title" example:"Requisição inválida"`
+	Status   int          `json:"status" example:"400"`
+	Detail   string       `json:"detail,omitempty" example:"dados do cliente inválidos"`
+	Instance string       `json:"instance,omitempty" example:"host/abc123-000001"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	// Dependents conta, por tipo, os registros que impedem a exclusão do recurso
+	Dependents map[string]int64 `json:"dependents,omitempty" example:"pedidos:3"`
+}
+
+// FieldError descreve um campo que não passou na validação
+type FieldError struct {
+	Field   string `json:"field" example:"cpf"`
+	Rule    string `json:"rule" example:"len"`
+	Message string `json:"message" example:"deve ter exatamente 11 caracteres"`
+}
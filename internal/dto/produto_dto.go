@@ -1,39 +1,66 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
 
 // CreateProdutoRequest representa a requisição para criar um produto
 type CreateProdutoRequest struct {
-	Nome      string  `json:"nome" validate:"required,min=3,max=200"`
-	Descricao string  `json:"descricao" validate:"max=1000"`
-	Preco     float64 `json:"preco" validate:"required,gt=0"`
-	Estoque   int     `json:"estoque" validate:"gte=0"`
-	SKU       string  `json:"sku" validate:"required,min=3,max=50"`
-	Categoria string  `json:"categoria" validate:"max=100"`
-	Ativo     *bool   `json:"ativo"` // pointer para permitir false explícito
+	Nome      string      `json:"nome" validate:"required,min=3,max=200"`
+	Descricao string      `json:"descricao" validate:"max=1000"`
+	Preco     money.Money `json:"preco" validate:"required,gt=0" swaggertype:"string" example:"2999.99"`
+	Estoque   int         `json:"estoque" validate:"gte=0"`
+	SKU       string      `json:"sku" validate:"required,min=3,max=50"`
+	Categoria string      `json:"categoria" validate:"max=100"`
+	Ativo     *bool       `json:"ativo"` // pointer para permitir false explícito
+	// Peso e dimensões da embalagem, usados no cálculo do frete
+	PesoGramas    int `json:"peso_gramas" validate:"gte=0" example:"2500"`
+	AlturaCm      int `json:"altura_cm" validate:"gte=0" example:"10"`
+	LarguraCm     int `json:"largura_cm" validate:"gte=0" example:"40"`
+	ComprimentoCm int `json:"comprimento_cm" validate:"gte=0" example:"50"`
 }
 
-// UpdateProdutoRequest representa a requisição para atualizar um produto
+// UpdateProdutoRequest representa a substituição completa de um produto (PUT).
+// Descrição e categoria omitidas são limpas; estoque e ativo são obrigatórios
+// para que um campo esquecido não zere o estoque ou desative o produto.
+// Para alterar apenas alguns campos use PATCH.
 type UpdateProdutoRequest struct {
-	Nome      string  `json:"nome" validate:"omitempty,min=3,max=200"`
-	Descricao string  `json:"descricao" validate:"max=1000"`
-	Preco     float64 `json:"preco" validate:"omitempty,gt=0"`
-	Estoque   int     `json:"estoque" validate:"gte=0"`
-	SKU       string  `json:"sku" validate:"omitempty,min=3,max=50"`
-	Categoria string  `json:"categoria" validate:"max=100"`
-	Ativo     *bool   `json:"ativo"`
+	Nome      string      `json:"nome" validate:"required,min=3,max=200"`
+	Descricao string      `json:"descricao,omitempty" validate:"max=1000"`
+	Preco     money.Money `json:"preco" validate:"required,gt=0" swaggertype:"string" example:"2999.99"`
+	Estoque   *int        `json:"estoque" validate:"required,gte=0"`
+	SKU       string      `json:"sku" validate:"required,min=3,max=50"`
+	Categoria string      `json:"categoria,omitempty" validate:"max=100"`
+	Ativo     *bool       `json:"ativo" validate:"required"`
+	// Peso e dimensões omitidos são zerados, como os demais campos opcionais
+	PesoGramas    int `json:"peso_gramas,omitempty" validate:"gte=0" example:"2500"`
+	AlturaCm      int `json:"altura_cm,omitempty" validate:"gte=0" example:"10"`
+	LarguraCm     int `json:"largura_cm,omitempty" validate:"gte=0" example:"40"`
+	ComprimentoCm int `json:"comprimento_cm,omitempty" validate:"gte=0" example:"50"`
 }
 
 // ProdutoResponse representa a resposta de um produto
 type ProdutoResponse struct {
-	ID        uint      `json:"id"`
-	Nome      string    `json:"nome"`
-	Descricao string    `json:"descricao"`
-	Preco     float64   `json:"preco"`
-	Estoque   int       `json:"estoque"`
-	SKU       string    `json:"sku"`
-	Categoria string    `json:"categoria"`
-	Ativo     bool      `json:"ativo"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint        `json:"id"`
+	Nome      string      `json:"nome"`
+	Descricao string      `json:"descricao"`
+	Preco     money.Money `json:"preco" swaggertype:"string" example:"2999.99"`
+	Estoque   int         `json:"estoque"`
+	SKU       string      `json:"sku"`
+	Categoria string      `json:"categoria"`
+	Ativo     bool        `json:"ativo"`
+	// Peso e dimensões da embalagem, usados no cálculo do frete
+	PesoGramas    int        `json:"peso_gramas"`
+	AlturaCm      int        `json:"altura_cm"`
+	LarguraCm     int        `json:"largura_cm"`
+	ComprimentoCm int        `json:"comprimento_cm"`
+	Versao        uint       `json:"versao"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
 }
+
+// ProdutoPageResponse representa uma página de produtos
+type ProdutoPageResponse = PageResponse[ProdutoResponse]
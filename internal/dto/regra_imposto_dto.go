@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// CreateRegraImpostoRequest representa a requisição para criar uma regra de
+// imposto. Categoria e UF vazias fazem a regra valer para todas as categorias
+// ou UFs de destino. A alíquota é um percentual com até duas casas decimais.
+type CreateRegraImpostoRequest struct {
+	Imposto   string         `json:"imposto" validate:"required,max=20,alphanum" example:"ICMS"`
+	Descricao string         `json:"descricao" validate:"max=200" example:"ICMS interno de SP"`
+	Categoria string         `json:"categoria" validate:"max=100" example:"Eletrônicos"`
+	UF        string         `json:"uf" validate:"omitempty,uf" example:"SP"`
+	Aliquota  money.Aliquota `json:"aliquota" validate:"aliquota" swaggertype:"string" example:"18.00"`
+	Modo      string         `json:"modo" validate:"required,oneof=inclusivo exclusivo" example:"inclusivo"`
+	Ativo     *bool          `json:"ativo"` // pointer para permitir false explícito
+}
+
+// UpdateRegraImpostoRequest representa a substituição completa de uma regra de imposto (PUT)
+type UpdateRegraImpostoRequest struct {
+	Imposto   string         `json:"imposto" validate:"required,max=20,alphanum" example:"ICMS"`
+	Descricao string         `json:"descricao" validate:"max=200" example:"ICMS interno de SP"`
+	Categoria string         `json:"categoria" validate:"max=100" example:"Eletrônicos"`
+	UF        string         `json:"uf" validate:"omitempty,uf" example:"SP"`
+	Aliquota  money.Aliquota `json:"aliquota" validate:"aliquota" swaggertype:"string" example:"18.00"`
+	Modo      string         `json:"modo" validate:"required,oneof=inclusivo exclusivo" example:"inclusivo"`
+	Ativo     *bool          `json:"ativo" validate:"required"`
+}
+
+// RegraImpostoResponse representa a resposta de uma regra de imposto
+type RegraImpostoResponse struct {
+	ID        uint           `json:"id"`
+	Imposto   string         `json:"imposto"`
+	Descricao string         `json:"descricao"`
+	Categoria string         `json:"categoria"`
+	UF        string         `json:"uf"`
+	Aliquota  money.Aliquota `json:"aliquota" swaggertype:"string" example:"18.00"`
+	Modo      string         `json:"modo"`
+	Ativo     bool           `json:"ativo"`
+	Versao    uint           `json:"versao"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// RegraImpostoPageResponse representa uma página de regras de imposto
+type RegraImpostoPageResponse = PageResponse[RegraImpostoResponse]
@@ -0,0 +1,25 @@
+package dto
+
+// SearchRequest representa os parâmetros de GET /search
+type SearchRequest struct {
+	Q     string
+	Tipos []string
+	Limit int
+}
+
+// SearchResult is a search hit. Titulo and Trecho are HTML-escaped and the
+// matched terms are wrapped in <mark></mark>
+type SearchResult struct {
+	Tipo   string  `json:"tipo" example:"produtos"`
+	ID     uint    `json:"id" example:"1"`
+	Titulo string  `json:"titulo" example:"<mark>Cafe</mark>teira Elétrica"`
+	Trecho string  `json:"trecho" example:"…prepara até 30 <mark>cafe</mark>zinhos…"`
+	Score  float64 `json:"score" example:"4.21"`
+	Href   string  `json:"href" example:"/api/v1/produtos/1"`
+}
+
+// SearchResponse represents the ranked results of a search, most relevant first
+type SearchResponse struct {
+	Query string         `json:"query"`
+	Data  []SearchResult `json:"data"`
+}
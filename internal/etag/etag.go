@@ -0,0 +1,68 @@
+package etag
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+)
+
+// Format retorna a ETag forte correspondente à versão de um registro, por exemplo "3"
+func Format(versao uint) string {
+	return `"` + strconv.FormatUint(uint64(versao), 10) + `"`
+}
+
+// Matches indica se o cabeçalho If-Match contém a ETag da versão, pela
+// comparação forte: uma ETag fraca (W/) nunca corresponde. O cabeçalho pode
+// listar várias ETags separadas por vírgula e "*" corresponde a qualquer versão.
+func Matches(header string, versao uint) bool {
+	return contem(header, versao, false)
+}
+
+// WeakMatches indica se o cabeçalho If-None-Match contém a ETag da versão,
+// pela comparação fraca, que ignora o prefixo W/
+func WeakMatches(header string, versao uint) bool {
+	return contem(header, versao, true)
+}
+
+func contem(header string, versao uint, fraca bool) bool {
+	esperada := Format(versao)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if fraca {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == "*" || tag == esperada {
+			return true
+		}
+	}
+	return false
+}
+
+type ifMatchKey struct{}
+
+// WithIfMatch retorna um contexto carregando o cabeçalho If-Match da requisição.
+// Um cabeçalho vazio não cria pré-condição.
+func WithIfMatch(ctx context.Context, header string) context.Context {
+	if strings.TrimSpace(header) == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ifMatchKey{}, header)
+}
+
+// HasIfMatch indica se o contexto carrega uma pré-condição If-Match
+func HasIfMatch(ctx context.Context) bool {
+	_, ok := ctx.Value(ifMatchKey{}).(string)
+	return ok
+}
+
+// Check verifica a pré-condição If-Match do contexto contra a versão atual do
+// registro. Sem If-Match no contexto a operação é sempre permitida.
+func Check(ctx context.Context, versao uint) error {
+	header, ok := ctx.Value(ifMatchKey{}).(string)
+	if !ok || Matches(header, versao) {
+		return nil
+	}
+	return apperror.PreconditionFailed("o registro foi alterado: a versão atual é " + Format(versao))
+}
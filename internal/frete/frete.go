@@ -0,0 +1,61 @@
+// Package frete cota o frete de um pacote para um CEP. O cálculo fica atrás
+// da interface Calculator, de modo que a tabela própria (Tabela) possa ser
+// trocada por uma integração com transportadoras sem mudar os pedidos.
+package frete
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// divisorCubagem converte o volume em cm³ em gramas de peso cubado (o fator
+// 6000 cm³/kg usado pelas transportadoras)
+const divisorCubagem = 6
+
+// Volume é um item a ser enviado, com o peso e as dimensões de uma unidade
+type Volume struct {
+	PesoGramas    int
+	AlturaCm      int
+	LarguraCm     int
+	ComprimentoCm int
+	Quantidade    int
+}
+
+// PesoTaxavel é o maior entre o peso real e o peso cubado do volume, já
+// multiplicado pela quantidade
+func (v Volume) PesoTaxavel() int {
+	cubado := v.AlturaCm * v.LarguraCm * v.ComprimentoCm / divisorCubagem
+	return max(v.PesoGramas, cubado) * v.Quantidade
+}
+
+// Pacote é o que se quer enviar e para onde
+type Pacote struct {
+	// CEP de destino com 8 dígitos
+	CEP     string
+	Volumes []Volume
+}
+
+// PesoTaxavel soma o peso taxável dos volumes do pacote
+func (p Pacote) PesoTaxavel() int {
+	var peso int
+	for _, v := range p.Volumes {
+		peso += v.PesoTaxavel()
+	}
+	return peso
+}
+
+// Opcao é um serviço de entrega disponível para o pacote
+type Opcao struct {
+	Servico   string
+	Valor     money.Money
+	PrazoDias int
+	// ZonaID identifica a linha da tabela de frete que originou a opção
+	ZonaID uint
+}
+
+// Calculator cota o frete de um pacote. As opções vêm da mais barata para a
+// mais cara; nenhuma opção significa que o CEP ou o peso não são atendidos.
+type Calculator interface {
+	Cotar(ctx context.Context, pacote Pacote) ([]Opcao, error)
+}
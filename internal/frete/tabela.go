@@ -0,0 +1,52 @@
+package frete
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/danmaciel/api/internal/repository"
+)
+
+type tabela struct {
+	repo repository.FreteRepository
+}
+
+// NewTabela cria o Calculator baseado nas zonas de frete cadastradas no banco:
+// cada zona ativa que atende o CEP e comporta o peso vira uma opção, pelo
+// preço da menor faixa de peso que comporta o pacote
+func NewTabela(repo repository.FreteRepository) Calculator {
+	return &tabela{repo: repo}
+}
+
+func (t *tabela) Cotar(ctx context.Context, pacote Pacote) ([]Opcao, error) {
+	zonas, err := t.repo.FindZonasAtivas(ctx, pacote.CEP)
+	if err != nil {
+		return nil, err
+	}
+
+	peso := pacote.PesoTaxavel()
+	opcoes := []Opcao{}
+	for i := range zonas {
+		zona := &zonas[i]
+		faixa := zona.Faixa(peso)
+		if faixa == nil {
+			continue
+		}
+
+		opcao := Opcao{Servico: zona.Servico, Valor: faixa.Valor, PrazoDias: zona.PrazoDias, ZonaID: zona.ID}
+		// zonas sobrepostas do mesmo serviço: vale a mais barata
+		if j := slices.IndexFunc(opcoes, func(o Opcao) bool { return o.Servico == opcao.Servico }); j >= 0 {
+			if opcao.Valor < opcoes[j].Valor {
+				opcoes[j] = opcao
+			}
+			continue
+		}
+		opcoes = append(opcoes, opcao)
+	}
+
+	slices.SortFunc(opcoes, func(a, b Opcao) int {
+		return cmp.Or(cmp.Compare(a.Valor, b.Valor), cmp.Compare(a.PrazoDias, b.PrazoDias), cmp.Compare(a.Servico, b.Servico))
+	})
+	return opcoes, nil
+}
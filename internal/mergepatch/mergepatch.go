@@ -0,0 +1,69 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ContentType é o media type dos documentos JSON Merge Patch (RFC 7396)
+const ContentType = "application/merge-patch+json"
+
+// Merge aplica o merge patch ao documento JSON e retorna o documento
+// resultante. Campos do patch com null são removidos do documento, objetos
+// são mesclados recursivamente e qualquer outro valor substitui o original.
+func Merge(doc, patch []byte) ([]byte, error) {
+	var alvo interface{}
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if err := decode(doc, &alvo); err != nil {
+			return nil, err
+		}
+	}
+
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(alvo, p))
+}
+
+// Apply aplica o merge patch à representação JSON de current e decodifica
+// o resultado em dst
+func Apply(current interface{}, patch []byte, dst interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	merged, err := Merge(doc, patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, dst)
+}
+
+func merge(alvo, patch interface{}) interface{} {
+	campos, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	obj, ok := alvo.(map[string]interface{})
+	if !ok {
+		obj = map[string]interface{}{}
+	}
+	for nome, valor := range campos {
+		if valor == nil {
+			delete(obj, nome)
+			continue
+		}
+		obj[nome] = merge(obj[nome], valor)
+	}
+	return obj
+}
+
+// decode preserva os números como json.Number para não perder precisão
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/problem"
+)
+
+// TokenVerifier valida um token e retorna o principal que ele representa
+type TokenVerifier interface {
+	Verify(token string) (*auth.Principal, error)
+}
+
+// APIKeyVerifier valida uma API key e retorna o principal que ela representa
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// APIKeyHeader é o cabeçalho usado por integrações para enviar a API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKey autentica requisições que trazem o cabeçalho X-API-Key. Uma chave
+// inválida ou revogada recebe 401; sem o cabeçalho a requisição segue para
+// o próximo autenticador (por exemplo Authenticate).
+func APIKey(verifier APIKeyVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := verifier.VerifyAPIKey(r.Context(), key)
+			if errors.Is(err, auth.ErrAPIKeyInvalida) {
+				unauthorized(w, r, "API key inválida ou revogada")
+				return
+			}
+			if err != nil {
+				log.Printf("falha ao validar API key: %v", err)
+				problem.Write(w, r, problem.New(http.StatusInternalServerError, ""))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// Authenticate exige um token Bearer válido e coloca o principal no contexto
+// da requisição. Requisições sem token ou com token inválido recebem 401.
+// Requisições já autenticadas por um middleware anterior (APIKey) passam direto.
+func Authenticate(verifier TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.FromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, r, "token de acesso ausente")
+				return
+			}
+
+			principal, err := verifier.Verify(token)
+			if err != nil {
+				detail := "token de acesso inválido"
+				if errors.Is(err, auth.ErrTokenExpirado) {
+					detail = "token de acesso expirado"
+				}
+				unauthorized(w, r, detail)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// Anonymous dispensa a autenticação e trata toda requisição como um principal
+// anônimo com papel admin. Serve apenas para testes e desenvolvimento local.
+func Anonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := &auth.Principal{Subject: auth.ActorAnonimo, Roles: []string{auth.RoleAdmin}}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// RequireRole responde 403 quando o principal autenticado não possui o papel
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := auth.RequireRole(r.Context(), role); err != nil {
+				problem.Write(w, r, problem.New(http.StatusForbidden, err.Error()))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authorize responde 403 quando o principal não pode executar no recurso uma
+// operação que exige o papel: usuários precisam do papel e API keys de um
+// escopo equivalente (por exemplo "pedidos:write" para operador em pedidos)
+func Authorize(recurso, role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := auth.Authorize(r.Context(), recurso, role); err != nil {
+				problem.Write(w, r, problem.New(http.StatusForbidden, err.Error()))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken extrai o token do cabeçalho "Authorization: Bearer <token>"
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	problem.Write(w, r, problem.New(http.StatusUnauthorized, detail))
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/problem"
+)
+
+const (
+	// IdempotencyKeyHeader é o cabeçalho com a chave de idempotência enviada pelo cliente
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marca respostas reproduzidas a partir de uma chave já usada
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyLease é por quanto tempo a reserva de uma requisição em
+	// andamento bloqueia as repetições. Passado esse prazo, bem maior que o
+	// WriteTimeout do servidor, a requisição original é dada como morta e
+	// uma repetição assume a chave.
+	idempotencyLease = time.Minute
+)
+
+// replayedHeaders são os cabeçalhos da resposta original reproduzidos nas repetições
+var replayedHeaders = []string{"Location", "ETag"}
+
+// IdempotencyStore persiste as chaves de idempotência e as respostas originais
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, key *model.IdempotencyKey) error
+	Release(ctx context.Context, id uint) error
+}
+
+// Idempotency honra o cabeçalho Idempotency-Key nos POSTs. A primeira resposta
+// é guardada com o hash do corpo da requisição e reproduzida nas repetições
+// dentro de ttl. Reutilizar a chave com outro corpo ou outra rota retorna 422
+// e repetir enquanto a requisição original está em andamento retorna 409, até
+// que a reserva dela vença (idempotencyLease).
+//
+// Respostas 5xx, 401 e 403 não são guardadas: a reserva é liberada e o cliente
+// pode repetir a requisição com a mesma chave.
+func Idempotency(store IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chave := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || chave == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(chave) > maxIdempotencyKeyLength {
+				problem.Write(w, r, problem.New(http.StatusBadRequest, "Idempotency-Key deve ter no máximo 255 caracteres"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				problem.Write(w, r, problem.New(http.StatusBadRequest, "falha ao ler o corpo da requisição"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := sha256.Sum256(body)
+
+			reserva := &model.IdempotencyKey{
+				Subject:      auth.Actor(r.Context()),
+				Chave:        chave,
+				Metodo:       r.Method,
+				Rota:         r.URL.Path,
+				RequestHash:  hex.EncodeToString(hash[:]),
+				ReservadaAte: time.Now().Add(idempotencyLease),
+				ExpiresAt:    time.Now().Add(ttl),
+			}
+
+			existente, err := store.Reserve(r.Context(), reserva)
+			if err != nil {
+				log.Printf("falha ao reservar a Idempotency-Key: %v", err)
+				problem.Write(w, r, problem.New(http.StatusInternalServerError, ""))
+				return
+			}
+			if existente != nil {
+				replay(w, r, reserva, existente)
+				return
+			}
+
+			capture := &captureWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			concluida := false
+			defer func() {
+				// em caso de panic a reserva é liberada antes de o Recovery responder
+				if !concluida {
+					release(r.Context(), store, reserva.ID)
+				}
+			}()
+
+			next.ServeHTTP(capture, r)
+			concluida = true
+
+			if !armazenavel(capture.statusCode) {
+				release(r.Context(), store, reserva.ID)
+				return
+			}
+
+			reserva.StatusCode = capture.statusCode
+			reserva.ContentType = capture.Header().Get("Content-Type")
+			reserva.Cabecalhos = encodeHeaders(capture.Header())
+			reserva.Resposta = capture.body.Bytes()
+			if err := store.Complete(r.Context(), reserva); err != nil {
+				log.Printf("falha ao gravar a resposta da Idempotency-Key: %v", err)
+			}
+		})
+	}
+}
+
+// replay responde a uma repetição a partir do registro existente da chave
+func replay(w http.ResponseWriter, r *http.Request, atual, existente *model.IdempotencyKey) {
+	if existente.RequestHash != atual.RequestHash || existente.Metodo != atual.Metodo || existente.Rota != atual.Rota {
+		problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, "Idempotency-Key já utilizada com outra requisição"))
+		return
+	}
+	if existente.EmAndamento() {
+		problem.Write(w, r, problem.New(http.StatusConflict, "requisição com a mesma Idempotency-Key ainda em andamento"))
+		return
+	}
+
+	if existente.ContentType != "" {
+		w.Header().Set("Content-Type", existente.ContentType)
+	}
+	for nome, valor := range decodeHeaders(existente.Cabecalhos) {
+		w.Header().Set(nome, valor)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(existente.StatusCode)
+	w.Write(existente.Resposta)
+}
+
+// encodeHeaders serializa os cabeçalhos reproduzíveis presentes na resposta
+func encodeHeaders(h http.Header) string {
+	cabecalhos := make(map[string]string)
+	for _, nome := range replayedHeaders {
+		if valor := h.Get(nome); valor != "" {
+			cabecalhos[nome] = valor
+		}
+	}
+	if len(cabecalhos) == 0 {
+		return ""
+	}
+	texto, _ := json.Marshal(cabecalhos)
+	return string(texto)
+}
+
+// decodeHeaders lê os cabeçalhos gravados por encodeHeaders
+func decodeHeaders(texto string) map[string]string {
+	var cabecalhos map[string]string
+	if texto != "" {
+		if err := json.Unmarshal([]byte(texto), &cabecalhos); err != nil {
+			log.Printf("cabeçalhos inválidos na Idempotency-Key: %v", err)
+		}
+	}
+	return cabecalhos
+}
+
+// armazenavel indica se a resposta deve ser guardada para as repetições
+func armazenavel(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusUnauthorized &&
+		status != http.StatusForbidden
+}
+
+func release(ctx context.Context, store IdempotencyStore, id uint) {
+	if err := store.Release(ctx, id); err != nil {
+		log.Printf("falha ao liberar a Idempotency-Key: %v", err)
+	}
+}
+
+// captureWriter repassa a resposta ao cliente e guarda uma cópia do status e do corpo
+type captureWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.statusCode = code
+		cw.wroteHeader = true
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	cw.wroteHeader = true
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
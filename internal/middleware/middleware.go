@@ -4,6 +4,8 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/danmaciel/api/internal/problem"
 )
 
 // Logger middleware logs HTTP requests
@@ -48,7 +50,7 @@ func Recovery(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("PANIC: %v", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				problem.Write(w, r, problem.New(http.StatusInternalServerError, ""))
 			}
 		}()
 		next.ServeHTTP(w, r)
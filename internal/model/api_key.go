@@ -0,0 +1,36 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey é uma credencial de longa duração usada por integrações (ERP,
+// marketplaces). Apenas o hash SHA-256 da chave é armazenado; o segredo é
+// exibido uma única vez na criação.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Nome       string     `gorm:"type:varchar(100);not null" json:"nome"`
+	Prefixo    string     `gorm:"type:varchar(20);not null" json:"prefixo"`
+	Hash       string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"type:varchar(500);not null" json:"scopes"`
+	CriadoPor  string     `gorm:"type:varchar(100);not null" json:"criado_por"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList retorna os escopos da chave, armazenados separados por espaço
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// Revogada indica se a chave foi revogada
+func (k *APIKey) Revogada() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Ações registradas no audit log
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// Entidades registradas no audit log
+const (
+	AuditCliente = "cliente"
+	AuditProduto = "produto"
+	AuditPedido  = "pedido"
+)
+
+// AuditLog é uma entrada do registro de auditoria: uma operação de escrita
+// sobre um cliente, produto ou pedido, com quem a executou, a requisição de
+// origem e a diferença entre o registro antes e depois dela. As entradas só
+// são inseridas, nunca alteradas. Cada uma guarda o hash da anterior
+// (HashAnterior) e o próprio Hash, calculado sobre todos os campos, então
+// alterar ou remover uma entrada quebra a cadeia a partir dela.
+type AuditLog struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Entidade   string `gorm:"type:varchar(30);not null;index:idx_audit_log_registro" json:"entidade"`
+	EntidadeID uint   `gorm:"not null;index:idx_audit_log_registro" json:"entidade_id"`
+	Acao       string `gorm:"type:varchar(20);not null" json:"acao"`
+	Ator       string `gorm:"type:varchar(100);not null" json:"ator"`
+	RequestID  string `gorm:"type:varchar(100);not null;default:''" json:"request_id"`
+	// Diff é um objeto JSON com os campos alterados, cada um no formato
+	// {"antes": ..., "depois": ...}
+	Diff string `gorm:"type:text;not null" json:"diff"`
+	// HashAnterior é único: duas entradas nunca continuam a mesma cadeia
+	HashAnterior string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"hash_anterior"`
+	Hash         string    `gorm:"type:varchar(64);not null" json:"hash"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// AuditCadeia é a ponta da cadeia do audit log: uma única linha com o hash da
+// última entrada. Cada gravação atualiza essa linha antes de ler o hash, o que
+// serializa as gravações concorrentes na cadeia.
+type AuditCadeia struct {
+	ID        uint   `gorm:"primaryKey"`
+	Hash      string `gorm:"type:varchar(64);not null"`
+	UpdatedAt time.Time
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (AuditCadeia) TableName() string {
+	return "audit_log_cadeia"
+}
+
+// CalcularHash retorna o SHA-256 (hex) dos campos da entrada e do hash da
+// anterior. O ID fica de fora por ser atribuído pelo banco só na inserção.
+func (a *AuditLog) CalcularHash() string {
+	conteudo, _ := json.Marshal([]interface{}{
+		a.HashAnterior,
+		a.Entidade,
+		a.EntidadeID,
+		a.Acao,
+		a.Ator,
+		a.RequestID,
+		a.Diff,
+		a.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	soma := sha256.Sum256(conteudo)
+	return hex.EncodeToString(soma[:])
+}
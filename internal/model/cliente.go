@@ -1,24 +1,62 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// Cliente represents a customer entity
+// Tipos de Cliente
+const (
+	ClientePessoaFisica   = "PF"
+	ClientePessoaJuridica = "PJ"
+)
+
+// Cliente represents a customer entity. Pessoas físicas (PF) are identified
+// by Nome and CPF; pessoas jurídicas (PJ) by RazaoSocial and CNPJ, with an
+// optional NomeFantasia and InscricaoEstadual. The fields of the other type
+// stay empty. Documents are stored as digits only and, like the email, are
+// unique among the filled ones of clientes that were not soft-deleted.
 type Cliente struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Nome      string         `gorm:"type:varchar(100);not null" json:"nome" validate:"required,min=3,max=100"`
-	Email     string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email" validate:"required,email"`
-	CPF       string         `gorm:"type:varchar(11);uniqueIndex;not null" json:"cpf" validate:"required,len=11,numeric"`
-	Telefone  string         `gorm:"type:varchar(15)" json:"telefone" validate:"omitempty,min=10,max=15"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primarykey" json:"id"`
+	Tipo              string         `gorm:"type:varchar(2);not null;default:'PF'" json:"tipo" validate:"required,oneof=PF PJ"`
+	Nome              string         `gorm:"type:varchar(100);not null" json:"nome" validate:"required_if=Tipo PF,omitempty,min=3,max=100"`
+	RazaoSocial       string         `gorm:"type:varchar(150);not null;default:''" json:"razao_social" validate:"required_if=Tipo PJ,omitempty,min=3,max=150"`
+	NomeFantasia      string         `gorm:"type:varchar(100);not null;default:''" json:"nome_fantasia" validate:"max=100"`
+	Email             string         `gorm:"type:varchar(100);uniqueIndex:idx_clientes_email,where:deleted_at IS NULL;not null" json:"email" validate:"required,email"`
+	CPF               string         `gorm:"type:varchar(11);uniqueIndex:idx_clientes_cpf,where:cpf <> '' AND deleted_at IS NULL;not null" json:"cpf" validate:"required_if=Tipo PF,omitempty,cpf"`
+	CNPJ              string         `gorm:"type:varchar(14);uniqueIndex:idx_clientes_cnpj,where:cnpj <> '' AND deleted_at IS NULL;not null;default:''" json:"cnpj" validate:"required_if=Tipo PJ,omitempty,cnpj"`
+	InscricaoEstadual string         `gorm:"type:varchar(14);not null;default:''" json:"inscricao_estadual" validate:"omitempty,ie"`
+	Telefone          string         `gorm:"type:varchar(15)" json:"telefone" validate:"omitempty,min=10,max=15"`
+	Versao            uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName specifies the table name for Cliente
 func (Cliente) TableName() string {
 	return "clientes"
 }
+
+// ClienteAnonimizado é o nome que substitui o do cliente anonimizado
+const ClienteAnonimizado = "Cliente anonimizado"
+
+// Anonimizar apaga os dados pessoais do cliente mantendo o registro, que
+// continua referenciado pelos seus pedidos. O email recebe um endereço
+// fictício derivado do ID, para não colidir com o índice único.
+func (c *Cliente) Anonimizar() {
+	c.Nome, c.RazaoSocial = "", ""
+	if c.Tipo == ClientePessoaJuridica {
+		c.RazaoSocial = ClienteAnonimizado
+	} else {
+		c.Nome = ClienteAnonimizado
+	}
+	c.NomeFantasia = ""
+	c.Email = fmt.Sprintf("anonimizado-%d@anonimizado.invalid", c.ID)
+	c.CPF = ""
+	c.CNPJ = ""
+	c.InscricaoEstadual = ""
+	c.Telefone = ""
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Papéis de um Contato na empresa cliente
+const (
+	ContatoCompras    = "compras"
+	ContatoFinanceiro = "financeiro"
+	ContatoFiscal     = "fiscal"
+	ContatoLogistica  = "logistica"
+	ContatoOutro      = "outro"
+)
+
+// Contato é uma pessoa de contato de um cliente PJ. Uma empresa pode ter
+// vários contatos, cada um com o papel que exerce (quem compra, quem paga,
+// quem recebe as notas ou as entregas).
+type Contato struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ClienteID uint           `gorm:"not null;index" json:"cliente_id"`
+	Cliente   Cliente        `gorm:"foreignKey:ClienteID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Nome      string         `gorm:"type:varchar(100);not null" json:"nome"`
+	Papel     string         `gorm:"type:varchar(20);not null;index" json:"papel"`
+	Cargo     string         `gorm:"type:varchar(100)" json:"cargo"`
+	Email     string         `gorm:"type:varchar(100)" json:"email"`
+	Telefone  string         `gorm:"type:varchar(15)" json:"telefone"`
+	Versao    uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (Contato) TableName() string {
+	return "contatos"
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+	"gorm.io/gorm"
+)
+
+// Tipos de desconto de um Cupom
+const (
+	CupomPercentual  = "percentual"
+	CupomValorFixo   = "valor_fixo"
+	CupomFreteGratis = "frete_gratis"
+)
+
+// Cupom representa um cupom de desconto aplicável na criação de pedidos.
+// Restrições vazias (categorias, produtos) e limites zerados não restringem.
+type Cupom struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Codigo é único entre os cupons não excluídos
+	Codigo string `gorm:"type:varchar(50);uniqueIndex:idx_cupons_codigo,where:deleted_at IS NULL;not null" json:"codigo"`
+	// Descricao aparece nas linhas de desconto do pedido
+	Descricao string `gorm:"type:varchar(200)" json:"descricao"`
+	Tipo      string `gorm:"type:varchar(20);not null" json:"tipo"`
+	// Percentual do desconto, de 1 a 100, para cupons percentuais
+	Percentual int `gorm:"not null;default:0" json:"percentual"`
+	// Valor do desconto para cupons de valor fixo
+	Valor money.Money `gorm:"type:integer;not null;default:0" json:"valor"`
+	// ValorMinimo é o subtotal mínimo do pedido para o cupom valer
+	ValorMinimo money.Money `gorm:"type:integer;not null;default:0" json:"valor_minimo"`
+	// Categorias e ProdutoIDs restringem os itens sobre os quais o desconto incide
+	Categorias []string `gorm:"type:text;serializer:json" json:"categorias"`
+	ProdutoIDs []uint   `gorm:"type:text;serializer:json" json:"produto_ids"`
+	// LimiteUso é o total de pedidos que podem usar o cupom; Usos é o contador
+	LimiteUso        int            `gorm:"not null;default:0" json:"limite_uso"`
+	LimitePorCliente int            `gorm:"not null;default:0" json:"limite_por_cliente"`
+	Usos             int            `gorm:"not null;default:0" json:"usos"`
+	ValidoDe         *time.Time     `json:"valido_de"`
+	ValidoAte        *time.Time     `json:"valido_ate"`
+	Ativo            bool           `gorm:"not null" json:"ativo"`
+	Versao           uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (Cupom) TableName() string {
+	return "cupons"
+}
+
+// Vigente indica se o cupom está dentro da janela de validade no instante informado
+func (c *Cupom) Vigente(agora time.Time) bool {
+	if c.ValidoDe != nil && agora.Before(*c.ValidoDe) {
+		return false
+	}
+	if c.ValidoAte != nil && agora.After(*c.ValidoAte) {
+		return false
+	}
+	return true
+}
+
+// Aplicavel indica se o desconto do cupom incide sobre o produto
+func (c *Cupom) Aplicavel(produtoID uint, categoria string) bool {
+	if len(c.Categorias) == 0 && len(c.ProdutoIDs) == 0 {
+		return true
+	}
+	return slices.Contains(c.ProdutoIDs, produtoID) || slices.Contains(c.Categorias, categoria)
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Endereco é um endereço de entrega de um cliente. Cada cliente pode ter
+// vários, e apenas um é o padrão usado quando o pedido não escolhe outro.
+type Endereco struct {
+	ID        uint    `gorm:"primaryKey" json:"id"`
+	ClienteID uint    `gorm:"not null;index" json:"cliente_id"`
+	Cliente   Cliente `gorm:"foreignKey:ClienteID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	// Apelido identifica o endereço para o cliente, por exemplo "Casa"
+	Apelido string `gorm:"type:varchar(50)" json:"apelido"`
+	// CEP é gravado apenas com os 8 dígitos
+	CEP         string         `gorm:"type:varchar(8);not null" json:"cep"`
+	Logradouro  string         `gorm:"type:varchar(200);not null" json:"logradouro"`
+	Numero      string         `gorm:"type:varchar(20);not null" json:"numero"`
+	Complemento string         `gorm:"type:varchar(100)" json:"complemento"`
+	Bairro      string         `gorm:"type:varchar(100);not null" json:"bairro"`
+	Cidade      string         `gorm:"type:varchar(100);not null" json:"cidade"`
+	UF          string         `gorm:"type:varchar(2);not null" json:"uf"`
+	Padrao      bool           `gorm:"not null" json:"padrao"`
+	Versao      uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (Endereco) TableName() string {
+	return "enderecos"
+}
+
+// Entrega retorna a cópia do endereço gravada no pedido
+func (e *Endereco) Entrega() EnderecoEntrega {
+	return EnderecoEntrega{
+		CEP:         e.CEP,
+		Logradouro:  e.Logradouro,
+		Numero:      e.Numero,
+		Complemento: e.Complemento,
+		Bairro:      e.Bairro,
+		Cidade:      e.Cidade,
+		UF:          e.UF,
+	}
+}
+
+// EnderecoEntrega é o endereço copiado para o pedido no momento da compra.
+// Fica nas colunas entrega_* do pedido, de modo que editar ou excluir o
+// endereço do cliente depois não reescreve o histórico.
+type EnderecoEntrega struct {
+	CEP         string `gorm:"type:varchar(8)" json:"cep"`
+	Logradouro  string `gorm:"type:varchar(200)" json:"logradouro"`
+	Numero      string `gorm:"type:varchar(20)" json:"numero"`
+	Complemento string `gorm:"type:varchar(100)" json:"complemento"`
+	Bairro      string `gorm:"type:varchar(100)" json:"bairro"`
+	Cidade      string `gorm:"type:varchar(100)" json:"cidade"`
+	UF          string `gorm:"type:varchar(2)" json:"uf"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+	"gorm.io/gorm"
+)
+
+// FreteZona é uma linha da tabela de frete: um serviço de entrega para uma
+// faixa de CEPs, com o preço por faixa de peso. Zonas sobrepostas com serviços
+// diferentes viram opções diferentes na cotação.
+type FreteZona struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Nome string `gorm:"type:varchar(100);not null" json:"nome"`
+	// Servico é o nome exibido e escolhido no pedido, por exemplo "Expresso"
+	Servico string `gorm:"type:varchar(50);not null" json:"servico"`
+	// CEPInicio e CEPFim delimitam a faixa atendida, inclusive, com 8 dígitos
+	CEPInicio string `gorm:"type:varchar(8);not null;index" json:"cep_inicio"`
+	CEPFim    string `gorm:"type:varchar(8);not null" json:"cep_fim"`
+	PrazoDias int    `gorm:"not null;default:0" json:"prazo_dias"`
+	// Faixas de peso em ordem crescente; pacotes acima da última não são atendidos
+	Faixas    []FreteFaixa   `gorm:"foreignKey:ZonaID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"faixas"`
+	Ativo     bool           `gorm:"not null" json:"ativo"`
+	Versao    uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (FreteZona) TableName() string {
+	return "frete_zonas"
+}
+
+// Atende indica se o CEP, com 8 dígitos, está na faixa da zona
+func (z *FreteZona) Atende(cep string) bool {
+	return cep >= z.CEPInicio && cep <= z.CEPFim
+}
+
+// Faixa retorna a menor faixa que comporta o peso, ou nil se nenhuma comportar
+func (z *FreteZona) Faixa(pesoGramas int) *FreteFaixa {
+	var escolhida *FreteFaixa
+	for i := range z.Faixas {
+		faixa := &z.Faixas[i]
+		if pesoGramas <= faixa.PesoMaximoGramas && (escolhida == nil || faixa.PesoMaximoGramas < escolhida.PesoMaximoGramas) {
+			escolhida = faixa
+		}
+	}
+	return escolhida
+}
+
+// FreteFaixa é o preço do frete de uma zona para pacotes de até PesoMaximoGramas
+type FreteFaixa struct {
+	ID               uint        `gorm:"primaryKey" json:"id"`
+	ZonaID           uint        `gorm:"not null;index" json:"zona_id"`
+	PesoMaximoGramas int         `gorm:"not null" json:"peso_maximo_gramas"`
+	Valor            money.Money `gorm:"type:integer;not null" json:"valor"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (FreteFaixa) TableName() string {
+	return "frete_faixas"
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// IdempotencyKey guarda a primeira resposta de um POST enviado com o
+// cabeçalho Idempotency-Key, para que repetições da mesma requisição recebam
+// a mesma resposta em vez de executar a operação novamente. A chave é única
+// por principal (Subject).
+//
+// Enquanto a requisição original está em andamento, a reserva vale até
+// ReservadaAte; se ela morrer sem liberar a chave, uma repetição depois disso
+// assume a reserva em vez de esperar o fim de ExpiresAt.
+type IdempotencyKey struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Subject     string `gorm:"type:varchar(150);not null;uniqueIndex:idx_idempotency_keys_subject_chave" json:"subject"`
+	Chave       string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_subject_chave" json:"chave"`
+	Metodo      string `gorm:"type:varchar(10);not null" json:"metodo"`
+	Rota        string `gorm:"type:varchar(255);not null" json:"rota"`
+	RequestHash string `gorm:"type:varchar(64);not null" json:"request_hash"`
+	StatusCode  int    `gorm:"not null;default:0" json:"status_code"`
+	ContentType string `gorm:"type:varchar(100)" json:"content_type"`
+	// Cabecalhos guarda, em JSON, os cabeçalhos da resposta reproduzidos nas
+	// repetições (Location, ETag)
+	Cabecalhos   string    `gorm:"type:text" json:"-"`
+	Resposta     []byte    `gorm:"type:blob" json:"-"`
+	ReservadaAte time.Time `json:"reservada_ate"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// EmAndamento indica que a requisição original ainda não terminou
+func (k *IdempotencyKey) EmAndamento() bool {
+	return k.StatusCode == 0
+}
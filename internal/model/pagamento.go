@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// Métodos de pagamento aceitos
+const (
+	MetodoPix    = "pix"
+	MetodoBoleto = "boleto"
+	MetodoCartao = "cartao"
+)
+
+// Status possíveis de um Pagamento: pendente → confirmado → estornado, ou
+// pendente → falhou
+const (
+	PagamentoPendente   = "pendente"
+	PagamentoConfirmado = "confirmado"
+	PagamentoFalhou     = "falhou"
+	PagamentoEstornado  = "estornado"
+)
+
+// PrefixoReferenciaProvisoria marca a referência de um pagamento gravado
+// antes da resposta do provedor; a conciliação a troca pela referência dele
+const PrefixoReferenciaProvisoria = "pendente_"
+
+// Pagamento é uma cobrança feita a um provedor para quitar um Pedido. Um
+// pedido pode ter vários pagamentos (pagamento parcial) e cada pagamento pode
+// ser estornado em partes.
+type Pagamento struct {
+	ID       uint        `gorm:"primaryKey" json:"id"`
+	PedidoID uint        `gorm:"not null;index" json:"pedido_id"`
+	Pedido   Pedido      `gorm:"foreignKey:PedidoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Metodo   string      `gorm:"type:varchar(20);not null" json:"metodo"`
+	Valor    money.Money `gorm:"type:integer;not null" json:"valor"`
+	// Estornado soma os estornos pendentes e concluídos; o pagamento fica
+	// estornado quando ele chega ao Valor
+	Estornado money.Money `gorm:"type:integer;not null;default:0" json:"estornado"`
+	Status    string      `gorm:"type:varchar(20);not null;default:'pendente'" json:"status"`
+	// Provedor e Referencia identificam a cobrança no gateway; a referência
+	// é a chave usada pelas notificações (webhooks) do provedor
+	Provedor     string             `gorm:"type:varchar(50);not null" json:"provedor"`
+	Referencia   string             `gorm:"type:varchar(100);not null;uniqueIndex" json:"referencia"`
+	ConfirmadoEm *time.Time         `json:"confirmado_em"`
+	Estornos     []PagamentoEstorno `gorm:"foreignKey:PagamentoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"estornos,omitempty"`
+	Versao       uint               `gorm:"not null;default:1" json:"versao"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (Pagamento) TableName() string {
+	return "pagamentos"
+}
+
+// Liquido é o valor que o pagamento efetivamente quita do pedido: o valor
+// confirmado menos os estornos. Pagamentos pendentes ou que falharam não quitam nada.
+func (p *Pagamento) Liquido() money.Money {
+	if p.Status != PagamentoConfirmado && p.Status != PagamentoEstornado {
+		return 0
+	}
+	return p.Valor - p.Estornado
+}
+
+// Status possíveis de um PagamentoEstorno: pendente enquanto o provedor não
+// responde, depois concluido ou falhou
+const (
+	EstornoPendente  = "pendente"
+	EstornoConcluido = "concluido"
+	EstornoFalhou    = "falhou"
+)
+
+// PagamentoEstorno registra a devolução de parte ou de todo um Pagamento. O
+// estorno é gravado pendente antes da chamada ao provedor e conciliado com a
+// resposta dele; um estorno que falhou não conta no Estornado do pagamento.
+type PagamentoEstorno struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	PagamentoID uint        `gorm:"not null;index" json:"pagamento_id"`
+	Valor       money.Money `gorm:"type:integer;not null" json:"valor"`
+	Status      string      `gorm:"type:varchar(20);not null;default:'concluido'" json:"status"`
+	// Referencia identifica o estorno no provedor; fica vazia até a resposta dele
+	Referencia string    `gorm:"type:varchar(100);not null" json:"referencia"`
+	Motivo     string    `gorm:"type:varchar(200)" json:"motivo"`
+	Usuario    string    `gorm:"type:varchar(100);not null" json:"usuario"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PagamentoEstorno) TableName() string {
+	return "pagamento_estornos"
+}
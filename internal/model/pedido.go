@@ -3,18 +3,48 @@ package model
 import (
 	"time"
 
+	"github.com/danmaciel/api/internal/money"
 	"gorm.io/gorm"
 )
 
+// Status possíveis de um Pedido
+const (
+	StatusPendente  = "pendente"
+	StatusPago      = "pago"
+	StatusEnviado   = "enviado"
+	StatusEntregue  = "entregue"
+	StatusCancelado = "cancelado"
+)
+
 // Pedido representa a entidade de domínio Pedido
 type Pedido struct {
 	ID          uint            `gorm:"primaryKey" json:"id"`
 	ClienteID   uint            `gorm:"not null" json:"cliente_id" validate:"required"`
 	Cliente     Cliente         `gorm:"foreignKey:ClienteID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"cliente,omitempty"`
 	Itens       []PedidoProduto `gorm:"foreignKey:PedidoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"itens,omitempty"`
-	ValorTotal  float64         `gorm:"type:decimal(10,2);not null;default:0" json:"valor_total"`
+	Descontos   []PedidoDesconto `gorm:"foreignKey:PedidoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"descontos,omitempty"`
+	// Subtotal é a soma dos itens; ValorTotal é o Subtotal menos o Desconto,
+	// mais os impostos exclusivos e o Frete. Imposto soma todos os impostos dos itens.
+	Subtotal    money.Money     `gorm:"type:integer;not null;default:0" json:"subtotal"`
+	Desconto    money.Money     `gorm:"type:integer;not null;default:0" json:"desconto"`
+	Imposto     money.Money     `gorm:"type:integer;not null;default:0" json:"imposto"`
+	ValorTotal  money.Money     `gorm:"type:integer;not null;default:0" json:"valor_total"`
 	Status      string          `gorm:"type:varchar(20);not null;default:'pendente'" json:"status" validate:"required,oneof=pendente pago enviado entregue cancelado"`
 	DataPedido  time.Time       `gorm:"not null" json:"data_pedido"`
+	// UFDestino é a UF de entrega, usada para escolher as regras de imposto
+	UFDestino   string          `gorm:"type:varchar(2);not null;default:''" json:"uf_destino"`
+	// EnderecoID aponta o endereço de origem; o conteúdo fica copiado em Entrega
+	EnderecoID  *uint           `gorm:"index" json:"endereco_id"`
+	Entrega     EnderecoEntrega `gorm:"embedded;embeddedPrefix:entrega_" json:"entrega"`
+	// Frete é o valor do serviço de entrega escolhido, somado ao ValorTotal
+	Frete          money.Money `gorm:"type:integer;not null;default:0" json:"frete"`
+	FreteServico   string      `gorm:"type:varchar(50);not null;default:''" json:"frete_servico"`
+	FretePrazoDias int         `gorm:"not null;default:0" json:"frete_prazo_dias"`
+	// MotivoCancelamento é informado em POST /pedidos/{id}/cancelamento
+	MotivoCancelamento string `gorm:"type:varchar(200);not null;default:''" json:"motivo_cancelamento"`
+	// Devolucoes registram os itens devolvidos; os itens originais não mudam
+	Devolucoes  []PedidoDevolucao `gorm:"foreignKey:PedidoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"devolucoes,omitempty"`
+	Versao      uint            `gorm:"not null;default:1" json:"versao"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
@@ -28,8 +58,14 @@ type PedidoProduto struct {
 	ProdutoID     uint           `gorm:"not null" json:"produto_id"`
 	Produto       Produto        `gorm:"foreignKey:ProdutoID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"produto,omitempty"`
 	Quantidade    int            `gorm:"not null" json:"quantidade" validate:"required,gt=0"`
-	PrecoUnitario float64        `gorm:"type:decimal(10,2);not null" json:"preco_unitario" validate:"required,gt=0"`
-	Subtotal      float64        `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	PrecoUnitario money.Money    `gorm:"type:integer;not null" json:"preco_unitario" validate:"required,gt=0"`
+	Subtotal      money.Money    `gorm:"type:integer;not null" json:"subtotal"`
+	// Imposto soma os impostos do item, inclusivos e exclusivos
+	Imposto       money.Money    `gorm:"type:integer;not null;default:0" json:"imposto"`
+	// Desconto é a parte do desconto do pedido rateada para o item, usada
+	// para calcular o reembolso de devoluções
+	Desconto      money.Money    `gorm:"type:integer;not null;default:0" json:"desconto"`
+	Impostos      []PedidoItemImposto `gorm:"foreignKey:PedidoProdutoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"impostos,omitempty"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -47,6 +83,18 @@ func (PedidoProduto) TableName() string {
 
 // BeforeSave hook para calcular o subtotal antes de salvar
 func (pp *PedidoProduto) BeforeSave(tx *gorm.DB) error {
-	pp.Subtotal = float64(pp.Quantidade) * pp.PrecoUnitario
+	pp.Subtotal = pp.PrecoUnitario.Mul(pp.Quantidade)
 	return nil
 }
+
+// QuantidadesDevolvidas soma as quantidades já devolvidas de cada item do
+// pedido, pelo ID do item
+func (p *Pedido) QuantidadesDevolvidas() map[uint]int {
+	devolvidas := map[uint]int{}
+	for _, devolucao := range p.Devolucoes {
+		for _, item := range devolucao.Itens {
+			devolvidas[item.PedidoProdutoID] += item.Quantidade
+		}
+	}
+	return devolvidas
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// PedidoDesconto é uma linha de desconto aplicada ao pedido. Guarda o código,
+// a base de cálculo e o valor no momento da compra, para que o total continue
+// auditável mesmo que o cupom seja alterado ou excluído depois.
+type PedidoDesconto struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	PedidoID  uint        `gorm:"not null;index" json:"pedido_id"`
+	CupomID   *uint       `gorm:"index" json:"cupom_id"`
+	Codigo    string      `gorm:"type:varchar(50);not null" json:"codigo"`
+	Tipo      string      `gorm:"type:varchar(20);not null" json:"tipo"`
+	Descricao string      `gorm:"type:varchar(200)" json:"descricao"`
+	Base      money.Money `gorm:"type:integer;not null" json:"base"`
+	Valor     money.Money `gorm:"type:integer;not null" json:"valor"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PedidoDesconto) TableName() string {
+	return "pedido_descontos"
+}
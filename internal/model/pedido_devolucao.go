@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// PedidoDevolucao registra a devolução de parte dos itens de um Pedido. Os
+// itens originais do pedido não são alterados; as quantidades devolvidas
+// ficam nos itens da devolução.
+type PedidoDevolucao struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	PedidoID uint   `gorm:"not null;index" json:"pedido_id"`
+	Motivo   string `gorm:"type:varchar(200)" json:"motivo"`
+	// Valor soma o reembolso calculado para os itens; Reembolsado é a parte
+	// efetivamente estornada nos pagamentos do pedido
+	Valor       money.Money           `gorm:"type:integer;not null" json:"valor"`
+	Reembolsado money.Money           `gorm:"type:integer;not null;default:0" json:"reembolsado"`
+	Usuario     string                `gorm:"type:varchar(100);not null" json:"usuario"`
+	Itens       []PedidoDevolucaoItem `gorm:"foreignKey:DevolucaoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"itens"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PedidoDevolucao) TableName() string {
+	return "pedido_devolucoes"
+}
+
+// PedidoDevolucaoItem é a quantidade devolvida de um item do pedido e o
+// reembolso calculado para ela
+type PedidoDevolucaoItem struct {
+	ID              uint        `gorm:"primaryKey" json:"id"`
+	DevolucaoID     uint        `gorm:"not null;index" json:"devolucao_id"`
+	PedidoProdutoID uint        `gorm:"not null;index" json:"pedido_produto_id"`
+	ProdutoID       uint        `gorm:"not null" json:"produto_id"`
+	Quantidade      int         `gorm:"not null" json:"quantidade"`
+	Valor           money.Money `gorm:"type:integer;not null" json:"valor"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PedidoDevolucaoItem) TableName() string {
+	return "pedido_devolucao_itens"
+}
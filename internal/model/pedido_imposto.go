@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// PedidoItemImposto é um imposto calculado sobre um item do pedido. Guarda a
+// alíquota, o modo e a base do momento da compra, para que o valor continue
+// auditável mesmo que a regra seja alterada ou excluída depois.
+type PedidoItemImposto struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	PedidoProdutoID uint           `gorm:"not null;index" json:"pedido_produto_id"`
+	RegraID         *uint          `gorm:"index" json:"regra_id"`
+	Imposto         string         `gorm:"type:varchar(20);not null" json:"imposto"`
+	Aliquota        money.Aliquota `gorm:"type:integer;not null" json:"aliquota"`
+	Modo            string         `gorm:"type:varchar(10);not null" json:"modo"`
+	// Base é o subtotal do item menos a parte do desconto rateada para ele
+	Base      money.Money `gorm:"type:integer;not null" json:"base"`
+	Valor     money.Money `gorm:"type:integer;not null" json:"valor"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PedidoItemImposto) TableName() string {
+	return "pedido_item_impostos"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PedidoStatusHistorico registra cada mudança de status de um Pedido
+type PedidoStatusHistorico struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	PedidoID       uint      `gorm:"not null;index" json:"pedido_id"`
+	Pedido         Pedido    `gorm:"foreignKey:PedidoID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	StatusAnterior string    `gorm:"type:varchar(20)" json:"status_anterior"`
+	StatusNovo     string    `gorm:"type:varchar(20);not null" json:"status_novo"`
+	Usuario        string    `gorm:"type:varchar(100);not null" json:"usuario"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (PedidoStatusHistorico) TableName() string {
+	return "pedido_status_historico"
+}
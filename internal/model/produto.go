@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/danmaciel/api/internal/money"
 	"gorm.io/gorm"
 )
 
@@ -11,11 +12,18 @@ type Produto struct {
 	ID         uint           `gorm:"primaryKey" json:"id"`
 	Nome       string         `gorm:"type:varchar(200);not null" json:"nome" validate:"required,min=3,max=200"`
 	Descricao  string         `gorm:"type:text" json:"descricao" validate:"max=1000"`
-	Preco      float64        `gorm:"type:decimal(10,2);not null" json:"preco" validate:"required,gt=0"`
+	Preco      money.Money    `gorm:"type:integer;not null" json:"preco" validate:"required,gt=0"`
 	Estoque    int            `gorm:"not null;default:0" json:"estoque" validate:"gte=0"`
-	SKU        string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"sku" validate:"required,min=3,max=50"`
+	// o SKU é único entre os produtos não excluídos; um produto excluído não impede recadastrá-lo
+	SKU        string         `gorm:"type:varchar(50);uniqueIndex:idx_produtos_sku,where:deleted_at IS NULL;not null" json:"sku" validate:"required,min=3,max=50"`
 	Categoria  string         `gorm:"type:varchar(100)" json:"categoria" validate:"max=100"`
+	// Peso e dimensões da embalagem, usados no cálculo do frete
+	PesoGramas    int `gorm:"not null;default:0" json:"peso_gramas" validate:"gte=0"`
+	AlturaCm      int `gorm:"not null;default:0" json:"altura_cm" validate:"gte=0"`
+	LarguraCm     int `gorm:"not null;default:0" json:"largura_cm" validate:"gte=0"`
+	ComprimentoCm int `gorm:"not null;default:0" json:"comprimento_cm" validate:"gte=0"`
 	Ativo      bool           `gorm:"default:true" json:"ativo"`
+	Versao     uint           `gorm:"not null;default:1" json:"versao"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
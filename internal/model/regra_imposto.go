@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/money"
+	"gorm.io/gorm"
+)
+
+// Modos de cálculo de uma RegraImposto
+const (
+	// ImpostoInclusivo é o imposto "por dentro", como o ICMS: já está contido
+	// no preço e não altera o total do pedido
+	ImpostoInclusivo = "inclusivo"
+	// ImpostoExclusivo é o imposto "por fora", como o IPI: é acrescido ao total
+	ImpostoExclusivo = "exclusivo"
+)
+
+// RegraImposto define a alíquota de um imposto para uma categoria de produto
+// e uma UF de destino. Categoria ou UF vazias valem para todas; quando mais de
+// uma regra do mesmo imposto se aplica a um item, vale a mais específica.
+type RegraImposto struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Imposto é o nome do tributo, por exemplo ICMS ou IPI
+	Imposto   string         `gorm:"type:varchar(20);not null;index:idx_regras_imposto_chave" json:"imposto"`
+	Descricao string         `gorm:"type:varchar(200)" json:"descricao"`
+	Categoria string         `gorm:"type:varchar(100);not null;default:'';index:idx_regras_imposto_chave" json:"categoria"`
+	UF        string         `gorm:"type:varchar(2);not null;default:'';index:idx_regras_imposto_chave" json:"uf"`
+	Aliquota  money.Aliquota `gorm:"type:integer;not null" json:"aliquota"`
+	Modo      string         `gorm:"type:varchar(10);not null" json:"modo"`
+	Ativo     bool           `gorm:"not null" json:"ativo"`
+	Versao    uint           `gorm:"not null;default:1" json:"versao"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela para o GORM
+func (RegraImposto) TableName() string {
+	return "regras_imposto"
+}
+
+// Aplicavel indica se a regra vale para a categoria do produto e a UF de destino
+func (r *RegraImposto) Aplicavel(categoria, uf string) bool {
+	return (r.Categoria == "" || r.Categoria == categoria) && (r.UF == "" || r.UF == uf)
+}
+
+// Especificidade ordena as regras aplicáveis: categoria pesa mais que UF, e
+// uma regra genérica (sem categoria nem UF) tem especificidade zero
+func (r *RegraImposto) Especificidade() int {
+	n := 0
+	if r.Categoria != "" {
+		n += 2
+	}
+	if r.UF != "" {
+		n++
+	}
+	return n
+}
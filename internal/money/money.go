@@ -0,0 +1,201 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Moeda é o código ISO 4217 da moeda de todos os valores do sistema
+const Moeda = "BRL"
+
+// ErrValorInvalido indica um valor monetário malformado ou com mais de duas casas decimais
+var ErrValorInvalido = errors.New("valor monetário inválido")
+
+// Money representa um valor monetário em centavos. Por ser inteiro, somas e
+// multiplicações são exatas, sem o erro de arredondamento do float64.
+type Money int64
+
+// FromCentavos cria um valor a partir da quantidade de centavos
+func FromCentavos(centavos int64) Money {
+	return Money(centavos)
+}
+
+// Parse converte um decimal como "12.34", "12.3" ou "12" em Money. Valores com
+// mais de duas casas decimais são rejeitados para não haver perda de precisão.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negativo := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	inteiro, fracao, _ := strings.Cut(s, ".")
+	if inteiro == "" || len(fracao) > 2 || !digitos(inteiro) || !digitos(fracao) {
+		return 0, fmt.Errorf("%w: %q", ErrValorInvalido, s)
+	}
+	fracao += strings.Repeat("0", 2-len(fracao))
+
+	reais, err := strconv.ParseInt(inteiro, 10, 64)
+	if err != nil || reais > math.MaxInt64/100 {
+		return 0, fmt.Errorf("%w: %q", ErrValorInvalido, s)
+	}
+	centavos, _ := strconv.ParseInt(fracao, 10, 64)
+
+	valor := reais*100 + centavos
+	if negativo {
+		valor = -valor
+	}
+	return Money(valor), nil
+}
+
+// MustParse é como Parse, mas entra em pânico se o valor for inválido.
+// Deve ser usado apenas com constantes conhecidas.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Centavos retorna o valor em centavos
+func (m Money) Centavos() int64 {
+	return int64(m)
+}
+
+// Mul multiplica o valor por uma quantidade inteira
+func (m Money) Mul(quantidade int) Money {
+	return m * Money(quantidade)
+}
+
+// Percent calcula a porcentagem do valor em pontos-base (1250 = 12,50%),
+// arredondando meio centavo para longe do zero
+func (m Money) Percent(pontosBase int64) Money {
+	produto := int64(m) * pontosBase
+	q, r := produto/10000, produto%10000
+	if r >= 5000 {
+		q++
+	} else if r <= -5000 {
+		q--
+	}
+	return Money(q)
+}
+
+// String formata o valor com duas casas decimais, por exemplo "12.34"
+func (m Money) String() string {
+	sinal := ""
+	v := int64(m)
+	if v < 0 {
+		sinal = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sinal, v/100, v%100)
+}
+
+// MarshalJSON serializa o valor como string com duas casas decimais
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON aceita tanto a string "12.34" quanto o número 12.34. O número
+// é lido a partir do texto original, sem passar por float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value grava o valor no banco como inteiro em centavos
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+// Scan lê o valor em centavos gravado no banco
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = Money(math.Round(v))
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("%w: tipo %T não suportado", ErrValorInvalido, value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	centavos, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrValorInvalido, s)
+	}
+	*m = Money(centavos)
+	return nil
+}
+
+// Aliquota é uma taxa percentual em pontos-base (1800 = 18,00%). Usa a
+// mesma representação decimal de Money: duas casas, sem float64.
+type Aliquota int64
+
+// ParseAliquota converte um percentual como "18", "7.5" ou "4.65" em Aliquota
+func ParseAliquota(s string) (Aliquota, error) {
+	v, err := Parse(s)
+	return Aliquota(v), err
+}
+
+// PontosBase retorna a alíquota em pontos-base, o formato aceito por Money.Percent
+func (a Aliquota) PontosBase() int64 {
+	return int64(a)
+}
+
+// String formata a alíquota com duas casas decimais, por exemplo "18.00"
+func (a Aliquota) String() string {
+	return Money(a).String()
+}
+
+// MarshalJSON serializa a alíquota como string com duas casas decimais
+func (a Aliquota) MarshalJSON() ([]byte, error) {
+	return Money(a).MarshalJSON()
+}
+
+// UnmarshalJSON aceita tanto a string "18.00" quanto o número 18
+func (a *Aliquota) UnmarshalJSON(data []byte) error {
+	return (*Money)(a).UnmarshalJSON(data)
+}
+
+// Value grava a alíquota no banco como inteiro em pontos-base
+func (a Aliquota) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan lê a alíquota gravada em pontos-base
+func (a *Aliquota) Scan(value interface{}) error {
+	return (*Money)(a).Scan(value)
+}
+
+func digitos(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package pagamento
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+)
+
+// Fake é um provedor de pagamentos em processo, para desenvolvimento e
+// testes. Cartões são aprovados na hora; pix e boleto ficam pendentes até
+// um webhook assinado com o segredo configurado. As cobranças ficam em
+// memória, pela referência enviada, e se perdem quando o processo reinicia.
+type Fake struct {
+	segredo   []byte
+	mu        sync.Mutex
+	cobrancas map[string]Transacao
+}
+
+// NewFake cria o provedor fake. Sem segredo, todo webhook é recusado.
+func NewFake(segredo string) *Fake {
+	return &Fake{segredo: []byte(segredo), cobrancas: map[string]Transacao{}}
+}
+
+// eventoFake é o corpo das notificações do provedor fake
+type eventoFake struct {
+	Referencia string `json:"referencia"`
+	Status     string `json:"status"`
+}
+
+func (f *Fake) Nome() string {
+	return "fake"
+}
+
+func (f *Fake) Cobrar(ctx context.Context, cobranca Cobranca) (*Transacao, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if transacao, ok := f.cobrancas[cobranca.Referencia]; ok {
+		return &transacao, nil
+	}
+
+	referencia, err := referenciaFake("pay")
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.PagamentoPendente
+	if cobranca.Metodo == model.MetodoCartao {
+		status = model.PagamentoConfirmado
+	}
+	transacao := Transacao{Referencia: referencia, Status: status}
+	if cobranca.Referencia != "" {
+		f.cobrancas[cobranca.Referencia] = transacao
+	}
+	return &transacao, nil
+}
+
+func (f *Fake) Consultar(ctx context.Context, referencia string) (*Transacao, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transacao, ok := f.cobrancas[referencia]
+	if !ok {
+		return nil, ErrCobrancaNaoEncontrada
+	}
+	return &transacao, nil
+}
+
+func (f *Fake) Estornar(ctx context.Context, referencia string, valor money.Money) (string, error) {
+	return referenciaFake("ref")
+}
+
+func (f *Fake) Webhook(payload []byte, assinatura string) (*Evento, error) {
+	if len(f.segredo) == 0 || !hmac.Equal([]byte(f.Assinar(payload)), []byte(assinatura)) {
+		return nil, ErrAssinaturaInvalida
+	}
+
+	var evento eventoFake
+	if err := json.Unmarshal(payload, &evento); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEventoInvalido, err)
+	}
+	if evento.Referencia == "" {
+		return nil, fmt.Errorf("%w: referencia é obrigatória", ErrEventoInvalido)
+	}
+	if evento.Status != model.PagamentoConfirmado && evento.Status != model.PagamentoFalhou {
+		return nil, fmt.Errorf("%w: status deve ser %s ou %s", ErrEventoInvalido, model.PagamentoConfirmado, model.PagamentoFalhou)
+	}
+	return &Evento{Referencia: evento.Referencia, Status: evento.Status}, nil
+}
+
+// Assinar calcula a assinatura de uma notificação: o HMAC-SHA256 do corpo com
+// o segredo, em hexadecimal. Serve para simular o provedor em testes.
+func (f *Fake) Assinar(payload []byte) string {
+	mac := hmac.New(sha256.New, f.segredo)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// referenciaFake gera um identificador aleatório no formato fake_<tipo>_<hex>
+func referenciaFake(tipo string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar a referência do pagamento: %w", err)
+	}
+	return "fake_" + tipo + "_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,65 @@
+// Package pagamento integra a API a provedores de pagamento. Os pedidos
+// falam apenas com a interface PaymentGateway; o Fake é um provedor em
+// processo para desenvolvimento e testes.
+package pagamento
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danmaciel/api/internal/money"
+)
+
+// WebhookSignatureHeader é o cabeçalho com a assinatura das notificações do provedor
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+var (
+	// ErrAssinaturaInvalida indica uma notificação que não foi assinada pelo provedor
+	ErrAssinaturaInvalida = errors.New("assinatura do webhook inválida")
+	// ErrEventoInvalido indica uma notificação que não pôde ser interpretada
+	ErrEventoInvalido = errors.New("evento do webhook inválido")
+	// ErrCobrancaNaoEncontrada indica uma consulta a uma cobrança que o provedor não recebeu
+	ErrCobrancaNaoEncontrada = errors.New("cobrança não encontrada no provedor")
+)
+
+// Cobranca é o pedido de pagamento enviado ao provedor. Referencia é a
+// referência provisória do pagamento na API: o provedor a usa como chave de
+// idempotência e permite consultar a cobrança por ela se a resposta se perder.
+type Cobranca struct {
+	Referencia string
+	PedidoID   uint
+	Metodo     string
+	Valor      money.Money
+}
+
+// Transacao é a resposta do provedor a uma cobrança. Status é pendente
+// quando a confirmação chega depois, por webhook (pix e boleto), ou já
+// confirmado quando o provedor aprova na hora (cartão).
+type Transacao struct {
+	Referencia string
+	Status     string
+}
+
+// Evento é uma notificação do provedor sobre a mudança de status de uma cobrança
+type Evento struct {
+	Referencia string
+	Status     string
+}
+
+// PaymentGateway é um provedor de pagamentos
+type PaymentGateway interface {
+	// Nome identifica o provedor nos pagamentos gravados
+	Nome() string
+	// Cobrar envia a cobrança; repetir a mesma Referencia devolve a mesma
+	// transação em vez de cobrar de novo. Uma recusa vem como Transacao com
+	// status falhou; o erro indica que não se sabe se a cobrança foi feita.
+	Cobrar(ctx context.Context, cobranca Cobranca) (*Transacao, error)
+	// Consultar busca a cobrança pela Referencia enviada em Cobrar, falhando
+	// com ErrCobrancaNaoEncontrada se o provedor não a recebeu
+	Consultar(ctx context.Context, referencia string) (*Transacao, error)
+	// Estornar devolve parte ou todo o valor de uma cobrança confirmada e
+	// retorna a referência do estorno no provedor
+	Estornar(ctx context.Context, referencia string, valor money.Money) (string, error)
+	// Webhook autentica e interpreta uma notificação recebida do provedor
+	Webhook(payload []byte, assinatura string) (*Evento, error)
+}
@@ -0,0 +1,261 @@
+// Package pricing calcula os valores de um pedido: subtotal dos itens,
+// descontos de cupons, impostos, frete e total. O cálculo é puro;
+// verificações que dependem do banco (limites de uso) ficam no serviço de
+// pedidos.
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+)
+
+// Item é um item do pedido com o preço vigente do produto
+type Item struct {
+	ProdutoID     uint
+	Categoria     string
+	Quantidade    int
+	PrecoUnitario money.Money
+}
+
+// Subtotal retorna o preço unitário multiplicado pela quantidade
+func (i Item) Subtotal() money.Money {
+	return i.PrecoUnitario.Mul(i.Quantidade)
+}
+
+// Entrada reúne o que é necessário para precificar um pedido
+type Entrada struct {
+	Itens []Item
+	// Cupom é opcional
+	Cupom *model.Cupom
+	// Agora é o instante usado para conferir a validade do cupom
+	Agora time.Time
+	// UF de destino, usada para escolher as regras de imposto
+	UF string
+	// Regras de imposto candidatas; as inativas são ignoradas
+	Regras []model.RegraImposto
+	// Frete já cotado para o pedido; zero quando não há entrega
+	Frete money.Money
+}
+
+// ItemPrecificado traz o desconto rateado e os impostos de um item, na mesma
+// posição do item em Entrada.Itens
+type ItemPrecificado struct {
+	Desconto money.Money
+	Imposto  money.Money
+	Impostos []model.PedidoItemImposto
+}
+
+// Resultado é o pedido precificado. Total = Subtotal - Desconto + os impostos
+// exclusivos + Frete; Desconto é a soma das linhas em Descontos (inclusive a
+// de frete grátis) e Imposto a soma de todos os impostos dos itens.
+type Resultado struct {
+	Subtotal  money.Money
+	Desconto  money.Money
+	Imposto   money.Money
+	Frete     money.Money
+	Total     money.Money
+	Descontos []model.PedidoDesconto
+	Itens     []ItemPrecificado
+}
+
+// Calcular precifica o pedido, aplicando o cupom se houver e os impostos de
+// cada item. Um cupom que não pode ser usado no pedido resulta em erro de
+// regra de negócio.
+func Calcular(e Entrada) (*Resultado, error) {
+	r := &Resultado{Itens: make([]ItemPrecificado, len(e.Itens)), Frete: e.Frete}
+	for _, item := range e.Itens {
+		r.Subtotal += item.Subtotal()
+	}
+
+	if e.Cupom != nil {
+		linha, elegiveis, err := descontoCupom(e.Cupom, e.Itens, r.Subtotal, e.Frete, e.Agora)
+		if err != nil {
+			return nil, err
+		}
+		r.Descontos = append(r.Descontos, linha)
+		// o desconto de frete grátis não reduz a base de impostos dos itens
+		if linha.Tipo != model.CupomFreteGratis {
+			for i, parte := range ratear(linha.Valor, e.Itens, elegiveis) {
+				r.Itens[i].Desconto += parte
+			}
+		}
+	}
+
+	var exclusivos money.Money
+	for i, item := range e.Itens {
+		precificado := &r.Itens[i]
+		precificado.Impostos = impostosItem(item.Categoria, item.Subtotal()-precificado.Desconto, e.UF, e.Regras)
+		for _, imposto := range precificado.Impostos {
+			precificado.Imposto += imposto.Valor
+			if imposto.Modo == model.ImpostoExclusivo {
+				exclusivos += imposto.Valor
+			}
+		}
+		r.Imposto += precificado.Imposto
+	}
+
+	for _, d := range r.Descontos {
+		r.Desconto += d.Valor
+	}
+	r.Total = r.Subtotal - r.Desconto + exclusivos + r.Frete
+	return r, nil
+}
+
+// descontoCupom confere as regras do cupom e calcula a linha de desconto. Os
+// itens sobre os quais o desconto incide são marcados em elegiveis.
+func descontoCupom(cupom *model.Cupom, itens []Item, subtotal, frete money.Money, agora time.Time) (linha model.PedidoDesconto, elegiveis []bool, err error) {
+	if !cupom.Ativo {
+		return linha, nil, apperror.BusinessRule(fmt.Sprintf("cupom %s inativo", cupom.Codigo))
+	}
+	if !cupom.Vigente(agora) {
+		return linha, nil, apperror.BusinessRule(fmt.Sprintf("cupom %s fora do período de validade", cupom.Codigo))
+	}
+	if subtotal < cupom.ValorMinimo {
+		return linha, nil, apperror.BusinessRule(fmt.Sprintf("cupom %s exige pedido mínimo de %s", cupom.Codigo, cupom.ValorMinimo))
+	}
+
+	// base é a parte do pedido sobre a qual o desconto incide
+	var base money.Money
+	aplicavel := false
+	elegiveis = make([]bool, len(itens))
+	for i, item := range itens {
+		if cupom.Aplicavel(item.ProdutoID, item.Categoria) {
+			base += item.Subtotal()
+			elegiveis[i] = true
+			aplicavel = true
+		}
+	}
+	if !aplicavel {
+		return linha, nil, apperror.BusinessRule(fmt.Sprintf("cupom %s não se aplica aos produtos do pedido", cupom.Codigo))
+	}
+
+	var valor money.Money
+	switch cupom.Tipo {
+	case model.CupomPercentual:
+		valor = base.Percent(int64(cupom.Percentual) * 100)
+	case model.CupomValorFixo:
+		// o desconto nunca passa do valor dos itens elegíveis
+		valor = min(cupom.Valor, base)
+	case model.CupomFreteGratis:
+		// abate o frete inteiro; sem entrega, a linha registra o benefício com valor zero
+		base = frete
+		valor = frete
+	default:
+		return linha, nil, fmt.Errorf("tipo de cupom desconhecido: %s", cupom.Tipo)
+	}
+
+	descricao := cupom.Descricao
+	if descricao == "" {
+		descricao = "Cupom " + cupom.Codigo
+	}
+	cupomID := cupom.ID
+	return model.PedidoDesconto{
+		CupomID:   &cupomID,
+		Codigo:    cupom.Codigo,
+		Tipo:      cupom.Tipo,
+		Descricao: descricao,
+		Base:      base,
+		Valor:     valor,
+	}, elegiveis, nil
+}
+
+// ratear distribui o valor entre os itens elegíveis na proporção dos seus
+// subtotais. A sobra do arredondamento fica com o último item elegível, de
+// modo que as partes sempre somam o valor.
+func ratear(valor money.Money, itens []Item, elegiveis []bool) []money.Money {
+	partes := make([]money.Money, len(itens))
+	var base money.Money
+	ultimo := -1
+	for i, item := range itens {
+		if elegiveis[i] {
+			base += item.Subtotal()
+			ultimo = i
+		}
+	}
+	if valor == 0 || base == 0 {
+		return partes
+	}
+
+	restante := valor
+	for i, item := range itens {
+		if !elegiveis[i] || i == ultimo {
+			continue
+		}
+		partes[i] = money.FromCentavos(valor.Centavos() * item.Subtotal().Centavos() / base.Centavos())
+		restante -= partes[i]
+	}
+	partes[ultimo] = restante
+	return partes
+}
+
+// impostosItem calcula os impostos de um item sobre a base informada. Para
+// cada imposto vale a regra ativa mais específica entre as aplicáveis; uma
+// regra específica com alíquota zero serve, por exemplo, para isentar uma
+// categoria de uma regra genérica.
+func impostosItem(categoria string, base money.Money, uf string, regras []model.RegraImposto) []model.PedidoItemImposto {
+	escolhidas := map[string]*model.RegraImposto{}
+	for i := range regras {
+		regra := &regras[i]
+		if !regra.Ativo || !regra.Aplicavel(categoria, uf) {
+			continue
+		}
+		if atual, ok := escolhidas[regra.Imposto]; !ok || regra.Especificidade() > atual.Especificidade() {
+			escolhidas[regra.Imposto] = regra
+		}
+	}
+
+	nomes := make([]string, 0, len(escolhidas))
+	for nome := range escolhidas {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+
+	impostos := make([]model.PedidoItemImposto, 0, len(nomes))
+	for _, nome := range nomes {
+		regra := escolhidas[nome]
+		regraID := regra.ID
+		impostos = append(impostos, model.PedidoItemImposto{
+			RegraID:  &regraID,
+			Imposto:  regra.Imposto,
+			Aliquota: regra.Aliquota,
+			Modo:     regra.Modo,
+			Base:     base,
+			// inclusivo ("por dentro") ou exclusivo, a alíquota incide sobre a
+			// base; o modo decide apenas se o valor é somado ao total
+			Valor: base.Percent(regra.Aliquota.PontosBase()),
+		})
+	}
+	return impostos
+}
+
+// Liquido é o valor efetivamente cobrado por um item do pedido: o subtotal
+// menos o desconto rateado, mais os impostos exclusivos. O frete não entra.
+func Liquido(item model.PedidoProduto) money.Money {
+	liquido := item.Subtotal - item.Desconto
+	for _, imposto := range item.Impostos {
+		if imposto.Modo == model.ImpostoExclusivo {
+			liquido += imposto.Valor
+		}
+	}
+	return liquido
+}
+
+// Reembolso calcula quanto devolver por quantidade unidades de um item, das
+// quais devolvidas já foram devolvidas antes. O valor líquido do item é
+// rateado pelas unidades de forma acumulada, assim devoluções parciais
+// somadas nunca passam do líquido e a última leva o centavo do arredondamento.
+func Reembolso(item model.PedidoProduto, devolvidas, quantidade int) money.Money {
+	if item.Quantidade == 0 {
+		return 0
+	}
+	liquido := Liquido(item).Centavos()
+	acumulado := func(unidades int) int64 {
+		return liquido * int64(unidades) / int64(item.Quantidade)
+	}
+	return money.FromCentavos(acumulado(devolvidas+quantidade) - acumulado(devolvidas))
+}
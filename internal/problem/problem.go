@@ -0,0 +1,59 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/danmaciel/api/internal/dto"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentType é o media type das respostas de erro (RFC 9457)
+const ContentType = "application/problem+json"
+
+// tipo identifica um problema pelo URI do type e pelo título
+type tipo struct {
+	uri    string
+	titulo string
+}
+
+// tipos associa cada status HTTP ao tipo de problema documentado pela API.
+// Status ausentes usam "about:blank" com o texto padrão do status.
+var tipos = map[int]tipo{
+	http.StatusBadRequest:           {"/problems/validation-error", "Requisição inválida"},
+	http.StatusUnauthorized:         {"/problems/unauthorized", "Não autenticado"},
+	http.StatusForbidden:            {"/problems/forbidden", "Acesso negado"},
+	http.StatusNotFound:             {"/problems/not-found", "Recurso não encontrado"},
+	http.StatusConflict:             {"/problems/conflict", "Conflito com o estado atual do recurso"},
+	http.StatusPreconditionFailed:   {"/problems/precondition-failed", "Versão do recurso desatualizada"},
+	http.StatusUnsupportedMediaType: {"/problems/unsupported-media-type", "Formato do corpo não suportado"},
+	http.StatusUnprocessableEntity:  {"/problems/business-rule", "Regra de negócio violada"},
+	http.StatusInternalServerError:  {"/problems/internal-error", "Erro interno do servidor"},
+	http.StatusServiceUnavailable:   {"/problems/service-unavailable", "Serviço indisponível"},
+}
+
+// New cria um problema para o status informado
+func New(status int, detail string) dto.ProblemDetails {
+	t, ok := tipos[status]
+	if !ok {
+		t = tipo{"about:blank", http.StatusText(status)}
+	}
+	return dto.ProblemDetails{
+		Type:   t.uri,
+		Title:  t.titulo,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Write escreve o problema como application/problem+json. Quando não
+// informado, instance recebe o ID da requisição gerado pelo chi.
+func Write(w http.ResponseWriter, r *http.Request, p dto.ProblemDetails) {
+	if p.Instance == "" {
+		p.Instance = chimiddleware.GetReqID(r.Context())
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,117 @@
+package problem
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldErrors converte os erros do validator em erros por campo, com o caminho
+// do campo no corpo da requisição (por exemplo "itens[0].quantidade") e uma
+// mensagem em português
+func FieldErrors(errs validator.ValidationErrors) []dto.FieldError {
+	fields := make([]dto.FieldError, len(errs))
+	for i, fe := range errs {
+		fields[i] = dto.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		}
+	}
+	return fields
+}
+
+// fieldPath remove o nome da struct raiz do namespace do validator
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+// message descreve a regra violada em português
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return "é obrigatório"
+	case "required_if":
+		campo, valor, _ := strings.Cut(param, " ")
+		return fmt.Sprintf("é obrigatório quando %s é %s", strings.ToLower(campo), valor)
+	case "required_unless":
+		campo, valor, _ := strings.Cut(param, " ")
+		return fmt.Sprintf("é obrigatório, exceto quando %s é %s", strings.ToLower(campo), valor)
+	case "required_without":
+		return fmt.Sprintf("é obrigatório quando %s não é informado", strings.ToLower(param))
+	case "excluded_if":
+		campo, valor, _ := strings.Cut(param, " ")
+		return fmt.Sprintf("não deve ser informado quando %s é %s", strings.ToLower(campo), valor)
+	case "excluded_unless":
+		campo, valor, _ := strings.Cut(param, " ")
+		return fmt.Sprintf("só deve ser informado quando %s é %s", strings.ToLower(campo), valor)
+	case "email":
+		return "deve ser um email válido"
+	case "numeric":
+		return "deve conter apenas números"
+	case "len":
+		return fmt.Sprintf("deve ter exatamente %s %s", param, unidade(fe.Kind()))
+	case "min":
+		if temTamanho(fe.Kind()) {
+			return fmt.Sprintf("deve ter no mínimo %s %s", param, unidade(fe.Kind()))
+		}
+		return "deve ser no mínimo " + param
+	case "max":
+		if temTamanho(fe.Kind()) {
+			return fmt.Sprintf("deve ter no máximo %s %s", param, unidade(fe.Kind()))
+		}
+		return "deve ser no máximo " + param
+	case "gt":
+		return "deve ser maior que " + param
+	case "gte":
+		return "deve ser maior ou igual a " + param
+	case "lt":
+		return "deve ser menor que " + param
+	case "lte":
+		return "deve ser menor ou igual a " + param
+	case "codigo":
+		return "deve conter apenas letras, números, - e _"
+	case "uf":
+		return "deve ser a sigla de uma UF, por exemplo SP"
+	case "cep":
+		return "deve ser um CEP no formato 00000-000"
+	case "cpf":
+		return "deve ser um CPF válido, com ou sem máscara"
+	case "cnpj":
+		return "deve ser um CNPJ válido, com ou sem máscara"
+	case "ie":
+		return "deve ser ISENTO ou a inscrição estadual, de 2 a 14 dígitos"
+	case "aliquota":
+		return "deve ser um percentual entre 0 e 100"
+	case "scope":
+		return "deve ter o formato <recurso>:<read|write|admin>"
+	case "oneof":
+		return "deve ser um dos valores: " + strings.Join(strings.Fields(param), ", ")
+	}
+	return fmt.Sprintf("não atende à regra %q", fe.Tag())
+}
+
+// temTamanho indica se min/max se referem ao tamanho do valor e não ao valor em si
+func temTamanho(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func unidade(kind reflect.Kind) string {
+	if kind == reflect.String {
+		return "caracteres"
+	}
+	return "itens"
+}
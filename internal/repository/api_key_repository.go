@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// APIKeyRepository defines the interface for api key data access
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.APIKey], error)
+	FindByID(ctx context.Context, id uint) (*model.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	Update(ctx context.Context, key *model.APIKey) error
+	TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepositorySQLite creates a new SQLite implementation of APIKeyRepository
+func NewAPIKeyRepositorySQLite(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepositorySQLite{db: db}
+}
+
+func (r *apiKeyRepositorySQLite) Create(ctx context.Context, key *model.APIKey) error {
+	result := conn(ctx, r.db).Create(key)
+	return translateError(result.Error, "API key")
+}
+
+func (r *apiKeyRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.APIKey], error) {
+	return paginate[model.APIKey](conn(ctx, r.db).Model(&model.APIKey{}), opts)
+}
+
+func (r *apiKeyRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	var key model.APIKey
+	result := conn(ctx, r.db).First(&key, id)
+	if result.Error != nil {
+		return nil, translateError(result.Error, "API key")
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepositorySQLite) FindByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	result := conn(ctx, r.db).Where("hash = ?", hash).First(&key)
+	if result.Error != nil {
+		return nil, translateError(result.Error, "API key")
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepositorySQLite) Update(ctx context.Context, key *model.APIKey) error {
+	result := conn(ctx, r.db).Save(key)
+	return translateError(result.Error, "API key")
+}
+
+// TouchLastUsed grava apenas last_used_at, sem passar pelos hooks do modelo
+func (r *apiKeyRepositorySQLite) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	result := conn(ctx, r.db).Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt)
+	return result.Error
+}
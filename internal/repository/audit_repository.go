@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// AuditRepository define o acesso ao audit log, que só recebe inserções
+type AuditRepository interface {
+	// Append grava a entrada no fim da cadeia, preenchendo CreatedAt,
+	// HashAnterior e Hash
+	Append(ctx context.Context, entry *model.AuditLog) error
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.AuditLog], error)
+	// FindPonta busca a ponta da cadeia, com o hash da última entrada gravada
+	FindPonta(ctx context.Context) (*model.AuditCadeia, error)
+	// ForEach percorre todas as entradas na ordem da cadeia, em lotes
+	ForEach(ctx context.Context, fn func(entry *model.AuditLog) error) error
+	// WithTransaction executa fn em uma única transação; os repositórios chamados
+	// com o contexto recebido por fn participam da mesma unidade de trabalho
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	// auditBatchSize é o tamanho dos lotes lidos por ForEach
+	auditBatchSize = 500
+	// auditCadeiaID é o ID da única linha de model.AuditCadeia
+	auditCadeiaID = 1
+)
+
+// ErrAuditConcorrente indica que outra gravação continuou a cadeia do audit
+// log ao mesmo tempo; a escrita auditada é desfeita e pode ser repetida
+var ErrAuditConcorrente = apperror.Conflict("o audit log recebeu outra gravação ao mesmo tempo, tente novamente")
+
+type auditRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewAuditRepositorySQLite creates a new SQLite implementation of AuditRepository
+func NewAuditRepositorySQLite(db *gorm.DB) AuditRepository {
+	return &auditRepositorySQLite{db: db}
+}
+
+func (r *auditRepositorySQLite) Append(ctx context.Context, entry *model.AuditLog) error {
+	return runInTransaction(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		// atualizar a ponta da cadeia antes de ler o hash trava a escrita: no
+		// SQLite a transação passa a ser a única gravando no banco e, em
+		// outros bancos, a linha fica bloqueada até o commit
+		cadeia := model.AuditCadeia{ID: auditCadeiaID}
+		result := db.Model(&cadeia).Update("updated_at", time.Now())
+		if result.Error != nil {
+			return translateAuditError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			if err := r.iniciarCadeia(ctx, &cadeia); err != nil {
+				return err
+			}
+		} else if err := db.Take(&cadeia).Error; err != nil {
+			return err
+		}
+
+		// a primeira entrada começa a cadeia com HashAnterior vazio
+		entry.HashAnterior = cadeia.Hash
+		entry.CreatedAt = time.Now()
+		entry.Hash = entry.CalcularHash()
+		if err := db.Create(entry).Error; err != nil {
+			return translateAuditError(err)
+		}
+		return db.Model(&cadeia).Update("hash", entry.Hash).Error
+	})
+}
+
+// iniciarCadeia cria a ponta da cadeia no fim das entradas já gravadas, que
+// podem existir de antes da tabela da ponta
+func (r *auditRepositorySQLite) iniciarCadeia(ctx context.Context, cadeia *model.AuditCadeia) error {
+	var ultima model.AuditLog
+	err := conn(ctx, r.db).Order("id DESC").Take(&ultima).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	cadeia.Hash = ultima.Hash
+	return translateAuditError(conn(ctx, r.db).Create(cadeia).Error)
+}
+
+// translateAuditError converte as violações de unicidade da cadeia, que só
+// acontecem quando duas gravações a continuam ao mesmo tempo, em conflito
+func translateAuditError(err error) error {
+	if err != nil && strings.Contains(err.Error(), uniqueViolation) {
+		return ErrAuditConcorrente
+	}
+	return err
+}
+
+func (r *auditRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.AuditLog], error) {
+	return paginate[model.AuditLog](conn(ctx, r.db).Model(&model.AuditLog{}), opts)
+}
+
+func (r *auditRepositorySQLite) FindPonta(ctx context.Context) (*model.AuditCadeia, error) {
+	var cadeia model.AuditCadeia
+	if err := conn(ctx, r.db).Take(&cadeia, auditCadeiaID).Error; err != nil {
+		return nil, translateError(err, "ponta do audit log")
+	}
+	return &cadeia, nil
+}
+
+func (r *auditRepositorySQLite) ForEach(ctx context.Context, fn func(entry *model.AuditLog) error) error {
+	var lote []model.AuditLog
+	result := conn(ctx, r.db).Order("id").FindInBatches(&lote, auditBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range lote {
+			if err := fn(&lote[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result.Error
+}
+
+func (r *auditRepositorySQLite) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTransaction(ctx, r.db, fn)
+}
@@ -9,10 +9,20 @@ import (
 // ClienteRepository defines the interface for cliente data access
 type ClienteRepository interface {
 	Create(ctx context.Context, cliente *model.Cliente) error
-	FindAll(ctx context.Context) ([]model.Cliente, error)
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.Cliente], error)
 	FindByID(ctx context.Context, id uint) (*model.Cliente, error)
-	FindByName(ctx context.Context, nome string) ([]model.Cliente, error)
+	FindByName(ctx context.Context, nome string, opts ListOptions) (*Page[model.Cliente], error)
 	Update(ctx context.Context, cliente *model.Cliente) error
 	Delete(ctx context.Context, id uint) error
+	// Restore desfaz a exclusão de um cliente excluído
+	Restore(ctx context.Context, id uint) error
+	// Purge remove definitivamente o cliente, com seus endereços e contatos
+	Purge(ctx context.Context, id uint) error
+	// Anonymize grava o cliente já anonimizado (ver model.Cliente.Anonimizar)
+	// e remove definitivamente seus endereços e contatos
+	Anonymize(ctx context.Context, cliente *model.Cliente) error
 	Count(ctx context.Context) (int64, error)
+	// CountPedidos conta os pedidos do cliente; os excluídos (soft delete) só
+	// entram com incluirExcluidos, pois ainda referenciam o cliente no banco
+	CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error)
 }
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"errors"
 
 	"github.com/danmaciel/api/internal/model"
 	"gorm.io/gorm"
@@ -18,61 +17,94 @@ func NewClienteRepositorySQLite(db *gorm.DB) ClienteRepository {
 }
 
 func (r *clienteRepositorySQLite) Create(ctx context.Context, cliente *model.Cliente) error {
-	result := r.db.WithContext(ctx).Create(cliente)
-	return result.Error
+	result := conn(ctx, r.db).Create(cliente)
+	return translateError(result.Error, "cliente")
 }
 
-func (r *clienteRepositorySQLite) FindAll(ctx context.Context) ([]model.Cliente, error) {
-	var clientes []model.Cliente
-	result := r.db.WithContext(ctx).Find(&clientes)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return clientes, nil
+func (r *clienteRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.Cliente], error) {
+	return paginate[model.Cliente](conn(ctx, r.db).Model(&model.Cliente{}), opts)
 }
 
 func (r *clienteRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Cliente, error) {
 	var cliente model.Cliente
-	result := r.db.WithContext(ctx).First(&cliente, id)
+	result := conn(ctx, r.db).First(&cliente, id)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+		return nil, translateError(result.Error, "cliente")
 	}
 	return &cliente, nil
 }
 
-func (r *clienteRepositorySQLite) FindByName(ctx context.Context, nome string) ([]model.Cliente, error) {
-	var clientes []model.Cliente
-	result := r.db.WithContext(ctx).Where("nome LIKE ?", "%"+nome+"%").Find(&clientes)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return clientes, nil
+func (r *clienteRepositorySQLite) FindByName(ctx context.Context, nome string, opts ListOptions) (*Page[model.Cliente], error) {
+	// clientes PJ são encontrados também pela razão social e pelo nome fantasia
+	padrao := "%" + nome + "%"
+	query := conn(ctx, r.db).Model(&model.Cliente{}).
+		Where("(nome LIKE ? OR razao_social LIKE ? OR nome_fantasia LIKE ?)", padrao, padrao, padrao)
+	return paginate[model.Cliente](query, opts)
 }
 
 func (r *clienteRepositorySQLite) Update(ctx context.Context, cliente *model.Cliente) error {
-	result := r.db.WithContext(ctx).Save(cliente)
-	return result.Error
+	return updateVersioned(conn(ctx, r.db), cliente, &cliente.Versao, "cliente")
 }
 
 func (r *clienteRepositorySQLite) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&model.Cliente{}, id)
+	result := conn(ctx, r.db).Delete(&model.Cliente{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return translateError(gorm.ErrRecordNotFound, "cliente")
 	}
 	return nil
 }
 
+func (r *clienteRepositorySQLite) Restore(ctx context.Context, id uint) error {
+	return restore(conn(ctx, r.db), &model.Cliente{}, id, "cliente")
+}
+
+func (r *clienteRepositorySQLite) Purge(ctx context.Context, id uint) error {
+	return runInTransaction(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		// endereços e contatos pertencem ao cliente e saem junto com ele
+		if err := removerDependentes(db, id); err != nil {
+			return err
+		}
+		return purge(db, &model.Cliente{}, id, "cliente")
+	})
+}
+
+func (r *clienteRepositorySQLite) Anonymize(ctx context.Context, cliente *model.Cliente) error {
+	return runInTransaction(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		if err := updateVersioned(db, cliente, &cliente.Versao, "cliente"); err != nil {
+			return err
+		}
+		return removerDependentes(db, cliente.ID)
+	})
+}
+
+// removerDependentes remove definitivamente os endereços e contatos do cliente
+func removerDependentes(db *gorm.DB, clienteID uint) error {
+	if err := db.Unscoped().Where("cliente_id = ?", clienteID).Delete(&model.Endereco{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("cliente_id = ?", clienteID).Delete(&model.Contato{}).Error
+}
+
 func (r *clienteRepositorySQLite) Count(ctx context.Context) (int64, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&model.Cliente{}).Count(&count)
+	result := conn(ctx, r.db).Model(&model.Cliente{}).Count(&count)
 	if result.Error != nil {
 		return 0, result.Error
 	}
 	return count, nil
 }
+
+func (r *clienteRepositorySQLite) CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error) {
+	db := conn(ctx, r.db)
+	if incluirExcluidos {
+		db = db.Unscoped()
+	}
+	var count int64
+	err := db.Model(&model.Pedido{}).Where("cliente_id = ?", id).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// ContatoRepository define a interface para operações de dados de Contato
+type ContatoRepository interface {
+	Create(ctx context.Context, contato *model.Contato) error
+	// FindByClienteID retorna os contatos do cliente por ordem de cadastro;
+	// com papel, apenas os contatos com esse papel
+	FindByClienteID(ctx context.Context, clienteID uint, papel string) ([]model.Contato, error)
+	FindByID(ctx context.Context, id uint) (*model.Contato, error)
+	Update(ctx context.Context, contato *model.Contato) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type contatoRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewContatoRepositorySQLite cria uma nova instância do repositório SQLite
+func NewContatoRepositorySQLite(db *gorm.DB) ContatoRepository {
+	return &contatoRepositorySQLite{db: db}
+}
+
+func (r *contatoRepositorySQLite) Create(ctx context.Context, contato *model.Contato) error {
+	return translateError(conn(ctx, r.db).Create(contato).Error, "contato")
+}
+
+func (r *contatoRepositorySQLite) FindByClienteID(ctx context.Context, clienteID uint, papel string) ([]model.Contato, error) {
+	contatos := []model.Contato{}
+	query := conn(ctx, r.db).Where("cliente_id = ?", clienteID)
+	if papel != "" {
+		query = query.Where("papel = ?", papel)
+	}
+	err := query.Order("id").Find(&contatos).Error
+	return contatos, err
+}
+
+func (r *contatoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Contato, error) {
+	var contato model.Contato
+	err := conn(ctx, r.db).First(&contato, id).Error
+	if err != nil {
+		return nil, translateError(err, "contato")
+	}
+	return &contato, nil
+}
+
+func (r *contatoRepositorySQLite) Update(ctx context.Context, contato *model.Contato) error {
+	return updateVersioned(conn(ctx, r.db), contato, &contato.Versao, "contato")
+}
+
+func (r *contatoRepositorySQLite) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.Contato{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "contato")
+	}
+	return nil
+}
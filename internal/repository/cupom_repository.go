@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/model"
+)
+
+// ErrCupomEsgotado indica que o cupom atingiu o limite global de usos
+var ErrCupomEsgotado = apperror.BusinessRule("cupom esgotado")
+
+// ErrCupomLimiteCliente indica que o cliente já usou o cupom o máximo de vezes permitido
+var ErrCupomLimiteCliente = apperror.BusinessRule("cupom já utilizado o máximo de vezes por este cliente")
+
+// CupomRepository define a interface para operações de dados de Cupom
+type CupomRepository interface {
+	Create(ctx context.Context, cupom *model.Cupom) error
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.Cupom], error)
+	FindByID(ctx context.Context, id uint) (*model.Cupom, error)
+	// FindByCodigo retorna nil, sem erro, se não houver cupom com o código
+	FindByCodigo(ctx context.Context, codigo string) (*model.Cupom, error)
+	Update(ctx context.Context, cupom *model.Cupom) error
+	Delete(ctx context.Context, id uint) error
+	// Restore desfaz a exclusão de um cupom excluído
+	Restore(ctx context.Context, id uint) error
+	// Purge remove definitivamente o cupom, excluído ou não
+	Purge(ctx context.Context, id uint) error
+	// IncrementUsos registra um uso do cliente de forma atômica, falhando com
+	// ErrCupomEsgotado se o limite global já tiver sido atingido ou com
+	// ErrCupomLimiteCliente se o cliente já atingiu o limite por cliente
+	IncrementUsos(ctx context.Context, id, clienteID uint) error
+	// DecrementUsos libera um uso, por exemplo quando o pedido é cancelado
+	DecrementUsos(ctx context.Context, id uint) error
+	// CountUsosCliente conta os pedidos não cancelados do cliente que usaram o cupom
+	CountUsosCliente(ctx context.Context, cupomID, clienteID uint) (int64, error)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type cupomRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewCupomRepositorySQLite cria uma nova instância do repositório SQLite
+func NewCupomRepositorySQLite(db *gorm.DB) CupomRepository {
+	return &cupomRepositorySQLite{db: db}
+}
+
+func (r *cupomRepositorySQLite) Create(ctx context.Context, cupom *model.Cupom) error {
+	return translateError(conn(ctx, r.db).Create(cupom).Error, "cupom")
+}
+
+func (r *cupomRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.Cupom], error) {
+	return paginate[model.Cupom](conn(ctx, r.db).Model(&model.Cupom{}), opts)
+}
+
+func (r *cupomRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Cupom, error) {
+	var cupom model.Cupom
+	err := conn(ctx, r.db).First(&cupom, id).Error
+	if err != nil {
+		return nil, translateError(err, "cupom")
+	}
+	return &cupom, nil
+}
+
+func (r *cupomRepositorySQLite) FindByCodigo(ctx context.Context, codigo string) (*model.Cupom, error) {
+	var cupom model.Cupom
+	err := conn(ctx, r.db).Where("codigo = ?", codigo).First(&cupom).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // código não encontrado não é erro
+		}
+		return nil, err
+	}
+	return &cupom, nil
+}
+
+func (r *cupomRepositorySQLite) Update(ctx context.Context, cupom *model.Cupom) error {
+	return updateVersioned(conn(ctx, r.db), cupom, &cupom.Versao, "cupom")
+}
+
+func (r *cupomRepositorySQLite) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.Cupom{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "cupom")
+	}
+	return nil
+}
+
+func (r *cupomRepositorySQLite) Restore(ctx context.Context, id uint) error {
+	return restore(conn(ctx, r.db), &model.Cupom{}, id, "cupom")
+}
+
+func (r *cupomRepositorySQLite) Purge(ctx context.Context, id uint) error {
+	return purge(conn(ctx, r.db), &model.Cupom{}, id, "cupom")
+}
+
+func (r *cupomRepositorySQLite) IncrementUsos(ctx context.Context, id, clienteID uint) error {
+	db := conn(ctx, r.db)
+	// as condições no WHERE garantem que pedidos concorrentes não ultrapassem
+	// os limites: a atualização trava o cupom até o commit, quando o pedido
+	// que usou o cupom já está gravado e entra na contagem do próximo
+	result := db.
+		Model(&model.Cupom{}).
+		Where("id = ? AND (limite_uso = 0 OR usos < limite_uso)", id).
+		Where("limite_por_cliente = 0 OR limite_por_cliente > (?)", usosCliente(db, id, clienteID).Select("COUNT(*)")).
+		Updates(map[string]interface{}{"usos": gorm.Expr("usos + 1"), "versao": incrementVersao})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		cupom, err := r.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if cupom.LimiteUso > 0 && cupom.Usos >= cupom.LimiteUso {
+			return ErrCupomEsgotado
+		}
+		return ErrCupomLimiteCliente
+	}
+	return nil
+}
+
+func (r *cupomRepositorySQLite) DecrementUsos(ctx context.Context, id uint) error {
+	// Unscoped permite liberar o uso mesmo que o cupom tenha sido removido depois da venda
+	return conn(ctx, r.db).
+		Unscoped().
+		Model(&model.Cupom{}).
+		Where("id = ? AND usos > 0", id).
+		Updates(map[string]interface{}{"usos": gorm.Expr("usos - 1"), "versao": incrementVersao}).Error
+}
+
+func (r *cupomRepositorySQLite) CountUsosCliente(ctx context.Context, cupomID, clienteID uint) (int64, error) {
+	var count int64
+	err := usosCliente(conn(ctx, r.db), cupomID, clienteID).Count(&count).Error
+	return count, err
+}
+
+// usosCliente seleciona os descontos do cupom em pedidos não cancelados do cliente
+func usosCliente(db *gorm.DB, cupomID, clienteID uint) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&model.PedidoDesconto{}).
+		Joins("JOIN pedidos ON pedidos.id = pedido_descontos.pedido_id").
+		Where("pedido_descontos.cupom_id = ? AND pedidos.cliente_id = ?", cupomID, clienteID).
+		Where("pedidos.status <> ? AND pedidos.deleted_at IS NULL", model.StatusCancelado)
+}
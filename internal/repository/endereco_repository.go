@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// EnderecoRepository define a interface para operações de dados de Endereco
+type EnderecoRepository interface {
+	Create(ctx context.Context, endereco *model.Endereco) error
+	// FindByClienteID retorna os endereços do cliente, o padrão primeiro
+	FindByClienteID(ctx context.Context, clienteID uint) ([]model.Endereco, error)
+	FindByID(ctx context.Context, id uint) (*model.Endereco, error)
+	// FindPadrao retorna o endereço padrão do cliente, ou nil, sem erro, se ele não tiver endereços
+	FindPadrao(ctx context.Context, clienteID uint) (*model.Endereco, error)
+	Update(ctx context.Context, endereco *model.Endereco) error
+	Delete(ctx context.Context, id uint) error
+	// DesmarcarPadrao tira a marcação de padrão dos demais endereços do cliente
+	DesmarcarPadrao(ctx context.Context, clienteID, exceto uint) error
+	// WithTransaction executa fn em uma única transação, como em PedidoRepository
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type enderecoRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewEnderecoRepositorySQLite cria uma nova instância do repositório SQLite
+func NewEnderecoRepositorySQLite(db *gorm.DB) EnderecoRepository {
+	return &enderecoRepositorySQLite{db: db}
+}
+
+func (r *enderecoRepositorySQLite) Create(ctx context.Context, endereco *model.Endereco) error {
+	return translateError(conn(ctx, r.db).Create(endereco).Error, "endereço")
+}
+
+func (r *enderecoRepositorySQLite) FindByClienteID(ctx context.Context, clienteID uint) ([]model.Endereco, error) {
+	enderecos := []model.Endereco{}
+	err := conn(ctx, r.db).
+		Where("cliente_id = ?", clienteID).
+		Order("padrao DESC, id").
+		Find(&enderecos).Error
+	return enderecos, err
+}
+
+func (r *enderecoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Endereco, error) {
+	var endereco model.Endereco
+	err := conn(ctx, r.db).First(&endereco, id).Error
+	if err != nil {
+		return nil, translateError(err, "endereço")
+	}
+	return &endereco, nil
+}
+
+func (r *enderecoRepositorySQLite) FindPadrao(ctx context.Context, clienteID uint) (*model.Endereco, error) {
+	var endereco model.Endereco
+	err := conn(ctx, r.db).
+		Where("cliente_id = ? AND padrao = ?", clienteID, true).
+		First(&endereco).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // cliente sem endereço não é erro
+		}
+		return nil, err
+	}
+	return &endereco, nil
+}
+
+func (r *enderecoRepositorySQLite) Update(ctx context.Context, endereco *model.Endereco) error {
+	return updateVersioned(conn(ctx, r.db), endereco, &endereco.Versao, "endereço")
+}
+
+func (r *enderecoRepositorySQLite) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.Endereco{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "endereço")
+	}
+	return nil
+}
+
+func (r *enderecoRepositorySQLite) DesmarcarPadrao(ctx context.Context, clienteID, exceto uint) error {
+	// a versão muda junto para que um PUT com ETag antigo não volte a marcá-los
+	return conn(ctx, r.db).
+		Model(&model.Endereco{}).
+		Where("cliente_id = ? AND id <> ? AND padrao = ?", clienteID, exceto, true).
+		Updates(map[string]interface{}{"padrao": false, "versao": incrementVersao}).Error
+}
+
+func (r *enderecoRepositorySQLite) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTransaction(ctx, r.db, fn)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation é o prefixo da mensagem do SQLite para violação de índice único
+const uniqueViolation = "UNIQUE constraint failed: "
+
+// foreignKeyViolation é a mensagem do SQLite quando a operação deixaria outro
+// registro apontando para um registro inexistente
+const foreignKeyViolation = "FOREIGN KEY constraint failed"
+
+// translateError converte erros do GORM/SQLite nos erros de domínio equivalentes.
+// entidade é usada na mensagem, por exemplo "produto".
+func translateError(err error, entidade string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.NotFound(entidade + " não encontrado")
+	}
+	if _, coluna, ok := strings.Cut(err.Error(), uniqueViolation); ok {
+		// a coluna vem no formato tabela.coluna
+		if i := strings.LastIndex(coluna, "."); i >= 0 {
+			coluna = coluna[i+1:]
+		}
+		return apperror.Conflict(entidade + " com " + coluna + " já cadastrado")
+	}
+	if strings.Contains(err.Error(), foreignKeyViolation) {
+		return apperror.Conflict(entidade + " é referenciado por outros registros")
+	}
+	return err
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// FreteRepository define a interface para operações de dados da tabela de frete
+type FreteRepository interface {
+	// CreateZona grava a zona com as suas faixas de peso
+	CreateZona(ctx context.Context, zona *model.FreteZona) error
+	FindAllZonas(ctx context.Context, opts ListOptions) (*Page[model.FreteZona], error)
+	FindZonaByID(ctx context.Context, id uint) (*model.FreteZona, error)
+	// FindZonasAtivas retorna as zonas ativas que atendem o CEP, com as faixas
+	FindZonasAtivas(ctx context.Context, cep string) ([]model.FreteZona, error)
+	// UpdateZona grava a zona e substitui todas as suas faixas de peso
+	UpdateZona(ctx context.Context, zona *model.FreteZona) error
+	DeleteZona(ctx context.Context, id uint) error
+}
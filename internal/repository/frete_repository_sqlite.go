@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type freteRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewFreteRepositorySQLite cria uma nova instância do repositório SQLite
+func NewFreteRepositorySQLite(db *gorm.DB) FreteRepository {
+	return &freteRepositorySQLite{db: db}
+}
+
+// faixasOrdenadas carrega as faixas de peso da menor para a maior
+func faixasOrdenadas(db *gorm.DB) *gorm.DB {
+	return db.Order("peso_maximo_gramas")
+}
+
+func (r *freteRepositorySQLite) CreateZona(ctx context.Context, zona *model.FreteZona) error {
+	return translateError(conn(ctx, r.db).Create(zona).Error, "zona de frete")
+}
+
+func (r *freteRepositorySQLite) FindAllZonas(ctx context.Context, opts ListOptions) (*Page[model.FreteZona], error) {
+	page, err := paginate[model.FreteZona](conn(ctx, r.db).Model(&model.FreteZona{}), opts, "Faixas")
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Items {
+		slices.SortFunc(page.Items[i].Faixas, func(a, b model.FreteFaixa) int {
+			return cmp.Compare(a.PesoMaximoGramas, b.PesoMaximoGramas)
+		})
+	}
+	return page, nil
+}
+
+func (r *freteRepositorySQLite) FindZonaByID(ctx context.Context, id uint) (*model.FreteZona, error) {
+	var zona model.FreteZona
+	err := conn(ctx, r.db).Preload("Faixas", faixasOrdenadas).First(&zona, id).Error
+	if err != nil {
+		return nil, translateError(err, "zona de frete")
+	}
+	return &zona, nil
+}
+
+func (r *freteRepositorySQLite) FindZonasAtivas(ctx context.Context, cep string) ([]model.FreteZona, error) {
+	var zonas []model.FreteZona
+	err := conn(ctx, r.db).
+		Preload("Faixas", faixasOrdenadas).
+		Where("ativo = ? AND cep_inicio <= ? AND cep_fim >= ?", true, cep, cep).
+		Order("id").
+		Find(&zonas).Error
+	return zonas, err
+}
+
+func (r *freteRepositorySQLite) UpdateZona(ctx context.Context, zona *model.FreteZona) error {
+	return runInTransaction(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+		if err := updateVersioned(db, zona, &zona.Versao, "zona de frete"); err != nil {
+			return err
+		}
+		if err := db.Where("zona_id = ?", zona.ID).Delete(&model.FreteFaixa{}).Error; err != nil {
+			return err
+		}
+		for i := range zona.Faixas {
+			zona.Faixas[i].ID = 0
+			zona.Faixas[i].ZonaID = zona.ID
+		}
+		if len(zona.Faixas) == 0 {
+			return nil
+		}
+		return db.Create(&zona.Faixas).Error
+	})
+}
+
+func (r *freteRepositorySQLite) DeleteZona(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.FreteZona{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "zona de frete")
+	}
+	return nil
+}
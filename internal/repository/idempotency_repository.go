@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	// Reserve grava a chave como em andamento. Se a chave já existir e não
+	// estiver expirada, nem em andamento com a reserva vencida, nada é gravado
+	// e o registro existente é retornado.
+	Reserve(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	// Complete grava a resposta da requisição original
+	Complete(ctx context.Context, key *model.IdempotencyKey) error
+	// Release remove a reserva, permitindo que a requisição seja repetida
+	Release(ctx context.Context, id uint) error
+	// DeleteExpired remove as chaves expiradas até now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type idempotencyRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepositorySQLite creates a new SQLite implementation of IdempotencyRepository
+func NewIdempotencyRepositorySQLite(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepositorySQLite{db: db}
+}
+
+// Reserve usa o índice único (subject, chave) para que apenas uma requisição
+// concorrente consiga a reserva; as demais recebem o registro existente
+func (r *idempotencyRepositorySQLite) Reserve(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	// uma chave expirada pode ser reutilizada, assim como a reserva de uma
+	// requisição que morreu sem concluí-la
+	agora := time.Now()
+	err := conn(ctx, r.db).
+		Where("subject = ? AND chave = ?", key.Subject, key.Chave).
+		Where("expires_at <= ? OR (status_code = 0 AND reservada_ate <= ?)", agora, agora).
+		Delete(&model.IdempotencyKey{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = translateError(conn(ctx, r.db).Create(key).Error, "idempotency key")
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, apperror.ErrConflict) {
+		return nil, err
+	}
+
+	var existente model.IdempotencyKey
+	result := conn(ctx, r.db).Where("subject = ? AND chave = ?", key.Subject, key.Chave).First(&existente)
+	if result.Error != nil {
+		return nil, translateError(result.Error, "idempotency key")
+	}
+	return &existente, nil
+}
+
+func (r *idempotencyRepositorySQLite) Complete(ctx context.Context, key *model.IdempotencyKey) error {
+	result := conn(ctx, r.db).Model(&model.IdempotencyKey{}).Where("id = ?", key.ID).Updates(map[string]interface{}{
+		"status_code":  key.StatusCode,
+		"content_type": key.ContentType,
+		"cabecalhos":   key.Cabecalhos,
+		"resposta":     key.Resposta,
+	})
+	return result.Error
+}
+
+func (r *idempotencyRepositorySQLite) Release(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.IdempotencyKey{}, id).Error
+}
+
+func (r *idempotencyRepositorySQLite) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// PagamentoRepository define a interface para operações de dados de Pagamento
+type PagamentoRepository interface {
+	Create(ctx context.Context, pagamento *model.Pagamento) error
+	FindByID(ctx context.Context, id uint) (*model.Pagamento, error)
+	// FindByReferencia busca o pagamento pela referência do provedor
+	FindByReferencia(ctx context.Context, referencia string) (*model.Pagamento, error)
+	// FindByPedidoID lista os pagamentos do pedido, com os estornos, do mais antigo ao mais novo
+	FindByPedidoID(ctx context.Context, pedidoID uint) ([]model.Pagamento, error)
+	// FindProvisorios lista os pagamentos pendentes criados antes de antes que
+	// ainda têm a referência provisória, sem resposta registrada do provedor
+	FindProvisorios(ctx context.Context, antes time.Time) ([]model.Pagamento, error)
+	// FindPedidosAQuitar lista os pedidos pendentes ou cancelados com pagamentos
+	// confirmados, que a quitação ainda pode ter de aplicar ou estornar
+	FindPedidosAQuitar(ctx context.Context) ([]uint, error)
+	Update(ctx context.Context, pagamento *model.Pagamento) error
+	AddEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error
+	// UpdateEstorno grava o status e a referência de um estorno conciliado com o provedor
+	UpdateEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error
+	// WithTransaction executa fn em uma única transação; os repositórios chamados
+	// com o contexto recebido por fn participam da mesma unidade de trabalho
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
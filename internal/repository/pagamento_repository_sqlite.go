@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type pagamentoRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewPagamentoRepositorySQLite cria uma nova instância do repositório SQLite
+func NewPagamentoRepositorySQLite(db *gorm.DB) PagamentoRepository {
+	return &pagamentoRepositorySQLite{db: db}
+}
+
+// estornosOrdenados carrega os estornos na ordem em que foram feitos
+func estornosOrdenados(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func (r *pagamentoRepositorySQLite) Create(ctx context.Context, pagamento *model.Pagamento) error {
+	return translateError(conn(ctx, r.db).Create(pagamento).Error, "pagamento")
+}
+
+func (r *pagamentoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Pagamento, error) {
+	var pagamento model.Pagamento
+	err := conn(ctx, r.db).Preload("Estornos", estornosOrdenados).First(&pagamento, id).Error
+	if err != nil {
+		return nil, translateError(err, "pagamento")
+	}
+	return &pagamento, nil
+}
+
+func (r *pagamentoRepositorySQLite) FindByReferencia(ctx context.Context, referencia string) (*model.Pagamento, error) {
+	var pagamento model.Pagamento
+	err := conn(ctx, r.db).
+		Preload("Estornos", estornosOrdenados).
+		Where("referencia = ?", referencia).
+		First(&pagamento).Error
+	if err != nil {
+		return nil, translateError(err, "pagamento")
+	}
+	return &pagamento, nil
+}
+
+func (r *pagamentoRepositorySQLite) FindByPedidoID(ctx context.Context, pedidoID uint) ([]model.Pagamento, error) {
+	pagamentos := []model.Pagamento{}
+	err := conn(ctx, r.db).
+		Preload("Estornos", estornosOrdenados).
+		Where("pedido_id = ?", pedidoID).
+		Order("id").
+		Find(&pagamentos).Error
+	return pagamentos, err
+}
+
+func (r *pagamentoRepositorySQLite) Update(ctx context.Context, pagamento *model.Pagamento) error {
+	return updateVersioned(conn(ctx, r.db), pagamento, &pagamento.Versao, "pagamento")
+}
+
+func (r *pagamentoRepositorySQLite) FindProvisorios(ctx context.Context, antes time.Time) ([]model.Pagamento, error) {
+	pagamentos := []model.Pagamento{}
+	err := conn(ctx, r.db).
+		Where("status = ? AND referencia LIKE ? AND created_at < ?", model.PagamentoPendente, model.PrefixoReferenciaProvisoria+"%", antes).
+		Order("id").
+		Find(&pagamentos).Error
+	return pagamentos, err
+}
+
+func (r *pagamentoRepositorySQLite) FindPedidosAQuitar(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).
+		Model(&model.Pagamento{}).
+		Distinct("pagamentos.pedido_id").
+		Joins("JOIN pedidos ON pedidos.id = pagamentos.pedido_id").
+		Where("pagamentos.status = ?", model.PagamentoConfirmado).
+		Where("pedidos.status IN ? AND pedidos.deleted_at IS NULL", []string{model.StatusPendente, model.StatusCancelado}).
+		Order("pagamentos.pedido_id").
+		Pluck("pagamentos.pedido_id", &ids).Error
+	return ids, err
+}
+
+func (r *pagamentoRepositorySQLite) AddEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error {
+	return conn(ctx, r.db).Create(estorno).Error
+}
+
+func (r *pagamentoRepositorySQLite) UpdateEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error {
+	return conn(ctx, r.db).Model(estorno).Select("status", "referencia").Updates(estorno).Error
+}
+
+func (r *pagamentoRepositorySQLite) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTransaction(ctx, r.db, fn)
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultPageSize é o tamanho de página usado quando o cliente não informa limite
+	DefaultPageSize = 20
+	// MaxPageSize é o maior tamanho de página aceito pelo servidor
+	MaxPageSize = 100
+)
+
+// Visibilidade dos registros excluídos (soft delete) em uma listagem; sem
+// nenhuma delas apenas os registros ativos são listados
+const (
+	// DeletedOnly lista apenas os registros excluídos
+	DeletedOnly = "only"
+	// DeletedInclude lista os registros ativos e os excluídos
+	DeletedInclude = "include"
+)
+
+// ListOptions define a janela de resultados de uma listagem. Quando AfterID é
+// informado a paginação é por cursor (registros depois do registro AfterID,
+// cujos valores das colunas de Sort são AfterKeys); caso contrário Offset é
+// usado para paginação por página. Filters e Sort restringem e ordenam a
+// listagem; sem Sort a ordem é pelo ID. Deleted (DeletedOnly ou
+// DeletedInclude) inclui os registros excluídos.
+type ListOptions struct {
+	Limit     int
+	AfterID   uint
+	AfterKeys []interface{}
+	Offset    int
+	Filters   []Filter
+	Sort      []Sort
+	Deleted   string
+}
+
+// Normalize aplica o tamanho padrão e o limite máximo de página
+func (o ListOptions) Normalize() ListOptions {
+	if o.Limit <= 0 {
+		o.Limit = DefaultPageSize
+	}
+	if o.Limit > MaxPageSize {
+		o.Limit = MaxPageSize
+	}
+	if o.Offset < 0 {
+		o.Offset = 0
+	}
+	return o
+}
+
+// Page é uma página de resultados de uma listagem. LastKeys traz os valores
+// das colunas de ordenação do último item, usados no cursor da próxima página.
+type Page[T any] struct {
+	Items    []T
+	Total    int64
+	HasMore  bool
+	LastKeys []interface{}
+}
+
+// paginate conta os registros de base que atendem aos filtros e busca a janela
+// definida por opts, ordenada pelos critérios de opts e pelo ID para que o
+// cursor seja estável. O preload é aplicado apenas na busca dos itens, não na
+// contagem.
+func paginate[T any](base *gorm.DB, opts ListOptions, preloads ...string) (*Page[T], error) {
+	opts = opts.Normalize()
+	base = withDeleted(base, opts.Deleted).Scopes(filtered(opts.Filters))
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := base.Session(&gorm.Session{})
+	for _, p := range preloads {
+		query = query.Preload(p)
+	}
+	if opts.AfterID > 0 {
+		query = query.Scopes(after(opts.Sort, opts.AfterKeys, opts.AfterID))
+	} else if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	// busca um registro a mais para saber se existe próxima página
+	items := make([]T, 0, opts.Limit+1)
+	result := query.Scopes(ordered(opts.Sort)).Limit(opts.Limit + 1).Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	page := &Page[T]{Items: items, Total: total, HasMore: len(items) > opts.Limit}
+	if page.HasMore {
+		page.Items = items[:opts.Limit]
+	}
+	if len(opts.Sort) > 0 && len(page.Items) > 0 {
+		page.LastKeys = sortKeys(result, opts.Sort, &page.Items[len(page.Items)-1])
+	}
+
+	return page, nil
+}
+
+// withDeleted remove o filtro de soft delete da consulta conforme deleted;
+// com DeletedOnly restam apenas os registros excluídos
+func withDeleted(db *gorm.DB, deleted string) *gorm.DB {
+	switch deleted {
+	case DeletedInclude:
+		return db.Unscoped()
+	case DeletedOnly:
+		return db.Unscoped().Where(clause.Neq{Column: column("deleted_at"), Value: nil})
+	}
+	return db
+}
+
+// sortKeys lê do item os valores das colunas de ordenação. Colunas opcionais
+// (ponteiros) são desreferenciadas e ficam nil quando não têm valor.
+func sortKeys(db *gorm.DB, sorts []Sort, item interface{}) []interface{} {
+	keys := make([]interface{}, len(sorts))
+	valor := reflect.ValueOf(item).Elem()
+	for i, s := range sorts {
+		field := db.Statement.Schema.LookUpField(s.Column)
+		if field == nil {
+			continue
+		}
+		v, zero := field.ValueOf(db.Statement.Context, valor)
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+			if zero || rv.IsNil() {
+				continue
+			}
+			v = rv.Elem().Interface()
+		}
+		keys[i] = v
+	}
+	return keys
+}
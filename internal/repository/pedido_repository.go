@@ -9,11 +9,26 @@ import (
 // PedidoRepository define a interface para operações de dados de Pedido
 type PedidoRepository interface {
 	Create(ctx context.Context, pedido *model.Pedido) error
-	FindAll(ctx context.Context) ([]model.Pedido, error)
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.Pedido], error)
 	FindByID(ctx context.Context, id uint) (*model.Pedido, error)
-	FindByClienteID(ctx context.Context, clienteID uint) ([]model.Pedido, error)
-	FindByStatus(ctx context.Context, status string) ([]model.Pedido, error)
+	FindByClienteID(ctx context.Context, clienteID uint, opts ListOptions) (*Page[model.Pedido], error)
+	FindByStatus(ctx context.Context, status string, opts ListOptions) (*Page[model.Pedido], error)
 	Update(ctx context.Context, pedido *model.Pedido) error
 	Delete(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
+	AddHistoricoStatus(ctx context.Context, historico *model.PedidoStatusHistorico) error
+	FindHistoricoStatus(ctx context.Context, pedidoID uint) ([]model.PedidoStatusHistorico, error)
+	// UpdateItens grava o pedido recalculado junto com os seus itens, impostos
+	// e descontos; itens que não estão mais em pedido.Itens são removidos
+	UpdateItens(ctx context.Context, pedido *model.Pedido) error
+	// CountPagamentosEmAberto conta os pagamentos do pedido pendentes ou
+	// confirmados, que dependem do valor total atual do pedido
+	CountPagamentosEmAberto(ctx context.Context, pedidoID uint) (int64, error)
+	// AddDevolucao grava a devolução junto com os seus itens
+	AddDevolucao(ctx context.Context, devolucao *model.PedidoDevolucao) error
+	// UpdateReembolsado grava o valor reembolsado de uma devolução já registrada
+	UpdateReembolsado(ctx context.Context, devolucao *model.PedidoDevolucao) error
+	// WithTransaction executa fn em uma única transação; os repositórios chamados
+	// com o contexto recebido por fn participam da mesma unidade de trabalho
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -2,10 +2,10 @@ package repository
 
 import (
 	"context"
-	"errors"
 
 	"github.com/danmaciel/api/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type pedidoRepositorySQLite struct {
@@ -18,81 +18,147 @@ func NewPedidoRepositorySQLite(db *gorm.DB) PedidoRepository {
 }
 
 func (r *pedidoRepositorySQLite) Create(ctx context.Context, pedido *model.Pedido) error {
-	return r.db.WithContext(ctx).Create(pedido).Error
+	return translateError(conn(ctx, r.db).Create(pedido).Error, "pedido")
 }
 
-func (r *pedidoRepositorySQLite) FindAll(ctx context.Context) ([]model.Pedido, error) {
-	var pedidos []model.Pedido
-	err := r.db.WithContext(ctx).
-		Preload("Cliente").
-		Preload("Itens").
-		Preload("Itens.Produto").
-		Find(&pedidos).Error
-	return pedidos, err
+// pedidoPreloads são os relacionamentos carregados junto com cada pedido
+var pedidoPreloads = []string{"Cliente", "Itens", "Itens.Produto", "Itens.Impostos", "Descontos", "Devolucoes", "Devolucoes.Itens"}
+
+func (r *pedidoRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.Pedido], error) {
+	return paginate[model.Pedido](conn(ctx, r.db).Model(&model.Pedido{}), opts, pedidoPreloads...)
+}
+
+// devolucoesOrdenadas carrega as devoluções na ordem em que foram feitas
+func devolucoesOrdenadas(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
 }
 
 func (r *pedidoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Pedido, error) {
 	var pedido model.Pedido
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Preload("Cliente").
 		Preload("Itens").
 		Preload("Itens.Produto").
+		Preload("Itens.Impostos").
+		Preload("Descontos").
+		Preload("Devolucoes", devolucoesOrdenadas).
+		Preload("Devolucoes.Itens").
 		First(&pedido, id).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("pedido not found")
-		}
-		return nil, err
+		return nil, translateError(err, "pedido")
 	}
 	return &pedido, nil
 }
 
-func (r *pedidoRepositorySQLite) FindByClienteID(ctx context.Context, clienteID uint) ([]model.Pedido, error) {
-	var pedidos []model.Pedido
-	err := r.db.WithContext(ctx).
-		Preload("Cliente").
-		Preload("Itens").
-		Preload("Itens.Produto").
-		Where("cliente_id = ?", clienteID).
-		Find(&pedidos).Error
-	return pedidos, err
+func (r *pedidoRepositorySQLite) FindByClienteID(ctx context.Context, clienteID uint, opts ListOptions) (*Page[model.Pedido], error) {
+	query := conn(ctx, r.db).Model(&model.Pedido{}).Where("cliente_id = ?", clienteID)
+	return paginate[model.Pedido](query, opts, pedidoPreloads...)
 }
 
-func (r *pedidoRepositorySQLite) FindByStatus(ctx context.Context, status string) ([]model.Pedido, error) {
-	var pedidos []model.Pedido
-	err := r.db.WithContext(ctx).
-		Preload("Cliente").
-		Preload("Itens").
-		Preload("Itens.Produto").
-		Where("status = ?", status).
-		Find(&pedidos).Error
-	return pedidos, err
+func (r *pedidoRepositorySQLite) FindByStatus(ctx context.Context, status string, opts ListOptions) (*Page[model.Pedido], error) {
+	query := conn(ctx, r.db).Model(&model.Pedido{}).Where("status = ?", status)
+	return paginate[model.Pedido](query, opts, pedidoPreloads...)
 }
 
 func (r *pedidoRepositorySQLite) Update(ctx context.Context, pedido *model.Pedido) error {
-	result := r.db.WithContext(ctx).Save(pedido)
-	if result.Error != nil {
-		return result.Error
+	return updateVersioned(conn(ctx, r.db), pedido, &pedido.Versao, "pedido")
+}
+
+func (r *pedidoRepositorySQLite) UpdateItens(ctx context.Context, pedido *model.Pedido) error {
+	db := conn(ctx, r.db)
+	if err := updateVersioned(db, pedido, &pedido.Versao, "pedido"); err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("pedido not found")
+
+	// Impostos e descontos são recalculados por inteiro e gravados de novo
+	itensDoPedido := db.Model(&model.PedidoProduto{}).Select("id").Where("pedido_id = ?", pedido.ID)
+	if err := db.Where("pedido_produto_id IN (?)", itensDoPedido).Delete(&model.PedidoItemImposto{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("pedido_id = ?", pedido.ID).Delete(&model.PedidoDesconto{}).Error; err != nil {
+		return err
+	}
+
+	ids := make([]uint, 0, len(pedido.Itens))
+	for i := range pedido.Itens {
+		item := &pedido.Itens[i]
+		item.PedidoID = pedido.ID
+		if err := db.Omit(clause.Associations).Save(item).Error; err != nil {
+			return translateError(err, "item do pedido")
+		}
+		ids = append(ids, item.ID)
+
+		for j := range item.Impostos {
+			item.Impostos[j].ID = 0
+			item.Impostos[j].PedidoProdutoID = item.ID
+		}
+		if len(item.Impostos) > 0 {
+			if err := db.Create(&item.Impostos).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := db.Where("pedido_id = ? AND id NOT IN ?", pedido.ID, ids).Delete(&model.PedidoProduto{}).Error; err != nil {
+		return err
+	}
+
+	for i := range pedido.Descontos {
+		pedido.Descontos[i].ID = 0
+		pedido.Descontos[i].PedidoID = pedido.ID
+	}
+	if len(pedido.Descontos) > 0 {
+		return db.Create(&pedido.Descontos).Error
 	}
 	return nil
 }
 
 func (r *pedidoRepositorySQLite) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&model.Pedido{}, id)
+	result := conn(ctx, r.db).Delete(&model.Pedido{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("pedido not found")
+		return translateError(gorm.ErrRecordNotFound, "pedido")
 	}
 	return nil
 }
 
 func (r *pedidoRepositorySQLite) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.Pedido{}).Count(&count).Error
+	err := conn(ctx, r.db).Model(&model.Pedido{}).Count(&count).Error
+	return count, err
+}
+
+func (r *pedidoRepositorySQLite) AddHistoricoStatus(ctx context.Context, historico *model.PedidoStatusHistorico) error {
+	return conn(ctx, r.db).Create(historico).Error
+}
+
+func (r *pedidoRepositorySQLite) FindHistoricoStatus(ctx context.Context, pedidoID uint) ([]model.PedidoStatusHistorico, error) {
+	var historico []model.PedidoStatusHistorico
+	err := conn(ctx, r.db).
+		Where("pedido_id = ?", pedidoID).
+		Order("created_at, id").
+		Find(&historico).Error
+	return historico, err
+}
+
+func (r *pedidoRepositorySQLite) CountPagamentosEmAberto(ctx context.Context, pedidoID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Pagamento{}).
+		Where("pedido_id = ? AND status IN ?", pedidoID, []string{model.PagamentoPendente, model.PagamentoConfirmado}).
+		Count(&count).Error
 	return count, err
 }
+
+func (r *pedidoRepositorySQLite) AddDevolucao(ctx context.Context, devolucao *model.PedidoDevolucao) error {
+	return conn(ctx, r.db).Create(devolucao).Error
+}
+
+func (r *pedidoRepositorySQLite) UpdateReembolsado(ctx context.Context, devolucao *model.PedidoDevolucao) error {
+	return conn(ctx, r.db).Model(devolucao).Update("reembolsado", devolucao.Reembolsado).Error
+}
+
+func (r *pedidoRepositorySQLite) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTransaction(ctx, r.db, fn)
+}
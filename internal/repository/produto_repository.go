@@ -3,18 +3,35 @@ package repository
 import (
 	"context"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/model"
 )
 
+// ErrEstoqueInsuficiente indica que o produto não possui estoque para a baixa solicitada
+var ErrEstoqueInsuficiente = apperror.BusinessRule("estoque insuficiente")
+
 // ProdutoRepository define a interface para operações de dados de Produto
 type ProdutoRepository interface {
 	Create(ctx context.Context, produto *model.Produto) error
-	FindAll(ctx context.Context) ([]model.Produto, error)
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.Produto], error)
 	FindByID(ctx context.Context, id uint) (*model.Produto, error)
-	FindByName(ctx context.Context, nome string) ([]model.Produto, error)
+	FindByName(ctx context.Context, nome string, opts ListOptions) (*Page[model.Produto], error)
 	FindBySKU(ctx context.Context, sku string) (*model.Produto, error)
-	FindByCategoria(ctx context.Context, categoria string) ([]model.Produto, error)
+	FindByCategoria(ctx context.Context, categoria string, opts ListOptions) (*Page[model.Produto], error)
 	Update(ctx context.Context, produto *model.Produto) error
 	Delete(ctx context.Context, id uint) error
+	// Restore desfaz a exclusão de um produto excluído
+	Restore(ctx context.Context, id uint) error
+	// Purge remove definitivamente o produto, excluído ou não
+	Purge(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
+	// CountPedidos conta os pedidos que têm o produto entre os itens; os
+	// excluídos (soft delete) só entram com incluirExcluidos, pois os itens
+	// deles ainda referenciam o produto no banco
+	CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error)
+	// DecrementEstoque baixa a quantidade do estoque de forma atômica, falhando
+	// com ErrEstoqueInsuficiente se o saldo não for suficiente
+	DecrementEstoque(ctx context.Context, id uint, quantidade int) error
+	// IncrementEstoque devolve a quantidade ao estoque do produto
+	IncrementEstoque(ctx context.Context, id uint, quantidade int) error
 }
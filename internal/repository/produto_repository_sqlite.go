@@ -18,36 +18,30 @@ func NewProdutoRepositorySQLite(db *gorm.DB) ProdutoRepository {
 }
 
 func (r *produtoRepositorySQLite) Create(ctx context.Context, produto *model.Produto) error {
-	return r.db.WithContext(ctx).Create(produto).Error
+	return translateError(conn(ctx, r.db).Create(produto).Error, "produto")
 }
 
-func (r *produtoRepositorySQLite) FindAll(ctx context.Context) ([]model.Produto, error) {
-	var produtos []model.Produto
-	err := r.db.WithContext(ctx).Find(&produtos).Error
-	return produtos, err
+func (r *produtoRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.Produto], error) {
+	return paginate[model.Produto](conn(ctx, r.db).Model(&model.Produto{}), opts)
 }
 
 func (r *produtoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.Produto, error) {
 	var produto model.Produto
-	err := r.db.WithContext(ctx).First(&produto, id).Error
+	err := conn(ctx, r.db).First(&produto, id).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("produto not found")
-		}
-		return nil, err
+		return nil, translateError(err, "produto")
 	}
 	return &produto, nil
 }
 
-func (r *produtoRepositorySQLite) FindByName(ctx context.Context, nome string) ([]model.Produto, error) {
-	var produtos []model.Produto
-	err := r.db.WithContext(ctx).Where("nome LIKE ?", "%"+nome+"%").Find(&produtos).Error
-	return produtos, err
+func (r *produtoRepositorySQLite) FindByName(ctx context.Context, nome string, opts ListOptions) (*Page[model.Produto], error) {
+	query := conn(ctx, r.db).Model(&model.Produto{}).Where("nome LIKE ?", "%"+nome+"%")
+	return paginate[model.Produto](query, opts)
 }
 
 func (r *produtoRepositorySQLite) FindBySKU(ctx context.Context, sku string) (*model.Produto, error) {
 	var produto model.Produto
-	err := r.db.WithContext(ctx).Where("sku = ?", sku).First(&produto).Error
+	err := conn(ctx, r.db).Where("sku = ?", sku).First(&produto).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // SKU não encontrado não é erro
@@ -57,36 +51,83 @@ func (r *produtoRepositorySQLite) FindBySKU(ctx context.Context, sku string) (*m
 	return &produto, nil
 }
 
-func (r *produtoRepositorySQLite) FindByCategoria(ctx context.Context, categoria string) ([]model.Produto, error) {
-	var produtos []model.Produto
-	err := r.db.WithContext(ctx).Where("categoria = ?", categoria).Find(&produtos).Error
-	return produtos, err
+func (r *produtoRepositorySQLite) FindByCategoria(ctx context.Context, categoria string, opts ListOptions) (*Page[model.Produto], error) {
+	query := conn(ctx, r.db).Model(&model.Produto{}).Where("categoria = ?", categoria)
+	return paginate[model.Produto](query, opts)
 }
 
 func (r *produtoRepositorySQLite) Update(ctx context.Context, produto *model.Produto) error {
-	result := r.db.WithContext(ctx).Save(produto)
+	return updateVersioned(conn(ctx, r.db), produto, &produto.Versao, "produto")
+}
+
+func (r *produtoRepositorySQLite) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.Produto{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("produto not found")
+		return translateError(gorm.ErrRecordNotFound, "produto")
 	}
 	return nil
 }
 
-func (r *produtoRepositorySQLite) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&model.Produto{}, id)
+func (r *produtoRepositorySQLite) Restore(ctx context.Context, id uint) error {
+	return restore(conn(ctx, r.db), &model.Produto{}, id, "produto")
+}
+
+func (r *produtoRepositorySQLite) Purge(ctx context.Context, id uint) error {
+	return purge(conn(ctx, r.db), &model.Produto{}, id, "produto")
+}
+
+func (r *produtoRepositorySQLite) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Produto{}).Count(&count).Error
+	return count, err
+}
+
+func (r *produtoRepositorySQLite) CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error) {
+	db := conn(ctx, r.db)
+	if incluirExcluidos {
+		db = db.Unscoped()
+	}
+	var count int64
+	err := db.
+		Model(&model.Pedido{}).
+		Where("id IN (?)", conn(ctx, r.db).Model(&model.PedidoProduto{}).Select("pedido_id").Where("produto_id = ?", id)).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *produtoRepositorySQLite) DecrementEstoque(ctx context.Context, id uint, quantidade int) error {
+	// a condição no WHERE garante que duas transações concorrentes não consumam o mesmo saldo
+	result := conn(ctx, r.db).
+		Model(&model.Produto{}).
+		Where("id = ? AND estoque >= ?", id, quantidade).
+		Updates(map[string]interface{}{"estoque": gorm.Expr("estoque - ?", quantidade), "versao": incrementVersao})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("produto not found")
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrEstoqueInsuficiente
 	}
 	return nil
 }
 
-func (r *produtoRepositorySQLite) Count(ctx context.Context) (int64, error) {
-	var count int64
-	err := r.db.WithContext(ctx).Model(&model.Produto{}).Count(&count).Error
-	return count, err
+func (r *produtoRepositorySQLite) IncrementEstoque(ctx context.Context, id uint, quantidade int) error {
+	// Unscoped permite devolver o estoque mesmo que o produto tenha sido removido depois da venda
+	result := conn(ctx, r.db).
+		Unscoped().
+		Model(&model.Produto{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"estoque": gorm.Expr("estoque + ?", quantidade), "versao": incrementVersao})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "produto")
+	}
+	return nil
 }
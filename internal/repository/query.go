@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Operadores aceitos nos filtros das listagens
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpIn   = "in"
+	OpLike = "like"
+)
+
+// Filter é uma condição sobre uma coluna. Column deve vir da whitelist da
+// entidade; os valores já convertidos para o tipo da coluna são sempre
+// enviados como parâmetros da query.
+type Filter struct {
+	Column string
+	Op     string
+	Values []interface{}
+}
+
+// Sort é um critério de ordenação sobre uma coluna da whitelist da entidade
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// column referencia a coluna na tabela principal da query
+func column(name string) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: name}
+}
+
+// expression traduz o filtro para a expressão GORM equivalente
+func (f Filter) expression() clause.Expression {
+	col := column(f.Column)
+	switch f.Op {
+	case OpNe:
+		return clause.Neq{Column: col, Value: f.Values[0]}
+	case OpGt:
+		return clause.Gt{Column: col, Value: f.Values[0]}
+	case OpGte:
+		return clause.Gte{Column: col, Value: f.Values[0]}
+	case OpLt:
+		return clause.Lt{Column: col, Value: f.Values[0]}
+	case OpLte:
+		return clause.Lte{Column: col, Value: f.Values[0]}
+	case OpIn:
+		return clause.IN{Column: col, Values: f.Values}
+	case OpLike:
+		return clause.Like{Column: col, Value: "%" + f.Values[0].(string) + "%"}
+	default:
+		return clause.Eq{Column: col, Value: f.Values[0]}
+	}
+}
+
+// filtered aplica os filtros da listagem como condições AND
+func filtered(filters []Filter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, f := range filters {
+			db = db.Where(f.expression())
+		}
+		return db
+	}
+}
+
+// ordered ordena pelos critérios informados e, por último, pelo ID, que
+// desempata registros com os mesmos valores e mantém o cursor estável
+func ordered(sorts []Sort) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, s := range sorts {
+			db = db.Order(clause.OrderByColumn{Column: column(s.Column), Desc: s.Desc})
+		}
+		return db.Order(clause.OrderByColumn{Column: column("id")})
+	}
+}
+
+// after restringe a busca aos registros posteriores ao cursor na ordenação
+// (sorts..., id). Para a ordenação (a, b) a condição é
+// a > va OR (a = va AND b > vb) OR (a = va AND b = vb AND id > vid),
+// com < nas colunas em ordem decrescente. Chaves nulas seguem a ordem do
+// SQLite, em que NULL vem antes de qualquer valor na ordem crescente e depois
+// na decrescente.
+func after(sorts []Sort, keys []interface{}, id uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		alternativas := make([]clause.Expression, 0, len(sorts)+1)
+		iguais := make([]clause.Expression, 0, len(sorts)+1)
+		for i, s := range sorts {
+			col := column(s.Column)
+			if depois := posteriores(col, keys[i], s.Desc); depois != nil {
+				alternativas = append(alternativas, clause.And(append(slices.Clip(iguais), depois)...))
+			}
+			// com valor nil, clause.Eq gera IS NULL
+			iguais = append(iguais, clause.Eq{Column: col, Value: keys[i]})
+		}
+		alternativas = append(alternativas, clause.And(append(iguais, clause.Gt{Column: column("id"), Value: id})...))
+		return db.Where(clause.Or(alternativas...))
+	}
+}
+
+// posteriores devolve a condição dos valores da coluna que vêm depois de
+// chave na ordenação, ou nil se nenhum valor vem depois dela (NULL em ordem
+// decrescente)
+func posteriores(col clause.Column, chave interface{}, desc bool) clause.Expression {
+	switch {
+	case chave == nil && desc:
+		return nil
+	case chave == nil:
+		return clause.Neq{Column: col, Value: nil}
+	case desc:
+		return clause.Or(clause.Lt{Column: col, Value: chave}, clause.Eq{Column: col, Value: nil})
+	default:
+		return clause.Gt{Column: col, Value: chave}
+	}
+}
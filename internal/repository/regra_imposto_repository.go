@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/model"
+)
+
+// RegraImpostoRepository define a interface para operações de dados de RegraImposto
+type RegraImpostoRepository interface {
+	Create(ctx context.Context, regra *model.RegraImposto) error
+	FindAll(ctx context.Context, opts ListOptions) (*Page[model.RegraImposto], error)
+	FindByID(ctx context.Context, id uint) (*model.RegraImposto, error)
+	// FindByChave retorna a regra do imposto para a categoria e a UF, ou nil,
+	// sem erro, se não houver
+	FindByChave(ctx context.Context, imposto, categoria, uf string) (*model.RegraImposto, error)
+	// FindAtivas retorna as regras ativas que podem valer para a UF de destino:
+	// as da própria UF e as sem UF
+	FindAtivas(ctx context.Context, uf string) ([]model.RegraImposto, error)
+	Update(ctx context.Context, regra *model.RegraImposto) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danmaciel/api/internal/model"
+	"gorm.io/gorm"
+)
+
+type regraImpostoRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewRegraImpostoRepositorySQLite cria uma nova instância do repositório SQLite
+func NewRegraImpostoRepositorySQLite(db *gorm.DB) RegraImpostoRepository {
+	return &regraImpostoRepositorySQLite{db: db}
+}
+
+func (r *regraImpostoRepositorySQLite) Create(ctx context.Context, regra *model.RegraImposto) error {
+	return translateError(conn(ctx, r.db).Create(regra).Error, "regra de imposto")
+}
+
+func (r *regraImpostoRepositorySQLite) FindAll(ctx context.Context, opts ListOptions) (*Page[model.RegraImposto], error) {
+	return paginate[model.RegraImposto](conn(ctx, r.db).Model(&model.RegraImposto{}), opts)
+}
+
+func (r *regraImpostoRepositorySQLite) FindByID(ctx context.Context, id uint) (*model.RegraImposto, error) {
+	var regra model.RegraImposto
+	err := conn(ctx, r.db).First(&regra, id).Error
+	if err != nil {
+		return nil, translateError(err, "regra de imposto")
+	}
+	return &regra, nil
+}
+
+func (r *regraImpostoRepositorySQLite) FindByChave(ctx context.Context, imposto, categoria, uf string) (*model.RegraImposto, error) {
+	var regra model.RegraImposto
+	err := conn(ctx, r.db).
+		Where("imposto = ? AND categoria = ? AND uf = ?", imposto, categoria, uf).
+		First(&regra).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // chave livre não é erro
+		}
+		return nil, err
+	}
+	return &regra, nil
+}
+
+func (r *regraImpostoRepositorySQLite) FindAtivas(ctx context.Context, uf string) ([]model.RegraImposto, error) {
+	var regras []model.RegraImposto
+	err := conn(ctx, r.db).
+		Where("ativo = ? AND uf IN ?", true, []string{uf, ""}).
+		Order("id").
+		Find(&regras).Error
+	return regras, err
+}
+
+func (r *regraImpostoRepositorySQLite) Update(ctx context.Context, regra *model.RegraImposto) error {
+	return updateVersioned(conn(ctx, r.db), regra, &regra.Versao, "regra de imposto")
+}
+
+func (r *regraImpostoRepositorySQLite) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&model.RegraImposto{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "regra de imposto")
+	}
+	return nil
+}
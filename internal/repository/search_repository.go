@@ -0,0 +1,44 @@
+package repository
+
+import "context"
+
+// Tipos de entidade indexados pela busca textual
+const (
+	SearchProdutos = "produtos"
+	SearchClientes = "clientes"
+)
+
+// SearchTipos lista os tipos de entidade que podem ser buscados
+var SearchTipos = []string{SearchClientes, SearchProdutos}
+
+// Marcadores que delimitam os termos encontrados em SearchHit.Titulo e
+// SearchHit.Trecho. São caracteres de controle para que o serviço possa
+// escapar o texto antes de trocá-los pela marcação final.
+const (
+	HighlightOpen  = "\x02"
+	HighlightClose = "\x03"
+)
+
+// SearchOptions define uma busca textual
+type SearchOptions struct {
+	// Termos já normalizados; cada um casa também como prefixo (autocomplete)
+	Termos []string
+	// Tipos de entidade a buscar, entre SearchTipos
+	Tipos []string
+	Limit int
+}
+
+// SearchHit é um resultado da busca textual, do mais relevante para o menos
+type SearchHit struct {
+	Tipo   string
+	ID     uint
+	Titulo string
+	Trecho string
+	// Rank é o bm25 do resultado; quanto menor, mais relevante
+	Rank float64
+}
+
+// SearchRepository define a interface da busca textual em produtos e clientes
+type SearchRepository interface {
+	Search(ctx context.Context, opts SearchOptions) ([]SearchHit, error)
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// indiceBusca descreve a tabela FTS5 de uma entidade. O índice usa a própria
+// tabela como conteúdo externo (content=), então só guarda os tokens; triggers
+// o mantêm em sincronia com inserções, alterações e exclusões.
+type indiceBusca struct {
+	tabela string
+	// colunas indexadas; a primeira é o título do resultado
+	colunas []string
+	// pesos do bm25 para cada coluna, na mesma ordem
+	pesos []float64
+	// titulos é quantas das primeiras colunas podem ser o título: vale a
+	// primeira preenchida. Zero equivale a um, só a primeira coluna.
+	titulos int
+}
+
+func (i indiceBusca) fts() string {
+	return i.tabela + "_fts"
+}
+
+var indicesBusca = map[string]indiceBusca{
+	SearchProdutos: {tabela: "produtos", colunas: []string{"nome", "descricao", "sku", "categoria"}, pesos: []float64{10, 2, 5, 3}},
+	// clientes PF têm nome e clientes PJ razão social, então o título é o que estiver preenchido
+	SearchClientes: {
+		tabela:  "clientes",
+		colunas: []string{"nome", "razao_social", "nome_fantasia", "email", "cpf", "cnpj", "telefone"},
+		pesos:   []float64{10, 10, 8, 5, 5, 5, 2},
+		titulos: 2,
+	},
+}
+
+// tokenizerBusca ignora maiúsculas e acentos ("Joao" encontra "João") e os
+// índices de prefixo aceleram as buscas de autocomplete
+const tokenizerBusca = `tokenize='unicode61 remove_diacritics 2', prefix='2 3'`
+
+// FTS5Available indica se o SQLite em uso foi compilado com FTS5 (no
+// go-sqlite3, com a build tag sqlite_fts5)
+func FTS5Available(db *gorm.DB) bool {
+	var usado int
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&usado).Error; err != nil {
+		return false
+	}
+	return usado == 1
+}
+
+// CreateSearchIndex cria as tabelas FTS5 e os triggers da busca textual. É
+// idempotente; um índice criado agora é populado com os registros existentes.
+// Um índice com colunas diferentes das atuais é recriado.
+func CreateSearchIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, tipo := range SearchTipos {
+			indice := indicesBusca[tipo]
+			novo := !tx.Migrator().HasTable(indice.fts())
+
+			if !novo {
+				desatualizado, err := indice.desatualizado(tx)
+				if err != nil {
+					return fmt.Errorf("índice de busca de %s: %w", indice.tabela, err)
+				}
+				if desatualizado {
+					if err := indice.remover(tx); err != nil {
+						return fmt.Errorf("índice de busca de %s: %w", indice.tabela, err)
+					}
+					novo = true
+				}
+			}
+
+			for _, sql := range indice.ddl() {
+				if err := tx.Exec(sql).Error; err != nil {
+					return fmt.Errorf("índice de busca de %s: %w", indice.tabela, err)
+				}
+			}
+			if novo {
+				rebuild := fmt.Sprintf("INSERT INTO %s(%s) VALUES('rebuild')", indice.fts(), indice.fts())
+				if err := tx.Exec(rebuild).Error; err != nil {
+					return fmt.Errorf("índice de busca de %s: %w", indice.tabela, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// desatualizado indica se a tabela FTS5 existente indexa outras colunas
+func (i indiceBusca) desatualizado(tx *gorm.DB) (bool, error) {
+	var colunas []string
+	if err := tx.Raw("SELECT name FROM pragma_table_info(?) ORDER BY cid", i.fts()).Scan(&colunas).Error; err != nil {
+		return false, err
+	}
+	return strings.Join(colunas, ",") != strings.Join(i.colunas, ","), nil
+}
+
+// remover apaga a tabela FTS5 e os triggers, para recriá-los com as colunas atuais
+func (i indiceBusca) remover(tx *gorm.DB) error {
+	fts := i.fts()
+	for _, sql := range []string{
+		"DROP TRIGGER IF EXISTS " + fts + "_ai",
+		"DROP TRIGGER IF EXISTS " + fts + "_ad",
+		"DROP TRIGGER IF EXISTS " + fts + "_au",
+		"DROP TABLE IF EXISTS " + fts,
+	} {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ddl retorna a tabela FTS5 e os triggers que a mantêm atualizada. Alterações
+// que não tocam as colunas indexadas (estoque, versão) não reindexam a linha.
+func (i indiceBusca) ddl() []string {
+	fts := i.fts()
+	colunas := strings.Join(i.colunas, ", ")
+	valores := func(prefixo string) string {
+		v := make([]string, len(i.colunas))
+		for n, c := range i.colunas {
+			v[n] = prefixo + "." + c
+		}
+		return strings.Join(v, ", ")
+	}
+	inserir := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (new.id, %s);", fts, colunas, valores("new"))
+	remover := fmt.Sprintf("INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.id, %s);", fts, fts, colunas, valores("old"))
+
+	return []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='id', %s)",
+			fts, colunas, i.tabela, tokenizerBusca),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_ai AFTER INSERT ON %s BEGIN %s END",
+			fts, i.tabela, inserir),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_ad AFTER DELETE ON %s BEGIN %s END",
+			fts, i.tabela, remover),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_au AFTER UPDATE OF %s ON %s BEGIN %s %s END",
+			fts, colunas, i.tabela, remover, inserir),
+	}
+}
+
+// consulta monta o SELECT de um tipo, ordenável pela coluna rank
+func (i indiceBusca) consulta(tipo string) string {
+	fts := i.fts()
+	pesos := make([]string, len(i.pesos))
+	for n, p := range i.pesos {
+		pesos[n] = fmt.Sprint(p)
+	}
+	// registros excluídos (soft delete) continuam no índice e são descartados pelo JOIN
+	return fmt.Sprintf(`SELECT '%s' AS tipo, t.id AS id,
+		%s AS titulo,
+		snippet(%s, -1, ?, ?, '…', 12) AS trecho,
+		bm25(%s, %s) AS rank
+		FROM %s JOIN %s t ON t.id = %s.rowid
+		WHERE %s MATCH ? AND t.deleted_at IS NULL`,
+		tipo, i.titulo(), fts, fts, strings.Join(pesos, ", "), fts, i.tabela, fts, fts)
+}
+
+// titulo destaca a primeira coluna de título preenchida
+func (i indiceBusca) titulo() string {
+	candidatas := make([]string, max(i.titulos, 1))
+	for n := range candidatas {
+		candidatas[n] = fmt.Sprintf("NULLIF(highlight(%s, %d, ?, ?), '')", i.fts(), n)
+	}
+	return "COALESCE(" + strings.Join(candidatas, ", ") + ", '')"
+}
+
+// argumentos retorna os parâmetros da consulta, na ordem dos placeholders
+func (i indiceBusca) argumentos(match string) []interface{} {
+	var args []interface{}
+	for n := 0; n < max(i.titulos, 1); n++ {
+		args = append(args, HighlightOpen, HighlightClose)
+	}
+	return append(args, HighlightOpen, HighlightClose, match)
+}
+
+type searchRepositorySQLite struct {
+	db *gorm.DB
+}
+
+// NewSearchRepositorySQLite cria uma nova instância do repositório de busca
+func NewSearchRepositorySQLite(db *gorm.DB) SearchRepository {
+	return &searchRepositorySQLite{db: db}
+}
+
+func (r *searchRepositorySQLite) Search(ctx context.Context, opts SearchOptions) ([]SearchHit, error) {
+	db := conn(ctx, r.db)
+	match := matchExpression(opts.Termos)
+
+	var consultas []string
+	var args []interface{}
+	for _, tipo := range opts.Tipos {
+		indice, ok := indicesBusca[tipo]
+		if !ok {
+			return nil, fmt.Errorf("tipo de busca desconhecido: %s", tipo)
+		}
+		if !db.Migrator().HasTable(indice.fts()) {
+			return nil, apperror.Unavailable("busca textual indisponível: o SQLite foi compilado sem FTS5")
+		}
+		consultas = append(consultas, indice.consulta(tipo))
+		args = append(args, indice.argumentos(match)...)
+	}
+	args = append(args, opts.Limit)
+
+	sql := "SELECT * FROM (" + strings.Join(consultas, " UNION ALL ") + ") ORDER BY rank, tipo, id LIMIT ?"
+	hits := []SearchHit{}
+	if err := db.Raw(sql, args...).Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// matchExpression monta a consulta FTS5 exigindo todos os termos, cada um
+// como palavra inteira ou prefixo. A palavra inteira repetida no OR pontua
+// de novo no bm25, de modo que "cafe" traz "Café" antes de "Cafeteira". Os
+// termos vão entre aspas para que nenhum seja lido como operador (AND, OR,
+// NOT, NEAR).
+func matchExpression(termos []string) string {
+	partes := make([]string, len(termos))
+	for n, t := range termos {
+		t = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+		partes[n] = "(" + t + " OR " + t + "*)"
+	}
+	return strings.Join(partes, " AND ")
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/danmaciel/api/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// restore desfaz a exclusão (soft delete) do registro id do model e incrementa
+// a versão. Retorna NotFound se o registro não existe, Conflict se ele não
+// está excluído e o Conflict de translateError se outro registro ativo já usa
+// uma das suas chaves únicas (email, SKU, código...).
+func restore(db *gorm.DB, model interface{}, id uint, entidade string) error {
+	result := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "versao": incrementVersao})
+	if result.Error != nil {
+		return translateError(result.Error, entidade)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// nada foi restaurado: o registro não existe ou está ativo
+	var count int64
+	if err := db.Unscoped().Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return translateError(gorm.ErrRecordNotFound, entidade)
+	}
+	return apperror.Conflict(entidade + " não está excluído")
+}
+
+// purge remove definitivamente do banco o registro id do model, esteja ele
+// ativo ou excluído (soft delete)
+func purge(db *gorm.DB, model interface{}, id uint, entidade string) error {
+	result := db.Unscoped().Delete(model, id)
+	if result.Error != nil {
+		return translateError(result.Error, entidade)
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, entidade)
+	}
+	return nil
+}
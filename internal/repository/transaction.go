@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey é a chave usada para carregar a transação corrente no contexto
+type txKey struct{}
+
+// runInTransaction executa fn dentro de uma transação do banco. A transação é
+// propagada pelo contexto, de forma que todos os repositórios chamados com esse
+// contexto participam da mesma unidade de trabalho. Se já houver uma transação
+// em andamento no contexto, fn é executada dentro dela.
+func runInTransaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn retorna a transação presente no contexto ou, na ausência dela, a conexão padrão
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
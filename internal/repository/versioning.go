@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/danmaciel/api/internal/apperror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateVersioned grava todos os campos de value (sem os relacionamentos)
+// apenas se a versão no banco ainda for *versao, a versão lida antes da
+// alteração, e incrementa a versão. Se outra requisição gravou o registro
+// nesse meio tempo nada é alterado e um erro de conflito é retornado, em vez
+// de sobrescrever a alteração concorrente.
+func updateVersioned(db *gorm.DB, value interface{}, versao *uint, entidade string) error {
+	lida := *versao
+	*versao = lida + 1
+
+	result := db.Model(value).
+		Where("versao = ?", lida).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(value)
+	if result.Error != nil {
+		*versao = lida
+		return translateError(result.Error, entidade)
+	}
+	if result.RowsAffected == 0 {
+		*versao = lida
+		return apperror.Conflict(entidade + " foi alterado por outra requisição, recarregue e tente novamente")
+	}
+	return nil
+}
+
+// incrementVersao é a expressão usada nas atualizações parciais que também
+// precisam invalidar a versão do registro (por exemplo a baixa de estoque)
+var incrementVersao = gorm.Expr("versao + 1")
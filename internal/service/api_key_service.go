@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// APIKeyService define a interface para gerenciamento e validação de API keys
+type APIKeyService interface {
+	Create(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error)
+	FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.APIKeyResponse], error)
+	Revoke(ctx context.Context, id uint) error
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+// apiKeyPrefix identifica as chaves emitidas pela API, no formato ak_<prefixo>_<segredo>
+const apiKeyPrefix = "ak_"
+
+// lastUsedResolution evita uma escrita no banco a cada requisição: last_used_at
+// só é atualizado quando o último uso registrado é mais antigo que isso
+const lastUsedResolution = time.Minute
+
+type apiKeyServiceImpl struct {
+	repo     repository.APIKeyRepository
+	validate *validator.Validate
+	now      func() time.Time
+}
+
+// NewAPIKeyService cria uma nova instância do serviço
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyServiceImpl{
+		repo:     repo,
+		validate: newValidator(),
+		now:      time.Now,
+	}
+}
+
+func (s *apiKeyServiceImpl) Create(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da API key inválidos", err)
+	}
+
+	prefixo, err := randomString(4, hex.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	segredo, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + prefixo + "_" + segredo
+
+	apiKey := &model.APIKey{
+		Nome:      req.Nome,
+		Prefixo:   apiKeyPrefix + prefixo,
+		Hash:      hashAPIKey(key),
+		Scopes:    strings.Join(uniqueScopes(req.Scopes), " "),
+		CriadoPor: auth.Actor(ctx),
+	}
+	if err := s.repo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	// o segredo só é devolvido nesta resposta; no banco fica apenas o hash
+	return &dto.APIKeyCreatedResponse{
+		APIKeyResponse: s.toResponseValue(apiKey),
+		Key:            key,
+	}, nil
+}
+
+func (s *apiKeyServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.APIKeyResponse], error) {
+	opts, err := toListOptions(page, nil)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar as API keys: %w", err)
+	}
+
+	return toPageResponse(keys, page, opts, s.toResponseValue, apiKeyID), nil
+}
+
+// Revoke revoga a chave; revogar uma chave já revogada mantém a data original
+func (s *apiKeyServiceImpl) Revoke(ctx context.Context, id uint) error {
+	apiKey, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if apiKey.Revogada() {
+		return nil
+	}
+
+	now := s.now()
+	apiKey.RevokedAt = &now
+	return s.repo.Update(ctx, apiKey)
+}
+
+// VerifyAPIKey valida a chave e retorna um principal com os escopos dela.
+// Chaves desconhecidas ou revogadas retornam auth.ErrAPIKeyInvalida.
+func (s *apiKeyServiceImpl) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, auth.ErrAPIKeyInvalida
+	}
+
+	apiKey, err := s.repo.FindByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, auth.ErrAPIKeyInvalida
+		}
+		return nil, err
+	}
+	if apiKey.Revogada() {
+		return nil, auth.ErrAPIKeyInvalida
+	}
+
+	now := s.now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			return nil, fmt.Errorf("falha ao registrar o uso da API key: %w", err)
+		}
+	}
+
+	return &auth.Principal{
+		Subject: "apikey:" + apiKey.Prefixo,
+		Scopes:  apiKey.ScopeList(),
+	}, nil
+}
+
+// hashAPIKey calcula o hash armazenado da chave. As chaves têm 256 bits
+// aleatórios, então um SHA-256 simples é suficiente (sem necessidade de salt)
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomString gera n bytes aleatórios e os codifica com encode
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar a API key: %w", err)
+	}
+	return encode(b), nil
+}
+
+// uniqueScopes remove escopos repetidos mantendo a ordem informada
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+func apiKeyID(key *model.APIKey) uint {
+	return key.ID
+}
+
+// model para response dto, por valor
+func (s *apiKeyServiceImpl) toResponseValue(key *model.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Nome:       key.Nome,
+		Prefixo:    key.Prefixo,
+		Scopes:     key.ScopeList(),
+		CriadoPor:  key.CriadoPor,
+		LastUsedAt: formatOptionalTime(key.LastUsedAt),
+		RevokedAt:  formatOptionalTime(key.RevokedAt),
+		CreatedAt:  key.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// AuditService define a interface de consulta ao audit log
+type AuditService interface {
+	FindAll(ctx context.Context, filtro dto.AuditFilter, page dto.PageRequest) (*dto.PageResponse[dto.AuditLogResponse], error)
+	Verify(ctx context.Context) (*dto.AuditVerifyResponse, error)
+}
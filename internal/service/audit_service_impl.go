@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+)
+
+// entidadesAuditadas são os valores aceitos no filtro entity
+var entidadesAuditadas = []string{model.AuditCliente, model.AuditProduto, model.AuditPedido}
+
+// errCadeiaQuebrada interrompe a verificação na primeira entrada inválida
+var errCadeiaQuebrada = errors.New("cadeia do audit log quebrada")
+
+type auditServiceImpl struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService cria uma nova instância do serviço
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditServiceImpl{repo: repo}
+}
+
+// FindAll lista as entradas na ordem em que foram gravadas, filtradas por
+// entidade, registro e período
+func (s *auditServiceImpl) FindAll(ctx context.Context, filtro dto.AuditFilter, page dto.PageRequest) (*dto.PageResponse[dto.AuditLogResponse], error) {
+	opts, err := toListOptions(page, nil)
+	if err != nil {
+		return nil, err
+	}
+	filtros, err := auditFiltros(filtro)
+	if err != nil {
+		return nil, err
+	}
+	opts.Filters = append(opts.Filters, filtros...)
+
+	entradas, err := s.repo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(entradas, page, opts, toAuditLogResponse, auditLogID), nil
+}
+
+// auditFiltros converte os parâmetros da consulta em filtros do repositório
+func auditFiltros(filtro dto.AuditFilter) ([]repository.Filter, error) {
+	var filtros []repository.Filter
+	if filtro.Entity != "" {
+		if !slices.Contains(entidadesAuditadas, filtro.Entity) {
+			return nil, errConsultaInvalida("entity deve ser cliente, produto ou pedido")
+		}
+		filtros = append(filtros, repository.Filter{Column: "entidade", Op: repository.OpEq, Values: []interface{}{filtro.Entity}})
+	}
+	if filtro.ID > 0 {
+		if filtro.Entity == "" {
+			return nil, errConsultaInvalida("id exige entity")
+		}
+		filtros = append(filtros, repository.Filter{Column: "entidade_id", Op: repository.OpEq, Values: []interface{}{filtro.ID}})
+	}
+
+	if filtro.From != "" {
+		de, err := tipoDataHora.parse(filtro.From)
+		if err != nil {
+			return nil, errConsultaInvalida("valor inválido para from: %v", err)
+		}
+		filtros = append(filtros, repository.Filter{Column: "created_at", Op: repository.OpGte, Values: []interface{}{de}})
+	}
+	if filtro.To != "" {
+		ate, err := tipoDataHora.parse(filtro.To)
+		if err != nil {
+			return nil, errConsultaInvalida("valor inválido para to: %v", err)
+		}
+		// uma data sem hora inclui o dia inteiro
+		op := repository.OpLte
+		if _, err := time.Parse(time.DateOnly, filtro.To); err == nil {
+			op = repository.OpLt
+			ate = ate.(time.Time).AddDate(0, 0, 1)
+		}
+		filtros = append(filtros, repository.Filter{Column: "created_at", Op: op, Values: []interface{}{ate}})
+	}
+	return filtros, nil
+}
+
+// Verify percorre a cadeia desde a primeira entrada e confere, em cada uma,
+// o hash da anterior e o próprio hash. Retorna a primeira entrada que não
+// confere: ela, ou a anterior, foi alterada ou removida. No fim, o hash da
+// última entrada precisa ser o da ponta da cadeia; se não for, as últimas
+// entradas foram removidas e não há uma entrada para apontar.
+func (s *auditServiceImpl) Verify(ctx context.Context) (*dto.AuditVerifyResponse, error) {
+	resp := &dto.AuditVerifyResponse{Valid: true}
+	// A ponta é lida na mesma transação que percorre a cadeia, assim uma
+	// gravação simultânea não aparece em só uma das leituras
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		ponta, err := s.repo.FindPonta(ctx)
+		// Sem ponta, a cadeia ainda não recebeu gravações desde que ela existe
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+
+		hashAnterior := ""
+		err = s.repo.ForEach(ctx, func(entrada *model.AuditLog) error {
+			if entrada.HashAnterior != hashAnterior || entrada.Hash != entrada.CalcularHash() {
+				id := entrada.ID
+				resp.Valid = false
+				resp.BrokenAt = &id
+				return errCadeiaQuebrada
+			}
+			resp.Entries++
+			hashAnterior = entrada.Hash
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if ponta != nil && hashAnterior != ponta.Hash {
+			resp.Valid = false
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errCadeiaQuebrada) {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func auditLogID(entrada *model.AuditLog) uint {
+	return entrada.ID
+}
+
+// model para response dto, por valor
+func toAuditLogResponse(entrada *model.AuditLog) dto.AuditLogResponse {
+	return dto.AuditLogResponse{
+		ID:           entrada.ID,
+		Entidade:     entrada.Entidade,
+		EntidadeID:   entrada.EntidadeID,
+		Acao:         entrada.Acao,
+		Ator:         entrada.Ator,
+		RequestID:    entrada.RequestID,
+		Diff:         json.RawMessage(entrada.Diff),
+		HashAnterior: entrada.HashAnterior,
+		Hash:         entrada.Hash,
+		CreatedAt:    entrada.CreatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// valorOmitido substitui no audit log os valores dos campos pessoais
+const valorOmitido = "[omitido]"
+
+// camposPessoais são, por entidade, os campos com dados pessoais. O audit log
+// é imutável e sobrevive à anonimização do cliente, então o diff registra que
+// esses campos mudaram, mas não os seus valores.
+var camposPessoais = map[string][]string{
+	model.AuditCliente: {"nome", "razao_social", "nome_fantasia", "email", "cpf", "cnpj", "inscricao_estadual", "telefone"},
+	model.AuditPedido:  {"cliente", "entrega"},
+}
+
+// auditoria registra no audit log as operações de escrita sobre uma entidade
+type auditoria struct {
+	repo     repository.AuditRepository
+	entidade string
+}
+
+// emTransacao executa fn, que faz a escrita e o registro dela, em uma única
+// transação: uma escrita nunca fica sem a sua entrada no audit log
+func (a auditoria) emTransacao(ctx context.Context, fn func(ctx context.Context) error) error {
+	return a.repo.WithTransaction(ctx, fn)
+}
+
+// registrar grava a operação acao sobre o registro id, com o ator e o request
+// ID do contexto. antes e depois são as representações do registro; nil
+// quando ele não existia (criação) ou deixou de existir (exclusão).
+func (a auditoria) registrar(ctx context.Context, id uint, acao string, antes, depois interface{}) error {
+	diff, err := diffJSON(antes, depois, camposPessoais[a.entidade])
+	if err != nil {
+		return fmt.Errorf("falha ao calcular a diferença para o audit log: %w", err)
+	}
+
+	return a.repo.Append(ctx, &model.AuditLog{
+		Entidade:   a.entidade,
+		EntidadeID: id,
+		Acao:       acao,
+		Ator:       auth.Actor(ctx),
+		RequestID:  chimiddleware.GetReqID(ctx),
+		Diff:       diff,
+	})
+}
+
+// diffJSON compara as representações JSON de antes e depois e retorna um
+// objeto apenas com os campos alterados, cada um como {"antes": ..., "depois": ...};
+// o lado em que o campo não existe é omitido. Os valores preenchidos dos
+// campos em pessoais aparecem como valorOmitido.
+func diffJSON(antes, depois interface{}, pessoais []string) (string, error) {
+	de, err := camposJSON(antes)
+	if err != nil {
+		return "", err
+	}
+	para, err := camposJSON(depois)
+	if err != nil {
+		return "", err
+	}
+
+	diff := make(map[string]map[string]interface{})
+	for campo, valor := range de {
+		novo, ok := para[campo]
+		if ok && reflect.DeepEqual(valor, novo) {
+			continue
+		}
+		diff[campo] = map[string]interface{}{"antes": valor}
+		if ok {
+			diff[campo]["depois"] = novo
+		}
+	}
+	for campo, novo := range para {
+		if _, ok := de[campo]; !ok {
+			diff[campo] = map[string]interface{}{"depois": novo}
+		}
+	}
+
+	for _, campo := range pessoais {
+		for lado, valor := range diff[campo] {
+			// um campo apagado (vazio) não revela nada e mostra a anonimização
+			if valor != nil && valor != "" {
+				diff[campo][lado] = valorOmitido
+			}
+		}
+	}
+
+	// as chaves de map saem ordenadas, então o mesmo diff gera sempre o mesmo texto
+	texto, err := json.Marshal(diff)
+	return string(texto), err
+}
+
+// camposJSON decodifica a representação JSON de v em um map de campos
+func camposJSON(v interface{}) (map[string]interface{}, error) {
+	campos := make(map[string]interface{})
+	if v == nil {
+		return campos, nil
+	}
+	texto, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(texto, &campos); err != nil {
+		return nil, err
+	}
+	return campos, nil
+}
@@ -9,10 +9,14 @@ import (
 // ClienteService defines the business logic interface for cliente operations
 type ClienteService interface {
 	Create(ctx context.Context, req *dto.CreateClienteRequest) (*dto.ClienteResponse, error)
-	FindAll(ctx context.Context) ([]dto.ClienteResponse, error)
+	FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.ClienteResponse], error)
 	FindByID(ctx context.Context, id uint) (*dto.ClienteResponse, error)
-	FindByName(ctx context.Context, nome string) ([]dto.ClienteResponse, error)
+	FindByName(ctx context.Context, nome string, page dto.PageRequest) (*dto.PageResponse[dto.ClienteResponse], error)
 	Update(ctx context.Context, id uint, req *dto.UpdateClienteRequest) (*dto.ClienteResponse, error)
+	Patch(ctx context.Context, id uint, patch []byte) (*dto.ClienteResponse, error)
 	Delete(ctx context.Context, id uint) error
+	Deactivate(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) (*dto.ClienteResponse, error)
+	Purge(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
 }
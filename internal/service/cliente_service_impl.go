@@ -5,133 +5,291 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/documento"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/mergepatch"
 	"github.com/danmaciel/api/internal/model"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/go-playground/validator/v10"
-	"gorm.io/gorm"
 )
 
 type clienteServiceImpl struct {
-	repo     repository.ClienteRepository
-	validate *validator.Validate
+	repo      repository.ClienteRepository
+	auditoria auditoria
+	validate  *validator.Validate
 }
 
 // NewPedidoService cria uma nova instância do serviço
-func NewClienteService(repo repository.ClienteRepository) ClienteService {
+func NewClienteService(repo repository.ClienteRepository, auditRepo repository.AuditRepository) ClienteService {
 	return &clienteServiceImpl{
-		repo:     repo,
-		validate: validator.New(),
+		repo:      repo,
+		auditoria: auditoria{repo: auditRepo, entidade: model.AuditCliente},
+		validate:  newValidator(),
 	}
 }
 
 func (s *clienteServiceImpl) Create(ctx context.Context, req *dto.CreateClienteRequest) (*dto.ClienteResponse, error) {
 	// Validar request
 	if err := s.validate.Struct(req); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return nil, apperror.Validation("dados do cliente inválidos", err)
 	}
 
-	// Dto para model
+	// Dto para model; os documentos são gravados sem máscara
 	cliente := &model.Cliente{
-		Nome:     req.Nome,
-		Email:    req.Email,
-		CPF:      req.CPF,
-		Telefone: req.Telefone,
+		Tipo:              tipoCliente(req.Tipo),
+		Nome:              req.Nome,
+		RazaoSocial:       req.RazaoSocial,
+		NomeFantasia:      req.NomeFantasia,
+		Email:             req.Email,
+		CPF:               documento.Normalizar(req.CPF),
+		CNPJ:              documento.Normalizar(req.CNPJ),
+		InscricaoEstadual: documento.NormalizarInscricaoEstadual(req.InscricaoEstadual),
+		Telefone:          req.Telefone,
 	}
 
 	// cria no banco
-	if err := s.repo.Create(ctx, cliente); err != nil {
-		return nil, fmt.Errorf("falha ao criar cliente: %w", err)
+	err := s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, cliente); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, cliente.ID, model.AuditCreate, nil, toClienteResponse(cliente))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// model para dto
-	return s.toResponse(cliente), nil
+	return toClienteResponse(cliente), nil
 }
 
-func (s *clienteServiceImpl) FindAll(ctx context.Context) ([]dto.ClienteResponse, error) {
-	clientes, err := s.repo.FindAll(ctx)
+func (s *clienteServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.ClienteResponse], error) {
+	opts, err := toListOptions(page, clienteCampos)
+	if err != nil {
+		return nil, err
+	}
+	clientes, err := s.repo.FindAll(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao buscar os clientes: %w", err)
 	}
 
-	responses := make([]dto.ClienteResponse, len(clientes))
-	for i, cliente := range clientes {
-		responses[i] = *s.toResponse(&cliente)
-	}
-	return responses, nil
+	return toPageResponse(clientes, page, opts, s.toResponseValue, clienteID), nil
 }
 
 func (s *clienteServiceImpl) FindByID(ctx context.Context, id uint) (*dto.ClienteResponse, error) {
 	cliente, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao buscar cliente por ID: %w", err)
+		return nil, err
 	}
-	if cliente == nil {
-		return nil, errors.New("cliente not found")
-	}
-	return s.toResponse(cliente), nil
+	return toClienteResponse(cliente), nil
 }
 
-func (s *clienteServiceImpl) FindByName(ctx context.Context, nome string) ([]dto.ClienteResponse, error) {
-	clientes, err := s.repo.FindByName(ctx, nome)
+func (s *clienteServiceImpl) FindByName(ctx context.Context, nome string, page dto.PageRequest) (*dto.PageResponse[dto.ClienteResponse], error) {
+	opts, err := toListOptions(page, clienteCampos)
+	if err != nil {
+		return nil, err
+	}
+	clientes, err := s.repo.FindByName(ctx, nome, opts)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao buscar clientes por nome: %w", err)
 	}
 
-	responses := make([]dto.ClienteResponse, len(clientes))
-	for i, cliente := range clientes {
-		responses[i] = *s.toResponse(&cliente)
-	}
-	return responses, nil
+	return toPageResponse(clientes, page, opts, s.toResponseValue, clienteID), nil
 }
 
 func (s *clienteServiceImpl) Update(ctx context.Context, id uint, req *dto.UpdateClienteRequest) (*dto.ClienteResponse, error) {
 	// valida request
 	if err := s.validate.Struct(req); err != nil {
-		return nil, fmt.Errorf("erro de validação: %w", err)
+		return nil, apperror.Validation("dados do cliente inválidos", err)
 	}
 
 	// procurar cliente existente
 	cliente, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao buscar cliente: %w", err)
+		return nil, err
 	}
-	if cliente == nil {
-		return nil, errors.New("cliente not found")
+	if err := etag.Check(ctx, cliente.Versao); err != nil {
+		return nil, err
 	}
 
-	// atualizar campos
-	if req.Nome != "" {
-		cliente.Nome = req.Nome
+	return s.replace(ctx, cliente, req)
+}
+
+func (s *clienteServiceImpl) Patch(ctx context.Context, id uint, patch []byte) (*dto.ClienteResponse, error) {
+	// procurar cliente existente
+	cliente, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if req.Email != "" {
-		cliente.Email = req.Email
+	if err := etag.Check(ctx, cliente.Versao); err != nil {
+		return nil, err
 	}
-	if req.CPF != "" {
-		cliente.CPF = req.CPF
+
+	// aplica o patch sobre a representação atual; o resultado é validado
+	// como uma substituição completa
+	var req dto.UpdateClienteRequest
+	if err := mergepatch.Apply(toUpdateClienteRequest(cliente), patch, &req); err != nil {
+		return nil, apperror.Validation("merge patch inválido", err)
 	}
-	if req.Telefone != "" {
-		cliente.Telefone = req.Telefone
+	if err := s.validate.Struct(&req); err != nil {
+		return nil, apperror.Validation("dados do cliente inválidos", err)
 	}
 
-	// atualizar no banco
-	if err := s.repo.Update(ctx, cliente); err != nil {
-		return nil, fmt.Errorf("falha ao atualizar cliente: %w", err)
+	return s.replace(ctx, cliente, &req)
+}
+
+// Delete exclui o cliente. Falha com conflito se ele tem pedidos, que
+// ficariam apontando para um cliente excluído (ver Deactivate).
+func (s *clienteServiceImpl) Delete(ctx context.Context, id uint) error {
+	cliente, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, cliente.Versao); err != nil {
+		return err
 	}
 
-	return s.toResponse(cliente), nil
+	// a contagem e a exclusão ficam na mesma transação, para que um pedido
+	// criado entre as duas não fique apontando para um cliente excluído
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.semPedidos(ctx, id, false); err != nil {
+			return err
+		}
+		return s.excluir(ctx, cliente)
+	})
 }
 
-func (s *clienteServiceImpl) Delete(ctx context.Context, id uint) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("cliente not found")
+// excluir exclui o cliente (soft delete) e registra a exclusão
+func (s *clienteServiceImpl) excluir(ctx context.Context, cliente *model.Cliente) error {
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, cliente.ID); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, cliente.ID, model.AuditDelete, toClienteResponse(cliente), nil)
+	})
+}
+
+// Deactivate exclui o cliente se ele não tem pedidos; se tem, anonimiza seus
+// dados pessoais e remove seus endereços e contatos, mantendo o registro
+// referenciado pelos pedidos
+func (s *clienteServiceImpl) Deactivate(ctx context.Context, id uint) error {
+	cliente, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, cliente.Versao); err != nil {
+		return err
+	}
+
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		pedidos, err := s.repo.CountPedidos(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if pedidos == 0 {
+			return s.excluir(ctx, cliente)
+		}
+
+		antes := toClienteResponse(cliente)
+		cliente.Anonimizar()
+		if err := s.repo.Anonymize(ctx, cliente); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, cliente.ID, model.AuditUpdate, antes, toClienteResponse(cliente))
+	})
+}
+
+// Restore desfaz a exclusão do cliente. Falha com conflito se o email ou um
+// documento dele foi cadastrado em outro cliente depois da exclusão.
+func (s *clienteServiceImpl) Restore(ctx context.Context, id uint) (*dto.ClienteResponse, error) {
+	var cliente *model.Cliente
+	err := s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		if cliente, err = s.repo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, id, model.AuditRestore, nil, toClienteResponse(cliente))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toClienteResponse(cliente), nil
+}
+
+// Purge remove o cliente definitivamente, esteja ele excluído ou não. Assim
+// como Delete, falha com conflito se ele tem pedidos, contando também os
+// pedidos excluídos, que continuam referenciando o cliente.
+func (s *clienteServiceImpl) Purge(ctx context.Context, id uint) error {
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.semPedidos(ctx, id, true); err != nil {
+			return err
 		}
-		return fmt.Errorf("falha ao deletar cliente: %w", err)
+
+		// um cliente já excluído não é encontrado, mas os dados dele ficaram na
+		// entrada da exclusão
+		var antes interface{}
+		cliente, err := s.repo.FindByID(ctx, id)
+		if err == nil {
+			antes = toClienteResponse(cliente)
+		} else if !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+
+		if err := s.repo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, id, model.AuditPurge, antes, nil)
+	})
+}
+
+// semPedidos retorna um conflito com a contagem de pedidos do cliente, se
+// houver algum
+func (s *clienteServiceImpl) semPedidos(ctx context.Context, id uint, incluirExcluidos bool) error {
+	pedidos, err := s.repo.CountPedidos(ctx, id, incluirExcluidos)
+	if err != nil {
+		return err
+	}
+	if pedidos > 0 {
+		return apperror.InUse(
+			fmt.Sprintf("cliente possui %d pedido(s); use cascade=deactivate para anonimizá-lo", pedidos),
+			map[string]int64{"pedidos": pedidos},
+		)
 	}
 	return nil
 }
 
+// replace substitui todos os campos editáveis do cliente pelos da requisição
+func (s *clienteServiceImpl) replace(ctx context.Context, cliente *model.Cliente, req *dto.UpdateClienteRequest) (*dto.ClienteResponse, error) {
+	antes := toClienteResponse(cliente)
+	cliente.Tipo = tipoCliente(req.Tipo)
+	cliente.Nome = req.Nome
+	cliente.RazaoSocial = req.RazaoSocial
+	cliente.NomeFantasia = req.NomeFantasia
+	cliente.Email = req.Email
+	cliente.CPF = documento.Normalizar(req.CPF)
+	cliente.CNPJ = documento.Normalizar(req.CNPJ)
+	cliente.InscricaoEstadual = documento.NormalizarInscricaoEstadual(req.InscricaoEstadual)
+	cliente.Telefone = req.Telefone
+
+	// atualizar no banco
+	err := s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, cliente); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, cliente.ID, model.AuditUpdate, antes, toClienteResponse(cliente))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toClienteResponse(cliente), nil
+}
+
 func (s *clienteServiceImpl) Count(ctx context.Context) (int64, error) {
 	count, err := s.repo.Count(ctx)
 	if err != nil {
@@ -140,15 +298,57 @@ func (s *clienteServiceImpl) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// model para response dto
-func (s *clienteServiceImpl) toResponse(cliente *model.Cliente) *dto.ClienteResponse {
-	return &dto.ClienteResponse{
-		ID:        cliente.ID,
-		Nome:      cliente.Nome,
-		Email:     cliente.Email,
-		CPF:       cliente.CPF,
-		Telefone:  cliente.Telefone,
-		CreatedAt: cliente.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: cliente.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+// model para o dto de substituição, base dos merge patches
+func toUpdateClienteRequest(cliente *model.Cliente) dto.UpdateClienteRequest {
+	return dto.UpdateClienteRequest{
+		Tipo:              cliente.Tipo,
+		Nome:              cliente.Nome,
+		RazaoSocial:       cliente.RazaoSocial,
+		NomeFantasia:      cliente.NomeFantasia,
+		Email:             cliente.Email,
+		CPF:               cliente.CPF,
+		CNPJ:              cliente.CNPJ,
+		InscricaoEstadual: cliente.InscricaoEstadual,
+		Telefone:          cliente.Telefone,
+	}
+}
+
+// tipoCliente aplica o tipo padrão, pessoa física, quando nenhum foi informado
+func tipoCliente(tipo string) string {
+	if tipo == "" {
+		return model.ClientePessoaFisica
+	}
+	return tipo
+}
+
+// model para response dto, por valor, usado nas listagens
+func (s *clienteServiceImpl) toResponseValue(cliente *model.Cliente) dto.ClienteResponse {
+	return *toClienteResponse(cliente)
+}
+
+func clienteID(cliente *model.Cliente) uint {
+	return cliente.ID
+}
+
+// model para response dto; também usado no cliente dos pedidos
+func toClienteResponse(cliente *model.Cliente) *dto.ClienteResponse {
+	resp := &dto.ClienteResponse{
+		ID:                cliente.ID,
+		Tipo:              cliente.Tipo,
+		Nome:              cliente.Nome,
+		RazaoSocial:       cliente.RazaoSocial,
+		NomeFantasia:      cliente.NomeFantasia,
+		Email:             cliente.Email,
+		CPF:               cliente.CPF,
+		CNPJ:              cliente.CNPJ,
+		InscricaoEstadual: cliente.InscricaoEstadual,
+		Telefone:          cliente.Telefone,
+		Versao:            cliente.Versao,
+		CreatedAt:         cliente.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         cliente.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if cliente.DeletedAt.Valid {
+		resp.DeletedAt = cliente.DeletedAt.Time.Format("2006-01-02T15:04:05Z07:00")
 	}
+	return resp
 }
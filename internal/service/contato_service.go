@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// ContatoService define a interface para operações de negócio dos contatos
+// de um cliente PJ. Como em EnderecoService, todas as operações recebem o
+// cliente dono do contato; um contato de outro cliente é tratado como
+// inexistente.
+type ContatoService interface {
+	Create(ctx context.Context, clienteID uint, req *dto.CreateContatoRequest) (*dto.ContatoResponse, error)
+	// FindByClienteID lista os contatos do cliente; papel, se informado, filtra pelo papel
+	FindByClienteID(ctx context.Context, clienteID uint, papel string) ([]dto.ContatoResponse, error)
+	FindByID(ctx context.Context, clienteID, id uint) (*dto.ContatoResponse, error)
+	Update(ctx context.Context, clienteID, id uint, req *dto.UpdateContatoRequest) (*dto.ContatoResponse, error)
+	Delete(ctx context.Context, clienteID, id uint) error
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+// papeisContato são os papéis aceitos no filtro da listagem
+var papeisContato = []string{model.ContatoCompras, model.ContatoFinanceiro, model.ContatoFiscal, model.ContatoLogistica, model.ContatoOutro}
+
+type contatoServiceImpl struct {
+	repo        repository.ContatoRepository
+	clienteRepo repository.ClienteRepository
+	validate    *validator.Validate
+}
+
+// NewContatoService cria uma nova instância do serviço
+func NewContatoService(repo repository.ContatoRepository, clienteRepo repository.ClienteRepository) ContatoService {
+	return &contatoServiceImpl{
+		repo:        repo,
+		clienteRepo: clienteRepo,
+		validate:    newValidator(),
+	}
+}
+
+func (s *contatoServiceImpl) Create(ctx context.Context, clienteID uint, req *dto.CreateContatoRequest) (*dto.ContatoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do contato inválidos", err)
+	}
+
+	if err := s.exigirPessoaJuridica(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	contato := &model.Contato{
+		ClienteID: clienteID,
+		Nome:      req.Nome,
+		Papel:     req.Papel,
+		Cargo:     req.Cargo,
+		Email:     req.Email,
+		Telefone:  req.Telefone,
+	}
+	if err := s.repo.Create(ctx, contato); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(contato), nil
+}
+
+func (s *contatoServiceImpl) FindByClienteID(ctx context.Context, clienteID uint, papel string) ([]dto.ContatoResponse, error) {
+	papel = strings.ToLower(papel)
+	if papel != "" && !slices.Contains(papeisContato, papel) {
+		return nil, apperror.Validation("parâmetros de consulta inválidos",
+			errors.New("papel deve ser um dos valores: "+strings.Join(papeisContato, ", ")))
+	}
+
+	// Verificar se o cliente existe, para não confundir 404 com lista vazia
+	if _, err := s.clienteRepo.FindByID(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	contatos, err := s.repo.FindByClienteID(ctx, clienteID, papel)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ContatoResponse, len(contatos))
+	for i := range contatos {
+		response[i] = *s.toResponse(&contatos[i])
+	}
+	return response, nil
+}
+
+func (s *contatoServiceImpl) FindByID(ctx context.Context, clienteID, id uint) (*dto.ContatoResponse, error) {
+	contato, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(contato), nil
+}
+
+func (s *contatoServiceImpl) Update(ctx context.Context, clienteID, id uint, req *dto.UpdateContatoRequest) (*dto.ContatoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do contato inválidos", err)
+	}
+
+	contato, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := etag.Check(ctx, contato.Versao); err != nil {
+		return nil, err
+	}
+	if err := s.exigirPessoaJuridica(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	contato.Nome = req.Nome
+	contato.Papel = req.Papel
+	contato.Cargo = req.Cargo
+	contato.Email = req.Email
+	contato.Telefone = req.Telefone
+
+	if err := s.repo.Update(ctx, contato); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(contato), nil
+}
+
+func (s *contatoServiceImpl) Delete(ctx context.Context, clienteID, id uint) error {
+	contato, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, contato.Versao); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// exigirPessoaJuridica confere que o cliente existe e é PJ. Os contatos de um
+// cliente que passou a PF continuam listados e podem ser excluídos, mas não
+// alterados.
+func (s *contatoServiceImpl) exigirPessoaJuridica(ctx context.Context, clienteID uint) error {
+	cliente, err := s.clienteRepo.FindByID(ctx, clienteID)
+	if err != nil {
+		return err
+	}
+	if cliente.Tipo != model.ClientePessoaJuridica {
+		return apperror.BusinessRule("apenas clientes PJ têm contatos")
+	}
+	return nil
+}
+
+// buscar retorna o contato se ele pertencer ao cliente
+func (s *contatoServiceImpl) buscar(ctx context.Context, clienteID, id uint) (*model.Contato, error) {
+	contato, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if contato.ClienteID != clienteID {
+		return nil, apperror.NotFound("contato não encontrado")
+	}
+	return contato, nil
+}
+
+// toResponse converte Model para Response DTO
+func (s *contatoServiceImpl) toResponse(contato *model.Contato) *dto.ContatoResponse {
+	return &dto.ContatoResponse{
+		ID:        contato.ID,
+		ClienteID: contato.ClienteID,
+		Nome:      contato.Nome,
+		Papel:     contato.Papel,
+		Cargo:     contato.Cargo,
+		Email:     contato.Email,
+		Telefone:  contato.Telefone,
+		Versao:    contato.Versao,
+		CreatedAt: contato.CreatedAt,
+		UpdatedAt: contato.UpdatedAt,
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// CupomService define a interface para operações de negócio de Cupom
+type CupomService interface {
+	Create(ctx context.Context, req *dto.CreateCupomRequest) (*dto.CupomResponse, error)
+	FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.CupomResponse], error)
+	FindByID(ctx context.Context, id uint) (*dto.CupomResponse, error)
+	Update(ctx context.Context, id uint, req *dto.UpdateCupomRequest) (*dto.CupomResponse, error)
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) (*dto.CupomResponse, error)
+	Purge(ctx context.Context, id uint) error
+}
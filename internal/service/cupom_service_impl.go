@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type cupomServiceImpl struct {
+	repo     repository.CupomRepository
+	validate *validator.Validate
+}
+
+// NewCupomService cria uma nova instância do serviço
+func NewCupomService(repo repository.CupomRepository) CupomService {
+	return &cupomServiceImpl{
+		repo:     repo,
+		validate: newValidator(),
+	}
+}
+
+func (s *cupomServiceImpl) Create(ctx context.Context, req *dto.CreateCupomRequest) (*dto.CupomResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do cupom inválidos", err)
+	}
+	if err := validarVigencia(req.ValidoDe, req.ValidoAte); err != nil {
+		return nil, err
+	}
+
+	ativo := true
+	if req.Ativo != nil {
+		ativo = *req.Ativo
+	}
+
+	cupom := &model.Cupom{
+		Codigo:           normalizarCodigo(req.Codigo),
+		Descricao:        req.Descricao,
+		Tipo:             req.Tipo,
+		Percentual:       req.Percentual,
+		Valor:            req.Valor,
+		ValorMinimo:      req.ValorMinimo,
+		Categorias:       req.Categorias,
+		ProdutoIDs:       req.ProdutoIDs,
+		LimiteUso:        req.LimiteUso,
+		LimitePorCliente: req.LimitePorCliente,
+		ValidoDe:         req.ValidoDe,
+		ValidoAte:        req.ValidoAte,
+		Ativo:            ativo,
+	}
+	limparCamposDoTipo(cupom)
+
+	// Criar no banco; código repetido vira conflito
+	if err := s.repo.Create(ctx, cupom); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(cupom), nil
+}
+
+func (s *cupomServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.CupomResponse], error) {
+	opts, err := toListOptions(page, cupomCampos)
+	if err != nil {
+		return nil, err
+	}
+	cupons, err := s.repo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(cupons, page, opts, s.toResponseValue, cupomID), nil
+}
+
+func (s *cupomServiceImpl) FindByID(ctx context.Context, id uint) (*dto.CupomResponse, error) {
+	cupom, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(cupom), nil
+}
+
+func (s *cupomServiceImpl) Update(ctx context.Context, id uint, req *dto.UpdateCupomRequest) (*dto.CupomResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do cupom inválidos", err)
+	}
+	if err := validarVigencia(req.ValidoDe, req.ValidoAte); err != nil {
+		return nil, err
+	}
+
+	// Buscar cupom existente
+	cupom, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := etag.Check(ctx, cupom.Versao); err != nil {
+		return nil, err
+	}
+
+	cupom.Codigo = normalizarCodigo(req.Codigo)
+	cupom.Descricao = req.Descricao
+	cupom.Tipo = req.Tipo
+	cupom.Percentual = req.Percentual
+	cupom.Valor = req.Valor
+	cupom.ValorMinimo = req.ValorMinimo
+	cupom.Categorias = req.Categorias
+	cupom.ProdutoIDs = req.ProdutoIDs
+	cupom.LimiteUso = req.LimiteUso
+	cupom.LimitePorCliente = req.LimitePorCliente
+	cupom.ValidoDe = req.ValidoDe
+	cupom.ValidoAte = req.ValidoAte
+	cupom.Ativo = *req.Ativo
+	limparCamposDoTipo(cupom)
+
+	// Atualizar no banco
+	if err := s.repo.Update(ctx, cupom); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(cupom), nil
+}
+
+func (s *cupomServiceImpl) Delete(ctx context.Context, id uint) error {
+	// Verificar se existe
+	cupom, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, cupom.Versao); err != nil {
+		return err
+	}
+
+	// os pedidos que já usaram o cupom mantêm suas linhas de desconto
+	return s.repo.Delete(ctx, id)
+}
+
+// Restore desfaz a exclusão do cupom. Falha com conflito se o código dele foi
+// cadastrado em outro cupom depois da exclusão.
+func (s *cupomServiceImpl) Restore(ctx context.Context, id uint) (*dto.CupomResponse, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	cupom, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(cupom), nil
+}
+
+// Purge remove o cupom definitivamente, esteja ele excluído ou não
+func (s *cupomServiceImpl) Purge(ctx context.Context, id uint) error {
+	return s.repo.Purge(ctx, id)
+}
+
+// normalizarCodigo deixa o código em maiúsculas; a busca no pedido usa a mesma forma
+func normalizarCodigo(codigo string) string {
+	return strings.ToUpper(strings.TrimSpace(codigo))
+}
+
+// validarVigencia exige que o fim da validade seja posterior ao início
+func validarVigencia(de, ate *time.Time) error {
+	if de != nil && ate != nil && !ate.After(*de) {
+		return apperror.Validation("dados do cupom inválidos", errors.New("valido_ate deve ser posterior a valido_de"))
+	}
+	return nil
+}
+
+// limparCamposDoTipo zera os valores que não se aplicam ao tipo do cupom
+func limparCamposDoTipo(cupom *model.Cupom) {
+	if cupom.Tipo != model.CupomPercentual {
+		cupom.Percentual = 0
+	}
+	if cupom.Tipo != model.CupomValorFixo {
+		cupom.Valor = 0
+	}
+}
+
+// toResponseValue converte Model para Response DTO por valor, usado nas listagens
+func (s *cupomServiceImpl) toResponseValue(cupom *model.Cupom) dto.CupomResponse {
+	return *s.toResponse(cupom)
+}
+
+func cupomID(cupom *model.Cupom) uint {
+	return cupom.ID
+}
+
+// toResponse converte Model para Response DTO
+func (s *cupomServiceImpl) toResponse(cupom *model.Cupom) *dto.CupomResponse {
+	categorias := cupom.Categorias
+	if categorias == nil {
+		categorias = []string{}
+	}
+	produtoIDs := cupom.ProdutoIDs
+	if produtoIDs == nil {
+		produtoIDs = []uint{}
+	}
+
+	return &dto.CupomResponse{
+		ID:               cupom.ID,
+		Codigo:           cupom.Codigo,
+		Descricao:        cupom.Descricao,
+		Tipo:             cupom.Tipo,
+		Percentual:       cupom.Percentual,
+		Valor:            cupom.Valor,
+		ValorMinimo:      cupom.ValorMinimo,
+		Categorias:       categorias,
+		ProdutoIDs:       produtoIDs,
+		LimiteUso:        cupom.LimiteUso,
+		LimitePorCliente: cupom.LimitePorCliente,
+		Usos:             cupom.Usos,
+		ValidoDe:         cupom.ValidoDe,
+		ValidoAte:        cupom.ValidoAte,
+		Ativo:            cupom.Ativo,
+		Versao:           cupom.Versao,
+		CreatedAt:        cupom.CreatedAt,
+		UpdatedAt:        cupom.UpdatedAt,
+		DeletedAt:        excluidoEm(cupom.DeletedAt),
+	}
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// DevolucaoService define a interface para cancelar pedidos e devolver parte
+// dos seus itens, devolvendo o estoque e estornando os pagamentos
+type DevolucaoService interface {
+	Cancelar(ctx context.Context, pedidoID uint, req *dto.CancelamentoRequest) (*dto.PedidoResponse, error)
+	Devolver(ctx context.Context, pedidoID uint, req *dto.CreateDevolucaoRequest) (*dto.DevolucaoResponse, error)
+}
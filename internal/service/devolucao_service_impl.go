@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/pricing"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+// statusDevolucao são os status em que o pedido aceita devoluções: depois do
+// pagamento e sem ter sido cancelado
+var statusDevolucao = []string{model.StatusPago, model.StatusEnviado, model.StatusEntregue}
+
+type devolucaoServiceImpl struct {
+	pedidoRepo    repository.PedidoRepository
+	produtoRepo   repository.ProdutoRepository
+	cupomRepo     repository.CupomRepository
+	pagamentoRepo repository.PagamentoRepository
+	gateway       pagamento.PaymentGateway
+	auditoria     auditoria
+	validate      *validator.Validate
+}
+
+// NewDevolucaoService cria uma nova instância do serviço
+func NewDevolucaoService(pedidoRepo repository.PedidoRepository, produtoRepo repository.ProdutoRepository, cupomRepo repository.CupomRepository, pagamentoRepo repository.PagamentoRepository, gateway pagamento.PaymentGateway, auditRepo repository.AuditRepository) DevolucaoService {
+	return &devolucaoServiceImpl{
+		pedidoRepo:    pedidoRepo,
+		produtoRepo:   produtoRepo,
+		cupomRepo:     cupomRepo,
+		pagamentoRepo: pagamentoRepo,
+		gateway:       gateway,
+		auditoria:     auditoria{repo: auditRepo, entidade: model.AuditPedido},
+		validate:      newValidator(),
+	}
+}
+
+func (s *devolucaoServiceImpl) Cancelar(ctx context.Context, pedidoID uint, req *dto.CancelamentoRequest) (*dto.PedidoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do cancelamento inválidos", err)
+	}
+
+	var pedido *model.Pedido
+	var pendentes []estornoPendente
+	err := s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		existente, err := s.pedidoRepo.FindByID(ctx, pedidoID)
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(ctx, existente.Versao); err != nil {
+			return err
+		}
+
+		statusAnterior := existente.Status
+		if !transicaoPermitida(statusAnterior, model.StatusCancelado) {
+			return fmt.Errorf("%w: de %s para %s", ErrTransicaoStatusInvalida, statusAnterior, model.StatusCancelado)
+		}
+
+		antes := toPedidoResponse(existente)
+
+		// Volta ao estoque o que ainda não voltou por devoluções, o uso do
+		// cupom é liberado e todo o valor pago é reservado para estorno
+		if err := restaurarEstoque(ctx, s.produtoRepo, existente); err != nil {
+			return err
+		}
+		if err := liberarCupons(ctx, s.cupomRepo, existente); err != nil {
+			return err
+		}
+		_, pendentes, err = reembolsar(ctx, s.pagamentoRepo, existente.ID, existente.ValorTotal, req.Motivo, auth.Actor(ctx))
+		if err != nil {
+			return err
+		}
+
+		existente.Status = model.StatusCancelado
+		existente.MotivoCancelamento = req.Motivo
+		if err := s.pedidoRepo.Update(ctx, existente); err != nil {
+			return err
+		}
+
+		if err := s.pedidoRepo.AddHistoricoStatus(ctx, &model.PedidoStatusHistorico{
+			PedidoID:       existente.ID,
+			StatusAnterior: statusAnterior,
+			StatusNovo:     existente.Status,
+			Usuario:        auth.Actor(ctx),
+		}); err != nil {
+			return err
+		}
+
+		pedido = existente
+		return s.auditoria.registrar(ctx, existente.ID, model.AuditUpdate, antes, toPedidoResponse(existente))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Os estornos vão ao provedor depois do commit. O cancelamento já está
+	// gravado; um estorno recusado fica como falhou e pode ser refeito pelo
+	// endpoint de estornos.
+	if _, err := enviarEstornos(ctx, s.pagamentoRepo, s.gateway, pendentes); err != nil {
+		log.Printf("falha ao estornar os pagamentos do pedido %d: %v", pedido.ID, err)
+	}
+
+	return toPedidoResponse(pedido), nil
+}
+
+func (s *devolucaoServiceImpl) Devolver(ctx context.Context, pedidoID uint, req *dto.CreateDevolucaoRequest) (*dto.DevolucaoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da devolução inválidos", err)
+	}
+
+	devolucao := &model.PedidoDevolucao{
+		PedidoID: pedidoID,
+		Motivo:   req.Motivo,
+		Usuario:  auth.Actor(ctx),
+	}
+	var pendentes []estornoPendente
+	err := s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		pedido, err := s.pedidoRepo.FindByID(ctx, pedidoID)
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(ctx, pedido.Versao); err != nil {
+			return err
+		}
+		if !slices.Contains(statusDevolucao, pedido.Status) {
+			return apperror.BusinessRule(fmt.Sprintf("pedido %d está %s e não aceita devoluções", pedido.ID, pedido.Status))
+		}
+		antes := toPedidoResponse(pedido)
+
+		devolvidas := pedido.QuantidadesDevolvidas()
+		for _, itemReq := range req.Itens {
+			i := slices.IndexFunc(pedido.Itens, func(item model.PedidoProduto) bool {
+				return item.ID == itemReq.ItemID
+			})
+			if i < 0 {
+				return apperror.BusinessRule(fmt.Sprintf("item %d não pertence ao pedido %d", itemReq.ItemID, pedido.ID))
+			}
+			item := pedido.Itens[i]
+
+			restante := item.Quantidade - devolvidas[item.ID]
+			if itemReq.Quantidade > restante {
+				return apperror.BusinessRule(fmt.Sprintf("item %d tem apenas %d unidade(s) a devolver", item.ID, restante))
+			}
+
+			// O reembolso considera as unidades já devolvidas, inclusive as
+			// de linhas anteriores desta mesma devolução
+			valor := pricing.Reembolso(item, devolvidas[item.ID], itemReq.Quantidade)
+			devolvidas[item.ID] += itemReq.Quantidade
+
+			if err := s.produtoRepo.IncrementEstoque(ctx, item.ProdutoID, itemReq.Quantidade); err != nil {
+				return err
+			}
+
+			devolucao.Valor += valor
+			devolucao.Itens = append(devolucao.Itens, model.PedidoDevolucaoItem{
+				PedidoProdutoID: item.ID,
+				ProdutoID:       item.ProdutoID,
+				Quantidade:      itemReq.Quantidade,
+				Valor:           valor,
+			})
+		}
+
+		motivo := "devolução de itens"
+		if req.Motivo != "" {
+			motivo = req.Motivo
+		}
+		devolucao.Reembolsado, pendentes, err = reembolsar(ctx, s.pagamentoRepo, pedido.ID, devolucao.Valor, motivo, devolucao.Usuario)
+		if err != nil {
+			return err
+		}
+
+		if err := s.pedidoRepo.AddDevolucao(ctx, devolucao); err != nil {
+			return err
+		}
+
+		// A devolução passa a fazer parte do pedido, então a versão dele avança:
+		// o ETag antigo deixa de valer e, de duas devoluções concorrentes, a
+		// segunda falha com conflito
+		if err := s.pedidoRepo.Update(ctx, pedido); err != nil {
+			return err
+		}
+		pedido.Devolucoes = append(pedido.Devolucoes, *devolucao)
+		return s.auditoria.registrar(ctx, pedido.ID, model.AuditUpdate, antes, toPedidoResponse(pedido))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Os estornos vão ao provedor depois do commit; o que ele recusar deixa
+	// de contar como reembolsado
+	falhou, err := enviarEstornos(ctx, s.pagamentoRepo, s.gateway, pendentes)
+	if err != nil {
+		log.Printf("falha ao estornar os pagamentos do pedido %d: %v", pedidoID, err)
+	}
+	if falhou > 0 {
+		devolucao.Reembolsado -= falhou
+		if err := s.pedidoRepo.UpdateReembolsado(ctx, devolucao); err != nil {
+			return nil, err
+		}
+	}
+
+	response := toDevolucaoResponse(devolucao)
+	return &response, nil
+}
+
+// toDevolucaoResponse converte Model para Response DTO
+func toDevolucaoResponse(devolucao *model.PedidoDevolucao) dto.DevolucaoResponse {
+	itens := make([]dto.DevolucaoItemResponse, len(devolucao.Itens))
+	for i, item := range devolucao.Itens {
+		itens[i] = dto.DevolucaoItemResponse{
+			ID:         item.ID,
+			ItemID:     item.PedidoProdutoID,
+			ProdutoID:  item.ProdutoID,
+			Quantidade: item.Quantidade,
+			Valor:      item.Valor,
+		}
+	}
+
+	return dto.DevolucaoResponse{
+		ID:          devolucao.ID,
+		PedidoID:    devolucao.PedidoID,
+		Motivo:      devolucao.Motivo,
+		Valor:       devolucao.Valor,
+		Reembolsado: devolucao.Reembolsado,
+		Usuario:     devolucao.Usuario,
+		Itens:       itens,
+		CreatedAt:   devolucao.CreatedAt,
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// EnderecoService define a interface para operações de negócio dos endereços
+// de um cliente. Todas as operações recebem o cliente dono do endereço; um
+// endereço de outro cliente é tratado como inexistente.
+type EnderecoService interface {
+	Create(ctx context.Context, clienteID uint, req *dto.CreateEnderecoRequest) (*dto.EnderecoResponse, error)
+	FindByClienteID(ctx context.Context, clienteID uint) ([]dto.EnderecoResponse, error)
+	FindByID(ctx context.Context, clienteID, id uint) (*dto.EnderecoResponse, error)
+	Update(ctx context.Context, clienteID, id uint, req *dto.UpdateEnderecoRequest) (*dto.EnderecoResponse, error)
+	Delete(ctx context.Context, clienteID, id uint) error
+}
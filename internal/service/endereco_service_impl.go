@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type enderecoServiceImpl struct {
+	repo        repository.EnderecoRepository
+	clienteRepo repository.ClienteRepository
+	validate    *validator.Validate
+}
+
+// NewEnderecoService cria uma nova instância do serviço
+func NewEnderecoService(repo repository.EnderecoRepository, clienteRepo repository.ClienteRepository) EnderecoService {
+	return &enderecoServiceImpl{
+		repo:        repo,
+		clienteRepo: clienteRepo,
+		validate:    newValidator(),
+	}
+}
+
+func (s *enderecoServiceImpl) Create(ctx context.Context, clienteID uint, req *dto.CreateEnderecoRequest) (*dto.EnderecoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do endereço inválidos", err)
+	}
+
+	// Verificar se o cliente existe
+	if _, err := s.clienteRepo.FindByID(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	endereco := &model.Endereco{
+		ClienteID:   clienteID,
+		Apelido:     req.Apelido,
+		CEP:         normalizarCEP(req.CEP),
+		Logradouro:  req.Logradouro,
+		Numero:      req.Numero,
+		Complemento: req.Complemento,
+		Bairro:      req.Bairro,
+		Cidade:      req.Cidade,
+		UF:          strings.ToUpper(req.UF),
+		Padrao:      req.Padrao,
+	}
+
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		// O primeiro endereço do cliente é sempre o padrão
+		padrao, err := s.repo.FindPadrao(ctx, clienteID)
+		if err != nil {
+			return err
+		}
+		if padrao == nil {
+			endereco.Padrao = true
+		}
+
+		if err := s.repo.Create(ctx, endereco); err != nil {
+			return err
+		}
+		if endereco.Padrao && padrao != nil {
+			return s.repo.DesmarcarPadrao(ctx, clienteID, endereco.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(endereco), nil
+}
+
+func (s *enderecoServiceImpl) FindByClienteID(ctx context.Context, clienteID uint) ([]dto.EnderecoResponse, error) {
+	// Verificar se o cliente existe, para não confundir 404 com lista vazia
+	if _, err := s.clienteRepo.FindByID(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	enderecos, err := s.repo.FindByClienteID(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.EnderecoResponse, len(enderecos))
+	for i := range enderecos {
+		response[i] = *s.toResponse(&enderecos[i])
+	}
+	return response, nil
+}
+
+func (s *enderecoServiceImpl) FindByID(ctx context.Context, clienteID, id uint) (*dto.EnderecoResponse, error) {
+	endereco, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(endereco), nil
+}
+
+func (s *enderecoServiceImpl) Update(ctx context.Context, clienteID, id uint, req *dto.UpdateEnderecoRequest) (*dto.EnderecoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do endereço inválidos", err)
+	}
+
+	endereco, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := etag.Check(ctx, endereco.Versao); err != nil {
+		return nil, err
+	}
+	if endereco.Padrao && !*req.Padrao {
+		return nil, apperror.BusinessRule("o cliente precisa de um endereço padrão; marque outro endereço como padrão")
+	}
+
+	// pedidos já feitos guardam uma cópia do endereço e não são afetados
+	marcarPadrao := !endereco.Padrao && *req.Padrao
+	endereco.Apelido = req.Apelido
+	endereco.CEP = normalizarCEP(req.CEP)
+	endereco.Logradouro = req.Logradouro
+	endereco.Numero = req.Numero
+	endereco.Complemento = req.Complemento
+	endereco.Bairro = req.Bairro
+	endereco.Cidade = req.Cidade
+	endereco.UF = strings.ToUpper(req.UF)
+	endereco.Padrao = *req.Padrao
+
+	err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, endereco); err != nil {
+			return err
+		}
+		if marcarPadrao {
+			return s.repo.DesmarcarPadrao(ctx, clienteID, endereco.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(endereco), nil
+}
+
+func (s *enderecoServiceImpl) Delete(ctx context.Context, clienteID, id uint) error {
+	endereco, err := s.buscar(ctx, clienteID, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, endereco.Versao); err != nil {
+		return err
+	}
+
+	return s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if !endereco.Padrao {
+			return nil
+		}
+
+		// Excluir o padrão promove o endereço mais antigo que restou
+		restantes, err := s.repo.FindByClienteID(ctx, clienteID)
+		if err != nil || len(restantes) == 0 {
+			return err
+		}
+		promovido := &restantes[0]
+		promovido.Padrao = true
+		return s.repo.Update(ctx, promovido)
+	})
+}
+
+// buscar retorna o endereço se ele pertencer ao cliente
+func (s *enderecoServiceImpl) buscar(ctx context.Context, clienteID, id uint) (*model.Endereco, error) {
+	endereco, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endereco.ClienteID != clienteID {
+		return nil, apperror.NotFound("endereço não encontrado")
+	}
+	return endereco, nil
+}
+
+// toResponse converte Model para Response DTO
+func (s *enderecoServiceImpl) toResponse(endereco *model.Endereco) *dto.EnderecoResponse {
+	return &dto.EnderecoResponse{
+		ID:          endereco.ID,
+		ClienteID:   endereco.ClienteID,
+		Apelido:     endereco.Apelido,
+		CEP:         endereco.CEP,
+		Logradouro:  endereco.Logradouro,
+		Numero:      endereco.Numero,
+		Complemento: endereco.Complemento,
+		Bairro:      endereco.Bairro,
+		Cidade:      endereco.Cidade,
+		UF:          endereco.UF,
+		Padrao:      endereco.Padrao,
+		Versao:      endereco.Versao,
+		CreatedAt:   endereco.CreatedAt,
+		UpdatedAt:   endereco.UpdatedAt,
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// FreteService define a interface para a cotação de frete e a manutenção da
+// tabela de frete (zonas por faixa de CEP e faixas de peso)
+type FreteService interface {
+	Cotar(ctx context.Context, req *dto.CotacaoFreteRequest) (*dto.CotacaoFreteResponse, error)
+	CreateZona(ctx context.Context, req *dto.CreateFreteZonaRequest) (*dto.FreteZonaResponse, error)
+	FindAllZonas(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.FreteZonaResponse], error)
+	FindZonaByID(ctx context.Context, id uint) (*dto.FreteZonaResponse, error)
+	UpdateZona(ctx context.Context, id uint, req *dto.UpdateFreteZonaRequest) (*dto.FreteZonaResponse, error)
+	DeleteZona(ctx context.Context, id uint) error
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type freteServiceImpl struct {
+	repo        repository.FreteRepository
+	produtoRepo repository.ProdutoRepository
+	calculator  frete.Calculator
+	validate    *validator.Validate
+}
+
+// NewFreteService cria uma nova instância do serviço
+func NewFreteService(repo repository.FreteRepository, produtoRepo repository.ProdutoRepository, calculator frete.Calculator) FreteService {
+	return &freteServiceImpl{
+		repo:        repo,
+		produtoRepo: produtoRepo,
+		calculator:  calculator,
+		validate:    newValidator(),
+	}
+}
+
+func (s *freteServiceImpl) Cotar(ctx context.Context, req *dto.CotacaoFreteRequest) (*dto.CotacaoFreteResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da cotação inválidos", err)
+	}
+
+	pacote := frete.Pacote{CEP: normalizarCEP(req.CEP)}
+	for _, item := range req.Itens {
+		produto, err := s.produtoRepo.FindByID(ctx, item.ProdutoID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, apperror.BusinessRule(fmt.Sprintf("produto %d não encontrado", item.ProdutoID))
+			}
+			return nil, err
+		}
+		pacote.Volumes = append(pacote.Volumes, volumeProduto(produto, item.Quantidade))
+	}
+
+	opcoes, err := s.calculator.Cotar(ctx, pacote)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CotacaoFreteResponse{
+		CEP:        pacote.CEP,
+		PesoGramas: pacote.PesoTaxavel(),
+		Opcoes:     make([]dto.OpcaoFreteResponse, len(opcoes)),
+	}
+	for i, opcao := range opcoes {
+		response.Opcoes[i] = dto.OpcaoFreteResponse{
+			Servico:   opcao.Servico,
+			Valor:     opcao.Valor,
+			PrazoDias: opcao.PrazoDias,
+		}
+	}
+	return response, nil
+}
+
+func (s *freteServiceImpl) CreateZona(ctx context.Context, req *dto.CreateFreteZonaRequest) (*dto.FreteZonaResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da zona de frete inválidos", err)
+	}
+
+	ativo := true
+	if req.Ativo != nil {
+		ativo = *req.Ativo
+	}
+
+	zona := &model.FreteZona{
+		Nome:      req.Nome,
+		Servico:   strings.TrimSpace(req.Servico),
+		CEPInicio: normalizarCEP(req.CEPInicio),
+		CEPFim:    normalizarCEP(req.CEPFim),
+		PrazoDias: req.PrazoDias,
+		Faixas:    toFreteFaixas(req.Faixas),
+		Ativo:     ativo,
+	}
+	if err := validarZona(zona); err != nil {
+		return nil, err
+	}
+
+	// Criar no banco (com cascade para as faixas)
+	if err := s.repo.CreateZona(ctx, zona); err != nil {
+		return nil, err
+	}
+
+	return s.toZonaResponse(zona), nil
+}
+
+func (s *freteServiceImpl) FindAllZonas(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.FreteZonaResponse], error) {
+	opts, err := toListOptions(page, freteZonaCampos)
+	if err != nil {
+		return nil, err
+	}
+	zonas, err := s.repo.FindAllZonas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(zonas, page, opts, s.toZonaResponseValue, freteZonaID), nil
+}
+
+func (s *freteServiceImpl) FindZonaByID(ctx context.Context, id uint) (*dto.FreteZonaResponse, error) {
+	zona, err := s.repo.FindZonaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toZonaResponse(zona), nil
+}
+
+func (s *freteServiceImpl) UpdateZona(ctx context.Context, id uint, req *dto.UpdateFreteZonaRequest) (*dto.FreteZonaResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da zona de frete inválidos", err)
+	}
+
+	// Buscar zona existente
+	zona, err := s.repo.FindZonaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := etag.Check(ctx, zona.Versao); err != nil {
+		return nil, err
+	}
+
+	zona.Nome = req.Nome
+	zona.Servico = strings.TrimSpace(req.Servico)
+	zona.CEPInicio = normalizarCEP(req.CEPInicio)
+	zona.CEPFim = normalizarCEP(req.CEPFim)
+	zona.PrazoDias = req.PrazoDias
+	zona.Faixas = toFreteFaixas(req.Faixas)
+	zona.Ativo = *req.Ativo
+	if err := validarZona(zona); err != nil {
+		return nil, err
+	}
+
+	// Atualizar no banco; os pedidos já criados mantêm o frete cobrado
+	if err := s.repo.UpdateZona(ctx, zona); err != nil {
+		return nil, err
+	}
+
+	return s.toZonaResponse(zona), nil
+}
+
+func (s *freteServiceImpl) DeleteZona(ctx context.Context, id uint) error {
+	// Verificar se existe
+	zona, err := s.repo.FindZonaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, zona.Versao); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteZona(ctx, id)
+}
+
+// validarZona confere a faixa de CEPs e impede faixas de peso repetidas, que
+// deixariam o preço ambíguo
+func validarZona(zona *model.FreteZona) error {
+	if zona.CEPFim < zona.CEPInicio {
+		return apperror.Validation("dados da zona de frete inválidos", errors.New("cep_fim deve ser maior ou igual a cep_inicio"))
+	}
+	pesos := map[int]bool{}
+	for _, faixa := range zona.Faixas {
+		if pesos[faixa.PesoMaximoGramas] {
+			return apperror.Validation("dados da zona de frete inválidos",
+				fmt.Errorf("faixa de peso de %d gramas repetida", faixa.PesoMaximoGramas))
+		}
+		pesos[faixa.PesoMaximoGramas] = true
+	}
+	return nil
+}
+
+// volumeProduto descreve a embalagem de um item para o cálculo do frete
+func volumeProduto(produto *model.Produto, quantidade int) frete.Volume {
+	return frete.Volume{
+		PesoGramas:    produto.PesoGramas,
+		AlturaCm:      produto.AlturaCm,
+		LarguraCm:     produto.LarguraCm,
+		ComprimentoCm: produto.ComprimentoCm,
+		Quantidade:    quantidade,
+	}
+}
+
+func toFreteFaixas(req []dto.FreteFaixaRequest) []model.FreteFaixa {
+	faixas := make([]model.FreteFaixa, len(req))
+	for i, faixa := range req {
+		faixas[i] = model.FreteFaixa{PesoMaximoGramas: faixa.PesoMaximoGramas, Valor: faixa.Valor}
+	}
+	return faixas
+}
+
+// toZonaResponseValue converte Model para Response DTO por valor, usado nas listagens
+func (s *freteServiceImpl) toZonaResponseValue(zona *model.FreteZona) dto.FreteZonaResponse {
+	return *s.toZonaResponse(zona)
+}
+
+func freteZonaID(zona *model.FreteZona) uint {
+	return zona.ID
+}
+
+// toZonaResponse converte Model para Response DTO
+func (s *freteServiceImpl) toZonaResponse(zona *model.FreteZona) *dto.FreteZonaResponse {
+	faixas := make([]dto.FreteFaixaResponse, len(zona.Faixas))
+	for i, faixa := range zona.Faixas {
+		faixas[i] = dto.FreteFaixaResponse{PesoMaximoGramas: faixa.PesoMaximoGramas, Valor: faixa.Valor}
+	}
+
+	return &dto.FreteZonaResponse{
+		ID:        zona.ID,
+		Nome:      zona.Nome,
+		Servico:   zona.Servico,
+		CEPInicio: zona.CEPInicio,
+		CEPFim:    zona.CEPFim,
+		PrazoDias: zona.PrazoDias,
+		Faixas:    faixas,
+		Ativo:     zona.Ativo,
+		Versao:    zona.Versao,
+		CreatedAt: zona.CreatedAt,
+		UpdatedAt: zona.UpdatedAt,
+		DeletedAt: excluidoEm(zona.DeletedAt),
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// PagamentoService define a interface para cobrar pedidos no provedor de
+// pagamentos, receber as suas notificações e estornar pagamentos
+type PagamentoService interface {
+	Create(ctx context.Context, pedidoID uint, req *dto.CreatePagamentoRequest) (*dto.PagamentoResponse, error)
+	// FindByPedidoID lista os pagamentos do pedido com a conciliação do valor pago
+	FindByPedidoID(ctx context.Context, pedidoID uint) (*dto.PagamentosPedidoResponse, error)
+	Estornar(ctx context.Context, pedidoID, pagamentoID uint, req *dto.CreateEstornoRequest) (*dto.PagamentoResponse, error)
+	// Webhook processa uma notificação assinada do provedor. Notificações
+	// repetidas são aceitas sem efeito.
+	Webhook(ctx context.Context, payload []byte, assinatura string) error
+	// Conciliar resolve com o provedor as cobranças sem resposta registrada
+	// criadas antes de antes e quita os pedidos com pagamentos não aplicados
+	Conciliar(ctx context.Context, antes time.Time) error
+}
@@ -0,0 +1,553 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+// tentativasQuitacao limita as repetições da quitação que perde a corrida
+// para outra gravação
+const tentativasQuitacao = 3
+
+type pagamentoServiceImpl struct {
+	repo       repository.PagamentoRepository
+	pedidoRepo repository.PedidoRepository
+	gateway    pagamento.PaymentGateway
+	auditoria  auditoria
+	validate   *validator.Validate
+}
+
+// NewPagamentoService cria uma nova instância do serviço
+func NewPagamentoService(repo repository.PagamentoRepository, pedidoRepo repository.PedidoRepository, gateway pagamento.PaymentGateway, auditRepo repository.AuditRepository) PagamentoService {
+	return &pagamentoServiceImpl{
+		repo:       repo,
+		pedidoRepo: pedidoRepo,
+		gateway:    gateway,
+		auditoria:  auditoria{repo: auditRepo, entidade: model.AuditPedido},
+		validate:   newValidator(),
+	}
+}
+
+// conciliacao resume os pagamentos de um pedido: pago é o valor confirmado
+// menos os estornos e emCobranca o valor dos pagamentos ainda pendentes
+type conciliacao struct {
+	pago       money.Money
+	emCobranca money.Money
+}
+
+func conciliar(pagamentos []model.Pagamento) conciliacao {
+	var c conciliacao
+	for i := range pagamentos {
+		c.pago += pagamentos[i].Liquido()
+		if pagamentos[i].Status == model.PagamentoPendente {
+			c.emCobranca += pagamentos[i].Valor
+		}
+	}
+	return c
+}
+
+func (s *pagamentoServiceImpl) Create(ctx context.Context, pedidoID uint, req *dto.CreatePagamentoRequest) (*dto.PagamentoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do pagamento inválidos", err)
+	}
+
+	var pag *model.Pagamento
+	// O saldo é conferido e a cobrança gravada pendente na mesma transação,
+	// assim duas cobranças simultâneas não ultrapassam o valor do pedido. O
+	// provedor só é chamado depois do commit, para a transação não ficar
+	// aberta esperando a rede.
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		pedido, err := s.pedidoRepo.FindByID(ctx, pedidoID)
+		if err != nil {
+			return err
+		}
+		if pedido.Status != model.StatusPendente {
+			return apperror.BusinessRule(fmt.Sprintf("pedido %d está %s e não aceita pagamentos", pedido.ID, pedido.Status))
+		}
+
+		pagamentos, err := s.repo.FindByPedidoID(ctx, pedido.ID)
+		if err != nil {
+			return err
+		}
+		c := conciliar(pagamentos)
+
+		// Sem valor, cobra tudo o que ainda não foi pago nem está em cobrança
+		aCobrar := pedido.ValorTotal - c.pago - c.emCobranca
+		if aCobrar <= 0 {
+			return apperror.BusinessRule(fmt.Sprintf("pedido %d não tem saldo a cobrar", pedido.ID))
+		}
+		valor := req.Valor
+		if valor == 0 {
+			valor = aCobrar
+		}
+		if valor > aCobrar {
+			return apperror.BusinessRule(fmt.Sprintf("valor %s excede o saldo a cobrar de %s", valor, aCobrar))
+		}
+
+		referencia, err := referenciaProvisoria()
+		if err != nil {
+			return err
+		}
+		pag = &model.Pagamento{
+			PedidoID:   pedido.ID,
+			Metodo:     req.Metodo,
+			Valor:      valor,
+			Status:     model.PagamentoPendente,
+			Provedor:   s.gateway.Nome(),
+			Referencia: referencia,
+		}
+		return s.repo.Create(ctx, pag)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A referência provisória vai como chave de idempotência: se a resposta se
+	// perder, a cobrança continua pendente e a conciliação a consulta por ela
+	transacao, err := s.gateway.Cobrar(ctx, pagamento.Cobranca{Referencia: pag.Referencia, PedidoID: pag.PedidoID, Metodo: pag.Metodo, Valor: pag.Valor})
+	if err != nil {
+		return nil, err
+	}
+
+	// A resposta do provedor é gravada sozinha, antes da quitação: o que
+	// acontecer com o pedido depois não desfaz o registro da cobrança
+	if err := s.registrarCobranca(ctx, pag, transacao); err != nil {
+		return nil, err
+	}
+	if pag.Status == model.PagamentoConfirmado {
+		if err := s.quitar(ctx, pag.PedidoID); err != nil {
+			// O pagamento já está gravado; a conciliação quita o pedido depois
+			log.Printf("falha ao quitar o pedido %d: %v", pag.PedidoID, err)
+		}
+	}
+
+	return toPagamentoResponse(pag), nil
+}
+
+// registrarCobranca troca a referência provisória do pagamento pela do
+// provedor e grava o status que ele informou
+func (s *pagamentoServiceImpl) registrarCobranca(ctx context.Context, pag *model.Pagamento, transacao *pagamento.Transacao) error {
+	pag.Referencia = transacao.Referencia
+	pag.Status = transacao.Status
+	if pag.Status == model.PagamentoConfirmado {
+		agora := time.Now()
+		pag.ConfirmadoEm = &agora
+	}
+	return s.repo.Update(ctx, pag)
+}
+
+// quitar aplica ao pedido os pagamentos confirmados numa transação própria,
+// repetida quando perde a corrida para outra gravação no pedido ou no audit log
+func (s *pagamentoServiceImpl) quitar(ctx context.Context, pedidoID uint) error {
+	var err error
+	for range tentativasQuitacao {
+		var pendentes []estornoPendente
+		err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			pendentes, err = s.quitarPedido(ctx, pedidoID)
+			return err
+		})
+		if err == nil {
+			s.concluirEstornos(ctx, pedidoID, pendentes)
+			return nil
+		}
+		if !errors.Is(err, apperror.ErrConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+// Conciliar resolve as cobranças gravadas antes de antes que ainda têm a
+// referência provisória, consultando o provedor: as que ele recebeu ganham a
+// referência e o status dele; as que não recebeu expiram como falhou e liberam
+// o saldo. Em seguida quita os pedidos com pagamentos confirmados ainda não
+// aplicados, o que também refaz os estornos recusados de pedidos cancelados.
+func (s *pagamentoServiceImpl) Conciliar(ctx context.Context, antes time.Time) error {
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: "gateway:" + s.gateway.Nome()})
+
+	provisorios, err := s.repo.FindProvisorios(ctx, antes)
+	if err != nil {
+		return err
+	}
+	var erros []error
+	for i := range provisorios {
+		pag := &provisorios[i]
+		transacao, err := s.gateway.Consultar(ctx, pag.Referencia)
+		switch {
+		case errors.Is(err, pagamento.ErrCobrancaNaoEncontrada):
+			pag.Status = model.PagamentoFalhou
+			err = s.repo.Update(ctx, pag)
+		case err == nil:
+			err = s.registrarCobranca(ctx, pag, transacao)
+		}
+		if err != nil {
+			erros = append(erros, fmt.Errorf("pagamento %d: %w", pag.ID, err))
+		}
+	}
+
+	pedidos, err := s.repo.FindPedidosAQuitar(ctx)
+	if err != nil {
+		return errors.Join(append(erros, err)...)
+	}
+	for _, pedidoID := range pedidos {
+		if err := s.quitar(ctx, pedidoID); err != nil {
+			erros = append(erros, fmt.Errorf("pedido %d: %w", pedidoID, err))
+		}
+	}
+	return errors.Join(erros...)
+}
+
+func (s *pagamentoServiceImpl) FindByPedidoID(ctx context.Context, pedidoID uint) (*dto.PagamentosPedidoResponse, error) {
+	pedido, err := s.pedidoRepo.FindByID(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+
+	pagamentos, err := s.repo.FindByPedidoID(ctx, pedido.ID)
+	if err != nil {
+		return nil, err
+	}
+	c := conciliar(pagamentos)
+
+	responses := make([]dto.PagamentoResponse, len(pagamentos))
+	for i := range pagamentos {
+		responses[i] = *toPagamentoResponse(&pagamentos[i])
+	}
+
+	return &dto.PagamentosPedidoResponse{
+		PedidoID:   pedido.ID,
+		ValorTotal: pedido.ValorTotal,
+		Pago:       c.pago,
+		EmCobranca: c.emCobranca,
+		Saldo:      pedido.ValorTotal - c.pago,
+		Pagamentos: responses,
+	}, nil
+}
+
+func (s *pagamentoServiceImpl) Estornar(ctx context.Context, pedidoID, pagamentoID uint, req *dto.CreateEstornoRequest) (*dto.PagamentoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do estorno inválidos", err)
+	}
+
+	var pendente estornoPendente
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		existente, err := s.repo.FindByID(ctx, pagamentoID)
+		if err != nil {
+			return err
+		}
+		// Um pagamento de outro pedido é tratado como inexistente
+		if existente.PedidoID != pedidoID {
+			return apperror.NotFound("pagamento não encontrado")
+		}
+		if existente.Status != model.PagamentoConfirmado {
+			return apperror.BusinessRule(fmt.Sprintf("pagamento %d está %s e não pode ser estornado", existente.ID, existente.Status))
+		}
+
+		// Sem valor, estorna tudo o que ainda não foi estornado
+		disponivel := existente.Valor - existente.Estornado
+		valor := req.Valor
+		if valor == 0 {
+			valor = disponivel
+		}
+		if valor > disponivel {
+			return apperror.BusinessRule(fmt.Sprintf("valor %s excede o disponível para estorno de %s", valor, disponivel))
+		}
+
+		pendente, err = estornarPagamento(ctx, s.repo, existente, valor, req.Motivo, auth.Actor(ctx))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Se o provedor recusar, o estorno fica como falhou, o valor volta a ficar
+	// disponível e o erro do provedor é devolvido
+	if _, err := enviarEstornos(ctx, s.repo, s.gateway, []estornoPendente{pendente}); err != nil {
+		return nil, err
+	}
+
+	pag, err := s.repo.FindByID(ctx, pagamentoID)
+	if err != nil {
+		return nil, err
+	}
+	return toPagamentoResponse(pag), nil
+}
+
+func (s *pagamentoServiceImpl) Webhook(ctx context.Context, payload []byte, assinatura string) error {
+	evento, err := s.gateway.Webhook(payload, assinatura)
+	if err != nil {
+		if errors.Is(err, pagamento.ErrAssinaturaInvalida) {
+			return apperror.Forbidden(err.Error())
+		}
+		if errors.Is(err, pagamento.ErrEventoInvalido) {
+			return apperror.Validation("notificação do provedor inválida", err)
+		}
+		return err
+	}
+
+	// A notificação não tem usuário autenticado; as escritas que ela causa
+	// ficam no audit log em nome do provedor, como no histórico do pedido
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: "gateway:" + s.gateway.Nome()})
+
+	// Uma notificação que chega antes da conciliação da cobrança não encontra
+	// a referência e recebe 404; o provedor a reenvia depois
+	var pag *model.Pagamento
+	var pendentes []estornoPendente
+	err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		pag, err = s.repo.FindByReferencia(ctx, evento.Referencia)
+		if err != nil {
+			return err
+		}
+
+		// O provedor pode reenviar a mesma notificação; a repetição não tem efeito
+		switch {
+		case evento.Status == model.PagamentoConfirmado && pag.ConfirmadoEm != nil:
+			return nil
+		case evento.Status == pag.Status:
+			return nil
+		case pag.Status != model.PagamentoPendente:
+			return apperror.Conflict(fmt.Sprintf("pagamento %d já está %s", pag.ID, pag.Status))
+		}
+
+		pag.Status = evento.Status
+		if pag.Status == model.PagamentoConfirmado {
+			agora := time.Now()
+			pag.ConfirmadoEm = &agora
+		}
+		if err := s.repo.Update(ctx, pag); err != nil {
+			return err
+		}
+
+		if pag.Status == model.PagamentoConfirmado {
+			pendentes, err = s.quitarPedido(ctx, pag.PedidoID)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.concluirEstornos(ctx, pag.PedidoID, pendentes)
+	return nil
+}
+
+// quitarPedido passa o pedido pendente para pago quando os pagamentos
+// confirmados, descontados os estornos, cobrem o valor total. Um pagamento
+// confirmado depois do cancelamento do pedido é estornado por inteiro; os
+// estornos retornados são enviados ao provedor depois do commit.
+func (s *pagamentoServiceImpl) quitarPedido(ctx context.Context, pedidoID uint) ([]estornoPendente, error) {
+	pedido, err := s.pedidoRepo.FindByID(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	if pedido.Status == model.StatusCancelado {
+		_, pendentes, err := reembolsar(ctx, s.repo, pedido.ID, pedido.ValorTotal, "pedido cancelado", "gateway:"+s.gateway.Nome())
+		return pendentes, err
+	}
+	if pedido.Status != model.StatusPendente {
+		return nil, nil
+	}
+
+	pagamentos, err := s.repo.FindByPedidoID(ctx, pedido.ID)
+	if err != nil {
+		return nil, err
+	}
+	if conciliar(pagamentos).pago < pedido.ValorTotal {
+		return nil, nil
+	}
+
+	antes := toPedidoResponse(pedido)
+	pedido.Status = model.StatusPago
+	if err := s.pedidoRepo.Update(ctx, pedido); err != nil {
+		return nil, err
+	}
+
+	// A mudança é registrada em nome do provedor que confirmou o pagamento
+	if err := s.pedidoRepo.AddHistoricoStatus(ctx, &model.PedidoStatusHistorico{
+		PedidoID:       pedido.ID,
+		StatusAnterior: model.StatusPendente,
+		StatusNovo:     model.StatusPago,
+		Usuario:        "gateway:" + s.gateway.Nome(),
+	}); err != nil {
+		return nil, err
+	}
+	return nil, s.auditoria.registrar(ctx, pedido.ID, model.AuditUpdate, antes, toPedidoResponse(pedido))
+}
+
+// concluirEstornos envia os estornos do pedido ao provedor. A cobrança já foi
+// gravada, então uma recusa não desfaz a operação: o estorno fica como falhou
+// e pode ser refeito pelo endpoint de estornos.
+func (s *pagamentoServiceImpl) concluirEstornos(ctx context.Context, pedidoID uint, pendentes []estornoPendente) {
+	if _, err := enviarEstornos(ctx, s.repo, s.gateway, pendentes); err != nil {
+		log.Printf("falha ao estornar os pagamentos do pedido %d: %v", pedidoID, err)
+	}
+}
+
+// estornoPendente é um estorno gravado na transação e ainda não enviado ao
+// provedor; cobranca é a referência do pagamento no provedor
+type estornoPendente struct {
+	estorno  *model.PagamentoEstorno
+	cobranca string
+}
+
+// estornarPagamento reserva parte de um pagamento confirmado: grava o estorno
+// pendente, soma o valor ao Estornado e marca o pagamento como estornado
+// quando nada resta. O provedor só é chamado por enviarEstornos, depois do commit.
+func estornarPagamento(ctx context.Context, repo repository.PagamentoRepository, pag *model.Pagamento, valor money.Money, motivo, usuario string) (estornoPendente, error) {
+	estorno := &model.PagamentoEstorno{
+		PagamentoID: pag.ID,
+		Valor:       valor,
+		Status:      model.EstornoPendente,
+		Motivo:      motivo,
+		Usuario:     usuario,
+	}
+	if err := repo.AddEstorno(ctx, estorno); err != nil {
+		return estornoPendente{}, err
+	}
+
+	pag.Estornado += valor
+	if pag.Estornado == pag.Valor {
+		pag.Status = model.PagamentoEstornado
+	}
+	pag.Estornos = append(pag.Estornos, *estorno)
+	if err := repo.Update(ctx, pag); err != nil {
+		return estornoPendente{}, err
+	}
+	return estornoPendente{estorno: estorno, cobranca: pag.Referencia}, nil
+}
+
+// reembolsar reserva para estorno até valor dos pagamentos confirmados do
+// pedido, do mais recente para o mais antigo, e retorna quanto foi reservado
+// e os estornos a enviar. Pedidos pagos por baixa manual não têm pagamentos
+// e o reembolso fica por fora.
+func reembolsar(ctx context.Context, repo repository.PagamentoRepository, pedidoID uint, valor money.Money, motivo, usuario string) (money.Money, []estornoPendente, error) {
+	pagamentos, err := repo.FindByPedidoID(ctx, pedidoID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var reembolsado money.Money
+	var pendentes []estornoPendente
+	for i := len(pagamentos) - 1; i >= 0 && reembolsado < valor; i-- {
+		pag := &pagamentos[i]
+		if pag.Status != model.PagamentoConfirmado {
+			continue
+		}
+		parte := min(pag.Valor-pag.Estornado, valor-reembolsado)
+		pendente, err := estornarPagamento(ctx, repo, pag, parte, motivo, usuario)
+		if err != nil {
+			return reembolsado, pendentes, err
+		}
+		reembolsado += parte
+		pendentes = append(pendentes, pendente)
+	}
+	return reembolsado, pendentes, nil
+}
+
+// enviarEstornos chama o provedor para cada estorno reservado, fora de
+// qualquer transação, e concilia o estorno com a resposta: concluído com a
+// referência do provedor ou, se recusado, falhou e com o valor devolvido ao
+// disponível do pagamento. Retorna o total recusado e os erros encontrados.
+func enviarEstornos(ctx context.Context, repo repository.PagamentoRepository, gateway pagamento.PaymentGateway, pendentes []estornoPendente) (money.Money, error) {
+	var falhou money.Money
+	var erros []error
+	for _, p := range pendentes {
+		referencia, err := gateway.Estornar(ctx, p.cobranca, p.estorno.Valor)
+		if err != nil {
+			erros = append(erros, err)
+			if err := liberarEstorno(ctx, repo, p.estorno); err != nil {
+				erros = append(erros, err)
+				continue
+			}
+			falhou += p.estorno.Valor
+			continue
+		}
+
+		p.estorno.Status = model.EstornoConcluido
+		p.estorno.Referencia = referencia
+		if err := repo.UpdateEstorno(ctx, p.estorno); err != nil {
+			erros = append(erros, err)
+		}
+	}
+	return falhou, errors.Join(erros...)
+}
+
+// liberarEstorno marca como falhou um estorno recusado pelo provedor e tira o
+// valor dele do Estornado do pagamento, que volta a ficar confirmado
+func liberarEstorno(ctx context.Context, repo repository.PagamentoRepository, estorno *model.PagamentoEstorno) error {
+	return repo.WithTransaction(ctx, func(ctx context.Context) error {
+		pag, err := repo.FindByID(ctx, estorno.PagamentoID)
+		if err != nil {
+			return err
+		}
+		pag.Estornado -= estorno.Valor
+		if pag.Status == model.PagamentoEstornado {
+			pag.Status = model.PagamentoConfirmado
+		}
+		if err := repo.Update(ctx, pag); err != nil {
+			return err
+		}
+
+		estorno.Status = model.EstornoFalhou
+		return repo.UpdateEstorno(ctx, estorno)
+	})
+}
+
+// referenciaProvisoria identifica a cobrança gravada antes da resposta do
+// provedor; a conciliação a troca pela referência dele
+func referenciaProvisoria() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar a referência do pagamento: %w", err)
+	}
+	return model.PrefixoReferenciaProvisoria + hex.EncodeToString(b), nil
+}
+
+// toPagamentoResponse converte Model para Response DTO
+func toPagamentoResponse(pag *model.Pagamento) *dto.PagamentoResponse {
+	estornos := make([]dto.EstornoResponse, len(pag.Estornos))
+	for i, e := range pag.Estornos {
+		estornos[i] = dto.EstornoResponse{
+			ID:         e.ID,
+			Valor:      e.Valor,
+			Status:     e.Status,
+			Referencia: e.Referencia,
+			Motivo:     e.Motivo,
+			Usuario:    e.Usuario,
+			CreatedAt:  e.CreatedAt,
+		}
+	}
+
+	return &dto.PagamentoResponse{
+		ID:           pag.ID,
+		PedidoID:     pag.PedidoID,
+		Metodo:       pag.Metodo,
+		Valor:        pag.Valor,
+		Estornado:    pag.Estornado,
+		Status:       pag.Status,
+		Provedor:     pag.Provedor,
+		Referencia:   pag.Referencia,
+		ConfirmadoEm: pag.ConfirmadoEm,
+		Estornos:     estornos,
+		Versao:       pag.Versao,
+		CreatedAt:    pag.CreatedAt,
+		UpdatedAt:    pag.UpdatedAt,
+	}
+}
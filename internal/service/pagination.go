@@ -0,0 +1,80 @@
+package service
+
+import (
+	"time"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// toListOptions converte os parâmetros de paginação, filtros e ordenação da
+// API para o repositório. Filtros e ordenação são validados contra a
+// whitelist c da entidade; campos fora dela retornam erro de validação.
+func toListOptions(req dto.PageRequest, c campos) (repository.ListOptions, error) {
+	opts := repository.ListOptions{Limit: req.Limit, Deleted: req.Deleted}.Normalize()
+
+	var err error
+	if opts.Filters, err = c.filters(req.Filters); err != nil {
+		return opts, err
+	}
+	if opts.Sort, err = c.sort(req.Sort); err != nil {
+		return opts, err
+	}
+
+	if req.Page > 0 {
+		opts.Offset = (req.Page - 1) * opts.Limit
+	} else if req.AfterID > 0 {
+		opts.AfterID = req.AfterID
+		if len(req.Sort) > 0 {
+			if opts.AfterKeys, err = c.afterKeys(req.Sort, req.AfterKeys); err != nil {
+				return opts, err
+			}
+		}
+	}
+	return opts, nil
+}
+
+// toPageResponse monta o envelope paginado convertendo cada item com convert.
+// id informa o ID de um item, usado para gerar o cursor da próxima página.
+func toPageResponse[M any, R any](page *repository.Page[M], req dto.PageRequest, opts repository.ListOptions, convert func(*M) R, id func(*M) uint) *dto.PageResponse[R] {
+	data := make([]R, len(page.Items))
+	for i := range page.Items {
+		data[i] = convert(&page.Items[i])
+	}
+
+	resp := &dto.PageResponse[R]{
+		Data:  data,
+		Total: page.Total,
+		Limit: opts.Limit,
+	}
+
+	if req.Page > 0 {
+		resp.Page = req.Page
+		if page.HasMore {
+			resp.NextPage = req.Page + 1
+		}
+	} else if page.HasMore && len(page.Items) > 0 {
+		ultimo := id(&page.Items[len(page.Items)-1])
+		resp.NextCursor = dto.EncodeCursor(ultimo)
+		if len(req.Sort) > 0 {
+			// listagens ordenadas continuam a partir dos valores de ordenação do último item
+			keys := make([]*string, len(page.LastKeys))
+			for i, k := range page.LastKeys {
+				keys[i] = formatKey(k)
+			}
+			resp.NextCursor = dto.EncodeSortCursor(dto.Cursor{ID: ultimo, Sort: dto.FormatSort(req.Sort), Keys: keys})
+		}
+	}
+
+	return resp
+}
+
+// excluidoEm devolve a data da exclusão (soft delete) para as respostas, ou
+// nil se o registro está ativo; só aparece nas listagens com ?deleted=
+func excluidoEm(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return &deletedAt.Time
+}
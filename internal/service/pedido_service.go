@@ -9,11 +9,17 @@ import (
 // PedidoService define a interface para operações de negócio de Pedido
 type PedidoService interface {
 	Create(ctx context.Context, req *dto.CreatePedidoRequest) (*dto.PedidoResponse, error)
-	FindAll(ctx context.Context) ([]dto.PedidoResponse, error)
+	FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error)
 	FindByID(ctx context.Context, id uint) (*dto.PedidoResponse, error)
-	FindByClienteID(ctx context.Context, clienteID uint) ([]dto.PedidoResponse, error)
-	FindByStatus(ctx context.Context, status string) ([]dto.PedidoResponse, error)
+	FindByClienteID(ctx context.Context, clienteID uint, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error)
+	FindByStatus(ctx context.Context, status string, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error)
 	UpdateStatus(ctx context.Context, id uint, req *dto.UpdatePedidoRequest) (*dto.PedidoResponse, error)
+	// AddItem, UpdateItem e RemoveItem alteram os itens de um pedido pendente
+	// e recalculam os seus valores
+	AddItem(ctx context.Context, id uint, req *dto.CreateItemPedidoRequest) (*dto.PedidoResponse, error)
+	UpdateItem(ctx context.Context, id, itemID uint, req *dto.UpdateItemPedidoRequest) (*dto.PedidoResponse, error)
+	RemoveItem(ctx context.Context, id, itemID uint) (*dto.PedidoResponse, error)
+	FindHistorico(ctx context.Context, id uint) ([]dto.PedidoStatusHistoricoResponse, error)
 	Delete(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
 }
@@ -1,96 +1,169 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/frete"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/pricing"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/go-playground/validator/v10"
 )
 
+// ErrTransicaoStatusInvalida indica uma mudança de status não prevista no fluxo do pedido
+var ErrTransicaoStatusInvalida = apperror.Conflict("transição de status inválida")
+
+// transicoesStatus define o fluxo permitido: pendente → pago → enviado → entregue,
+// com cancelamento possível apenas antes do envio
+var transicoesStatus = map[string][]string{
+	model.StatusPendente: {model.StatusPago, model.StatusCancelado},
+	model.StatusPago:     {model.StatusEnviado, model.StatusCancelado},
+	model.StatusEnviado:  {model.StatusEntregue},
+}
+
 type pedidoServiceImpl struct {
-	pedidoRepo  repository.PedidoRepository
-	clienteRepo repository.ClienteRepository
-	produtoRepo repository.ProdutoRepository
-	validate    *validator.Validate
+	pedidoRepo   repository.PedidoRepository
+	clienteRepo  repository.ClienteRepository
+	produtoRepo  repository.ProdutoRepository
+	cupomRepo    repository.CupomRepository
+	impostoRepo  repository.RegraImpostoRepository
+	enderecoRepo repository.EnderecoRepository
+	freteCalc    frete.Calculator
+	auditoria    auditoria
+	validate     *validator.Validate
 }
 
 // NewPedidoService cria uma nova instância do serviço
-func NewPedidoService(pedidoRepo repository.PedidoRepository, clienteRepo repository.ClienteRepository, produtoRepo repository.ProdutoRepository) PedidoService {
+func NewPedidoService(pedidoRepo repository.PedidoRepository, clienteRepo repository.ClienteRepository, produtoRepo repository.ProdutoRepository, cupomRepo repository.CupomRepository, impostoRepo repository.RegraImpostoRepository, enderecoRepo repository.EnderecoRepository, freteCalc frete.Calculator, auditRepo repository.AuditRepository) PedidoService {
 	return &pedidoServiceImpl{
-		pedidoRepo:  pedidoRepo,
-		clienteRepo: clienteRepo,
-		produtoRepo: produtoRepo,
-		validate:    validator.New(),
+		pedidoRepo:   pedidoRepo,
+		clienteRepo:  clienteRepo,
+		produtoRepo:  produtoRepo,
+		cupomRepo:    cupomRepo,
+		impostoRepo:  impostoRepo,
+		enderecoRepo: enderecoRepo,
+		freteCalc:    freteCalc,
+		auditoria:    auditoria{repo: auditRepo, entidade: model.AuditPedido},
+		validate:     newValidator(),
 	}
 }
 
 func (s *pedidoServiceImpl) Create(ctx context.Context, req *dto.CreatePedidoRequest) (*dto.PedidoResponse, error) {
 	// Validar request
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation("dados do pedido inválidos", err)
 	}
 
 	// Validar se cliente existe
 	cliente, err := s.clienteRepo.FindByID(ctx, req.ClienteID)
 	if err != nil {
-		return nil, errors.New("cliente não encontrado")
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("cliente %d não encontrado", req.ClienteID))
+		}
+		return nil, err
 	}
 
-	// Preparar itens do pedido
-	var itens []model.PedidoProduto
-	var valorTotal float64
+	// Todo pedido começa como pendente
+	pedido := &model.Pedido{
+		ClienteID:  req.ClienteID,
+		Status:     model.StatusPendente,
+		DataPedido: time.Now(),
+		UFDestino:  strings.ToUpper(req.UFDestino),
+	}
 
-	for _, itemReq := range req.Itens {
-		// Buscar produto
-		produto, err := s.produtoRepo.FindByID(ctx, itemReq.ProdutoID)
-		if err != nil {
-			return nil, errors.New("produto " + string(rune(itemReq.ProdutoID)) + " não encontrado")
+	// O endereço de entrega é copiado para o pedido; editar o endereço depois
+	// não altera pedidos já feitos
+	endereco, err := s.enderecoEntrega(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if endereco != nil {
+		if pedido.UFDestino != "" && pedido.UFDestino != endereco.UF {
+			return nil, apperror.BusinessRule(fmt.Sprintf("uf_destino %s diverge da UF do endereço de entrega (%s)", pedido.UFDestino, endereco.UF))
 		}
+		pedido.UFDestino = endereco.UF
+		pedido.EnderecoID = &endereco.ID
+		pedido.Entrega = endereco.Entrega()
+	}
+
+	// Reserva de estoque e gravação do pedido acontecem na mesma transação,
+	// assim uma falha em qualquer item desfaz as baixas já realizadas
+	err = s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var itens []pricing.Item
+		var volumes []frete.Volume
+
+		for _, itemReq := range req.Itens {
+			produto, err := s.reservarEstoque(ctx, itemReq.ProdutoID, itemReq.Quantidade)
+			if err != nil {
+				return err
+			}
 
-		// Verificar estoque
-		if produto.Estoque < itemReq.Quantidade {
-			return nil, errors.New("estoque insuficiente para produto: " + produto.Nome)
+			// Criar item do pedido
+			item := pricing.Item{
+				ProdutoID:     produto.ID,
+				Categoria:     produto.Categoria,
+				Quantidade:    itemReq.Quantidade,
+				PrecoUnitario: produto.Preco,
+			}
+			itens = append(itens, item)
+			volumes = append(volumes, volumeProduto(produto, itemReq.Quantidade))
+			pedido.Itens = append(pedido.Itens, model.PedidoProduto{
+				ProdutoID:     itemReq.ProdutoID,
+				Quantidade:    itemReq.Quantidade,
+				PrecoUnitario: produto.Preco,
+				Subtotal:      item.Subtotal(),
+			})
 		}
 
-		// Verificar se produto está ativo
-		if !produto.Ativo {
-			return nil, errors.New("produto inativo: " + produto.Nome)
+		cupom, err := s.resgatarCupom(ctx, req.Cupom, req.ClienteID)
+		if err != nil {
+			return err
 		}
 
-		// Criar item do pedido
-		subtotal := float64(itemReq.Quantidade) * produto.Preco
-		item := model.PedidoProduto{
-			ProdutoID:     itemReq.ProdutoID,
-			Quantidade:    itemReq.Quantidade,
-			PrecoUnitario: produto.Preco,
-			Subtotal:      subtotal,
+		regras, err := s.impostoRepo.FindAtivas(ctx, pedido.UFDestino)
+		if err != nil {
+			return err
 		}
 
-		itens = append(itens, item)
-		valorTotal += subtotal
-	}
+		if err := s.escolherFrete(ctx, pedido, volumes, req.FreteServico); err != nil {
+			return err
+		}
 
-	// Definir status padrão se não fornecido
-	status := req.Status
-	if status == "" {
-		status = "pendente"
-	}
+		// Subtotal, descontos, impostos e total saem do motor de preços
+		precos, err := pricing.Calcular(pricing.Entrada{
+			Itens:  itens,
+			Cupom:  cupom,
+			Agora:  pedido.DataPedido,
+			UF:     pedido.UFDestino,
+			Regras: regras,
+			Frete:  pedido.Frete,
+		})
+		if err != nil {
+			return err
+		}
+		aplicarPrecos(pedido, precos)
 
-	// Criar pedido
-	pedido := &model.Pedido{
-		ClienteID:  req.ClienteID,
-		Itens:      itens,
-		ValorTotal: valorTotal,
-		Status:     status,
-		DataPedido: time.Now(),
-	}
+		// Salvar no banco (com cascade para itens)
+		if err := s.pedidoRepo.Create(ctx, pedido); err != nil {
+			return err
+		}
 
-	// Salvar no banco (com cascade para itens)
-	if err := s.pedidoRepo.Create(ctx, pedido); err != nil {
+		if err := s.registrarHistorico(ctx, pedido.ID, "", pedido.Status); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, pedido.ID, model.AuditCreate, nil, toPedidoResponse(pedido))
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -103,21 +176,126 @@ func (s *pedidoServiceImpl) Create(ctx context.Context, req *dto.CreatePedidoReq
 	// Atribuir cliente ao pedido completo
 	pedidoCompleto.Cliente = *cliente
 
-	return s.toResponse(pedidoCompleto), nil
+	return toPedidoResponse(pedidoCompleto), nil
 }
 
-func (s *pedidoServiceImpl) FindAll(ctx context.Context) ([]dto.PedidoResponse, error) {
-	pedidos, err := s.pedidoRepo.FindAll(ctx)
+// reservarEstoque confere se o produto existe, está ativo e tem saldo para a
+// quantidade pedida, e baixa essa quantidade do estoque
+func (s *pedidoServiceImpl) reservarEstoque(ctx context.Context, produtoID uint, quantidade int) (*model.Produto, error) {
+	// Buscar produto
+	produto, err := s.produtoRepo.FindByID(ctx, produtoID)
 	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("produto %d não encontrado", produtoID))
+		}
 		return nil, err
 	}
 
-	responses := make([]dto.PedidoResponse, len(pedidos))
-	for i, pedido := range pedidos {
-		responses[i] = *s.toResponse(&pedido)
+	// Verificar estoque
+	if produto.Estoque < quantidade {
+		return nil, apperror.BusinessRule("estoque insuficiente para produto: " + produto.Nome)
 	}
 
-	return responses, nil
+	// Verificar se produto está ativo
+	if !produto.Ativo {
+		return nil, apperror.BusinessRule("produto inativo: " + produto.Nome)
+	}
+
+	// Baixar estoque de forma atômica; protege contra pedidos concorrentes
+	// que tenham passado pela verificação acima com o mesmo saldo
+	if err := s.produtoRepo.DecrementEstoque(ctx, produto.ID, quantidade); err != nil {
+		if errors.Is(err, repository.ErrEstoqueInsuficiente) {
+			return nil, apperror.BusinessRule("estoque insuficiente para produto: " + produto.Nome)
+		}
+		return nil, err
+	}
+	return produto, nil
+}
+
+// aplicarPrecos copia para o pedido e os seus itens os valores calculados
+// pelo motor de preços
+func aplicarPrecos(pedido *model.Pedido, precos *pricing.Resultado) {
+	pedido.Subtotal = precos.Subtotal
+	pedido.Desconto = precos.Desconto
+	pedido.Imposto = precos.Imposto
+	pedido.ValorTotal = precos.Total
+	pedido.Descontos = precos.Descontos
+	for i, item := range precos.Itens {
+		pedido.Itens[i].Imposto = item.Imposto
+		pedido.Itens[i].Impostos = item.Impostos
+		pedido.Itens[i].Desconto = item.Desconto
+	}
+}
+
+// escolherFrete cota o frete para o endereço de entrega do pedido e grava o
+// serviço escolhido, ou o mais barato se nenhum foi pedido. Pedidos sem
+// endereço de entrega não têm frete.
+func (s *pedidoServiceImpl) escolherFrete(ctx context.Context, pedido *model.Pedido, volumes []frete.Volume, servico string) error {
+	cep := pedido.Entrega.CEP
+	if cep == "" {
+		if servico != "" {
+			return apperror.BusinessRule("frete_servico exige um endereço de entrega")
+		}
+		return nil
+	}
+
+	opcoes, err := s.freteCalc.Cotar(ctx, frete.Pacote{CEP: cep, Volumes: volumes})
+	if err != nil {
+		return err
+	}
+	if len(opcoes) == 0 {
+		return apperror.BusinessRule(fmt.Sprintf("não há frete disponível para o CEP %s", cep))
+	}
+
+	// as opções vêm da mais barata para a mais cara
+	opcao := opcoes[0]
+	if servico != "" {
+		i := slices.IndexFunc(opcoes, func(o frete.Opcao) bool {
+			return strings.EqualFold(o.Servico, strings.TrimSpace(servico))
+		})
+		if i < 0 {
+			return apperror.BusinessRule(fmt.Sprintf("serviço de frete %s indisponível para o CEP %s", servico, cep))
+		}
+		opcao = opcoes[i]
+	}
+
+	pedido.Frete = opcao.Valor
+	pedido.FreteServico = opcao.Servico
+	pedido.FretePrazoDias = opcao.PrazoDias
+	return nil
+}
+
+// enderecoEntrega retorna o endereço escolhido no pedido ou, se nenhum foi
+// informado, o endereço padrão do cliente (nil se ele não tiver endereços)
+func (s *pedidoServiceImpl) enderecoEntrega(ctx context.Context, req *dto.CreatePedidoRequest) (*model.Endereco, error) {
+	if req.EnderecoID == nil {
+		return s.enderecoRepo.FindPadrao(ctx, req.ClienteID)
+	}
+
+	endereco, err := s.enderecoRepo.FindByID(ctx, *req.EnderecoID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("endereço %d não encontrado", *req.EnderecoID))
+		}
+		return nil, err
+	}
+	if endereco.ClienteID != req.ClienteID {
+		return nil, apperror.BusinessRule(fmt.Sprintf("endereço %d não pertence ao cliente %d", endereco.ID, req.ClienteID))
+	}
+	return endereco, nil
+}
+
+func (s *pedidoServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error) {
+	opts, err := toListOptions(page, pedidoCampos)
+	if err != nil {
+		return nil, err
+	}
+	pedidos, err := s.pedidoRepo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(pedidos, page, opts, s.toResponseValue, pedidoID), nil
 }
 
 func (s *pedidoServiceImpl) FindByID(ctx context.Context, id uint) (*dto.PedidoResponse, error) {
@@ -126,91 +304,491 @@ func (s *pedidoServiceImpl) FindByID(ctx context.Context, id uint) (*dto.PedidoR
 		return nil, err
 	}
 
-	return s.toResponse(pedido), nil
+	return toPedidoResponse(pedido), nil
 }
 
-func (s *pedidoServiceImpl) FindByClienteID(ctx context.Context, clienteID uint) ([]dto.PedidoResponse, error) {
-	pedidos, err := s.pedidoRepo.FindByClienteID(ctx, clienteID)
+func (s *pedidoServiceImpl) FindByClienteID(ctx context.Context, clienteID uint, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error) {
+	opts, err := toListOptions(page, pedidoCampos)
 	if err != nil {
 		return nil, err
 	}
+	pedidos, err := s.pedidoRepo.FindByClienteID(ctx, clienteID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(pedidos, page, opts, s.toResponseValue, pedidoID), nil
+}
 
-	responses := make([]dto.PedidoResponse, len(pedidos))
-	for i, pedido := range pedidos {
-		responses[i] = *s.toResponse(&pedido)
+func (s *pedidoServiceImpl) FindByStatus(ctx context.Context, status string, page dto.PageRequest) (*dto.PageResponse[dto.PedidoResponse], error) {
+	opts, err := toListOptions(page, pedidoCampos)
+	if err != nil {
+		return nil, err
+	}
+	pedidos, err := s.pedidoRepo.FindByStatus(ctx, status, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return responses, nil
+	return toPageResponse(pedidos, page, opts, s.toResponseValue, pedidoID), nil
 }
 
-func (s *pedidoServiceImpl) FindByStatus(ctx context.Context, status string) ([]dto.PedidoResponse, error) {
-	pedidos, err := s.pedidoRepo.FindByStatus(ctx, status)
+func (s *pedidoServiceImpl) UpdateStatus(ctx context.Context, id uint, req *dto.UpdatePedidoRequest) (*dto.PedidoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do pedido inválidos", err)
+	}
+
+	var pedido *model.Pedido
+	err := s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		// Buscar pedido existente dentro da transação para que a devolução de
+		// estoque não seja aplicada duas vezes por requisições concorrentes
+		existente, err := s.pedidoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(ctx, existente.Versao); err != nil {
+			return err
+		}
+
+		antes := toPedidoResponse(existente)
+		statusAnterior := existente.Status
+		if !transicaoPermitida(statusAnterior, req.Status) {
+			return fmt.Errorf("%w: de %s para %s", ErrTransicaoStatusInvalida, statusAnterior, req.Status)
+		}
+
+		// Pedidos ficam pagos quando os pagamentos confirmados cobrem o total;
+		// marcar como pago à mão é uma baixa manual, restrita a administradores
+		if req.Status == model.StatusPago {
+			if err := auth.Authorize(ctx, auth.RecursoPedidos, auth.RoleAdmin); err != nil {
+				return err
+			}
+		}
+
+		// Cancelar também devolve estoque, cupons e pagamentos; isso é feito
+		// apenas pelo cancelamento, que registra o motivo
+		if req.Status == model.StatusCancelado {
+			return apperror.BusinessRule("pedidos são cancelados em POST /pedidos/{id}/cancelamento, informando o motivo")
+		}
+
+		// Atualizar status
+		existente.Status = req.Status
+
+		// Atualizar no banco
+		if err := s.pedidoRepo.Update(ctx, existente); err != nil {
+			return err
+		}
+
+		if err := s.registrarHistorico(ctx, existente.ID, statusAnterior, existente.Status); err != nil {
+			return err
+		}
+		if err := s.auditoria.registrar(ctx, existente.ID, model.AuditUpdate, antes, toPedidoResponse(existente)); err != nil {
+			return err
+		}
+
+		pedido = existente
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	responses := make([]dto.PedidoResponse, len(pedidos))
-	for i, pedido := range pedidos {
-		responses[i] = *s.toResponse(&pedido)
+	return toPedidoResponse(pedido), nil
+}
+
+func (s *pedidoServiceImpl) AddItem(ctx context.Context, id uint, req *dto.CreateItemPedidoRequest) (*dto.PedidoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do item inválidos", err)
 	}
 
-	return responses, nil
+	return s.editarItens(ctx, id, func(ctx context.Context, pedido *model.Pedido) error {
+		produto, err := s.reservarEstoque(ctx, req.ProdutoID, req.Quantidade)
+		if err != nil {
+			return err
+		}
+
+		// Um produto que já está no pedido soma à linha existente, que mantém
+		// o preço da compra
+		i := slices.IndexFunc(pedido.Itens, func(item model.PedidoProduto) bool {
+			return item.ProdutoID == produto.ID
+		})
+		if i >= 0 {
+			pedido.Itens[i].Quantidade += req.Quantidade
+			return nil
+		}
+
+		pedido.Itens = append(pedido.Itens, model.PedidoProduto{
+			PedidoID:      pedido.ID,
+			ProdutoID:     produto.ID,
+			Produto:       *produto,
+			Quantidade:    req.Quantidade,
+			PrecoUnitario: produto.Preco,
+		})
+		return nil
+	})
 }
 
-func (s *pedidoServiceImpl) UpdateStatus(ctx context.Context, id uint, req *dto.UpdatePedidoRequest) (*dto.PedidoResponse, error) {
+func (s *pedidoServiceImpl) UpdateItem(ctx context.Context, id, itemID uint, req *dto.UpdateItemPedidoRequest) (*dto.PedidoResponse, error) {
 	// Validar request
 	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados do item inválidos", err)
+	}
+
+	return s.editarItens(ctx, id, func(ctx context.Context, pedido *model.Pedido) error {
+		item, err := itemDoPedido(pedido, itemID)
+		if err != nil {
+			return err
+		}
+
+		// Aumentar reserva o que falta; diminuir devolve a sobra ao estoque
+		diferenca := req.Quantidade - item.Quantidade
+		if diferenca > 0 {
+			if _, err := s.reservarEstoque(ctx, item.ProdutoID, diferenca); err != nil {
+				return err
+			}
+		}
+		if diferenca < 0 {
+			if err := s.produtoRepo.IncrementEstoque(ctx, item.ProdutoID, -diferenca); err != nil {
+				return err
+			}
+		}
+
+		item.Quantidade = req.Quantidade
+		return nil
+	})
+}
+
+func (s *pedidoServiceImpl) RemoveItem(ctx context.Context, id, itemID uint) (*dto.PedidoResponse, error) {
+	return s.editarItens(ctx, id, func(ctx context.Context, pedido *model.Pedido) error {
+		item, err := itemDoPedido(pedido, itemID)
+		if err != nil {
+			return err
+		}
+		if len(pedido.Itens) == 1 {
+			return apperror.BusinessRule("o pedido precisa de ao menos um item; para desistir da compra, cancele o pedido")
+		}
+
+		if err := s.produtoRepo.IncrementEstoque(ctx, item.ProdutoID, item.Quantidade); err != nil {
+			return err
+		}
+
+		pedido.Itens = slices.DeleteFunc(pedido.Itens, func(i model.PedidoProduto) bool {
+			return i.ID == itemID
+		})
+		return nil
+	})
+}
+
+// editarItens aplica fn aos itens de um pedido pendente, sem pagamentos em
+// aberto, e precifica o pedido de novo, tudo na mesma transação: uma falha
+// em qualquer etapa desfaz as mudanças de estoque já feitas por fn
+func (s *pedidoServiceImpl) editarItens(ctx context.Context, id uint, fn func(ctx context.Context, pedido *model.Pedido) error) (*dto.PedidoResponse, error) {
+	err := s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		pedido, err := s.pedidoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(ctx, pedido.Versao); err != nil {
+			return err
+		}
+		if pedido.Status != model.StatusPendente {
+			return apperror.BusinessRule(fmt.Sprintf("pedido %d está %s; apenas pedidos pendentes têm os itens alterados", pedido.ID, pedido.Status))
+		}
+		// As cobranças em aberto foram feitas sobre o total atual; mudar os
+		// itens deixaria o pedido quitado a mais ou a menos
+		pagamentos, err := s.pedidoRepo.CountPagamentosEmAberto(ctx, pedido.ID)
+		if err != nil {
+			return err
+		}
+		if pagamentos > 0 {
+			return apperror.Conflict(fmt.Sprintf("pedido %d tem pagamentos pendentes ou confirmados; os itens não podem ser alterados", pedido.ID))
+		}
+
+		antes := toPedidoResponse(pedido)
+		if err := fn(ctx, pedido); err != nil {
+			return err
+		}
+		if err := s.reprecificar(ctx, pedido); err != nil {
+			return err
+		}
+
+		if err := s.pedidoRepo.UpdateItens(ctx, pedido); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, pedido.ID, model.AuditUpdate, antes, toPedidoResponse(pedido))
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Buscar pedido existente
+	// Buscar pedido completo com relacionamentos
 	pedido, err := s.pedidoRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Atualizar status
-	pedido.Status = req.Status
+	return toPedidoResponse(pedido), nil
+}
 
-	// Atualizar no banco
-	if err := s.pedidoRepo.Update(ctx, pedido); err != nil {
-		return nil, err
+// reprecificar recalcula subtotal, desconto, impostos, frete e total de um
+// pedido já gravado. Os itens mantêm o preço unitário da compra; o cupom do
+// pedido continua aplicado com a validade da data do pedido, sem contar um
+// novo uso, e o frete é cotado de novo para o mesmo serviço.
+func (s *pedidoServiceImpl) reprecificar(ctx context.Context, pedido *model.Pedido) error {
+	itens := make([]pricing.Item, len(pedido.Itens))
+	volumes := make([]frete.Volume, len(pedido.Itens))
+	for i := range pedido.Itens {
+		item := &pedido.Itens[i]
+		itens[i] = pricing.Item{
+			ProdutoID:     item.ProdutoID,
+			Categoria:     item.Produto.Categoria,
+			Quantidade:    item.Quantidade,
+			PrecoUnitario: item.PrecoUnitario,
+		}
+		volumes[i] = volumeProduto(&item.Produto, item.Quantidade)
+		item.Subtotal = itens[i].Subtotal()
 	}
 
-	return s.toResponse(pedido), nil
+	cupom, err := s.cupomDoPedido(ctx, pedido)
+	if err != nil {
+		return err
+	}
+
+	regras, err := s.impostoRepo.FindAtivas(ctx, pedido.UFDestino)
+	if err != nil {
+		return err
+	}
+
+	if err := s.escolherFrete(ctx, pedido, volumes, pedido.FreteServico); err != nil {
+		return err
+	}
+
+	precos, err := pricing.Calcular(pricing.Entrada{
+		Itens:  itens,
+		Cupom:  cupom,
+		Agora:  pedido.DataPedido,
+		UF:     pedido.UFDestino,
+		Regras: regras,
+		Frete:  pedido.Frete,
+	})
+	if err != nil {
+		return err
+	}
+	aplicarPrecos(pedido, precos)
+	return nil
 }
 
-func (s *pedidoServiceImpl) Delete(ctx context.Context, id uint) error {
+// cupomDoPedido retorna o cupom aplicado ao pedido, ou nil se não houver
+func (s *pedidoServiceImpl) cupomDoPedido(ctx context.Context, pedido *model.Pedido) (*model.Cupom, error) {
+	for _, desconto := range pedido.Descontos {
+		if desconto.CupomID == nil {
+			continue
+		}
+		cupom, err := s.cupomRepo.FindByID(ctx, *desconto.CupomID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, apperror.BusinessRule(fmt.Sprintf("cupom %s do pedido não está mais disponível", desconto.Codigo))
+			}
+			return nil, err
+		}
+		return cupom, nil
+	}
+	return nil, nil
+}
+
+// itemDoPedido retorna o item do pedido com o ID informado
+func itemDoPedido(pedido *model.Pedido, itemID uint) (*model.PedidoProduto, error) {
+	i := slices.IndexFunc(pedido.Itens, func(item model.PedidoProduto) bool {
+		return item.ID == itemID
+	})
+	if i < 0 {
+		return nil, apperror.NotFound(fmt.Sprintf("item %d não encontrado no pedido %d", itemID, pedido.ID))
+	}
+	return &pedido.Itens[i], nil
+}
+
+func (s *pedidoServiceImpl) FindHistorico(ctx context.Context, id uint) ([]dto.PedidoStatusHistoricoResponse, error) {
 	// Verificar se existe
-	_, err := s.pedidoRepo.FindByID(ctx, id)
+	if _, err := s.pedidoRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	historico, err := s.pedidoRepo.FindHistoricoStatus(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.pedidoRepo.Delete(ctx, id)
+	responses := make([]dto.PedidoStatusHistoricoResponse, len(historico))
+	for i, h := range historico {
+		responses[i] = dto.PedidoStatusHistoricoResponse{
+			ID:             h.ID,
+			PedidoID:       h.PedidoID,
+			StatusAnterior: h.StatusAnterior,
+			StatusNovo:     h.StatusNovo,
+			Usuario:        h.Usuario,
+			CreatedAt:      h.CreatedAt,
+		}
+	}
+
+	return responses, nil
+}
+
+func (s *pedidoServiceImpl) Delete(ctx context.Context, id uint) error {
+	return s.pedidoRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		// Verificar se existe
+		pedido, err := s.pedidoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(ctx, pedido.Versao); err != nil {
+			return err
+		}
+
+		// Só pedidos pendentes ou cancelados, sem cobranças em aberto, são
+		// excluídos; os demais já foram pagos e saem pelo cancelamento, que
+		// estorna os pagamentos
+		if pedido.Status != model.StatusPendente && pedido.Status != model.StatusCancelado {
+			return apperror.Conflict(fmt.Sprintf("pedido %d está %s e não pode ser excluído; use POST /pedidos/%d/cancelamento", pedido.ID, pedido.Status, pedido.ID))
+		}
+		abertos, err := s.pedidoRepo.CountPagamentosEmAberto(ctx, pedido.ID)
+		if err != nil {
+			return err
+		}
+		if abertos > 0 {
+			return apperror.Conflict(fmt.Sprintf("pedido %d tem pagamentos pendentes ou confirmados e não pode ser excluído; use POST /pedidos/%d/cancelamento", pedido.ID, pedido.ID))
+		}
+
+		// Pedidos cancelados já tiveram o estoque e os cupons devolvidos
+		if pedido.Status != model.StatusCancelado {
+			if err := restaurarEstoque(ctx, s.produtoRepo, pedido); err != nil {
+				return err
+			}
+			if err := liberarCupons(ctx, s.cupomRepo, pedido); err != nil {
+				return err
+			}
+		}
+
+		if err := s.pedidoRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, id, model.AuditDelete, toPedidoResponse(pedido), nil)
+	})
 }
 
 func (s *pedidoServiceImpl) Count(ctx context.Context) (int64, error) {
 	return s.pedidoRepo.Count(ctx)
 }
 
-// toResponse converte Model para Response DTO
-func (s *pedidoServiceImpl) toResponse(pedido *model.Pedido) *dto.PedidoResponse {
+// transicaoPermitida verifica se o fluxo de status permite ir de um status para outro
+func transicaoPermitida(de, para string) bool {
+	for _, permitido := range transicoesStatus[de] {
+		if permitido == para {
+			return true
+		}
+	}
+	return false
+}
+
+// registrarHistorico grava a mudança de status junto com quem a realizou
+func (s *pedidoServiceImpl) registrarHistorico(ctx context.Context, pedidoID uint, de, para string) error {
+	return s.pedidoRepo.AddHistoricoStatus(ctx, &model.PedidoStatusHistorico{
+		PedidoID:       pedidoID,
+		StatusAnterior: de,
+		StatusNovo:     para,
+		Usuario:        auth.Actor(ctx),
+	})
+}
+
+// restaurarEstoque devolve ao estoque as quantidades dos itens do pedido que
+// ainda não voltaram por uma devolução
+func restaurarEstoque(ctx context.Context, produtoRepo repository.ProdutoRepository, pedido *model.Pedido) error {
+	devolvidas := pedido.QuantidadesDevolvidas()
+	for _, item := range pedido.Itens {
+		quantidade := item.Quantidade - devolvidas[item.ID]
+		if quantidade <= 0 {
+			continue
+		}
+		if err := produtoRepo.IncrementEstoque(ctx, item.ProdutoID, quantidade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resgatarCupom busca o cupom informado no pedido e registra o seu uso,
+// respeitando os limites por cliente e global. Sem código, retorna nil.
+// As demais regras do cupom são conferidas pelo motor de preços.
+func (s *pedidoServiceImpl) resgatarCupom(ctx context.Context, codigo string, clienteID uint) (*model.Cupom, error) {
+	if codigo == "" {
+		return nil, nil
+	}
+	codigo = normalizarCodigo(codigo)
+
+	cupom, err := s.cupomRepo.FindByCodigo(ctx, codigo)
+	if err != nil {
+		return nil, err
+	}
+	if cupom == nil {
+		return nil, apperror.BusinessRule(fmt.Sprintf("cupom %s não encontrado", codigo))
+	}
+
+	if cupom.LimitePorCliente > 0 {
+		usos, err := s.cupomRepo.CountUsosCliente(ctx, cupom.ID, clienteID)
+		if err != nil {
+			return nil, err
+		}
+		if usos >= int64(cupom.LimitePorCliente) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("cupom %s já utilizado o máximo de vezes por este cliente", codigo))
+		}
+	}
+
+	// o contador é incrementado de forma atômica, conferindo de novo os dois
+	// limites; se o pedido falhar depois, a transação desfaz o uso
+	if err := s.cupomRepo.IncrementUsos(ctx, cupom.ID, clienteID); err != nil {
+		if errors.Is(err, repository.ErrCupomEsgotado) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("cupom %s esgotado", codigo))
+		}
+		if errors.Is(err, repository.ErrCupomLimiteCliente) {
+			return nil, apperror.BusinessRule(fmt.Sprintf("cupom %s já utilizado o máximo de vezes por este cliente", codigo))
+		}
+		return nil, err
+	}
+	return cupom, nil
+}
+
+// liberarCupons devolve os usos dos cupons aplicados ao pedido
+func liberarCupons(ctx context.Context, cupomRepo repository.CupomRepository, pedido *model.Pedido) error {
+	for _, desconto := range pedido.Descontos {
+		if desconto.CupomID == nil {
+			continue
+		}
+		if err := cupomRepo.DecrementUsos(ctx, *desconto.CupomID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toResponseValue converte Model para Response DTO por valor, usado nas listagens
+func (s *pedidoServiceImpl) toResponseValue(pedido *model.Pedido) dto.PedidoResponse {
+	return *toPedidoResponse(pedido)
+}
+
+func pedidoID(pedido *model.Pedido) uint {
+	return pedido.ID
+}
+
+// toPedidoResponse converte Model para Response DTO
+func toPedidoResponse(pedido *model.Pedido) *dto.PedidoResponse {
 	// Converter cliente
 	var clienteResp *dto.ClienteResponse
 	if pedido.Cliente.ID != 0 {
-		clienteResp = &dto.ClienteResponse{
-			ID:        pedido.Cliente.ID,
-			Nome:      pedido.Cliente.Nome,
-			Email:     pedido.Cliente.Email,
-			CPF:       pedido.Cliente.CPF,
-			Telefone:  pedido.Cliente.Telefone,
-			CreatedAt: pedido.Cliente.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: pedido.Cliente.UpdatedAt.Format(time.RFC3339),
-		}
+		clienteResp = toClienteResponse(&pedido.Cliente)
 	}
 
 	// Converter itens
+	devolvidas := pedido.QuantidadesDevolvidas()
 	itens := make([]dto.ItemPedidoResponse, len(pedido.Itens))
 	for i, item := range pedido.Itens {
 		var produtoResp *dto.ProdutoResponse
@@ -229,25 +807,112 @@ func (s *pedidoServiceImpl) toResponse(pedido *model.Pedido) *dto.PedidoResponse
 			}
 		}
 
+		impostos := make([]dto.ImpostoItemResponse, len(item.Impostos))
+		for j, imposto := range item.Impostos {
+			impostos[j] = dto.ImpostoItemResponse{
+				RegraID:  imposto.RegraID,
+				Imposto:  imposto.Imposto,
+				Aliquota: imposto.Aliquota,
+				Modo:     imposto.Modo,
+				Base:     imposto.Base,
+				Valor:    imposto.Valor,
+			}
+		}
+
 		itens[i] = dto.ItemPedidoResponse{
-			ID:            item.ID,
-			ProdutoID:     item.ProdutoID,
-			Produto:       produtoResp,
-			Quantidade:    item.Quantidade,
-			PrecoUnitario: item.PrecoUnitario,
-			Subtotal:      item.Subtotal,
+			ID:                  item.ID,
+			ProdutoID:           item.ProdutoID,
+			Produto:             produtoResp,
+			Quantidade:          item.Quantidade,
+			PrecoUnitario:       item.PrecoUnitario,
+			Subtotal:            item.Subtotal,
+			Imposto:             item.Imposto,
+			Impostos:            impostos,
+			Desconto:            item.Desconto,
+			QuantidadeDevolvida: devolvidas[item.ID],
+		}
+	}
+
+	// Converter descontos
+	descontos := make([]dto.DescontoResponse, len(pedido.Descontos))
+	for i, d := range pedido.Descontos {
+		descontos[i] = dto.DescontoResponse{
+			ID:        d.ID,
+			CupomID:   d.CupomID,
+			Codigo:    d.Codigo,
+			Tipo:      d.Tipo,
+			Descricao: d.Descricao,
+			Base:      d.Base,
+			Valor:     d.Valor,
+		}
+	}
+
+	// Converter devoluções
+	devolucoes := make([]dto.DevolucaoResponse, len(pedido.Devolucoes))
+	for i := range pedido.Devolucoes {
+		devolucoes[i] = toDevolucaoResponse(&pedido.Devolucoes[i])
+	}
+
+	// Pedidos sem endereço de entrega respondem entrega null
+	var entregaResp *dto.EnderecoEntregaResponse
+	if pedido.Entrega.CEP != "" {
+		entregaResp = &dto.EnderecoEntregaResponse{
+			CEP:         pedido.Entrega.CEP,
+			Logradouro:  pedido.Entrega.Logradouro,
+			Numero:      pedido.Entrega.Numero,
+			Complemento: pedido.Entrega.Complemento,
+			Bairro:      pedido.Entrega.Bairro,
+			Cidade:      pedido.Entrega.Cidade,
+			UF:          pedido.Entrega.UF,
 		}
 	}
 
 	return &dto.PedidoResponse{
-		ID:         pedido.ID,
-		ClienteID:  pedido.ClienteID,
-		Cliente:    clienteResp,
-		Itens:      itens,
-		ValorTotal: pedido.ValorTotal,
-		Status:     pedido.Status,
-		DataPedido: pedido.DataPedido,
-		CreatedAt:  pedido.CreatedAt,
-		UpdatedAt:  pedido.UpdatedAt,
+		ID:                 pedido.ID,
+		ClienteID:          pedido.ClienteID,
+		Cliente:            clienteResp,
+		Itens:              itens,
+		Descontos:          descontos,
+		Subtotal:           pedido.Subtotal,
+		Desconto:           pedido.Desconto,
+		Impostos:           resumirImpostos(pedido.Itens),
+		Imposto:            pedido.Imposto,
+		ValorTotal:         pedido.ValorTotal,
+		UFDestino:          pedido.UFDestino,
+		EnderecoID:         pedido.EnderecoID,
+		Entrega:            entregaResp,
+		Frete:              pedido.Frete,
+		FreteServico:       pedido.FreteServico,
+		FretePrazoDias:     pedido.FretePrazoDias,
+		Status:             pedido.Status,
+		MotivoCancelamento: pedido.MotivoCancelamento,
+		Devolucoes:         devolucoes,
+		DataPedido:         pedido.DataPedido,
+		Versao:             pedido.Versao,
+		CreatedAt:          pedido.CreatedAt,
+		UpdatedAt:          pedido.UpdatedAt,
+		DeletedAt:          excluidoEm(pedido.DeletedAt),
+	}
+}
+
+// resumirImpostos totaliza os impostos dos itens por imposto e modo, em ordem alfabética
+func resumirImpostos(itens []model.PedidoProduto) []dto.ImpostoResumoResponse {
+	resumo := []dto.ImpostoResumoResponse{}
+	for _, item := range itens {
+		for _, imposto := range item.Impostos {
+			i := slices.IndexFunc(resumo, func(r dto.ImpostoResumoResponse) bool {
+				return r.Imposto == imposto.Imposto && r.Modo == imposto.Modo
+			})
+			if i < 0 {
+				resumo = append(resumo, dto.ImpostoResumoResponse{Imposto: imposto.Imposto, Modo: imposto.Modo})
+				i = len(resumo) - 1
+			}
+			resumo[i].Base += imposto.Base
+			resumo[i].Valor += imposto.Valor
+		}
 	}
+	slices.SortFunc(resumo, func(a, b dto.ImpostoResumoResponse) int {
+		return cmp.Or(strings.Compare(a.Imposto, b.Imposto), strings.Compare(a.Modo, b.Modo))
+	})
+	return resumo
 }
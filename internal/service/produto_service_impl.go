@@ -3,30 +3,37 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/mergepatch"
 	"github.com/danmaciel/api/internal/model"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/go-playground/validator/v10"
 )
 
 type produtoServiceImpl struct {
-	repo     repository.ProdutoRepository
-	validate *validator.Validate
+	repo      repository.ProdutoRepository
+	auditoria auditoria
+	validate  *validator.Validate
 }
 
 // NewProdutoService cria uma nova instância do serviço
-func NewProdutoService(repo repository.ProdutoRepository) ProdutoService {
+func NewProdutoService(repo repository.ProdutoRepository, auditRepo repository.AuditRepository) ProdutoService {
 	return &produtoServiceImpl{
-		repo:     repo,
-		validate: validator.New(),
+		repo:      repo,
+		auditoria: auditoria{repo: auditRepo, entidade: model.AuditProduto},
+		validate:  newValidator(),
 	}
 }
 
 func (s *produtoServiceImpl) Create(ctx context.Context, req *dto.CreateProdutoRequest) (*dto.ProdutoResponse, error) {
 	// Validar request
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation("dados do produto inválidos", err)
 	}
 
 	// Verificar se SKU já existe
@@ -35,7 +42,7 @@ func (s *produtoServiceImpl) Create(ctx context.Context, req *dto.CreateProdutoR
 		return nil, err
 	}
 	if existente != nil {
-		return nil, errors.New("SKU já cadastrado")
+		return nil, apperror.Conflict("SKU já cadastrado")
 	}
 
 	// Mapear DTO para Model
@@ -45,17 +52,27 @@ func (s *produtoServiceImpl) Create(ctx context.Context, req *dto.CreateProdutoR
 	}
 
 	produto := &model.Produto{
-		Nome:      req.Nome,
-		Descricao: req.Descricao,
-		Preco:     req.Preco,
-		Estoque:   req.Estoque,
-		SKU:       req.SKU,
-		Categoria: req.Categoria,
-		Ativo:     ativo,
+		Nome:          req.Nome,
+		Descricao:     req.Descricao,
+		Preco:         req.Preco,
+		Estoque:       req.Estoque,
+		SKU:           req.SKU,
+		Categoria:     req.Categoria,
+		Ativo:         ativo,
+		PesoGramas:    req.PesoGramas,
+		AlturaCm:      req.AlturaCm,
+		LarguraCm:     req.LarguraCm,
+		ComprimentoCm: req.ComprimentoCm,
 	}
 
 	// Criar no banco
-	if err := s.repo.Create(ctx, produto); err != nil {
+	err = s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, produto); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, produto.ID, model.AuditCreate, nil, s.toResponse(produto))
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -63,18 +80,17 @@ func (s *produtoServiceImpl) Create(ctx context.Context, req *dto.CreateProdutoR
 	return s.toResponse(produto), nil
 }
 
-func (s *produtoServiceImpl) FindAll(ctx context.Context) ([]dto.ProdutoResponse, error) {
-	produtos, err := s.repo.FindAll(ctx)
+func (s *produtoServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.ProdutoResponse], error) {
+	opts, err := toListOptions(page, produtoCampos)
 	if err != nil {
 		return nil, err
 	}
-
-	responses := make([]dto.ProdutoResponse, len(produtos))
-	for i, produto := range produtos {
-		responses[i] = *s.toResponse(&produto)
+	produtos, err := s.repo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return responses, nil
+	return toPageResponse(produtos, page, opts, s.toResponseValue, produtoID), nil
 }
 
 func (s *produtoServiceImpl) FindByID(ctx context.Context, id uint) (*dto.ProdutoResponse, error) {
@@ -86,38 +102,36 @@ func (s *produtoServiceImpl) FindByID(ctx context.Context, id uint) (*dto.Produt
 	return s.toResponse(produto), nil
 }
 
-func (s *produtoServiceImpl) FindByName(ctx context.Context, nome string) ([]dto.ProdutoResponse, error) {
-	produtos, err := s.repo.FindByName(ctx, nome)
+func (s *produtoServiceImpl) FindByName(ctx context.Context, nome string, page dto.PageRequest) (*dto.PageResponse[dto.ProdutoResponse], error) {
+	opts, err := toListOptions(page, produtoCampos)
 	if err != nil {
 		return nil, err
 	}
-
-	responses := make([]dto.ProdutoResponse, len(produtos))
-	for i, produto := range produtos {
-		responses[i] = *s.toResponse(&produto)
+	produtos, err := s.repo.FindByName(ctx, nome, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return responses, nil
+	return toPageResponse(produtos, page, opts, s.toResponseValue, produtoID), nil
 }
 
-func (s *produtoServiceImpl) FindByCategoria(ctx context.Context, categoria string) ([]dto.ProdutoResponse, error) {
-	produtos, err := s.repo.FindByCategoria(ctx, categoria)
+func (s *produtoServiceImpl) FindByCategoria(ctx context.Context, categoria string, page dto.PageRequest) (*dto.PageResponse[dto.ProdutoResponse], error) {
+	opts, err := toListOptions(page, produtoCampos)
 	if err != nil {
 		return nil, err
 	}
-
-	responses := make([]dto.ProdutoResponse, len(produtos))
-	for i, produto := range produtos {
-		responses[i] = *s.toResponse(&produto)
+	produtos, err := s.repo.FindByCategoria(ctx, categoria, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return responses, nil
+	return toPageResponse(produtos, page, opts, s.toResponseValue, produtoID), nil
 }
 
 func (s *produtoServiceImpl) Update(ctx context.Context, id uint, req *dto.UpdateProdutoRequest) (*dto.ProdutoResponse, error) {
 	// Validar request
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation("dados do produto inválidos", err)
 	}
 
 	// Buscar produto existente
@@ -125,72 +139,255 @@ func (s *produtoServiceImpl) Update(ctx context.Context, id uint, req *dto.Updat
 	if err != nil {
 		return nil, err
 	}
+	if err := etag.Check(ctx, produto.Versao); err != nil {
+		return nil, err
+	}
+
+	return s.replace(ctx, produto, req)
+}
 
-	// Atualizar campos se fornecidos
-	if req.Nome != "" {
-		produto.Nome = req.Nome
+func (s *produtoServiceImpl) Patch(ctx context.Context, id uint, patch []byte) (*dto.ProdutoResponse, error) {
+	// Buscar produto existente
+	produto, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if req.Descricao != "" {
-		produto.Descricao = req.Descricao
+	if err := etag.Check(ctx, produto.Versao); err != nil {
+		return nil, err
 	}
-	if req.Preco > 0 {
-		produto.Preco = req.Preco
+
+	// Aplicar o patch sobre a representação atual e validar o resultado
+	// como uma substituição completa
+	var req dto.UpdateProdutoRequest
+	if err := mergepatch.Apply(toUpdateProdutoRequest(produto), patch, &req); err != nil {
+		return nil, apperror.Validation("merge patch inválido", err)
 	}
-	if req.Estoque >= 0 {
-		produto.Estoque = req.Estoque
+	if err := s.validate.Struct(&req); err != nil {
+		return nil, apperror.Validation("dados do produto inválidos", err)
 	}
-	if req.SKU != "" {
+
+	return s.replace(ctx, produto, &req)
+}
+
+// replace substitui todos os campos editáveis do produto pelos da requisição
+func (s *produtoServiceImpl) replace(ctx context.Context, produto *model.Produto, req *dto.UpdateProdutoRequest) (*dto.ProdutoResponse, error) {
+	antes := s.toResponse(produto)
+	if req.Preco != produto.Preco {
+		// alterar o preço é restrito a administradores (ou ao escopo produtos:admin)
+		if err := auth.Authorize(ctx, auth.RecursoProdutos, auth.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if req.SKU != produto.SKU {
 		// Verificar se novo SKU já existe em outro produto
 		existente, err := s.repo.FindBySKU(ctx, req.SKU)
 		if err != nil {
 			return nil, err
 		}
-		if existente != nil && existente.ID != id {
-			return nil, errors.New("SKU já cadastrado em outro produto")
+		if existente != nil && existente.ID != produto.ID {
+			return nil, apperror.Conflict("SKU já cadastrado em outro produto")
 		}
-		produto.SKU = req.SKU
-	}
-	if req.Categoria != "" {
-		produto.Categoria = req.Categoria
-	}
-	if req.Ativo != nil {
-		produto.Ativo = *req.Ativo
 	}
 
+	produto.Nome = req.Nome
+	produto.Descricao = req.Descricao
+	produto.Preco = req.Preco
+	produto.Estoque = *req.Estoque
+	produto.SKU = req.SKU
+	produto.Categoria = req.Categoria
+	produto.Ativo = *req.Ativo
+	produto.PesoGramas = req.PesoGramas
+	produto.AlturaCm = req.AlturaCm
+	produto.LarguraCm = req.LarguraCm
+	produto.ComprimentoCm = req.ComprimentoCm
+
 	// Atualizar no banco
-	if err := s.repo.Update(ctx, produto); err != nil {
+	if err := s.atualizar(ctx, produto, antes); err != nil {
 		return nil, err
 	}
 
 	return s.toResponse(produto), nil
 }
 
+// atualizar grava o produto e registra a alteração em relação a antes
+func (s *produtoServiceImpl) atualizar(ctx context.Context, produto *model.Produto, antes *dto.ProdutoResponse) error {
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, produto); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, produto.ID, model.AuditUpdate, antes, s.toResponse(produto))
+	})
+}
+
+// excluir exclui o produto (soft delete) e registra a exclusão
+func (s *produtoServiceImpl) excluir(ctx context.Context, produto *model.Produto) error {
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, produto.ID); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, produto.ID, model.AuditDelete, s.toResponse(produto), nil)
+	})
+}
+
+// Delete exclui o produto. Falha com conflito se ele está em pedidos, que
+// ficariam apontando para um produto excluído (ver Deactivate).
 func (s *produtoServiceImpl) Delete(ctx context.Context, id uint) error {
 	// Verificar se existe
-	_, err := s.repo.FindByID(ctx, id)
+	produto, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if err := etag.Check(ctx, produto.Versao); err != nil {
+		return err
+	}
 
-	return s.repo.Delete(ctx, id)
+	// a contagem e a exclusão ficam na mesma transação, para que um pedido
+	// criado entre as duas não fique apontando para um produto excluído
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.semPedidos(ctx, id, false); err != nil {
+			return err
+		}
+		return s.excluir(ctx, produto)
+	})
+}
+
+// Deactivate exclui o produto se ele não está em pedidos; se está, apenas o
+// desativa, o que impede novos pedidos com ele
+func (s *produtoServiceImpl) Deactivate(ctx context.Context, id uint) error {
+	produto, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, produto.Versao); err != nil {
+		return err
+	}
+
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		pedidos, err := s.repo.CountPedidos(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if pedidos == 0 {
+			return s.excluir(ctx, produto)
+		}
+
+		antes := s.toResponse(produto)
+		produto.Ativo = false
+		return s.atualizar(ctx, produto, antes)
+	})
+}
+
+// Restore desfaz a exclusão do produto. Falha com conflito se o SKU dele foi
+// cadastrado em outro produto depois da exclusão.
+func (s *produtoServiceImpl) Restore(ctx context.Context, id uint) (*dto.ProdutoResponse, error) {
+	var produto *model.Produto
+	err := s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		if produto, err = s.repo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, id, model.AuditRestore, nil, s.toResponse(produto))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(produto), nil
+}
+
+// Purge remove o produto definitivamente, esteja ele excluído ou não. Assim
+// como Delete, falha com conflito se ele está em pedidos, contando também os
+// pedidos excluídos, cujos itens continuam referenciando o produto.
+func (s *produtoServiceImpl) Purge(ctx context.Context, id uint) error {
+	return s.auditoria.emTransacao(ctx, func(ctx context.Context) error {
+		if err := s.semPedidos(ctx, id, true); err != nil {
+			return err
+		}
+
+		// um produto já excluído não é encontrado, mas os dados dele ficaram na
+		// entrada da exclusão
+		var antes interface{}
+		produto, err := s.repo.FindByID(ctx, id)
+		if err == nil {
+			antes = s.toResponse(produto)
+		} else if !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+
+		if err := s.repo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return s.auditoria.registrar(ctx, id, model.AuditPurge, antes, nil)
+	})
+}
+
+// semPedidos retorna um conflito com a contagem de pedidos que têm o produto,
+// se houver algum
+func (s *produtoServiceImpl) semPedidos(ctx context.Context, id uint, incluirExcluidos bool) error {
+	pedidos, err := s.repo.CountPedidos(ctx, id, incluirExcluidos)
+	if err != nil {
+		return err
+	}
+	if pedidos > 0 {
+		return apperror.InUse(
+			fmt.Sprintf("produto está em %d pedido(s); use cascade=deactivate para desativá-lo", pedidos),
+			map[string]int64{"pedidos": pedidos},
+		)
+	}
+	return nil
 }
 
 func (s *produtoServiceImpl) Count(ctx context.Context) (int64, error) {
 	return s.repo.Count(ctx)
 }
 
+// toUpdateProdutoRequest converte Model para o DTO de substituição, base dos merge patches
+func toUpdateProdutoRequest(produto *model.Produto) dto.UpdateProdutoRequest {
+	return dto.UpdateProdutoRequest{
+		Nome:          produto.Nome,
+		Descricao:     produto.Descricao,
+		Preco:         produto.Preco,
+		Estoque:       &produto.Estoque,
+		SKU:           produto.SKU,
+		Categoria:     produto.Categoria,
+		Ativo:         &produto.Ativo,
+		PesoGramas:    produto.PesoGramas,
+		AlturaCm:      produto.AlturaCm,
+		LarguraCm:     produto.LarguraCm,
+		ComprimentoCm: produto.ComprimentoCm,
+	}
+}
+
+// toResponseValue converte Model para Response DTO por valor, usado nas listagens
+func (s *produtoServiceImpl) toResponseValue(produto *model.Produto) dto.ProdutoResponse {
+	return *s.toResponse(produto)
+}
+
+func produtoID(produto *model.Produto) uint {
+	return produto.ID
+}
+
 // toResponse converte Model para Response DTO
 func (s *produtoServiceImpl) toResponse(produto *model.Produto) *dto.ProdutoResponse {
 	return &dto.ProdutoResponse{
-		ID:        produto.ID,
-		Nome:      produto.Nome,
-		Descricao: produto.Descricao,
-		Preco:     produto.Preco,
-		Estoque:   produto.Estoque,
-		SKU:       produto.SKU,
-		Categoria: produto.Categoria,
-		Ativo:     produto.Ativo,
-		CreatedAt: produto.CreatedAt,
-		UpdatedAt: produto.UpdatedAt,
+		ID:            produto.ID,
+		Nome:          produto.Nome,
+		Descricao:     produto.Descricao,
+		Preco:         produto.Preco,
+		Estoque:       produto.Estoque,
+		SKU:           produto.SKU,
+		Categoria:     produto.Categoria,
+		Ativo:         produto.Ativo,
+		PesoGramas:    produto.PesoGramas,
+		AlturaCm:      produto.AlturaCm,
+		LarguraCm:     produto.LarguraCm,
+		ComprimentoCm: produto.ComprimentoCm,
+		Versao:        produto.Versao,
+		CreatedAt:     produto.CreatedAt,
+		UpdatedAt:     produto.UpdatedAt,
+		DeletedAt:     excluidoEm(produto.DeletedAt),
 	}
 }
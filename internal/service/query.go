@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+)
+
+// tipoCampo define como o valor de um filtro é convertido e quais operadores aceita
+type tipoCampo int
+
+const (
+	tipoTexto tipoCampo = iota
+	tipoInteiro
+	tipoDinheiro
+	tipoBooleano
+	tipoDataHora
+)
+
+// operadores aceitos por tipo de campo
+var operadoresPorTipo = map[tipoCampo][]string{
+	tipoTexto:    {repository.OpEq, repository.OpNe, repository.OpIn, repository.OpLike},
+	tipoInteiro:  {repository.OpEq, repository.OpNe, repository.OpGt, repository.OpGte, repository.OpLt, repository.OpLte, repository.OpIn},
+	tipoDinheiro: {repository.OpEq, repository.OpNe, repository.OpGt, repository.OpGte, repository.OpLt, repository.OpLte, repository.OpIn},
+	tipoBooleano: {repository.OpEq, repository.OpNe},
+	tipoDataHora: {repository.OpEq, repository.OpNe, repository.OpGt, repository.OpGte, repository.OpLt, repository.OpLte},
+}
+
+// campo é uma propriedade da API que pode ser filtrada e ordenada
+type campo struct {
+	coluna string
+	tipo   tipoCampo
+}
+
+// campos é a whitelist de filtros e ordenação de uma entidade, indexada pelo
+// nome da propriedade na API. Campos fora dela retornam 400.
+type campos map[string]campo
+
+var clienteCampos = campos{
+	"id":                 {"id", tipoInteiro},
+	"nome":               {"nome", tipoTexto},
+	"razao_social":       {"razao_social", tipoTexto},
+	"nome_fantasia":      {"nome_fantasia", tipoTexto},
+	"email":              {"email", tipoTexto},
+	"tipo":               {"tipo", tipoTexto},
+	"cpf":                {"cpf", tipoTexto},
+	"cnpj":               {"cnpj", tipoTexto},
+	"inscricao_estadual": {"inscricao_estadual", tipoTexto},
+	"telefone":           {"telefone", tipoTexto},
+	"created_at":         {"created_at", tipoDataHora},
+	"updated_at":         {"updated_at", tipoDataHora},
+}
+
+var produtoCampos = campos{
+	"id":          {"id", tipoInteiro},
+	"nome":        {"nome", tipoTexto},
+	"preco":       {"preco", tipoDinheiro},
+	"estoque":     {"estoque", tipoInteiro},
+	"sku":         {"sku", tipoTexto},
+	"categoria":   {"categoria", tipoTexto},
+	"peso_gramas": {"peso_gramas", tipoInteiro},
+	"ativo":       {"ativo", tipoBooleano},
+	"created_at":  {"created_at", tipoDataHora},
+	"updated_at":  {"updated_at", tipoDataHora},
+}
+
+var pedidoCampos = campos{
+	"id":            {"id", tipoInteiro},
+	"cliente_id":    {"cliente_id", tipoInteiro},
+	"subtotal":      {"subtotal", tipoDinheiro},
+	"desconto":      {"desconto", tipoDinheiro},
+	"imposto":       {"imposto", tipoDinheiro},
+	"valor_total":   {"valor_total", tipoDinheiro},
+	"status":        {"status", tipoTexto},
+	"uf_destino":    {"uf_destino", tipoTexto},
+	"endereco_id":   {"endereco_id", tipoInteiro},
+	"frete":         {"frete", tipoDinheiro},
+	"frete_servico": {"frete_servico", tipoTexto},
+	"data_pedido":   {"data_pedido", tipoDataHora},
+	"created_at":    {"created_at", tipoDataHora},
+	"updated_at":    {"updated_at", tipoDataHora},
+}
+
+var cupomCampos = campos{
+	"id":         {"id", tipoInteiro},
+	"codigo":     {"codigo", tipoTexto},
+	"tipo":       {"tipo", tipoTexto},
+	"ativo":      {"ativo", tipoBooleano},
+	"usos":       {"usos", tipoInteiro},
+	"valido_de":  {"valido_de", tipoDataHora},
+	"valido_ate": {"valido_ate", tipoDataHora},
+	"created_at": {"created_at", tipoDataHora},
+	"updated_at": {"updated_at", tipoDataHora},
+}
+
+var regraImpostoCampos = campos{
+	"id":         {"id", tipoInteiro},
+	"imposto":    {"imposto", tipoTexto},
+	"categoria":  {"categoria", tipoTexto},
+	"uf":         {"uf", tipoTexto},
+	"aliquota":   {"aliquota", tipoDinheiro},
+	"modo":       {"modo", tipoTexto},
+	"ativo":      {"ativo", tipoBooleano},
+	"created_at": {"created_at", tipoDataHora},
+	"updated_at": {"updated_at", tipoDataHora},
+}
+
+var freteZonaCampos = campos{
+	"id":         {"id", tipoInteiro},
+	"nome":       {"nome", tipoTexto},
+	"servico":    {"servico", tipoTexto},
+	"cep_inicio": {"cep_inicio", tipoTexto},
+	"cep_fim":    {"cep_fim", tipoTexto},
+	"prazo_dias": {"prazo_dias", tipoInteiro},
+	"ativo":      {"ativo", tipoBooleano},
+	"created_at": {"created_at", tipoDataHora},
+	"updated_at": {"updated_at", tipoDataHora},
+}
+
+// errConsultaInvalida agrupa os erros de filtros, ordenação e cursor
+func errConsultaInvalida(format string, args ...interface{}) error {
+	return apperror.Validation("parâmetros de consulta inválidos", fmt.Errorf(format, args...))
+}
+
+// filters valida os filtros contra a whitelist e converte os valores para o tipo da coluna
+func (c campos) filters(params []dto.FilterParam) ([]repository.Filter, error) {
+	var filters []repository.Filter
+	for _, p := range params {
+		cp, ok := c[p.Field]
+		if !ok {
+			return nil, errConsultaInvalida("o campo %q não pode ser filtrado", p.Field)
+		}
+		if !operadorPermitido(cp.tipo, p.Op) {
+			return nil, errConsultaInvalida("operador %q não é aceito para o campo %q", p.Op, p.Field)
+		}
+
+		brutos := []string{p.Value}
+		if p.Op == repository.OpIn {
+			brutos = strings.Split(p.Value, ",")
+		}
+		valores := make([]interface{}, len(brutos))
+		for i, b := range brutos {
+			v, err := cp.tipo.parse(strings.TrimSpace(b))
+			if err != nil {
+				return nil, errConsultaInvalida("valor inválido para o campo %q: %v", p.Field, err)
+			}
+			valores[i] = v
+		}
+
+		filters = append(filters, repository.Filter{Column: cp.coluna, Op: p.Op, Values: valores})
+	}
+	return filters, nil
+}
+
+// sort valida a ordenação contra a whitelist
+func (c campos) sort(params []dto.SortParam) ([]repository.Sort, error) {
+	var sorts []repository.Sort
+	for _, p := range params {
+		cp, ok := c[p.Field]
+		if !ok {
+			return nil, errConsultaInvalida("o campo %q não pode ser usado na ordenação", p.Field)
+		}
+		sorts = append(sorts, repository.Sort{Column: cp.coluna, Desc: p.Desc})
+	}
+	return sorts, nil
+}
+
+// afterKeys converte os valores das chaves de ordenação guardados no cursor;
+// chaves nulas continuam nil
+func (c campos) afterKeys(params []dto.SortParam, keys []*string) ([]interface{}, error) {
+	if len(keys) != len(params) {
+		return nil, errConsultaInvalida("cursor inválido para a ordenação informada")
+	}
+	valores := make([]interface{}, len(keys))
+	for i, p := range params {
+		if keys[i] == nil {
+			continue
+		}
+		v, err := c[p.Field].tipo.parse(*keys[i])
+		if err != nil {
+			return nil, errConsultaInvalida("cursor inválido para a ordenação informada")
+		}
+		valores[i] = v
+	}
+	return valores, nil
+}
+
+func operadorPermitido(tipo tipoCampo, op string) bool {
+	for _, o := range operadoresPorTipo[tipo] {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parse converte o valor recebido na query string para o tipo da coluna
+func (t tipoCampo) parse(valor string) (interface{}, error) {
+	switch t {
+	case tipoInteiro:
+		return strconv.ParseInt(valor, 10, 64)
+	case tipoDinheiro:
+		return money.Parse(valor)
+	case tipoBooleano:
+		return strconv.ParseBool(valor)
+	case tipoDataHora:
+		// datas sem hora são aceitas e correspondem à meia-noite (UTC)
+		instante, err := time.Parse(time.RFC3339Nano, valor)
+		if err != nil {
+			instante, err = time.Parse(time.DateOnly, valor)
+		}
+		if err != nil {
+			return nil, errors.New("use o formato RFC 3339 (2006-01-02T15:04:05Z) ou 2006-01-02")
+		}
+		// as datas são gravadas no fuso local; comparar no mesmo fuso mantém a ordem textual do SQLite
+		return instante.Local(), nil
+	default:
+		return valor, nil
+	}
+}
+
+// formatKey formata o valor de uma chave de ordenação no formato aceito por
+// parse; colunas opcionais sem valor viram nil (null no cursor)
+func formatKey(v interface{}) *string {
+	var texto string
+	switch valor := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		texto = valor.Format(time.RFC3339Nano)
+	case money.Money:
+		texto = valor.String()
+	default:
+		texto = fmt.Sprint(valor)
+	}
+	return &texto
+}
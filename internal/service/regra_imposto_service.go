@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// RegraImpostoService define a interface para operações de negócio de RegraImposto
+type RegraImpostoService interface {
+	Create(ctx context.Context, req *dto.CreateRegraImpostoRequest) (*dto.RegraImpostoResponse, error)
+	FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.RegraImpostoResponse], error)
+	FindByID(ctx context.Context, id uint) (*dto.RegraImpostoResponse, error)
+	Update(ctx context.Context, id uint, req *dto.UpdateRegraImpostoRequest) (*dto.RegraImpostoResponse, error)
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type regraImpostoServiceImpl struct {
+	repo     repository.RegraImpostoRepository
+	validate *validator.Validate
+}
+
+// NewRegraImpostoService cria uma nova instância do serviço
+func NewRegraImpostoService(repo repository.RegraImpostoRepository) RegraImpostoService {
+	return &regraImpostoServiceImpl{
+		repo:     repo,
+		validate: newValidator(),
+	}
+}
+
+func (s *regraImpostoServiceImpl) Create(ctx context.Context, req *dto.CreateRegraImpostoRequest) (*dto.RegraImpostoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da regra de imposto inválidos", err)
+	}
+
+	ativo := true
+	if req.Ativo != nil {
+		ativo = *req.Ativo
+	}
+
+	regra := &model.RegraImposto{
+		Imposto:   strings.ToUpper(req.Imposto),
+		Descricao: req.Descricao,
+		Categoria: strings.TrimSpace(req.Categoria),
+		UF:        strings.ToUpper(req.UF),
+		Aliquota:  req.Aliquota,
+		Modo:      req.Modo,
+		Ativo:     ativo,
+	}
+
+	// Só pode haver uma regra por imposto, categoria e UF
+	if err := s.verificarChave(ctx, regra); err != nil {
+		return nil, err
+	}
+
+	// Criar no banco
+	if err := s.repo.Create(ctx, regra); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(regra), nil
+}
+
+func (s *regraImpostoServiceImpl) FindAll(ctx context.Context, page dto.PageRequest) (*dto.PageResponse[dto.RegraImpostoResponse], error) {
+	opts, err := toListOptions(page, regraImpostoCampos)
+	if err != nil {
+		return nil, err
+	}
+	regras, err := s.repo.FindAll(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPageResponse(regras, page, opts, s.toResponseValue, regraImpostoID), nil
+}
+
+func (s *regraImpostoServiceImpl) FindByID(ctx context.Context, id uint) (*dto.RegraImpostoResponse, error) {
+	regra, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(regra), nil
+}
+
+func (s *regraImpostoServiceImpl) Update(ctx context.Context, id uint, req *dto.UpdateRegraImpostoRequest) (*dto.RegraImpostoResponse, error) {
+	// Validar request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation("dados da regra de imposto inválidos", err)
+	}
+
+	// Buscar regra existente
+	regra, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := etag.Check(ctx, regra.Versao); err != nil {
+		return nil, err
+	}
+
+	regra.Imposto = strings.ToUpper(req.Imposto)
+	regra.Descricao = req.Descricao
+	regra.Categoria = strings.TrimSpace(req.Categoria)
+	regra.UF = strings.ToUpper(req.UF)
+	regra.Aliquota = req.Aliquota
+	regra.Modo = req.Modo
+	regra.Ativo = *req.Ativo
+
+	if err := s.verificarChave(ctx, regra); err != nil {
+		return nil, err
+	}
+
+	// Atualizar no banco
+	if err := s.repo.Update(ctx, regra); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(regra), nil
+}
+
+func (s *regraImpostoServiceImpl) Delete(ctx context.Context, id uint) error {
+	// Verificar se existe
+	regra, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := etag.Check(ctx, regra.Versao); err != nil {
+		return err
+	}
+
+	// os pedidos já criados mantêm os impostos calculados
+	return s.repo.Delete(ctx, id)
+}
+
+// verificarChave impede duas regras para o mesmo imposto, categoria e UF, o
+// que deixaria ambígua a escolha da regra no cálculo do pedido
+func (s *regraImpostoServiceImpl) verificarChave(ctx context.Context, regra *model.RegraImposto) error {
+	existente, err := s.repo.FindByChave(ctx, regra.Imposto, regra.Categoria, regra.UF)
+	if err != nil {
+		return err
+	}
+	if existente != nil && existente.ID != regra.ID {
+		categoria, uf := regra.Categoria, regra.UF
+		if categoria == "" {
+			categoria = "todas"
+		}
+		if uf == "" {
+			uf = "todas"
+		}
+		return apperror.Conflict(fmt.Sprintf("já existe regra de %s para categoria %s e UF %s (id %d)",
+			regra.Imposto, categoria, uf, existente.ID))
+	}
+	return nil
+}
+
+// toResponseValue converte Model para Response DTO por valor, usado nas listagens
+func (s *regraImpostoServiceImpl) toResponseValue(regra *model.RegraImposto) dto.RegraImpostoResponse {
+	return *s.toResponse(regra)
+}
+
+func regraImpostoID(regra *model.RegraImposto) uint {
+	return regra.ID
+}
+
+// toResponse converte Model para Response DTO
+func (s *regraImpostoServiceImpl) toResponse(regra *model.RegraImposto) *dto.RegraImpostoResponse {
+	return &dto.RegraImpostoResponse{
+		ID:        regra.ID,
+		Imposto:   regra.Imposto,
+		Descricao: regra.Descricao,
+		Categoria: regra.Categoria,
+		UF:        regra.UF,
+		Aliquota:  regra.Aliquota,
+		Modo:      regra.Modo,
+		Ativo:     regra.Ativo,
+		Versao:    regra.Versao,
+		CreatedAt: regra.CreatedAt,
+		UpdatedAt: regra.UpdatedAt,
+		DeletedAt: excluidoEm(regra.DeletedAt),
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/danmaciel/api/internal/dto"
+)
+
+// SearchService define a interface da busca textual em produtos e clientes
+type SearchService interface {
+	Search(ctx context.Context, req dto.SearchRequest) (*dto.SearchResponse, error)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/repository"
+)
+
+const (
+	// searchDefaultLimit é a quantidade de resultados quando o cliente não informa limit
+	searchDefaultLimit = 20
+	// searchMaxLimit é a maior quantidade de resultados retornada por busca
+	searchMaxLimit = 50
+	// searchMaxQuery é o maior tamanho aceito para o texto buscado
+	searchMaxQuery = 200
+	// searchMaxTermos limita o número de termos para manter a consulta barata
+	searchMaxTermos = 10
+)
+
+type searchServiceImpl struct {
+	repo repository.SearchRepository
+}
+
+// NewSearchService cria uma nova instância do serviço
+func NewSearchService(repo repository.SearchRepository) SearchService {
+	return &searchServiceImpl{repo: repo}
+}
+
+func (s *searchServiceImpl) Search(ctx context.Context, req dto.SearchRequest) (*dto.SearchResponse, error) {
+	termos, err := termosBusca(req.Q)
+	if err != nil {
+		return nil, apperror.Validation("parâmetros de busca inválidos", err)
+	}
+
+	tipos, err := tiposBusca(ctx, req.Tipos)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+
+	hits, err := s.repo.Search(ctx, repository.SearchOptions{Termos: termos, Tipos: tipos, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SearchResponse{Query: req.Q, Data: make([]dto.SearchResult, len(hits))}
+	for i, hit := range hits {
+		response.Data[i] = dto.SearchResult{
+			Tipo:   hit.Tipo,
+			ID:     hit.ID,
+			Titulo: destacar(hit.Titulo),
+			Trecho: destacar(hit.Trecho),
+			// o bm25 é negativo e menor para os mais relevantes
+			Score: -hit.Rank,
+			Href:  fmt.Sprintf("/api/v1/%s/%d", hit.Tipo, hit.ID),
+		}
+	}
+	return response, nil
+}
+
+// termosBusca separa o texto em palavras, descartando pontuação e símbolos.
+// Maiúsculas e acentos são tratados pelo próprio índice.
+func termosBusca(q string) ([]string, error) {
+	if utf8.RuneCountInString(q) > searchMaxQuery {
+		return nil, fmt.Errorf("q deve ter no máximo %d caracteres", searchMaxQuery)
+	}
+	termos := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(termos) == 0 {
+		return nil, errors.New("q deve conter ao menos uma letra ou número")
+	}
+	if len(termos) > searchMaxTermos {
+		return nil, fmt.Errorf("q deve ter no máximo %d termos", searchMaxTermos)
+	}
+	return termos, nil
+}
+
+// tiposBusca valida os tipos pedidos e a permissão de leitura em cada um. Sem
+// tipos informados, busca em todos os que o principal pode ler.
+func tiposBusca(ctx context.Context, pedidos []string) ([]string, error) {
+	if len(pedidos) == 0 {
+		var tipos []string
+		for _, tipo := range repository.SearchTipos {
+			if auth.Authorize(ctx, tipo, auth.RoleLeitura) == nil {
+				tipos = append(tipos, tipo)
+			}
+		}
+		if len(tipos) == 0 {
+			return nil, apperror.Forbidden("nenhum tipo de busca permitido para o principal")
+		}
+		return tipos, nil
+	}
+
+	var tipos []string
+	for _, tipo := range pedidos {
+		if !slices.Contains(repository.SearchTipos, tipo) {
+			return nil, apperror.Validation("parâmetros de busca inválidos",
+				fmt.Errorf("tipo %q não é pesquisável (use %s)", tipo, strings.Join(repository.SearchTipos, ", ")))
+		}
+		if err := auth.Authorize(ctx, tipo, auth.RoleLeitura); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(tipos, tipo) {
+			tipos = append(tipos, tipo)
+		}
+	}
+	return tipos, nil
+}
+
+// destacar escapa o texto para HTML e troca os marcadores do índice por <mark>
+func destacar(texto string) string {
+	texto = html.EscapeString(texto)
+	texto = strings.ReplaceAll(texto, repository.HighlightOpen, "<mark>")
+	return strings.ReplaceAll(texto, repository.HighlightClose, "</mark>")
+}
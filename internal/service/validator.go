@@ -0,0 +1,76 @@
+package service
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/documento"
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator cria o validador usado pelos serviços. Os campos são
+// identificados pelo nome da tag json, o mesmo que o cliente da API envia.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	// scope valida escopos de API key no formato "<recurso>:<ação>"
+	v.RegisterValidation("scope", func(fl validator.FieldLevel) bool {
+		return auth.ValidScope(fl.Field().String())
+	})
+	// codigo valida códigos de cupom: letras, números, hífen e sublinhado
+	v.RegisterValidation("codigo", func(fl validator.FieldLevel) bool {
+		return codigoCupom.MatchString(fl.Field().String())
+	})
+	// uf valida a sigla de uma unidade federativa, em maiúsculas ou minúsculas
+	v.RegisterValidation("uf", func(fl validator.FieldLevel) bool {
+		return slices.Contains(ufs, strings.ToUpper(fl.Field().String()))
+	})
+	// cep valida o CEP com ou sem hífen: 00000-000 ou 00000000
+	v.RegisterValidation("cep", func(fl validator.FieldLevel) bool {
+		return formatoCEP.MatchString(fl.Field().String())
+	})
+	// cpf e cnpj validam os dígitos verificadores, com ou sem máscara
+	v.RegisterValidation("cpf", func(fl validator.FieldLevel) bool {
+		return documento.CPFValido(fl.Field().String())
+	})
+	v.RegisterValidation("cnpj", func(fl validator.FieldLevel) bool {
+		return documento.CNPJValido(fl.Field().String())
+	})
+	// ie valida a inscrição estadual: ISENTO ou apenas dígitos, com ou sem máscara
+	v.RegisterValidation("ie", func(fl validator.FieldLevel) bool {
+		return documento.InscricaoEstadualValida(fl.Field().String())
+	})
+	// aliquota valida percentuais de imposto, de 0 a 100%
+	v.RegisterValidation("aliquota", func(fl validator.FieldLevel) bool {
+		pontosBase := fl.Field().Int()
+		return pontosBase >= 0 && pontosBase <= 10000
+	})
+	return v
+}
+
+var codigoCupom = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var formatoCEP = regexp.MustCompile(`^[0-9]{5}-?[0-9]{3}$`)
+
+// normalizarCEP remove o hífen, deixando só os 8 dígitos gravados no banco
+func normalizarCEP(cep string) string {
+	return strings.ReplaceAll(cep, "-", "")
+}
+
+// ufs são as siglas das unidades federativas
+var ufs = []string{
+	"AC", "AL", "AM", "AP", "BA", "CE", "DF", "ES", "GO", "MA", "MG", "MS", "MT", "PA",
+	"PB", "PE", "PI", "PR", "RJ", "RN", "RO", "RR", "RS", "SC", "SE", "SP", "TO",
+}
@@ -0,0 +1,174 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupAPIKeyTestRouter monta o roteador como em produção: API key seguida de JWT
+func setupAPIKeyTestRouter(t *testing.T) (*chi.Mux, *gorm.DB, string) {
+	db := setupProdutoTestDB(t)
+	if err := db.AutoMigrate(&model.APIKey{}); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	controllers := setupProdutoTestRouter(db)
+
+	jwt, err := auth.NewHS256([]byte("segredo-de-teste-com-pelo-menos-32-bytes"), "cliente-api")
+	assert.NoError(t, err)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepositorySQLite(db))
+	router := controller.SetupRouter(controllers, middleware.APIKey(apiKeyService), middleware.Authenticate(jwt))
+
+	adminToken, err := jwt.Issue("admin", []string{auth.RoleAdmin}, time.Hour)
+	assert.NoError(t, err)
+	return router, db, "Bearer " + adminToken
+}
+
+func createAPIKey(t *testing.T, router *chi.Mux, adminToken string, scopes ...string) dto.APIKeyCreatedResponse {
+	body, _ := json.Marshal(dto.CreateAPIKeyRequest{Nome: "Conector ERP", Scopes: scopes})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", adminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created dto.APIKeyCreatedResponse
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	return created
+}
+
+func TestAPIKey_CreateListAndUse_Integration(t *testing.T) {
+	router, db, adminToken := setupAPIKeyTestRouter(t)
+
+	created := createAPIKey(t, router, adminToken, "produtos:write")
+	assert.NotEmpty(t, created.Key)
+	assert.Equal(t, "admin", created.CriadoPor)
+	assert.Nil(t, created.LastUsedAt)
+
+	// apenas o hash é persistido
+	var stored model.APIKey
+	db.First(&stored, created.ID)
+	assert.NotEqual(t, created.Key, stored.Hash)
+	assert.NotContains(t, stored.Hash, created.Key)
+
+	// a chave autentica e autoriza pelo escopo
+	body := `{"nome":"Teclado","sku":"TC-001","preco":"150.00","estoque":5,"categoria":"Periféricos"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/produtos", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", created.Key)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// a listagem não devolve o segredo e mostra o último uso
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/api-keys", nil)
+	req.Header.Set("Authorization", adminToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), created.Key)
+	var page dto.APIKeyPageResponse
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, created.Prefixo, page.Data[0].Prefixo)
+	assert.NotNil(t, page.Data[0].LastUsedAt)
+}
+
+func TestAPIKey_ScopeEnforcement_Integration(t *testing.T) {
+	router, _, adminToken := setupAPIKeyTestRouter(t)
+	created := createAPIKey(t, router, adminToken, "pedidos:write")
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"write implies read on same resource", http.MethodGet, "/api/v1/pedidos", http.StatusOK},
+		{"other resource is forbidden", http.MethodGet, "/api/v1/produtos", http.StatusForbidden},
+		{"delete requires admin scope", http.MethodDelete, "/api/v1/pedidos/1", http.StatusForbidden},
+		{"api keys cannot manage api keys", http.MethodGet, "/api/v1/api-keys", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-API-Key", created.Key)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestAPIKey_RevokedAndInvalid_Integration(t *testing.T) {
+	router, _, adminToken := setupAPIKeyTestRouter(t)
+	created := createAPIKey(t, router, adminToken, "produtos:read")
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/api-keys/1", nil)
+	req.Header.Set("Authorization", adminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	for _, key := range []string{created.Key, "ak_00000000_inexistente"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/produtos", nil)
+		req.Header.Set("X-API-Key", key)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestAPIKey_AdminOnly_Integration(t *testing.T) {
+	router, _, _ := setupAPIKeyTestRouter(t)
+
+	jwt, _ := auth.NewHS256([]byte("segredo-de-teste-com-pelo-menos-32-bytes"), "cliente-api")
+	operadorToken, _ := jwt.Issue("joao", []string{auth.RoleOperador}, time.Hour)
+
+	body := `{"nome":"Conector","scopes":["pedidos:write"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+operadorToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAPIKey_InvalidScope_Integration(t *testing.T) {
+	router, _, adminToken := setupAPIKeyTestRouter(t)
+
+	body := `{"nome":"Conector","scopes":["pedidos:delete"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", adminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p dto.ProblemDetails
+	json.Unmarshal(rec.Body.Bytes(), &p)
+	if assert.Len(t, p.Errors, 1) {
+		assert.Equal(t, "scopes[0]", p.Errors[0].Field)
+		assert.Equal(t, "scope", p.Errors[0].Rule)
+	}
+}
@@ -0,0 +1,277 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func listAudit(t *testing.T, router *chi.Mux, query string) dto.PageResponse[dto.AuditLogResponse] {
+	rec := sendWithHeaders(router, http.MethodGet, "/api/v1/audit"+query, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page dto.PageResponse[dto.AuditLogResponse]
+	json.NewDecoder(rec.Body).Decode(&page)
+	return page
+}
+
+func verifyAudit(t *testing.T, router *chi.Mux) dto.AuditVerifyResponse {
+	rec := sendWithHeaders(router, http.MethodGet, "/api/v1/audit/verify", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp dto.AuditVerifyResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	return resp
+}
+
+func TestAudit_RegistraEscritas_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/clientes",
+		dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"},
+		map[string]string{"X-Request-Id": "req-criar"})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var cliente dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&cliente)
+	target := fmt.Sprintf("/api/v1/clientes/%d", cliente.ID)
+
+	rec = sendWithHeaders(router, http.MethodPut, target, dto.UpdateClienteRequest{Nome: "Maria Souza", Email: "maria@example.com", CPF: "52998224725"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// uma escrita que falha não deixa entrada
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos", dto.CreateProdutoRequest{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("99.90"), Estoque: 10}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos", dto.CreateProdutoRequest{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("99.90"), Estoque: 10}, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	page := listAudit(t, router, fmt.Sprintf("?entity=cliente&id=%d", cliente.ID))
+	assert.Equal(t, int64(3), page.Total)
+	if !assert.Len(t, page.Data, 3) {
+		return
+	}
+
+	criacao, alteracao, exclusao := page.Data[0], page.Data[1], page.Data[2]
+	assert.Equal(t, model.AuditCreate, criacao.Acao)
+	assert.Equal(t, auth.ActorAnonimo, criacao.Ator)
+	assert.Equal(t, "req-criar", criacao.RequestID)
+	assert.Empty(t, criacao.HashAnterior)
+	assert.Contains(t, string(criacao.Diff), `"tipo":{"depois":"PF"}`)
+	// os dados pessoais aparecem como alterados, sem os valores
+	assert.Contains(t, string(criacao.Diff), `"email":{"depois":"[omitido]"}`)
+	assert.NotContains(t, string(criacao.Diff), "maria@example.com")
+
+	// a alteração guarda apenas os campos alterados
+	assert.Equal(t, model.AuditUpdate, alteracao.Acao)
+	assert.NotEmpty(t, alteracao.RequestID)
+	assert.Equal(t, criacao.Hash, alteracao.HashAnterior)
+	assert.Contains(t, string(alteracao.Diff), `"nome":{"antes":"[omitido]","depois":"[omitido]"}`)
+	assert.NotContains(t, string(alteracao.Diff), "Maria")
+	assert.NotContains(t, string(alteracao.Diff), "email")
+
+	assert.Equal(t, model.AuditDelete, exclusao.Acao)
+	assert.Contains(t, string(exclusao.Diff), `"nome":{"antes":"[omitido]"}`)
+
+	page = listAudit(t, router, "?entity=produto")
+	assert.Equal(t, int64(1), page.Total)
+	page = listAudit(t, router, "")
+	assert.Equal(t, int64(4), page.Total)
+
+	// to com apenas a data inclui o dia inteiro
+	hoje := time.Now().Format(time.DateOnly)
+	amanha := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	page = listAudit(t, router, "?from="+hoje+"&to="+hoje)
+	assert.Equal(t, int64(4), page.Total)
+	page = listAudit(t, router, "?from="+amanha)
+	assert.Equal(t, int64(0), page.Total)
+
+	for _, query := range []string{"?entity=cupom", "?id=1", "?id=abc", "?from=ontem"} {
+		rec = sendWithHeaders(router, http.MethodGet, "/api/v1/audit"+query, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestAudit_PedidoEPurge_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	rec := sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), dto.UpdatePedidoRequest{Status: model.StatusPago}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	page := listAudit(t, router, fmt.Sprintf("?entity=pedido&id=%d", pedido.ID))
+	if assert.Len(t, page.Data, 2) {
+		assert.Equal(t, model.AuditCreate, page.Data[0].Acao)
+		assert.Contains(t, string(page.Data[1].Diff), `"status":{"antes":"pendente","depois":"pago"}`)
+	}
+
+	// o produto sem pedidos removido definitivamente fica no audit log
+	produto := &model.Produto{Nome: "Caneta", SKU: "CN-001", Preco: money.MustParse("2.50"), Estoque: 100, Ativo: true}
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos", dto.CreateProdutoRequest{Nome: produto.Nome, SKU: produto.SKU, Preco: produto.Preco, Estoque: produto.Estoque}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	json.NewDecoder(rec.Body).Decode(produto)
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/produtos/%d?purge=true", produto.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	page = listAudit(t, router, fmt.Sprintf("?entity=produto&id=%d", produto.ID))
+	if assert.Len(t, page.Data, 2) {
+		assert.Equal(t, model.AuditPurge, page.Data[1].Acao)
+		assert.Contains(t, string(page.Data[1].Diff), `"sku":{"antes":"CN-001"}`)
+	}
+}
+
+func TestAudit_PagamentoDevolucaoECancelamento_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	// a quitação pela notificação do provedor é registrada em nome dele
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix})
+	rec := notificar(router, pag.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/devolucoes", pedido.ID), dto.CreateDevolucaoRequest{
+		Itens: []dto.DevolucaoItemRequest{{ItemID: pedido.Itens[0].ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Cliente desistiu"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	page := listAudit(t, router, fmt.Sprintf("?entity=pedido&id=%d", pedido.ID))
+	if !assert.Len(t, page.Data, 4) {
+		return
+	}
+	quitacao, devolucao, cancelamento := page.Data[1], page.Data[2], page.Data[3]
+	assert.Equal(t, "gateway:fake", quitacao.Ator)
+	assert.Contains(t, string(quitacao.Diff), `"status":{"antes":"pendente","depois":"pago"}`)
+	assert.Equal(t, auth.ActorAnonimo, devolucao.Ator)
+	assert.Contains(t, string(devolucao.Diff), `"devolucoes"`)
+	assert.Contains(t, string(cancelamento.Diff), `"status":{"antes":"pago","depois":"cancelado"}`)
+	assert.Contains(t, string(cancelamento.Diff), `"motivo_cancelamento":{"depois":"Cliente desistiu"}`)
+	assert.True(t, verifyAudit(t, router).Valid)
+}
+
+func TestAudit_Verify_Integration(t *testing.T) {
+	router, db := setupSoftDeleteTestRouter(t)
+
+	assert.Equal(t, dto.AuditVerifyResponse{Valid: true}, verifyAudit(t, router))
+
+	for _, req := range []dto.CreateClienteRequest{
+		{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"},
+		{Nome: "João Souza", Email: "joao@example.com", CPF: "12345678909"},
+		{Nome: "Ana Lima", Email: "ana@example.com", CPF: "11144477735"},
+	} {
+		createClienteAPI(t, router, req)
+	}
+
+	resp := verifyAudit(t, router)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, int64(3), resp.Entries)
+
+	// alterar uma entrada direto no banco quebra a cadeia nela
+	db.Exec("UPDATE audit_log SET ator = 'outro' WHERE id = 2")
+	resp = verifyAudit(t, router)
+	assert.False(t, resp.Valid)
+	if assert.NotNil(t, resp.BrokenAt) {
+		assert.Equal(t, uint(2), *resp.BrokenAt)
+	}
+
+	// remover uma entrada quebra a cadeia na seguinte
+	db.Exec("UPDATE audit_log SET ator = ? WHERE id = 2", auth.ActorAnonimo)
+	assert.True(t, verifyAudit(t, router).Valid)
+	db.Exec("DELETE FROM audit_log WHERE id = 2")
+	resp = verifyAudit(t, router)
+	assert.False(t, resp.Valid)
+	if assert.NotNil(t, resp.BrokenAt) {
+		assert.Equal(t, uint(3), *resp.BrokenAt)
+	}
+}
+
+func TestAudit_Verify_UltimasRemovidas_Integration(t *testing.T) {
+	router, db := setupSoftDeleteTestRouter(t)
+
+	for _, req := range []dto.CreateClienteRequest{
+		{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"},
+		{Nome: "João Souza", Email: "joao@example.com", CPF: "12345678909"},
+		{Nome: "Ana Lima", Email: "ana@example.com", CPF: "11144477735"},
+	} {
+		createClienteAPI(t, router, req)
+	}
+
+	// remover as últimas entradas deixa a cadeia restante íntegra, mas a ponta
+	// guarda o hash da última gravada
+	db.Exec("DELETE FROM audit_log WHERE id >= 2")
+	resp := verifyAudit(t, router)
+	assert.False(t, resp.Valid)
+	assert.Equal(t, int64(1), resp.Entries)
+	assert.Nil(t, resp.BrokenAt)
+}
+
+func TestAudit_PontaDaCadeia_Integration(t *testing.T) {
+	router, db := setupSoftDeleteTestRouter(t)
+
+	// entradas gravadas antes da ponta da cadeia existir continuam a valer
+	antiga := model.AuditLog{Entidade: model.AuditCliente, EntidadeID: 99, Acao: model.AuditCreate, Ator: "legado", Diff: "{}", CreatedAt: time.Now()}
+	antiga.Hash = antiga.CalcularHash()
+	db.Create(&antiga)
+
+	createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"})
+	var cadeia model.AuditCadeia
+	db.First(&cadeia)
+	page := listAudit(t, router, "")
+	if assert.Len(t, page.Data, 2) {
+		assert.Equal(t, antiga.Hash, page.Data[1].HashAnterior)
+		assert.Equal(t, page.Data[1].Hash, cadeia.Hash)
+	}
+
+	// uma gravação concorrente que já continuou a cadeia a partir do mesmo
+	// hash faz a escrita auditada falhar com 409, sem deixar nada gravado
+	db.Model(&cadeia).Update("hash", antiga.Hash)
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/clientes", dto.CreateClienteRequest{Nome: "João Souza", Email: "joao@example.com", CPF: "12345678909"}, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Equal(t, int64(1), listClientes(t, router, "").Total)
+
+	// a ponta fora do lugar também aparece na verificação
+	assert.False(t, verifyAudit(t, router).Valid)
+	db.Model(&cadeia).Update("hash", page.Data[1].Hash)
+	assert.True(t, verifyAudit(t, router).Valid)
+}
+
+func TestAudit_RequireAdmin_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+	admin := map[string]string{"Authorization": issue(auth.RoleAdmin)}
+
+	rec := sendWithHeaders(router, http.MethodGet, "/api/v1/audit", nil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/audit/verify", nil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// o ator é o usuário do token
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/produtos/1", nil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/produtos/1", nil, admin)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/audit?entity=produto&id=1", nil, admin)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page dto.PageResponse[dto.AuditLogResponse]
+	json.NewDecoder(rec.Body).Decode(&page)
+	if assert.Len(t, page.Data, 1) {
+		assert.Equal(t, model.AuditDelete, page.Data[0].Acao)
+		assert.Equal(t, "tester", page.Data[0].Ator)
+	}
+
+	// o audit log é somente leitura
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/audit", nil, admin)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
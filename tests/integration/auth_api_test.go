@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAuthTestRouter monta o roteador com autenticação JWT real e retorna
+// uma função que emite tokens para os papéis informados
+func setupAuthTestRouter(t *testing.T) (*chi.Mux, func(roles ...string) string) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+
+	jwt, err := auth.NewHS256([]byte("segredo-de-teste-com-pelo-menos-32-bytes"), "cliente-api")
+	assert.NoError(t, err)
+	router := controller.SetupRouter(controllers, middleware.Authenticate(jwt))
+
+	db.Create(&model.Produto{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("99.90"), Estoque: 10, Categoria: "Periféricos", Ativo: true})
+
+	issue := func(roles ...string) string {
+		token, err := jwt.Issue("tester", roles, time.Hour)
+		assert.NoError(t, err)
+		return "Bearer " + token
+	}
+	return router, issue
+}
+
+func TestAuth_MissingToken_Integration(t *testing.T) {
+	router, _ := setupAuthTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+
+	// health e swagger continuam públicos
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuth_RoleRequirements_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+
+	novoProduto := map[string]interface{}{"nome": "Teclado", "sku": "TC-001", "preco": "150.00", "estoque": 5, "categoria": "Periféricos"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		roles          []string
+		expectedStatus int
+	}{
+		{"leitura can list", http.MethodGet, "/api/v1/produtos", nil, []string{auth.RoleLeitura}, http.StatusOK},
+		{"leitura cannot create", http.MethodPost, "/api/v1/produtos", novoProduto, []string{auth.RoleLeitura}, http.StatusForbidden},
+		{"operador can create", http.MethodPost, "/api/v1/produtos", novoProduto, []string{auth.RoleOperador}, http.StatusCreated},
+		{"operador can update stock", http.MethodPatch, "/api/v1/produtos/1", map[string]interface{}{"estoque": 20}, []string{auth.RoleOperador}, http.StatusOK},
+		{"operador cannot change price", http.MethodPatch, "/api/v1/produtos/1", map[string]interface{}{"preco": "89.90"}, []string{auth.RoleOperador}, http.StatusForbidden},
+		{"admin can change price", http.MethodPatch, "/api/v1/produtos/1", map[string]interface{}{"preco": "89.90"}, []string{auth.RoleAdmin}, http.StatusOK},
+		{"leitura cannot change order status", http.MethodPut, "/api/v1/pedidos/1", map[string]interface{}{"status": "confirmado"}, []string{auth.RoleLeitura}, http.StatusForbidden},
+		{"operador cannot delete", http.MethodDelete, "/api/v1/produtos/1", nil, []string{auth.RoleOperador}, http.StatusForbidden},
+		{"admin can delete", http.MethodDelete, "/api/v1/produtos/1", nil, []string{auth.RoleAdmin}, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			if tt.body != nil {
+				json.NewEncoder(&body).Encode(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, &body)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.method == http.MethodPatch {
+				req.Header.Set("Content-Type", "application/merge-patch+json")
+			}
+			req.Header.Set("Authorization", issue(tt.roles...))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
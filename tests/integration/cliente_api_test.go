@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/danmaciel/api/internal/controller"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/middleware"
 	"github.com/danmaciel/api/internal/model"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
@@ -24,36 +27,94 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Run migrations
-	if err := db.AutoMigrate(&model.Cliente{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}); err != nil {
+	if err := db.AutoMigrate(&model.Cliente{}, &model.Endereco{}, &model.Contato{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}, &model.PedidoDesconto{}, &model.Cupom{}, &model.RegraImposto{}, &model.PedidoItemImposto{}, &model.FreteZona{}, &model.FreteFaixa{}, &model.Pagamento{}, &model.PagamentoEstorno{}, &model.PedidoDevolucao{}, &model.PedidoDevolucaoItem{}, &model.AuditLog{}, &model.AuditCadeia{}); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return db
 }
 
-func setupTestRouter(db *gorm.DB) (*controller.ClienteController, *controller.ProdutoController, *controller.PedidoController) {
+func setupTestRouter(db *gorm.DB) controller.Controllers {
+	// Audit log
+	auditRepo := repository.NewAuditRepositorySQLite(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditController := controller.NewAuditController(auditService)
+
 	// Cliente
 	clienteRepo := repository.NewClienteRepositorySQLite(db)
-	clienteService := service.NewClienteService(clienteRepo)
+	clienteService := service.NewClienteService(clienteRepo, auditRepo)
 	clienteController := controller.NewClienteController(clienteService)
 
 	// Produto
 	produtoRepo := repository.NewProdutoRepositorySQLite(db)
-	produtoService := service.NewProdutoService(produtoRepo)
+	produtoService := service.NewProdutoService(produtoRepo, auditRepo)
 	produtoController := controller.NewProdutoController(produtoService)
 
+	// Cupom
+	cupomRepo := repository.NewCupomRepositorySQLite(db)
+	cupomService := service.NewCupomService(cupomRepo)
+	cupomController := controller.NewCupomController(cupomService)
+
+	// Regras de imposto
+	regraImpostoRepo := repository.NewRegraImpostoRepositorySQLite(db)
+	regraImpostoService := service.NewRegraImpostoService(regraImpostoRepo)
+	regraImpostoController := controller.NewRegraImpostoController(regraImpostoService)
+
+	// Endereços
+	enderecoRepo := repository.NewEnderecoRepositorySQLite(db)
+	enderecoService := service.NewEnderecoService(enderecoRepo, clienteRepo)
+	enderecoController := controller.NewEnderecoController(enderecoService)
+
+	// Contatos
+	contatoRepo := repository.NewContatoRepositorySQLite(db)
+	contatoService := service.NewContatoService(contatoRepo, clienteRepo)
+	contatoController := controller.NewContatoController(contatoService)
+
+	// Frete
+	freteRepo := repository.NewFreteRepositorySQLite(db)
+	freteCalculator := frete.NewTabela(freteRepo)
+	freteService := service.NewFreteService(freteRepo, produtoRepo, freteCalculator)
+	freteController := controller.NewFreteController(freteService)
+
 	// Pedido
 	pedidoRepo := repository.NewPedidoRepositorySQLite(db)
-	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo)
+	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, cupomRepo, regraImpostoRepo, enderecoRepo, freteCalculator, auditRepo)
 	pedidoController := controller.NewPedidoController(pedidoService)
 
-	return clienteController, produtoController, pedidoController
+	// Pagamentos
+	pagamentoRepo := repository.NewPagamentoRepositorySQLite(db)
+	pagamentoService := service.NewPagamentoService(pagamentoRepo, pedidoRepo, testGateway, auditRepo)
+	pagamentoController := controller.NewPagamentoController(pagamentoService)
+
+	// Cancelamento e devoluções
+	devolucaoService := service.NewDevolucaoService(pedidoRepo, produtoRepo, cupomRepo, pagamentoRepo, testGateway, auditRepo)
+	devolucaoController := controller.NewDevolucaoController(devolucaoService)
+
+	// API keys
+	apiKeyRepo := repository.NewAPIKeyRepositorySQLite(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+
+	return controller.Controllers{
+		Cliente:      clienteController,
+		Produto:      produtoController,
+		Pedido:       pedidoController,
+		APIKey:       apiKeyController,
+		Cupom:        cupomController,
+		RegraImposto: regraImpostoController,
+		Endereco:     enderecoController,
+		Frete:        freteController,
+		Pagamento:    pagamentoController,
+		Devolucao:    devolucaoController,
+		Contato:      contatoController,
+		Audit:        auditController,
+	}
 }
 
 func TestCreateCliente_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.CreateClienteRequest{
 		Nome:     "Maria Silva",
@@ -80,8 +141,8 @@ func TestCreateCliente_Integration(t *testing.T) {
 
 func TestGetAllClientes_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	db.Create(&model.Cliente{Nome: "Cliente 1", Email: "c1@example.com", CPF: "11111111111"})
@@ -94,15 +155,15 @@ func TestGetAllClientes_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ClienteResponse
+	var response dto.PageResponse[dto.ClienteResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
+	assert.Len(t, response.Data, 2)
 }
 
 func TestGetClienteByID_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "33333333333"}
@@ -122,8 +183,8 @@ func TestGetClienteByID_Integration(t *testing.T) {
 
 func TestCountClientes_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	db.Create(&model.Cliente{Nome: "Cliente 1", Email: "c1@example.com", CPF: "11111111111"})
@@ -143,15 +204,17 @@ func TestCountClientes_Integration(t *testing.T) {
 
 func TestUpdateCliente_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	cliente := &model.Cliente{Nome: "Cliente Original", Email: "original@example.com", CPF: "44444444444"}
+	cliente := &model.Cliente{Nome: "Cliente Original", Email: "original@example.com", CPF: "39053344705"}
 	db.Create(cliente)
 
 	reqBody := dto.UpdateClienteRequest{
-		Nome: "Cliente Atualizado",
+		Nome:  "Cliente Atualizado",
+		Email: "original@example.com",
+		CPF:   "39053344705",
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -168,10 +231,56 @@ func TestUpdateCliente_Integration(t *testing.T) {
 	assert.Equal(t, "Cliente Atualizado", response.Nome)
 }
 
+func TestPatchCliente_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	// Create test data
+	cliente := &model.Cliente{Nome: "Cliente Original", Email: "original@example.com", CPF: "39053344705", Telefone: "11999999999"}
+	db.Create(cliente)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/clientes/1", strings.NewReader(`{"nome": "Cliente Atualizado", "telefone": null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, "Cliente Atualizado", response.Nome)
+	assert.Equal(t, "original@example.com", response.Email)
+	assert.Empty(t, response.Telefone)
+
+	var atualizado model.Cliente
+	db.First(&atualizado, cliente.ID)
+	assert.Empty(t, atualizado.Telefone)
+}
+
+func TestPatchCliente_UnsupportedMediaType_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "Cliente Original", Email: "original@example.com", CPF: "39053344705"}
+	db.Create(cliente)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/clientes/1", strings.NewReader(`{"nome": "Cliente Atualizado"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "application/merge-patch+json", rec.Header().Get("Accept-Patch"))
+}
+
 func TestDeleteCliente_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Para Deletar", Email: "deletar@example.com", CPF: "55555555555"}
@@ -187,8 +296,8 @@ func TestDeleteCliente_Integration(t *testing.T) {
 
 func TestFindByNome_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	db.Create(&model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "66666666666"})
@@ -201,17 +310,17 @@ func TestFindByNome_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ClienteResponse
+	var response dto.PageResponse[dto.ClienteResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 1)
-	assert.Equal(t, "João Silva", response[0].Nome)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "João Silva", response.Data[0].Nome)
 }
 
 // Testes de erro para aumentar cobertura
 func TestCreateCliente_InvalidJSON_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/clientes", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -224,8 +333,8 @@ func TestCreateCliente_InvalidJSON_Integration(t *testing.T) {
 
 func TestCreateCliente_ValidationError_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.CreateClienteRequest{
 		Nome:  "", // Invalid: empty name
@@ -240,14 +349,180 @@ func TestCreateCliente_ValidationError_Integration(t *testing.T) {
 
 	router.ServeHTTP(rec, req)
 
-	// Service validation returns 500 for validation errors in this implementation
-	assert.True(t, rec.Code == http.StatusBadRequest || rec.Code == http.StatusInternalServerError)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	var response dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, "/problems/validation-error", response.Type)
+	assert.Equal(t, http.StatusBadRequest, response.Status)
+	assert.Equal(t, "dados do cliente inválidos", response.Detail)
+	assert.NotEmpty(t, response.Instance)
+	assert.ElementsMatch(t, []dto.FieldError{
+		{Field: "nome", Rule: "required_unless", Message: "é obrigatório, exceto quando tipo é PJ"},
+		{Field: "email", Rule: "email", Message: "deve ser um email válido"},
+		{Field: "cpf", Rule: "cpf", Message: "deve ser um CPF válido, com ou sem máscara"},
+	}, response.Errors)
+}
+
+func TestCreateCliente_DuplicateEmail_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	db.Create(&model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"})
+
+	reqBody := dto.CreateClienteRequest{
+		Nome:  "João Souza",
+		Email: "joao@example.com",
+		CPF:   "15350946056",
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clientes", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var response dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Contains(t, response.Detail, "email")
+}
+
+func TestCreateCliente_MaskedDocuments_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	criar := func(body string) (int, dto.ClienteResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/clientes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var response dto.ClienteResponse
+		json.NewDecoder(rec.Body).Decode(&response)
+		return rec.Code, response
+	}
+
+	code, pf := criar(`{"nome": "João Silva", "email": "joao@example.com", "cpf": "529.982.247-25"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, model.ClientePessoaFisica, pf.Tipo)
+	assert.Equal(t, "52998224725", pf.CPF)
+	assert.Empty(t, pf.CNPJ)
+
+	// clientes PJ não têm CPF, então vários podem coexistir
+	code, pj := criar(`{"tipo": "PJ", "razao_social": "Acme Comércio Ltda", "nome_fantasia": "Acme", "email": "acme@example.com", "cnpj": "11.222.333/0001-81", "inscricao_estadual": "110.042.490.114"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, model.ClientePessoaJuridica, pj.Tipo)
+	assert.Equal(t, "11222333000181", pj.CNPJ)
+	assert.Equal(t, "110042490114", pj.InscricaoEstadual)
+	assert.Equal(t, "Acme Comércio Ltda", pj.RazaoSocial)
+	assert.Empty(t, pj.CPF)
+	assert.Empty(t, pj.Nome)
+
+	code, _ = criar(`{"tipo": "PJ", "razao_social": "Beta S.A.", "email": "beta@example.com", "cnpj": "11444777000161", "inscricao_estadual": "isento"}`)
+	assert.Equal(t, http.StatusCreated, code)
+
+	// o mesmo documento com ou sem máscara é duplicado
+	code, _ = criar(`{"nome": "João Souza", "email": "souza@example.com", "cpf": "52998224725"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = criar(`{"tipo": "PJ", "razao_social": "Acme Filial", "email": "filial@example.com", "cnpj": "11222333000181"}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	var salvo model.Cliente
+	db.First(&salvo, pf.ID)
+	assert.Equal(t, "52998224725", salvo.CPF)
+}
+
+func TestCreateCliente_PessoaJuridicaComCamposDePF_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clientes", strings.NewReader(`{"tipo": "PJ", "nome": "Acme Ltda", "email": "acme@example.com", "cpf": "52998224725", "inscricao_estadual": "ABC"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&response)
+	campos := make([]string, 0, len(response.Errors))
+	for _, e := range response.Errors {
+		campos = append(campos, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"nome", "razao_social", "cpf", "cnpj", "inscricao_estadual"}, campos)
+}
+
+func TestPatchCliente_PessoaFisicaParaJuridica_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	db.Create(&model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725"})
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/clientes/1", strings.NewReader(`{"tipo": "PJ", "nome": null, "razao_social": "João Silva ME", "cpf": null, "cnpj": "11.222.333/0001-81"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var atualizado model.Cliente
+	db.First(&atualizado, 1)
+	assert.Equal(t, model.ClientePessoaJuridica, atualizado.Tipo)
+	assert.Empty(t, atualizado.Nome)
+	assert.Equal(t, "João Silva ME", atualizado.RazaoSocial)
+	assert.Empty(t, atualizado.CPF)
+	assert.Equal(t, "11222333000181", atualizado.CNPJ)
+}
+
+func TestProblemDetails_InstanceIsRequestID_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/clientes/9999", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	var response dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, "/problems/not-found", response.Type)
+	assert.Equal(t, "cliente não encontrado", response.Detail)
+	assert.Equal(t, "req-123", response.Instance)
+}
+
+func TestUnknownRoute_ProblemDetails_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inexistente", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
 }
 
 func TestGetClienteByID_InvalidID_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/clientes/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -259,8 +534,8 @@ func TestGetClienteByID_InvalidID_Integration(t *testing.T) {
 
 func TestGetClienteByID_NotFound_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/clientes/9999", nil)
 	rec := httptest.NewRecorder()
@@ -272,8 +547,8 @@ func TestGetClienteByID_NotFound_Integration(t *testing.T) {
 
 func TestUpdateCliente_InvalidJSON_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/clientes/1", bytes.NewReader([]byte("invalid")))
 	req.Header.Set("Content-Type", "application/json")
@@ -286,8 +561,8 @@ func TestUpdateCliente_InvalidJSON_Integration(t *testing.T) {
 
 func TestUpdateCliente_InvalidID_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.UpdateClienteRequest{Nome: "Test"}
 	body, _ := json.Marshal(reqBody)
@@ -303,10 +578,10 @@ func TestUpdateCliente_InvalidID_Integration(t *testing.T) {
 
 func TestUpdateCliente_NotFound_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
-	reqBody := dto.UpdateClienteRequest{Nome: "Test"}
+	reqBody := dto.UpdateClienteRequest{Nome: "Test", Email: "test@example.com", CPF: "12345678909"}
 	body, _ := json.Marshal(reqBody)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/clientes/9999", bytes.NewReader(body))
@@ -320,8 +595,8 @@ func TestUpdateCliente_NotFound_Integration(t *testing.T) {
 
 func TestDeleteCliente_InvalidID_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/clientes/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -333,8 +608,8 @@ func TestDeleteCliente_InvalidID_Integration(t *testing.T) {
 
 func TestFindByNome_EmptyResults_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/clientes/nome/NãoExiste", nil)
 	rec := httptest.NewRecorder()
@@ -343,15 +618,15 @@ func TestFindByNome_EmptyResults_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ClienteResponse
+	var response dto.PageResponse[dto.ClienteResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 0)
+	assert.Len(t, response.Data, 0)
 }
 
 func TestGetAllClientes_DBError_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -367,8 +642,8 @@ func TestGetAllClientes_DBError_Integration(t *testing.T) {
 
 func TestCount_DBError_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -384,8 +659,8 @@ func TestCount_DBError_Integration(t *testing.T) {
 
 func TestFindByNome_DBError_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -401,8 +676,8 @@ func TestFindByNome_DBError_Integration(t *testing.T) {
 
 func TestDeleteCliente_DBError_Integration(t *testing.T) {
 	db := setupTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
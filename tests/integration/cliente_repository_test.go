@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/model"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -47,9 +48,9 @@ func TestClienteRepository_FindAll(t *testing.T) {
 	db.Create(&model.Cliente{Nome: "Cliente 1", Email: "c1@test.com", CPF: "11111111111"})
 	db.Create(&model.Cliente{Nome: "Cliente 2", Email: "c2@test.com", CPF: "22222222222"})
 
-	clientes, err := repo.FindAll(context.Background())
+	clientes, err := repo.FindAll(context.Background(), repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, clientes, 2)
+	assert.Len(t, clientes.Items, 2)
 }
 
 func TestClienteRepository_FindAll_DBError(t *testing.T) {
@@ -60,7 +61,7 @@ func TestClienteRepository_FindAll_DBError(t *testing.T) {
 	sqlDB, _ := db.DB()
 	sqlDB.Close()
 
-	_, err := repo.FindAll(context.Background())
+	_, err := repo.FindAll(context.Background(), repository.ListOptions{})
 	assert.Error(t, err)
 }
 
@@ -82,7 +83,7 @@ func TestClienteRepository_FindByID_NotFound(t *testing.T) {
 	repo := repository.NewClienteRepositorySQLite(db)
 
 	cliente, err := repo.FindByID(context.Background(), 9999)
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 	assert.Nil(t, cliente)
 }
 
@@ -105,10 +106,10 @@ func TestClienteRepository_FindByName(t *testing.T) {
 	db.Create(&model.Cliente{Nome: "João Silva", Email: "joao@test.com", CPF: "11111111111"})
 	db.Create(&model.Cliente{Nome: "Maria Santos", Email: "maria@test.com", CPF: "22222222222"})
 
-	clientes, err := repo.FindByName(context.Background(), "João")
+	clientes, err := repo.FindByName(context.Background(), "João", repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, clientes, 1)
-	assert.Equal(t, "João Silva", clientes[0].Nome)
+	assert.Len(t, clientes.Items, 1)
+	assert.Equal(t, "João Silva", clientes.Items[0].Nome)
 }
 
 func TestClienteRepository_Update(t *testing.T) {
@@ -148,7 +149,7 @@ func TestClienteRepository_Delete_NotFound(t *testing.T) {
 
 	err := repo.Delete(context.Background(), 9999)
 	assert.Error(t, err)
-	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 }
 
 func TestClienteRepository_Delete_DBError(t *testing.T) {
@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupContatoTestRouter(t *testing.T) (*chi.Mux, *gorm.DB, *model.Cliente, *model.Cliente) {
+	db := setupTestDB(t)
+	router := controller.SetupRouter(setupTestRouter(db), middleware.Anonymous)
+
+	pf := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725"}
+	db.Create(pf)
+	pj := &model.Cliente{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Acme Comércio Ltda", NomeFantasia: "Acme", Email: "contato@acme.com.br", CNPJ: "11222333000181"}
+	db.Create(pj)
+
+	return router, db, pf, pj
+}
+
+func createContato(t *testing.T, router *chi.Mux, clienteID uint, req dto.CreateContatoRequest) dto.ContatoResponse {
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/clientes/%d/contatos", clienteID), req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var contato dto.ContatoResponse
+	json.NewDecoder(rec.Body).Decode(&contato)
+	return contato
+}
+
+func listContatos(t *testing.T, router *chi.Mux, target string) []dto.ContatoResponse {
+	rec := sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var contatos []dto.ContatoResponse
+	json.NewDecoder(rec.Body).Decode(&contatos)
+	return contatos
+}
+
+func TestContato_CRUD_Integration(t *testing.T) {
+	router, _, _, pj := setupContatoTestRouter(t)
+	base := fmt.Sprintf("/api/v1/clientes/%d/contatos", pj.ID)
+
+	ana := createContato(t, router, pj.ID, dto.CreateContatoRequest{Nome: "Ana Souza", Papel: "financeiro", Cargo: "Gerente financeira", Email: "ana@acme.com.br"})
+	bruno := createContato(t, router, pj.ID, dto.CreateContatoRequest{Nome: "Bruno Lima", Papel: "compras", Telefone: "11987654321"})
+	createContato(t, router, pj.ID, dto.CreateContatoRequest{Nome: "Carla Dias", Papel: "financeiro", Email: "carla@acme.com.br"})
+	assert.Equal(t, pj.ID, ana.ClienteID)
+
+	assert.Len(t, listContatos(t, router, base), 3)
+	financeiro := listContatos(t, router, base+"?papel=financeiro")
+	if assert.Len(t, financeiro, 2) {
+		assert.Equal(t, ana.ID, financeiro[0].ID)
+	}
+
+	// PUT substitui o contato inteiro; o email omitido é limpo
+	rec := sendWithHeaders(router, http.MethodPut, fmt.Sprintf("%s/%d", base, bruno.ID), dto.UpdateContatoRequest{
+		Nome: "Bruno Lima", Papel: "logistica", Telefone: "11987654321",
+	}, map[string]string{"If-Match": etag.Format(bruno.Versao)})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var atualizado dto.ContatoResponse
+	json.NewDecoder(rec.Body).Decode(&atualizado)
+	assert.Equal(t, "logistica", atualizado.Papel)
+	assert.Equal(t, uint(2), atualizado.Versao)
+
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("%s/%d", base, bruno.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, etag.Format(2), rec.Header().Get("ETag"))
+
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("%s/%d", base, ana.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, listContatos(t, router, base), 2)
+}
+
+func TestContato_ClientePessoaFisica_Integration(t *testing.T) {
+	router, _, pf, _ := setupContatoTestRouter(t)
+
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/clientes/%d/contatos", pf.ID),
+		dto.CreateContatoRequest{Nome: "Ana Souza", Papel: "financeiro", Email: "ana@example.com"}, nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestContato_Validation_Integration(t *testing.T) {
+	router, _, _, pj := setupContatoTestRouter(t)
+	base := fmt.Sprintf("/api/v1/clientes/%d/contatos", pj.ID)
+
+	// sem email nem telefone
+	rec := sendWithHeaders(router, http.MethodPost, base, dto.CreateContatoRequest{Nome: "Ana Souza", Papel: "financeiro"}, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	assert.Equal(t, []dto.FieldError{
+		{Field: "email", Rule: "required_without", Message: "é obrigatório quando telefone não é informado"},
+	}, problem.Errors)
+
+	rec = sendWithHeaders(router, http.MethodGet, base+"?papel=diretoria", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestContato_OutroCliente_Integration(t *testing.T) {
+	router, db, _, pj := setupContatoTestRouter(t)
+	outra := &model.Cliente{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Beta S.A.", Email: "beta@example.com", CNPJ: "11444777000161"}
+	db.Create(outra)
+
+	contato := createContato(t, router, pj.ID, dto.CreateContatoRequest{Nome: "Ana Souza", Papel: "financeiro", Email: "ana@acme.com.br"})
+
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/clientes/%d/contatos/%d", outra.ID, contato.ID), nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/clientes/9999/contatos", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFindByNome_RazaoSocialENomeFantasia_Integration(t *testing.T) {
+	router, _, _, _ := setupContatoTestRouter(t)
+
+	buscar := func(nome string) []dto.ClienteResponse {
+		rec := sendWithHeaders(router, http.MethodGet, "/api/v1/clientes/nome/"+nome, nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page dto.ClientePageResponse
+		json.NewDecoder(rec.Body).Decode(&page)
+		return page.Data
+	}
+
+	if resultado := buscar("Comércio"); assert.Len(t, resultado, 1) {
+		assert.Equal(t, "Acme Comércio Ltda", resultado[0].RazaoSocial)
+		assert.Equal(t, model.ClientePessoaJuridica, resultado[0].Tipo)
+		assert.Empty(t, resultado[0].Nome)
+	}
+	assert.Len(t, buscar("Acme"), 1)
+	if resultado := buscar("João"); assert.Len(t, resultado, 1) {
+		assert.Equal(t, "João Silva", resultado[0].Nome)
+		assert.Empty(t, resultado[0].RazaoSocial)
+	}
+}
@@ -0,0 +1,315 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupCupomTestRouter prepara um cliente e dois produtos de categorias
+// diferentes para os pedidos com cupom
+func setupCupomTestRouter(t *testing.T) (*chi.Mux, *gorm.DB, *model.Cliente, *model.Produto, *model.Produto) {
+	db := setupPedidoTestDB(t)
+	router := controller.SetupRouter(setupPedidoTestRouter(db), middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+	notebook := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2000.00"), Estoque: 10, Categoria: "Eletrônicos", Ativo: true}
+	livro := &model.Produto{Nome: "Livro", SKU: "LV-001", Preco: money.MustParse("50.00"), Estoque: 10, Categoria: "Livros", Ativo: true}
+	db.Create(notebook)
+	db.Create(livro)
+
+	return router, db, cliente, notebook, livro
+}
+
+func createCupom(t *testing.T, router *chi.Mux, req dto.CreateCupomRequest) dto.CupomResponse {
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/cupons", req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var cupom dto.CupomResponse
+	json.NewDecoder(rec.Body).Decode(&cupom)
+	return cupom
+}
+
+func TestCupom_CRUD_Integration(t *testing.T) {
+	router, _, _, _, _ := setupCupomTestRouter(t)
+
+	cupom := createCupom(t, router, dto.CreateCupomRequest{
+		Codigo:     "bemvindo10",
+		Tipo:       model.CupomPercentual,
+		Percentual: 10,
+		Categorias: []string{"Livros"},
+	})
+	assert.Equal(t, "BEMVINDO10", cupom.Codigo)
+	assert.True(t, cupom.Ativo)
+	assert.Equal(t, 0, cupom.Usos)
+
+	// código repetido, mesmo com outra grafia
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/cupons", dto.CreateCupomRequest{Codigo: "BemVindo10", Tipo: model.CupomFreteGratis}, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	ativo := false
+	rec = sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/cupons/%d", cupom.ID), dto.UpdateCupomRequest{
+		Codigo: "BEMVINDO10",
+		Tipo:   model.CupomValorFixo,
+		Valor:  money.MustParse("15.00"),
+		Ativo:  &ativo,
+	}, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var atualizado dto.CupomResponse
+	json.NewDecoder(rec.Body).Decode(&atualizado)
+	assert.Equal(t, money.MustParse("15.00"), atualizado.Valor)
+	assert.Equal(t, 0, atualizado.Percentual)
+	assert.Equal(t, []string{}, atualizado.Categorias)
+	assert.False(t, atualizado.Ativo)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/cupons?filter[tipo]=valor_fixo", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page dto.CupomPageResponse
+	json.NewDecoder(rec.Body).Decode(&page)
+	assert.Len(t, page.Data, 1)
+
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/cupons/%d", cupom.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/cupons/%d", cupom.ID), nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCupom_ValidationError_Integration(t *testing.T) {
+	router, _, _, _, _ := setupCupomTestRouter(t)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/cupons", dto.CreateCupomRequest{Codigo: "DESC", Tipo: model.CupomPercentual}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if assert.Len(t, problem.Errors, 1) {
+		assert.Equal(t, "percentual", problem.Errors[0].Field)
+	}
+}
+
+func TestCreatePedido_ComCupom_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+
+	createCupom(t, router, dto.CreateCupomRequest{
+		Codigo:     "LIVROS20",
+		Tipo:       model.CupomPercentual,
+		Percentual: 20,
+		Categorias: []string{"Livros"},
+	})
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Cupom:     "livros20",
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: notebook.ID, Quantidade: 1},
+			{ProdutoID: livro.ID, Quantidade: 3},
+		},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var raw map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&raw)
+	assert.Equal(t, "2150.00", raw["subtotal"])
+	assert.Equal(t, "30.00", raw["desconto"])
+	assert.Equal(t, "2120.00", raw["valor_total"])
+	descontos := raw["descontos"].([]interface{})
+	if assert.Len(t, descontos, 1) {
+		linha := descontos[0].(map[string]interface{})
+		assert.Equal(t, "LIVROS20", linha["codigo"])
+		assert.Equal(t, "150.00", linha["base"])
+		assert.Equal(t, "30.00", linha["valor"])
+	}
+
+	// o uso fica registrado no cupom
+	var cupom model.Cupom
+	db.Where("codigo = ?", "LIVROS20").First(&cupom)
+	assert.Equal(t, 1, cupom.Usos)
+}
+
+func TestCreatePedido_CupomRecusado_Integration(t *testing.T) {
+	router, db, cliente, notebook, _ := setupCupomTestRouter(t)
+
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "LIVROS20", Tipo: model.CupomPercentual, Percentual: 20, Categorias: []string{"Livros"}})
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "MINIMO", Tipo: model.CupomValorFixo, Valor: money.MustParse("100.00"), ValorMinimo: money.MustParse("5000.00")})
+
+	casos := []struct {
+		cupom  string
+		detail string
+	}{
+		{"NAOEXISTE", "cupom NAOEXISTE não encontrado"},
+		{"LIVROS20", "cupom LIVROS20 não se aplica aos produtos do pedido"},
+		{"MINIMO", "cupom MINIMO exige pedido mínimo de 5000.00"},
+	}
+	for _, tt := range casos {
+		t.Run(tt.cupom, func(t *testing.T) {
+			rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+				ClienteID: cliente.ID,
+				Cupom:     tt.cupom,
+				Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}},
+			}, nil)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			var problem dto.ProblemDetails
+			json.NewDecoder(rec.Body).Decode(&problem)
+			assert.Equal(t, tt.detail, problem.Detail)
+		})
+	}
+
+	// o pedido recusado não baixa estoque nem consome o cupom
+	var produto model.Produto
+	db.First(&produto, notebook.ID)
+	assert.Equal(t, 10, produto.Estoque)
+	var cupons []model.Cupom
+	db.Find(&cupons)
+	for _, c := range cupons {
+		assert.Equal(t, 0, c.Usos)
+	}
+}
+
+func TestCreatePedido_CupomLimites_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+
+	outro := &model.Cliente{Nome: "Maria Souza", Email: "maria@example.com", CPF: "98765432100"}
+	db.Create(outro)
+
+	createCupom(t, router, dto.CreateCupomRequest{
+		Codigo:           "UNICO",
+		Tipo:             model.CupomValorFixo,
+		Valor:            money.MustParse("10.00"),
+		LimiteUso:        2,
+		LimitePorCliente: 1,
+	})
+
+	// pedir retorna o id do pedido criado ou o detalhe da recusa
+	pedir := func(clienteID uint) (uint, string) {
+		rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+			ClienteID: clienteID,
+			Cupom:     "UNICO",
+			Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livro.ID, Quantidade: 1}},
+		}, nil)
+		if rec.Code != http.StatusCreated {
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			var problem dto.ProblemDetails
+			json.NewDecoder(rec.Body).Decode(&problem)
+			return 0, problem.Detail
+		}
+		var pedido dto.PedidoResponse
+		json.NewDecoder(rec.Body).Decode(&pedido)
+		return pedido.ID, ""
+	}
+
+	primeiro, _ := pedir(cliente.ID)
+	assert.NotZero(t, primeiro)
+	_, detail := pedir(cliente.ID)
+	assert.Equal(t, "cupom UNICO já utilizado o máximo de vezes por este cliente", detail)
+
+	// o cancelamento devolve o uso ao cliente
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", primeiro), dto.CancelamentoRequest{Motivo: "Cliente desistiu"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	id, _ := pedir(cliente.ID)
+	assert.NotZero(t, id)
+
+	// o limite global é de dois usos ativos
+	id, _ = pedir(outro.ID)
+	assert.NotZero(t, id)
+	terceiro := &model.Cliente{Nome: "Ana Lima", Email: "ana@example.com", CPF: "11122233344"}
+	db.Create(terceiro)
+	_, detail = pedir(terceiro.ID)
+	assert.Equal(t, "cupom UNICO esgotado", detail)
+
+	var cupom model.Cupom
+	db.Where("codigo = ?", "UNICO").First(&cupom)
+	assert.Equal(t, 2, cupom.Usos)
+}
+
+func TestCupomRepository_IncrementUsos_LimitePorCliente_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	repo := repository.NewCupomRepositorySQLite(db)
+
+	cupom := createCupom(t, router, dto.CreateCupomRequest{
+		Codigo:           "UNICO",
+		Tipo:             model.CupomValorFixo,
+		Valor:            money.MustParse("10.00"),
+		LimitePorCliente: 1,
+	})
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Cupom:     "UNICO",
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livro.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// um pedido concorrente que contou os usos antes desse ser gravado ainda
+	// é barrado no registro do uso
+	err := repo.IncrementUsos(context.Background(), cupom.ID, cliente.ID)
+	assert.ErrorIs(t, err, repository.ErrCupomLimiteCliente)
+
+	outro := &model.Cliente{Nome: "Maria Souza", Email: "maria@example.com", CPF: "98765432100"}
+	db.Create(outro)
+	assert.NoError(t, repo.IncrementUsos(context.Background(), cupom.ID, outro.ID))
+
+	var atual model.Cupom
+	db.First(&atual, cupom.ID)
+	assert.Equal(t, 2, atual.Usos)
+}
+
+func TestCupom_OperadorNaoGerencia_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/cupons", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: model.CupomFreteGratis}, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/cupons", nil, operador)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestListCupons_SortedCursorPaginationWithNulls_Integration(t *testing.T) {
+	router, _, _, _, _ := setupCupomTestRouter(t)
+
+	fim := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cedo := fim.AddDate(-1, 0, 0)
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "SEMFIM1", Tipo: model.CupomFreteGratis})
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "TARDE", Tipo: model.CupomFreteGratis, ValidoAte: &fim})
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "SEMFIM2", Tipo: model.CupomFreteGratis})
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "CEDO", Tipo: model.CupomFreteGratis, ValidoAte: &cedo})
+
+	listar := func(sort string) []string {
+		var vistos []string
+		path := "/api/v1/cupons?limit=1&sort=" + sort
+		for i := 0; i < 6; i++ {
+			rec := sendWithHeaders(router, http.MethodGet, path, nil, nil)
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var page dto.CupomPageResponse
+			json.NewDecoder(rec.Body).Decode(&page)
+			for _, c := range page.Data {
+				vistos = append(vistos, c.Codigo)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			path = "/api/v1/cupons?limit=1&sort=" + sort + "&cursor=" + page.NextCursor
+		}
+		return vistos
+	}
+
+	// sem validade (NULL) vem antes na ordem crescente e depois na decrescente
+	assert.Equal(t, []string{"SEMFIM1", "SEMFIM2", "CEDO", "TARDE"}, listar("valido_ate"))
+	assert.Equal(t, []string{"TARDE", "CEDO", "SEMFIM1", "SEMFIM2"}, listar("-valido_ate"))
+}
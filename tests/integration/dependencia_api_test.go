@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCliente_DeleteComPedidos_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	db.Create(&model.Endereco{ClienteID: cliente.ID, CEP: "01310100", Logradouro: "Avenida Paulista", Numero: "1000", Bairro: "Bela Vista", Cidade: "São Paulo", UF: "SP", Padrao: true})
+	target := fmt.Sprintf("/api/v1/clientes/%d", cliente.ID)
+
+	for _, query := range []string{"", "?purge=true"} {
+		rec := sendWithHeaders(router, http.MethodDelete, target+query, nil, nil)
+		assert.Equal(t, http.StatusConflict, rec.Code, query)
+		var problem dto.ProblemDetails
+		json.NewDecoder(rec.Body).Decode(&problem)
+		assert.Equal(t, map[string]int64{"pedidos": 1}, problem.Dependents)
+		assert.Contains(t, problem.Detail, "cascade=deactivate")
+	}
+
+	rec := sendWithHeaders(router, http.MethodDelete, target+"?cascade=delete", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?cascade=deactivate&purge=true", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?cascade=deactivate", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	// o cliente continua existindo, sem os dados pessoais
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var anonimizado dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&anonimizado)
+	assert.Equal(t, model.ClienteAnonimizado, anonimizado.Nome)
+	assert.Equal(t, fmt.Sprintf("anonimizado-%d@anonimizado.invalid", cliente.ID), anonimizado.Email)
+	assert.Empty(t, anonimizado.CPF)
+	assert.Equal(t, uint(2), anonimizado.Versao)
+
+	var enderecos int64
+	db.Unscoped().Model(&model.Endereco{}).Where("cliente_id = ?", cliente.ID).Count(&enderecos)
+	assert.Equal(t, int64(0), enderecos)
+
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCliente_DeactivateSemPedidos_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	cliente := createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"})
+	target := fmt.Sprintf("/api/v1/clientes/%d", cliente.ID)
+
+	// sem pedidos, cascade=deactivate exclui normalmente
+	rec := sendWithHeaders(router, http.MethodDelete, target+"?cascade=deactivate", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestProduto_DeleteComPedidos_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+	createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPedidoLivros(t, router, cliente.ID, livro.ID)
+	target := fmt.Sprintf("/api/v1/produtos/%d", livro.ID)
+
+	rec := sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	assert.Equal(t, map[string]int64{"pedidos": 2}, problem.Dependents)
+
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?cascade=deactivate", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var desativado dto.ProdutoResponse
+	json.NewDecoder(rec.Body).Decode(&desativado)
+	assert.False(t, desativado.Ativo)
+
+	// o produto desativado não entra em novos pedidos
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livro.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// o produto sem pedidos é excluído
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/produtos/%d?cascade=deactivate", notebook.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	var count int64
+	db.Model(&model.Produto{}).Where("id = ?", notebook.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestPurge_ComPedidosExcluidos_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	rec := sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	// o pedido excluído ainda referencia o cliente e o produto no banco: a
+	// exclusão comum passa, a definitiva responde 409 em vez de violar a chave estrangeira
+	for _, target := range []string{fmt.Sprintf("/api/v1/clientes/%d", cliente.ID), fmt.Sprintf("/api/v1/produtos/%d", livro.ID)} {
+		rec = sendWithHeaders(router, http.MethodDelete, target+"?purge=true", nil, nil)
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+		var problem dto.ProblemDetails
+		json.NewDecoder(rec.Body).Decode(&problem)
+		assert.Equal(t, map[string]int64{"pedidos": 1}, problem.Dependents)
+
+		rec = sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	var count int64
+	db.Unscoped().Model(&model.Cliente{}).Where("id = ?", cliente.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelamento_EstornaPagamentos_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	// cartão é confirmado na hora e quita o pedido
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+	assert.Equal(t, model.PagamentoConfirmado, pag.Status)
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+
+	// o motivo é obrigatório
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{}, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Cliente desistiu"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+	var cancelado dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&cancelado)
+	assert.Equal(t, model.StatusCancelado, cancelado.Status)
+	assert.Equal(t, "Cliente desistiu", cancelado.MotivoCancelamento)
+
+	// o estoque volta e o pagamento é estornado por inteiro
+	var produto model.Produto
+	db.First(&produto, livro.ID)
+	assert.Equal(t, 10, produto.Estoque)
+
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.Pago)
+	if assert.Len(t, pagamentos.Pagamentos, 1) {
+		assert.Equal(t, model.PagamentoEstornado, pagamentos.Pagamentos[0].Status)
+		if assert.Len(t, pagamentos.Pagamentos[0].Estornos, 1) {
+			assert.Equal(t, "Cliente desistiu", pagamentos.Pagamentos[0].Estornos[0].Motivo)
+		}
+	}
+
+	// cancelar de novo é uma transição inválida
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Outra vez"}, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestCancelamento_PeloStatus_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	// o PUT de status não cancela: o cancelamento precisa do motivo
+	rec := sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), dto.UpdatePedidoRequest{Status: model.StatusCancelado}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, model.StatusPendente, findPedido(t, router, pedido.ID).Status)
+}
+
+func TestCancelamento_PagamentoConfirmadoDepois_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	pix := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix})
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Cliente desistiu"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// o pix pago depois do cancelamento é devolvido assim que confirmado
+	rec = notificar(router, pix.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	assert.Equal(t, model.StatusCancelado, findPedido(t, router, pedido.ID).Status)
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.Pago)
+	if assert.Len(t, pagamentos.Pagamentos, 1) {
+		assert.Equal(t, model.PagamentoEstornado, pagamentos.Pagamentos[0].Status)
+		assert.Equal(t, "gateway:fake", pagamentos.Pagamentos[0].Estornos[0].Usuario)
+	}
+}
+
+func TestDevolucao_Parcial_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "DEZ", Tipo: model.CupomValorFixo, Valor: money.MustParse("10.00")})
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Cupom:     "DEZ",
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livro.ID, Quantidade: 4}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	assert.Equal(t, money.MustParse("190.00"), pedido.ValorTotal)
+	itemID := pedido.Itens[0].ID
+
+	// pedidos pendentes não aceitam devolução
+	devolver := func(quantidade int) *dto.DevolucaoResponse {
+		rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/devolucoes", pedido.ID), dto.CreateDevolucaoRequest{
+			Motivo: "Chegou com defeito",
+			Itens:  []dto.DevolucaoItemRequest{{ItemID: itemID, Quantidade: quantidade}},
+		}, nil)
+		if rec.Code != http.StatusCreated {
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+			return nil
+		}
+		var devolucao dto.DevolucaoResponse
+		json.NewDecoder(rec.Body).Decode(&devolucao)
+		return &devolucao
+	}
+	assert.Nil(t, devolver(1))
+
+	createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	// 190,00 por 4 unidades: cada uma devolve 47,50, já sem o desconto do cupom
+	primeira := devolver(1)
+	if assert.NotNil(t, primeira) {
+		assert.Equal(t, money.MustParse("47.50"), primeira.Valor)
+		assert.Equal(t, money.MustParse("47.50"), primeira.Reembolsado)
+		assert.Equal(t, "anonimo", primeira.Usuario)
+	}
+
+	// não há 4 unidades restantes
+	assert.Nil(t, devolver(4))
+
+	segunda := devolver(3)
+	if assert.NotNil(t, segunda) {
+		assert.Equal(t, money.MustParse("142.50"), segunda.Valor)
+	}
+	assert.Nil(t, devolver(1))
+
+	var produto model.Produto
+	db.First(&produto, livro.ID)
+	assert.Equal(t, 10, produto.Estoque)
+
+	// as linhas originais são mantidas e as devoluções ficam no pedido
+	atual := findPedido(t, router, pedido.ID)
+	assert.Equal(t, model.StatusPago, atual.Status)
+	assert.Equal(t, 4, atual.Itens[0].Quantidade)
+	assert.Equal(t, 4, atual.Itens[0].QuantidadeDevolvida)
+	assert.Equal(t, money.MustParse("10.00"), atual.Itens[0].Desconto)
+	assert.Len(t, atual.Devolucoes, 2)
+
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.Pago)
+
+	// cancelar depois da devolução total não devolve o estoque de novo
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Tudo devolvido"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	db.First(&produto, livro.ID)
+	assert.Equal(t, 10, produto.Estoque)
+}
+
+func TestDevolucao_ItemDeOutroPedido_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	outro := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/devolucoes", pedido.ID), dto.CreateDevolucaoRequest{
+		Itens: []dto.DevolucaoItemRequest{{ItemID: outro.Itens[0].ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/devolucoes", pedido.ID), dto.CreateDevolucaoRequest{}, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDevolucao_AvancaVersao_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+	target := fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID)
+
+	rec := sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	antiga := rec.Header().Get("ETag")
+	var pago dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pago)
+
+	req := dto.CreateDevolucaoRequest{Itens: []dto.DevolucaoItemRequest{{ItemID: pago.Itens[0].ID, Quantidade: 1}}}
+
+	// uma devolução baseada em outra versão do pedido é recusada
+	rec = sendWithHeaders(router, http.MethodPost, target+"/devolucoes", req, map[string]string{"If-Match": `"99"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, target+"/devolucoes", req, map[string]string{"If-Match": antiga})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// o pedido mudou: o ETag antigo não vale mais nem para leitura nem para outra devolução
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, map[string]string{"If-None-Match": antiga})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, antiga, rec.Header().Get("ETag"))
+	var atual dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&atual)
+	assert.Len(t, atual.Devolucoes, 1)
+	assert.Equal(t, pago.Versao+1, atual.Versao)
+
+	rec = sendWithHeaders(router, http.MethodPost, target+"/devolucoes", req, map[string]string{"If-Match": antiga})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
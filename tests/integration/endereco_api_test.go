@@ -0,0 +1,196 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func createEndereco(t *testing.T, router *chi.Mux, clienteID uint, req dto.CreateEnderecoRequest) dto.EnderecoResponse {
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/clientes/%d/enderecos", clienteID), req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var endereco dto.EnderecoResponse
+	json.NewDecoder(rec.Body).Decode(&endereco)
+	return endereco
+}
+
+func listEnderecos(t *testing.T, router *chi.Mux, clienteID uint) []dto.EnderecoResponse {
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/clientes/%d/enderecos", clienteID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var enderecos []dto.EnderecoResponse
+	json.NewDecoder(rec.Body).Decode(&enderecos)
+	return enderecos
+}
+
+var (
+	enderecoCasa = dto.CreateEnderecoRequest{
+		Apelido: "Casa", CEP: "01310-100", Logradouro: "Avenida Paulista", Numero: "1000",
+		Complemento: "Apto 42", Bairro: "Bela Vista", Cidade: "São Paulo", UF: "sp",
+	}
+	enderecoTrabalho = dto.CreateEnderecoRequest{
+		Apelido: "Trabalho", CEP: "20040002", Logradouro: "Rua da Assembleia", Numero: "10",
+		Bairro: "Centro", Cidade: "Rio de Janeiro", UF: "RJ",
+	}
+)
+
+func TestEndereco_CRUD_Integration(t *testing.T) {
+	router, db, cliente, _, _ := setupCupomTestRouter(t)
+
+	// o primeiro endereço vira o padrão
+	casa := createEndereco(t, router, cliente.ID, enderecoCasa)
+	assert.True(t, casa.Padrao)
+	assert.Equal(t, "01310100", casa.CEP)
+	assert.Equal(t, "SP", casa.UF)
+
+	trabalho := createEndereco(t, router, cliente.ID, enderecoTrabalho)
+	assert.False(t, trabalho.Padrao)
+
+	// marcar outro como padrão desmarca o anterior
+	padrao := true
+	rec := sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", cliente.ID, trabalho.ID), dto.UpdateEnderecoRequest{
+		Apelido: "Trabalho", CEP: "20040-002", Logradouro: "Rua da Assembleia", Numero: "12",
+		Bairro: "Centro", Cidade: "Rio de Janeiro", UF: "RJ", Padrao: &padrao,
+	}, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	enderecos := listEnderecos(t, router, cliente.ID)
+	if assert.Len(t, enderecos, 2) {
+		assert.Equal(t, trabalho.ID, enderecos[0].ID)
+		assert.True(t, enderecos[0].Padrao)
+		assert.Equal(t, "12", enderecos[0].Numero)
+		assert.False(t, enderecos[1].Padrao)
+		// desmarcar o padrão também muda a versão
+		assert.Equal(t, uint(2), enderecos[1].Versao)
+	}
+
+	// excluir o padrão promove o que restou
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", cliente.ID, trabalho.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", cliente.ID, casa.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var atual dto.EnderecoResponse
+	json.NewDecoder(rec.Body).Decode(&atual)
+	assert.True(t, atual.Padrao)
+
+	// o endereço só é encontrado pelo próprio cliente
+	outro := &model.Cliente{Nome: "Maria Souza", Email: "maria@example.com", CPF: "10987654321"}
+	db.Create(outro)
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", outro.ID, casa.ID), nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, listEnderecos(t, router, outro.ID))
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/clientes/999/enderecos", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEndereco_PadraoObrigatorio_Integration(t *testing.T) {
+	router, _, cliente, _, _ := setupCupomTestRouter(t)
+	casa := createEndereco(t, router, cliente.ID, enderecoCasa)
+
+	padrao := false
+	rec := sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", cliente.ID, casa.ID), dto.UpdateEnderecoRequest{
+		CEP: "01310100", Logradouro: "Avenida Paulista", Numero: "1000",
+		Bairro: "Bela Vista", Cidade: "São Paulo", UF: "SP", Padrao: &padrao,
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestEndereco_ValidationError_Integration(t *testing.T) {
+	router, _, cliente, _, _ := setupCupomTestRouter(t)
+
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/clientes/%d/enderecos", cliente.ID), map[string]interface{}{
+		"cep": "0131-0100", "logradouro": "Avenida Paulista", "bairro": "Bela Vista", "cidade": "São Paulo", "uf": "SP",
+	}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "cep", problem.Errors[0].Field)
+		assert.Equal(t, "deve ser um CEP no formato 00000-000", problem.Errors[0].Message)
+		assert.Equal(t, "numero", problem.Errors[1].Field)
+	}
+}
+
+func TestCreatePedido_EnderecoEntrega_Integration(t *testing.T) {
+	router, db, cliente, notebook, _ := setupCupomTestRouter(t)
+	createFreteZona(t, router, zonaBrasil)
+	casa := createEndereco(t, router, cliente.ID, enderecoCasa)
+	trabalho := createEndereco(t, router, cliente.ID, enderecoTrabalho)
+
+	// sem endereco_id vale o endereço padrão
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	assert.Equal(t, &casa.ID, pedido.EnderecoID)
+	assert.Equal(t, "SP", pedido.UFDestino)
+
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID:  cliente.ID,
+		EnderecoID: &trabalho.ID,
+		Itens:      []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	assert.Equal(t, "RJ", pedido.UFDestino)
+
+	// editar o endereço depois não reescreve o pedido
+	padrao := false
+	rec = sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/clientes/%d/enderecos/%d", cliente.ID, trabalho.ID), dto.UpdateEnderecoRequest{
+		CEP: "22250-040", Logradouro: "Praia de Botafogo", Numero: "300",
+		Bairro: "Botafogo", Cidade: "Rio de Janeiro", UF: "RJ", Padrao: &padrao,
+	}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var lido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&lido)
+	if assert.NotNil(t, lido.Entrega) {
+		assert.Equal(t, "20040002", lido.Entrega.CEP)
+		assert.Equal(t, "Rua da Assembleia", lido.Entrega.Logradouro)
+		assert.Equal(t, "10", lido.Entrega.Numero)
+		assert.Equal(t, "RJ", lido.Entrega.UF)
+	}
+
+	// endereço de outro cliente e UF divergente são recusados
+	outro := &model.Cliente{Nome: "Maria Souza", Email: "maria@example.com", CPF: "10987654321"}
+	db.Create(outro)
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID:  outro.ID,
+		EnderecoID: &casa.ID,
+		Itens:      []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID:  cliente.ID,
+		EnderecoID: &casa.ID,
+		UFDestino:  "RJ",
+		Itens:      []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestEndereco_LeituraNaoCadastra_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	leitura := map[string]string{"Authorization": issue(auth.RoleLeitura)}
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/clientes/1/enderecos", enderecoCasa, leitura)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
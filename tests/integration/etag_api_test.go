@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func sendWithHeaders(router *chi.Mux, method, target string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func sendMergePatch(router *chi.Mux, target, patch string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(patch))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetProdutoByID_ETag_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 10}
+	db.Create(produto)
+
+	rec := sendWithHeaders(router, http.MethodGet, "/api/v1/produtos/1", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	var response dto.ProdutoResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, uint(1), response.Versao)
+
+	// leitura condicional com a versão em cache
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/produtos/1", nil, map[string]string{"If-None-Match": `"1"`})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	// depois de uma alteração a cópia em cache deixa de valer
+	rec = sendMergePatch(router, "/api/v1/produtos/1", `{"nome": "Notebook Pro"}`, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/produtos/1", nil, map[string]string{"If-None-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+}
+
+func TestPatchProduto_IfMatch_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 10}
+	db.Create(produto)
+
+	// dois administradores leem a versão "1"; o primeiro grava
+	rec := sendMergePatch(router, "/api/v1/produtos/1", `{"estoque": 7}`, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// o segundo recebe 412 em vez de sobrescrever o estoque
+	rec = sendMergePatch(router, "/api/v1/produtos/1", `{"estoque": 3}`, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	var atual model.Produto
+	db.First(&atual, produto.ID)
+	assert.Equal(t, 7, atual.Estoque)
+	assert.Equal(t, uint(2), atual.Versao)
+}
+
+func TestDeleteCliente_IfMatch_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	controllers := setupTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	rec := sendWithHeaders(router, http.MethodDelete, "/api/v1/clientes/1", nil, map[string]string{"If-Match": `"7"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	// If-Match exige a comparação forte; a ETag fraca da versão atual não satisfaz
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/clientes/1", nil, map[string]string{"If-Match": `W/"1"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/clientes/1", nil, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestCreatePedido_BumpsProdutoVersao_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 10, Ativo: true}
+	db.Create(produto)
+
+	req := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: produto.ID, Quantidade: 2}},
+	}
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// a baixa de estoque invalida a ETag lida antes do pedido
+	rec = sendMergePatch(router, "/api/v1/produtos/1", `{"estoque": 20}`, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
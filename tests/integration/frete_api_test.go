@@ -0,0 +1,228 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func createFreteZona(t *testing.T, router *chi.Mux, req dto.CreateFreteZonaRequest) dto.FreteZonaResponse {
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/frete/zonas", req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var zona dto.FreteZonaResponse
+	json.NewDecoder(rec.Body).Decode(&zona)
+	return zona
+}
+
+var (
+	// zonaBrasil atende qualquer CEP até 30 kg
+	zonaBrasil = dto.CreateFreteZonaRequest{
+		Nome: "Brasil", Servico: "Econômico", CEPInicio: "00000-000", CEPFim: "99999-999", PrazoDias: 8,
+		Faixas: []dto.FreteFaixaRequest{
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("19.90")},
+			{PesoMaximoGramas: 30000, Valor: money.MustParse("59.90")},
+		},
+	}
+	// zonaCapitalSP atende a capital paulista até 10 kg
+	zonaCapitalSP = dto.CreateFreteZonaRequest{
+		Nome: "Capital SP", Servico: "Expresso", CEPInicio: "01000-000", CEPFim: "05999-999", PrazoDias: 1,
+		Faixas: []dto.FreteFaixaRequest{
+			{PesoMaximoGramas: 10000, Valor: money.MustParse("29.90")},
+		},
+	}
+)
+
+func TestFreteZona_CRUD_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	router := controller.SetupRouter(setupPedidoTestRouter(db), middleware.Anonymous)
+
+	zona := createFreteZona(t, router, zonaCapitalSP)
+	assert.Equal(t, "01000000", zona.CEPInicio)
+	assert.Equal(t, "05999999", zona.CEPFim)
+	assert.True(t, zona.Ativo)
+	assert.Len(t, zona.Faixas, 1)
+
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/frete/zonas/%d", zona.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	// o PUT substitui as faixas de peso
+	ativo := false
+	rec = sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/frete/zonas/%d", zona.ID), dto.UpdateFreteZonaRequest{
+		Nome: "Capital SP", Servico: "Expresso", CEPInicio: "01000000", CEPFim: "05999999", PrazoDias: 2,
+		Faixas: []dto.FreteFaixaRequest{
+			{PesoMaximoGramas: 5000, Valor: money.MustParse("24.90")},
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("14.90")},
+		},
+		Ativo: &ativo,
+	}, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/frete/zonas?filter[ativo]=false", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page dto.FreteZonaPageResponse
+	json.NewDecoder(rec.Body).Decode(&page)
+	if assert.Len(t, page.Data, 1) {
+		assert.Equal(t, 2, page.Data[0].PrazoDias)
+		assert.Equal(t, []dto.FreteFaixaResponse{
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("14.90")},
+			{PesoMaximoGramas: 5000, Valor: money.MustParse("24.90")},
+		}, page.Data[0].Faixas)
+	}
+	var faixas int64
+	db.Model(&model.FreteFaixa{}).Count(&faixas)
+	assert.Equal(t, int64(2), faixas)
+
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/frete/zonas/%d", zona.ID), nil, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/frete/zonas/%d", zona.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/frete/zonas/%d", zona.ID), nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFreteZona_ValidationError_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	router := controller.SetupRouter(setupPedidoTestRouter(db), middleware.Anonymous)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/frete/zonas", map[string]interface{}{
+		"nome": "Zona", "servico": "PAC", "cep_inicio": "0100", "cep_fim": "05999-999", "faixas": []interface{}{},
+	}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "cep_inicio", problem.Errors[0].Field)
+		assert.Equal(t, "deve ser um CEP no formato 00000-000", problem.Errors[0].Message)
+		assert.Equal(t, "faixas", problem.Errors[1].Field)
+	}
+
+	// faixa de CEPs invertida
+	req := zonaCapitalSP
+	req.CEPInicio, req.CEPFim = req.CEPFim, req.CEPInicio
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/frete/zonas", req, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCotacaoFrete_Integration(t *testing.T) {
+	router, db, _, notebook, livro := setupCupomTestRouter(t)
+	createFreteZona(t, router, zonaBrasil)
+	createFreteZona(t, router, zonaCapitalSP)
+
+	// 2,5 kg reais; 40 x 30 x 10 cm = 2 kg cubados
+	db.Model(notebook).Updates(map[string]interface{}{"peso_gramas": 2500, "altura_cm": 10, "largura_cm": 30, "comprimento_cm": 40})
+	// 300 g reais; 30 x 20 x 5 cm = 500 g cubados
+	db.Model(livro).Updates(map[string]interface{}{"peso_gramas": 300, "altura_cm": 5, "largura_cm": 20, "comprimento_cm": 30})
+
+	cotar := func(cep string, itens ...dto.CreateItemPedidoRequest) dto.CotacaoFreteResponse {
+		rec := sendWithHeaders(router, http.MethodPost, "/api/v1/frete/cotacao", dto.CotacaoFreteRequest{CEP: cep, Itens: itens}, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var cotacao dto.CotacaoFreteResponse
+		json.NewDecoder(rec.Body).Decode(&cotacao)
+		return cotacao
+	}
+
+	// na capital paulista há as duas opções, da mais barata para a mais cara
+	cotacao := cotar("01310-100", dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, dto.CreateItemPedidoRequest{ProdutoID: livro.ID, Quantidade: 2})
+	assert.Equal(t, "01310100", cotacao.CEP)
+	assert.Equal(t, 3500, cotacao.PesoGramas)
+	assert.Equal(t, []dto.OpcaoFreteResponse{
+		{Servico: "Expresso", Valor: money.MustParse("29.90"), PrazoDias: 1},
+		{Servico: "Econômico", Valor: money.MustParse("59.90"), PrazoDias: 8},
+	}, cotacao.Opcoes)
+
+	// fora da capital só o econômico, pela faixa de até 1 kg
+	cotacao = cotar("20040002", dto.CreateItemPedidoRequest{ProdutoID: livro.ID, Quantidade: 1})
+	assert.Equal(t, []dto.OpcaoFreteResponse{{Servico: "Econômico", Valor: money.MustParse("19.90"), PrazoDias: 8}}, cotacao.Opcoes)
+
+	// acima da última faixa não há opções
+	cotacao = cotar("01310100", dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 13})
+	assert.Empty(t, cotacao.Opcoes)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/frete/cotacao", dto.CotacaoFreteRequest{
+		CEP: "01310100", Itens: []dto.CreateItemPedidoRequest{{ProdutoID: 999, Quantidade: 1}},
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestCreatePedido_Frete_Integration(t *testing.T) {
+	router, db, cliente, notebook, _ := setupCupomTestRouter(t)
+	createFreteZona(t, router, zonaBrasil)
+	createFreteZona(t, router, zonaCapitalSP)
+	db.Model(notebook).Update("peso_gramas", 2500)
+	createEndereco(t, router, cliente.ID, enderecoCasa)
+
+	criar := func(req dto.CreatePedidoRequest) dto.PedidoResponse {
+		rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", req, nil)
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var pedido dto.PedidoResponse
+		json.NewDecoder(rec.Body).Decode(&pedido)
+		return pedido
+	}
+	itens := []dto.CreateItemPedidoRequest{{ProdutoID: notebook.ID, Quantidade: 1}}
+
+	// sem serviço escolhido vale o mais barato
+	pedido := criar(dto.CreatePedidoRequest{ClienteID: cliente.ID, Itens: itens})
+	assert.Equal(t, money.MustParse("29.90"), pedido.Frete)
+	assert.Equal(t, "Expresso", pedido.FreteServico)
+	assert.Equal(t, 1, pedido.FretePrazoDias)
+	assert.Equal(t, money.MustParse("2029.90"), pedido.ValorTotal)
+
+	pedido = criar(dto.CreatePedidoRequest{ClienteID: cliente.ID, FreteServico: "econômico", Itens: itens})
+	assert.Equal(t, money.MustParse("59.90"), pedido.Frete)
+	assert.Equal(t, "Econômico", pedido.FreteServico)
+
+	// o cupom de frete grátis abate o frete cobrado
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "FRETEGRATIS", Tipo: model.CupomFreteGratis})
+	pedido = criar(dto.CreatePedidoRequest{ClienteID: cliente.ID, Cupom: "FRETEGRATIS", Itens: itens})
+	assert.Equal(t, money.MustParse("29.90"), pedido.Frete)
+	assert.Equal(t, money.MustParse("29.90"), pedido.Desconto)
+	assert.Equal(t, money.MustParse("2000.00"), pedido.ValorTotal)
+
+	// o frete cobrado não muda quando a tabela muda
+	db.Model(&model.FreteFaixa{}).Where("valor = ?", money.MustParse("29.90")).Update("valor", money.MustParse("99.90"))
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	var lido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&lido)
+	assert.Equal(t, money.MustParse("29.90"), lido.Frete)
+
+	// serviço que não atende o CEP
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID, FreteServico: "Moto", Itens: itens,
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// sem endereço de entrega não há frete para escolher
+	outro := &model.Cliente{Nome: "Maria Souza", Email: "maria@example.com", CPF: "10987654321"}
+	db.Create(outro)
+	pedido = criar(dto.CreatePedidoRequest{ClienteID: outro.ID, Itens: itens})
+	assert.Equal(t, money.Money(0), pedido.Frete)
+	assert.Empty(t, pedido.FreteServico)
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: outro.ID, FreteServico: "Expresso", Itens: itens,
+	}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestFreteZona_OperadorNaoGerencia_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/frete/zonas", zonaBrasil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/frete/zonas", nil, operador)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupIdempotencyTestRouter(t *testing.T) (*chi.Mux, *gorm.DB) {
+	db := setupPedidoTestDB(t)
+	if err := db.AutoMigrate(&model.IdempotencyKey{}); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	controllers := setupPedidoTestRouter(db)
+	store := repository.NewIdempotencyRepositorySQLite(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous, middleware.Idempotency(store, time.Hour))
+	return router, db
+}
+
+func postPedido(router *chi.Mux, key string, req dto.CreatePedidoRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httpReq)
+	return rec
+}
+
+func TestCreatePedido_IdempotencyKey_Integration(t *testing.T) {
+	router, db := setupIdempotencyTestRouter(t)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 10, Ativo: true}
+	db.Create(produto)
+
+	req := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: produto.ID, Quantidade: 2}},
+	}
+
+	first := postPedido(router, "checkout-123", req)
+	retry := postPedido(router, "checkout-123", req)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	// o pedido foi criado e o estoque baixado apenas uma vez
+	var count int64
+	db.Model(&model.Pedido{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 8, atualizado.Estoque)
+
+	// a mesma chave com outro corpo é rejeitada
+	req.Itens[0].Quantidade = 3
+	mismatch := postPedido(router, "checkout-123", req)
+	assert.Equal(t, http.StatusUnprocessableEntity, mismatch.Code)
+
+	// uma nova chave cria um novo pedido
+	other := postPedido(router, "checkout-456", req)
+	assert.Equal(t, http.StatusCreated, other.Code)
+	db.Model(&model.Pedido{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestIdempotencyRepository_ReserveAndExpire_Integration(t *testing.T) {
+	_, db := setupIdempotencyTestRouter(t)
+	repo := repository.NewIdempotencyRepositorySQLite(db)
+	ctx := context.Background()
+
+	newKey := func(expiresAt time.Time) *model.IdempotencyKey {
+		return &model.IdempotencyKey{Subject: "maria", Chave: "abc", Metodo: "POST", Rota: "/api/v1/pedidos", RequestHash: "h", ReservadaAte: time.Now().Add(time.Minute), ExpiresAt: expiresAt}
+	}
+
+	existing, err := repo.Reserve(ctx, newKey(time.Now().Add(-time.Minute)))
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// a reserva anterior expirou, então a chave pode ser reutilizada
+	reserved := newKey(time.Now().Add(time.Hour))
+	existing, err = repo.Reserve(ctx, reserved)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// outra reserva ativa com a mesma chave devolve a existente, ainda em andamento
+	existing, err = repo.Reserve(ctx, newKey(time.Now().Add(time.Hour)))
+	assert.NoError(t, err)
+	if assert.NotNil(t, existing) {
+		assert.Equal(t, reserved.ID, existing.ID)
+		assert.True(t, existing.EmAndamento())
+	}
+
+	// a mesma chave de outro principal é independente
+	other := newKey(time.Now().Add(time.Hour))
+	other.Subject = "joao"
+	existing, err = repo.Reserve(ctx, other)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// uma reserva em andamento vencida é assumida pela repetição
+	db.Model(&model.IdempotencyKey{}).Where("id = ?", other.ID).Update("reservada_ate", time.Now().Add(-time.Second))
+	retomada := newKey(time.Now().Add(time.Hour))
+	retomada.Subject = "joao"
+	existing, err = repo.Reserve(ctx, retomada)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NotEqual(t, other.ID, retomada.ID)
+
+	reserved.StatusCode = http.StatusCreated
+	reserved.Resposta = []byte(`{"id":1}`)
+	reserved.Cabecalhos = `{"Location":"/api/v1/pedidos/1"}`
+	assert.NoError(t, repo.Complete(ctx, reserved))
+
+	// uma resposta concluída não é assumida, mesmo com a reserva vencida
+	db.Model(&model.IdempotencyKey{}).Where("id = ?", reserved.ID).Update("reservada_ate", time.Now().Add(-time.Second))
+	existing, _ = repo.Reserve(ctx, newKey(time.Now().Add(time.Hour)))
+	assert.Equal(t, http.StatusCreated, existing.StatusCode)
+	assert.Equal(t, `{"id":1}`, string(existing.Resposta))
+	assert.Equal(t, `{"Location":"/api/v1/pedidos/1"}`, existing.Cabecalhos)
+
+	n, err := repo.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
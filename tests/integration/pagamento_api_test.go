@@ -0,0 +1,340 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// testGateway é o provedor de pagamentos dos roteadores de teste; o segredo
+// conhecido permite assinar as notificações simuladas
+var testGateway = pagamento.NewFake("segredo-dos-webhooks-de-teste")
+
+// sendWebhook envia ao roteador uma notificação do provedor assinada com a assinatura informada
+func sendWebhook(router *chi.Mux, payload []byte, assinatura string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/pagamentos", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pagamento.WebhookSignatureHeader, assinatura)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// notificar simula o provedor mudando o status de uma cobrança
+func notificar(router *chi.Mux, referencia, status string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(map[string]string{"referencia": referencia, "status": status})
+	return sendWebhook(router, payload, testGateway.Assinar(payload))
+}
+
+func createPagamento(t *testing.T, router *chi.Mux, pedidoID uint, req dto.CreatePagamentoRequest) dto.PagamentoResponse {
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos", pedidoID), req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var pag dto.PagamentoResponse
+	json.NewDecoder(rec.Body).Decode(&pag)
+	return pag
+}
+
+func findPagamentos(t *testing.T, router *chi.Mux, pedidoID uint) dto.PagamentosPedidoResponse {
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos", pedidoID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var pagamentos dto.PagamentosPedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pagamentos)
+	return pagamentos
+}
+
+func findPedido(t *testing.T, router *chi.Mux, id uint) dto.PedidoResponse {
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", id), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	return pedido
+}
+
+// createPedidoLivros cria um pedido pendente de R$ 100,00 (dois livros, sem frete)
+func createPedidoLivros(t *testing.T, router *chi.Mux, clienteID, livroID uint) dto.PedidoResponse {
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: clienteID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livroID, Quantidade: 2}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	assert.Equal(t, money.MustParse("100.00"), pedido.ValorTotal)
+	return pedido
+}
+
+func TestPagamento_Parcial_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	// pix fica pendente até a notificação do provedor
+	primeiro := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix, Valor: money.MustParse("40.00")})
+	assert.Equal(t, model.PagamentoPendente, primeiro.Status)
+	assert.Equal(t, "fake", primeiro.Provedor)
+	// a referência provisória já foi trocada pela do provedor
+	assert.True(t, strings.HasPrefix(primeiro.Referencia, "fake_pay_"), primeiro.Referencia)
+	assert.Nil(t, primeiro.ConfirmadoEm)
+
+	// sem valor, cobra o que ainda não está em cobrança
+	segundo := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoBoleto})
+	assert.Equal(t, money.MustParse("60.00"), segundo.Valor)
+
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos", pedido.ID), dto.CreatePagamentoRequest{Metodo: model.MetodoPix}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.Pago)
+	assert.Equal(t, money.MustParse("100.00"), pagamentos.EmCobranca)
+	assert.Equal(t, money.MustParse("100.00"), pagamentos.Saldo)
+	assert.Len(t, pagamentos.Pagamentos, 2)
+
+	// um pagamento parcial confirmado não quita o pedido
+	rec = notificar(router, primeiro.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	assert.Equal(t, model.StatusPendente, findPedido(t, router, pedido.ID).Status)
+
+	pagamentos = findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.MustParse("40.00"), pagamentos.Pago)
+	assert.Equal(t, money.MustParse("60.00"), pagamentos.Saldo)
+	assert.NotNil(t, pagamentos.Pagamentos[0].ConfirmadoEm)
+
+	// o segundo cobre o total e o pedido passa a pago, em nome do provedor
+	rec = notificar(router, segundo.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d/historico", pedido.ID), nil, nil)
+	var historico []dto.PedidoStatusHistoricoResponse
+	json.NewDecoder(rec.Body).Decode(&historico)
+	if assert.Len(t, historico, 2) {
+		assert.Equal(t, model.StatusPago, historico[1].StatusNovo)
+		assert.Equal(t, "gateway:fake", historico[1].Usuario)
+	}
+
+	// notificações repetidas não têm efeito; contraditórias são recusadas
+	rec = notificar(router, segundo.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = notificar(router, segundo.Referencia, model.PagamentoFalhou)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos", pedido.ID), dto.CreatePagamentoRequest{Metodo: model.MetodoPix}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestPagamento_Falhou_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoBoleto})
+	rec := notificar(router, pag.Referencia, model.PagamentoFalhou)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	// a cobrança que falhou libera o saldo para uma nova
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.EmCobranca)
+	assert.Equal(t, model.PagamentoFalhou, pagamentos.Pagamentos[0].Status)
+
+	novo := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+	assert.Equal(t, money.MustParse("100.00"), novo.Valor)
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+}
+
+func TestPagamento_Conciliacao_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	svc := service.NewPagamentoService(repository.NewPagamentoRepositorySQLite(db), repository.NewPedidoRepositorySQLite(db), testGateway, repository.NewAuditRepositorySQLite(db))
+	ctx := context.Background()
+	antigo := time.Now().Add(-time.Hour)
+
+	// o provedor recebeu a cobrança do primeiro pedido, mas a resposta se perdeu
+	recebido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	cobranca := pagamento.Cobranca{Referencia: "pendente_conciliacao_recebida", PedidoID: recebido.ID, Metodo: model.MetodoCartao, Valor: money.MustParse("100.00")}
+	transacao, err := testGateway.Cobrar(ctx, cobranca)
+	assert.NoError(t, err)
+	db.Create(&model.Pagamento{PedidoID: recebido.ID, Metodo: model.MetodoCartao, Valor: cobranca.Valor, Status: model.PagamentoPendente, Provedor: "fake", Referencia: cobranca.Referencia, CreatedAt: antigo})
+
+	// a do segundo nunca chegou ao provedor
+	perdido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	db.Create(&model.Pagamento{PedidoID: perdido.ID, Metodo: model.MetodoPix, Valor: money.MustParse("100.00"), Status: model.PagamentoPendente, Provedor: "fake", Referencia: "pendente_conciliacao_perdida", CreatedAt: antigo})
+
+	// a do terceiro foi confirmada, mas a quitação do pedido não chegou a ser gravada
+	naoQuitado := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	db.Create(&model.Pagamento{PedidoID: naoQuitado.ID, Metodo: model.MetodoCartao, Valor: money.MustParse("100.00"), Status: model.PagamentoConfirmado, Provedor: "fake", Referencia: "fake_pay_conciliacao"})
+
+	// uma cobrança recente ainda pode estar a caminho e fica para a próxima rodada
+	recente := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	db.Create(&model.Pagamento{PedidoID: recente.ID, Metodo: model.MetodoPix, Valor: money.MustParse("100.00"), Status: model.PagamentoPendente, Provedor: "fake", Referencia: "pendente_conciliacao_recente"})
+
+	assert.NoError(t, svc.Conciliar(ctx, time.Now().Add(-5*time.Minute)))
+
+	pagamentos := findPagamentos(t, router, recebido.ID)
+	assert.Equal(t, transacao.Referencia, pagamentos.Pagamentos[0].Referencia)
+	assert.Equal(t, model.PagamentoConfirmado, pagamentos.Pagamentos[0].Status)
+	assert.Equal(t, model.StatusPago, findPedido(t, router, recebido.ID).Status)
+
+	pagamentos = findPagamentos(t, router, perdido.ID)
+	assert.Equal(t, model.PagamentoFalhou, pagamentos.Pagamentos[0].Status)
+	assert.Equal(t, money.Money(0), pagamentos.EmCobranca)
+	assert.Equal(t, model.StatusPendente, findPedido(t, router, perdido.ID).Status)
+
+	assert.Equal(t, model.StatusPago, findPedido(t, router, naoQuitado.ID).Status)
+
+	pagamentos = findPagamentos(t, router, recente.ID)
+	assert.Equal(t, model.PagamentoPendente, pagamentos.Pagamentos[0].Status)
+	assert.Equal(t, "pendente_conciliacao_recente", pagamentos.Pagamentos[0].Referencia)
+
+	// uma nova rodada não tem o que fazer
+	assert.NoError(t, svc.Conciliar(ctx, time.Now().Add(-5*time.Minute)))
+	assert.Equal(t, model.StatusPago, findPedido(t, router, recebido.ID).Status)
+}
+
+func TestPagamento_Estorno_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	// cartão é confirmado na hora e quita o pedido
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+	assert.Equal(t, model.PagamentoConfirmado, pag.Status)
+	assert.NotNil(t, pag.ConfirmadoEm)
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+
+	estornos := fmt.Sprintf("/api/v1/pedidos/%d/pagamentos/%d/estornos", pedido.ID, pag.ID)
+	rec := sendWithHeaders(router, http.MethodPost, estornos, dto.CreateEstornoRequest{Valor: money.MustParse("30.00"), Motivo: "Livro danificado"}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	json.NewDecoder(rec.Body).Decode(&pag)
+	assert.Equal(t, money.MustParse("30.00"), pag.Estornado)
+	assert.Equal(t, model.PagamentoConfirmado, pag.Status)
+	if assert.Len(t, pag.Estornos, 1) {
+		assert.Equal(t, "Livro danificado", pag.Estornos[0].Motivo)
+		assert.Equal(t, auth.ActorAnonimo, pag.Estornos[0].Usuario)
+		// o estorno é conciliado com a resposta do provedor depois do commit
+		assert.Equal(t, model.EstornoConcluido, pag.Estornos[0].Status)
+		assert.True(t, strings.HasPrefix(pag.Estornos[0].Referencia, "fake_ref_"), pag.Estornos[0].Referencia)
+	}
+
+	rec = sendWithHeaders(router, http.MethodPost, estornos, dto.CreateEstornoRequest{Valor: money.MustParse("70.01")}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// sem valor, estorna o restante
+	rec = sendWithHeaders(router, http.MethodPost, estornos, dto.CreateEstornoRequest{}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	json.NewDecoder(rec.Body).Decode(&pag)
+	assert.Equal(t, model.PagamentoEstornado, pag.Status)
+	assert.Len(t, pag.Estornos, 2)
+
+	pagamentos := findPagamentos(t, router, pedido.ID)
+	assert.Equal(t, money.Money(0), pagamentos.Pago)
+	assert.Equal(t, money.MustParse("100.00"), pagamentos.Saldo)
+	// o estorno não muda o status do pedido
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+
+	rec = sendWithHeaders(router, http.MethodPost, estornos, dto.CreateEstornoRequest{}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// o pagamento só é encontrado pelo seu pedido
+	outro := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos/%d/estornos", outro.ID, pag.ID), dto.CreateEstornoRequest{}, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeletePedido_Pago_Integration(t *testing.T) {
+	router, db, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	var antes model.Produto
+	db.First(&antes, livro.ID)
+
+	// o pedido pago não é excluído: o estoque fica como está e o pagamento não perde o pedido
+	rec := sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	assert.Contains(t, problem.Detail, "/cancelamento")
+
+	var depois model.Produto
+	db.First(&depois, livro.ID)
+	assert.Equal(t, antes.Estoque, depois.Estoque)
+	assert.Equal(t, model.StatusPago, findPedido(t, router, pedido.ID).Status)
+
+	// um pendente com cobrança em aberto também não
+	outro := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPagamento(t, router, outro.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix})
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", outro.ID), nil, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+
+	// cancelado, com o pagamento estornado, o pedido pode ser excluído
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", pedido.ID), dto.CancelamentoRequest{Motivo: "Cliente desistiu"}, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
+
+func TestPagamentoWebhook_Assinatura_Integration(t *testing.T) {
+	router, _, cliente, _, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix})
+
+	payload, _ := json.Marshal(map[string]string{"referencia": pag.Referencia, "status": model.PagamentoConfirmado})
+	rec := sendWebhook(router, payload, pagamento.NewFake("outro-segredo").Assinar(payload))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = sendWebhook(router, payload, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, model.StatusPendente, findPedido(t, router, pedido.ID).Status)
+
+	rec = notificar(router, "fake_pay_inexistente", model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = notificar(router, pag.Referencia, model.PagamentoEstornado)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPagamento_Autorizacao_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+	admin := map[string]string{"Authorization": issue(auth.RoleAdmin)}
+
+	// o webhook não exige token: é autenticado pela assinatura
+	rec := notificar(router, "fake_pay_inexistente", model.PagamentoConfirmado)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/clientes", dto.CreateClienteRequest{Nome: "Ana Lima", Email: "ana@example.com", CPF: "12345678909"}, operador)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var cliente dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&cliente)
+
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID, Itens: []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+	}, operador)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+
+	// marcar como pago sem pagamento é uma baixa manual, só para admin
+	url := fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID)
+	rec = sendWithHeaders(router, http.MethodPut, url, dto.UpdatePedidoRequest{Status: model.StatusPago}, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = sendWithHeaders(router, http.MethodPut, url, dto.UpdatePedidoRequest{Status: model.StatusPago}, admin)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/pagamentos/1/estornos", pedido.ID), dto.CreateEstornoRequest{}, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -3,13 +3,17 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/danmaciel/api/internal/controller"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/middleware"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -24,42 +28,100 @@ func setupPedidoTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Run migrations
-	if err := db.AutoMigrate(&model.Cliente{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}); err != nil {
+	if err := db.AutoMigrate(&model.Cliente{}, &model.Endereco{}, &model.Contato{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}, &model.PedidoDesconto{}, &model.Cupom{}, &model.RegraImposto{}, &model.PedidoItemImposto{}, &model.FreteZona{}, &model.FreteFaixa{}, &model.Pagamento{}, &model.PagamentoEstorno{}, &model.PedidoDevolucao{}, &model.PedidoDevolucaoItem{}, &model.PedidoStatusHistorico{}, &model.AuditLog{}, &model.AuditCadeia{}); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return db
 }
 
-func setupPedidoTestRouter(db *gorm.DB) (*controller.ClienteController, *controller.ProdutoController, *controller.PedidoController) {
+func setupPedidoTestRouter(db *gorm.DB) controller.Controllers {
+	// Audit log
+	auditRepo := repository.NewAuditRepositorySQLite(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditController := controller.NewAuditController(auditService)
+
 	// Cliente
 	clienteRepo := repository.NewClienteRepositorySQLite(db)
-	clienteService := service.NewClienteService(clienteRepo)
+	clienteService := service.NewClienteService(clienteRepo, auditRepo)
 	clienteController := controller.NewClienteController(clienteService)
 
 	// Produto
 	produtoRepo := repository.NewProdutoRepositorySQLite(db)
-	produtoService := service.NewProdutoService(produtoRepo)
+	produtoService := service.NewProdutoService(produtoRepo, auditRepo)
 	produtoController := controller.NewProdutoController(produtoService)
 
+	// Cupom
+	cupomRepo := repository.NewCupomRepositorySQLite(db)
+	cupomService := service.NewCupomService(cupomRepo)
+	cupomController := controller.NewCupomController(cupomService)
+
+	// Regras de imposto
+	regraImpostoRepo := repository.NewRegraImpostoRepositorySQLite(db)
+	regraImpostoService := service.NewRegraImpostoService(regraImpostoRepo)
+	regraImpostoController := controller.NewRegraImpostoController(regraImpostoService)
+
+	// Endereços
+	enderecoRepo := repository.NewEnderecoRepositorySQLite(db)
+	enderecoService := service.NewEnderecoService(enderecoRepo, clienteRepo)
+	enderecoController := controller.NewEnderecoController(enderecoService)
+
+	// Contatos
+	contatoRepo := repository.NewContatoRepositorySQLite(db)
+	contatoService := service.NewContatoService(contatoRepo, clienteRepo)
+	contatoController := controller.NewContatoController(contatoService)
+
+	// Frete
+	freteRepo := repository.NewFreteRepositorySQLite(db)
+	freteCalculator := frete.NewTabela(freteRepo)
+	freteService := service.NewFreteService(freteRepo, produtoRepo, freteCalculator)
+	freteController := controller.NewFreteController(freteService)
+
 	// Pedido
 	pedidoRepo := repository.NewPedidoRepositorySQLite(db)
-	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo)
+	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, cupomRepo, regraImpostoRepo, enderecoRepo, freteCalculator, auditRepo)
 	pedidoController := controller.NewPedidoController(pedidoService)
 
-	return clienteController, produtoController, pedidoController
+	// Pagamentos
+	pagamentoRepo := repository.NewPagamentoRepositorySQLite(db)
+	pagamentoService := service.NewPagamentoService(pagamentoRepo, pedidoRepo, testGateway, auditRepo)
+	pagamentoController := controller.NewPagamentoController(pagamentoService)
+
+	// Cancelamento e devoluções
+	devolucaoService := service.NewDevolucaoService(pedidoRepo, produtoRepo, cupomRepo, pagamentoRepo, testGateway, auditRepo)
+	devolucaoController := controller.NewDevolucaoController(devolucaoService)
+
+	// API keys
+	apiKeyRepo := repository.NewAPIKeyRepositorySQLite(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+
+	return controller.Controllers{
+		Cliente:      clienteController,
+		Produto:      produtoController,
+		Pedido:       pedidoController,
+		APIKey:       apiKeyController,
+		Cupom:        cupomController,
+		RegraImposto: regraImpostoController,
+		Endereco:     enderecoController,
+		Frete:        freteController,
+		Pagamento:    pagamentoController,
+		Devolucao:    devolucaoController,
+		Contato:      contatoController,
+		Audit:        auditController,
+	}
 }
 
 func TestCreatePedido_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: 2999.99, Estoque: 10, Ativo: true}
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 10, Ativo: true}
 	db.Create(produto)
 
 	reqBody := dto.CreatePedidoRequest{
@@ -85,17 +147,222 @@ func TestCreatePedido_Integration(t *testing.T) {
 	assert.NotZero(t, response.ValorTotal)
 }
 
+func TestCreatePedido_ValorTotalExato_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	// 0.10 and 0.20 cannot be represented exactly as float64
+	caneta := &model.Produto{Nome: "Caneta", SKU: "CN-001", Preco: money.MustParse("0.10"), Estoque: 10, Ativo: true}
+	lapis := &model.Produto{Nome: "Lapis", SKU: "LP-001", Preco: money.MustParse("0.20"), Estoque: 10, Ativo: true}
+	db.Create(caneta)
+	db.Create(lapis)
+
+	reqBody := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: caneta.ID, Quantidade: 3},
+			{ProdutoID: lapis.ID, Quantidade: 1},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var raw map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&raw)
+	assert.Equal(t, "0.50", raw["valor_total"])
+
+	var pedido model.Pedido
+	db.First(&pedido)
+	assert.Equal(t, money.FromCentavos(50), pedido.ValorTotal)
+}
+
+func TestCreatePedido_BaixaEstoque_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 3, Ativo: true}
+	db.Create(produto)
+
+	reqBody := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: produto.ID, Quantidade: 2},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 1, atualizado.Estoque)
+
+	// O mesmo saldo não pode ser vendido novamente
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.NotEqual(t, http.StatusCreated, rec.Code)
+
+	var pedidos int64
+	db.Model(&model.Pedido{}).Count(&pedidos)
+	assert.Equal(t, int64(1), pedidos)
+
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 1, atualizado.Estoque)
+}
+
+func TestCreatePedido_RollbackEstoque_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	comEstoque := &model.Produto{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("50.00"), Estoque: 10, Ativo: true}
+	db.Create(comEstoque)
+	semEstoque := &model.Produto{Nome: "Teclado", SKU: "TC-001", Preco: money.MustParse("150.00"), Estoque: 1, Ativo: true}
+	db.Create(semEstoque)
+
+	reqBody := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: comEstoque.ID, Quantidade: 5},
+			{ProdutoID: semEstoque.ID, Quantidade: 2},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.NotEqual(t, http.StatusCreated, rec.Code)
+
+	// A baixa do primeiro item deve ser desfeita junto com a falha do segundo
+	var atualizado model.Produto
+	db.First(&atualizado, comEstoque.ID)
+	assert.Equal(t, 10, atualizado.Estoque)
+}
+
+func TestCancelarPedido_RestauraEstoque_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 5, Ativo: true}
+	db.Create(produto)
+
+	reqBody := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: produto.ID, Quantidade: 2},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	body, _ = json.Marshal(dto.CancelamentoRequest{Motivo: "Cliente desistiu"})
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/cancelamento", created.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 5, atualizado.Estoque)
+
+	// Excluir um pedido já cancelado não devolve o estoque novamente
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", created.ID), nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 5, atualizado.Estoque)
+}
+
+func TestDeletePedido_RestauraEstoque_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 5, Ativo: true}
+	db.Create(produto)
+
+	reqBody := dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: produto.ID, Quantidade: 3},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d", created.ID), nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 5, atualizado.Estoque)
+}
+
 func TestGetAllPedidos_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 100.00, Status: "pendente"})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 200.00, Status: "pago"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("200.00"), Status: "pago"})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos", nil)
 	rec := httptest.NewRecorder()
@@ -104,21 +371,21 @@ func TestGetAllPedidos_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.PedidoResponse
+	var response dto.PageResponse[dto.PedidoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
+	assert.Len(t, response.Data, 2)
 }
 
 func TestGetPedidoByID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: 150.00, Status: "pendente"}
+	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("150.00"), Status: "pendente"}
 	db.Create(pedido)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/1", nil)
@@ -135,15 +402,15 @@ func TestGetPedidoByID_Integration(t *testing.T) {
 
 func TestCountPedidos_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 100.00, Status: "pendente"})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 200.00, Status: "pago"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("200.00"), Status: "pago"})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/count", nil)
 	rec := httptest.NewRecorder()
@@ -159,14 +426,14 @@ func TestCountPedidos_Integration(t *testing.T) {
 
 func TestUpdatePedidoStatus_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: 100.00, Status: "pendente"}
+	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"}
 	db.Create(pedido)
 
 	reqBody := dto.UpdatePedidoRequest{
@@ -187,16 +454,103 @@ func TestUpdatePedidoStatus_Integration(t *testing.T) {
 	assert.Equal(t, "pago", response.Status)
 }
 
+func TestUpdatePedidoStatus_TransicaoInvalida_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
+	db.Create(cliente)
+
+	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "entregue"}
+	db.Create(pedido)
+
+	body, _ := json.Marshal(dto.UpdatePedidoRequest{Status: "pendente"})
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d", pedido.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var atual model.Pedido
+	db.First(&atual, pedido.ID)
+	assert.Equal(t, "entregue", atual.Status)
+}
+
+func TestGetPedidoHistorico_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 5, Ativo: true}
+	db.Create(produto)
+
+	body, _ := json.Marshal(dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: produto.ID, Quantidade: 1}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	for _, status := range []string{"pago", "enviado"} {
+		body, _ = json.Marshal(dto.UpdatePedidoRequest{Status: status})
+		req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d", created.ID), bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d/historico", created.ID), nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var historico []dto.PedidoStatusHistoricoResponse
+	json.NewDecoder(rec.Body).Decode(&historico)
+	assert.Len(t, historico, 3)
+	assert.Equal(t, "", historico[0].StatusAnterior)
+	assert.Equal(t, "pendente", historico[0].StatusNovo)
+	assert.Equal(t, "pago", historico[2].StatusAnterior)
+	assert.Equal(t, "enviado", historico[2].StatusNovo)
+	assert.Equal(t, "anonimo", historico[2].Usuario)
+}
+
+func TestGetPedidoHistorico_NotFound_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/9999/historico", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 func TestDeletePedido_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: 100.00, Status: "pendente"}
+	pedido := &model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"}
 	db.Create(pedido)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/pedidos/1", nil)
@@ -209,8 +563,8 @@ func TestDeletePedido_Integration(t *testing.T) {
 
 func TestFindByClienteID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente1 := &model.Cliente{Nome: "Cliente 1", Email: "cliente1@example.com", CPF: "11111111111"}
@@ -219,9 +573,9 @@ func TestFindByClienteID_Integration(t *testing.T) {
 	cliente2 := &model.Cliente{Nome: "Cliente 2", Email: "cliente2@example.com", CPF: "22222222222"}
 	db.Create(cliente2)
 
-	db.Create(&model.Pedido{ClienteID: cliente1.ID, ValorTotal: 100.00, Status: "pendente"})
-	db.Create(&model.Pedido{ClienteID: cliente1.ID, ValorTotal: 200.00, Status: "pago"})
-	db.Create(&model.Pedido{ClienteID: cliente2.ID, ValorTotal: 300.00, Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente1.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente1.ID, ValorTotal: money.MustParse("200.00"), Status: "pago"})
+	db.Create(&model.Pedido{ClienteID: cliente2.ID, ValorTotal: money.MustParse("300.00"), Status: "pendente"})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/cliente/1", nil)
 	rec := httptest.NewRecorder()
@@ -230,24 +584,24 @@ func TestFindByClienteID_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.PedidoResponse
+	var response dto.PageResponse[dto.PedidoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
-	assert.Equal(t, cliente1.ID, response[0].ClienteID)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, cliente1.ID, response.Data[0].ClienteID)
 }
 
 func TestFindByStatus_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
 	cliente := &model.Cliente{Nome: "Cliente Teste", Email: "teste@example.com", CPF: "11111111111"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 100.00, Status: "pendente"})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 200.00, Status: "pendente"})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: 300.00, Status: "pago"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("100.00"), Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("200.00"), Status: "pendente"})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, ValorTotal: money.MustParse("300.00"), Status: "pago"})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/status/pendente", nil)
 	rec := httptest.NewRecorder()
@@ -256,17 +610,17 @@ func TestFindByStatus_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.PedidoResponse
+	var response dto.PageResponse[dto.PedidoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
-	assert.Equal(t, "pendente", response[0].Status)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "pendente", response.Data[0].Status)
 }
 
 // Testes de erro para aumentar cobertura
 func TestCreatePedido_InvalidJSON_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader([]byte("invalid")))
 	req.Header.Set("Content-Type", "application/json")
@@ -279,8 +633,8 @@ func TestCreatePedido_InvalidJSON_Integration(t *testing.T) {
 
 func TestCreatePedido_ValidationError_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.CreatePedidoRequest{
 		ClienteID: 0, // Invalid
@@ -294,13 +648,81 @@ func TestCreatePedido_ValidationError_Integration(t *testing.T) {
 
 	router.ServeHTTP(rec, req)
 
-	assert.True(t, rec.Code == http.StatusBadRequest || rec.Code == http.StatusInternalServerError)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCreatePedido_ItemValidationError_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	body := []byte(`{"cliente_id": 1, "itens": [{"produto_id": 1, "quantidade": 1}, {"produto_id": 2, "quantidade": 0}]}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, []dto.FieldError{
+		{Field: "itens[1].quantidade", Rule: "required", Message: "é obrigatório"},
+	}, response.Errors)
+}
+
+func TestCreatePedido_BusinessRuleErrors_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+
+	produto := &model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Estoque: 1, Ativo: true}
+	db.Create(produto)
+
+	tests := []struct {
+		name      string
+		clienteID uint
+		produtoID uint
+		qtd       int
+		message   string
+	}{
+		{"cliente inexistente", 9999, produto.ID, 1, "cliente 9999 não encontrado"},
+		{"produto inexistente", cliente.ID, 9999, 1, "produto 9999 não encontrado"},
+		{"estoque insuficiente", cliente.ID, produto.ID, 5, "estoque insuficiente"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := dto.CreatePedidoRequest{
+				ClienteID: tt.clienteID,
+				Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: tt.produtoID, Quantidade: tt.qtd}},
+			}
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/pedidos", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+			var response dto.ProblemDetails
+			json.NewDecoder(rec.Body).Decode(&response)
+			assert.Contains(t, response.Detail, tt.message)
+		})
+	}
 }
 
 func TestGetPedidoByID_InvalidID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -312,8 +734,8 @@ func TestGetPedidoByID_InvalidID_Integration(t *testing.T) {
 
 func TestGetPedidoByID_NotFound_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/9999", nil)
 	rec := httptest.NewRecorder()
@@ -325,8 +747,8 @@ func TestGetPedidoByID_NotFound_Integration(t *testing.T) {
 
 func TestUpdatePedidoStatus_InvalidJSON_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/pedidos/1", bytes.NewReader([]byte("invalid")))
 	req.Header.Set("Content-Type", "application/json")
@@ -339,8 +761,8 @@ func TestUpdatePedidoStatus_InvalidJSON_Integration(t *testing.T) {
 
 func TestUpdatePedidoStatus_InvalidID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.UpdatePedidoRequest{Status: "pago"}
 	body, _ := json.Marshal(reqBody)
@@ -356,8 +778,8 @@ func TestUpdatePedidoStatus_InvalidID_Integration(t *testing.T) {
 
 func TestUpdatePedidoStatus_NotFound_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.UpdatePedidoRequest{Status: "pago"}
 	body, _ := json.Marshal(reqBody)
@@ -373,8 +795,8 @@ func TestUpdatePedidoStatus_NotFound_Integration(t *testing.T) {
 
 func TestDeletePedido_InvalidID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/pedidos/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -386,8 +808,8 @@ func TestDeletePedido_InvalidID_Integration(t *testing.T) {
 
 func TestFindByClienteID_InvalidID_Integration(t *testing.T) {
 	db := setupPedidoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupPedidoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos/cliente/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// editarItem envia a alteração de itens e decodifica o pedido recalculado
+func editarItem(t *testing.T, router *chi.Mux, method, path string, body interface{}, status int) dto.PedidoResponse {
+	rec := sendWithHeaders(router, method, path, body, nil)
+	assert.Equal(t, status, rec.Code, rec.Body.String())
+
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	return pedido
+}
+
+func estoque(db *gorm.DB, produtoID uint) int {
+	var produto model.Produto
+	db.First(&produto, produtoID)
+	return produto.Estoque
+}
+
+func TestPedidoItens_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	itensPath := fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID)
+	livroItemID := pedido.Itens[0].ID
+
+	// incluir um produto novo reserva o estoque e recalcula o total
+	atual := editarItem(t, router, http.MethodPost, itensPath, dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, http.StatusCreated)
+	assert.Len(t, atual.Itens, 2)
+	assert.Equal(t, money.MustParse("2100.00"), atual.ValorTotal)
+	assert.Equal(t, pedido.Versao+1, atual.Versao)
+	assert.Equal(t, 9, estoque(db, notebook.ID))
+	notebookItemID := atual.Itens[1].ID
+
+	// o mesmo produto soma à linha existente
+	atual = editarItem(t, router, http.MethodPost, itensPath, dto.CreateItemPedidoRequest{ProdutoID: livro.ID, Quantidade: 1}, http.StatusCreated)
+	assert.Len(t, atual.Itens, 2)
+	assert.Equal(t, 3, atual.Itens[0].Quantidade)
+	assert.Equal(t, money.MustParse("150.00"), atual.Itens[0].Subtotal)
+	assert.Equal(t, 7, estoque(db, livro.ID))
+
+	// diminuir a quantidade devolve a diferença ao estoque
+	atual = editarItem(t, router, http.MethodPut, fmt.Sprintf("%s/%d", itensPath, livroItemID), dto.UpdateItemPedidoRequest{Quantidade: 1}, http.StatusOK)
+	assert.Equal(t, money.MustParse("2050.00"), atual.ValorTotal)
+	assert.Equal(t, 9, estoque(db, livro.ID))
+
+	// aumentar além do estoque é recusado sem alterar nada
+	editarItem(t, router, http.MethodPut, fmt.Sprintf("%s/%d", itensPath, notebookItemID), dto.UpdateItemPedidoRequest{Quantidade: 11}, http.StatusUnprocessableEntity)
+	assert.Equal(t, 9, estoque(db, notebook.ID))
+
+	atual = editarItem(t, router, http.MethodDelete, fmt.Sprintf("%s/%d", itensPath, notebookItemID), nil, http.StatusOK)
+	assert.Len(t, atual.Itens, 1)
+	assert.Equal(t, money.MustParse("50.00"), atual.Subtotal)
+	assert.Equal(t, money.MustParse("50.00"), atual.ValorTotal)
+	assert.Equal(t, 10, estoque(db, notebook.ID))
+
+	// o último item não pode ser removido e itens de outros pedidos não existem aqui
+	editarItem(t, router, http.MethodDelete, fmt.Sprintf("%s/%d", itensPath, livroItemID), nil, http.StatusUnprocessableEntity)
+	editarItem(t, router, http.MethodDelete, fmt.Sprintf("%s/%d", itensPath, notebookItemID), nil, http.StatusNotFound)
+
+	assert.Equal(t, atual.ValorTotal, findPedido(t, router, pedido.ID).ValorTotal)
+}
+
+func TestPedidoItens_ProdutoIndisponivel_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	itensPath := fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID)
+
+	editarItem(t, router, http.MethodPost, itensPath, dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 11}, http.StatusUnprocessableEntity)
+	editarItem(t, router, http.MethodPost, itensPath, dto.CreateItemPedidoRequest{ProdutoID: 999, Quantidade: 1}, http.StatusUnprocessableEntity)
+
+	db.Model(&model.Produto{}).Where("id = ?", livro.ID).Update("ativo", false)
+	editarItem(t, router, http.MethodPut, fmt.Sprintf("%s/%d", itensPath, pedido.Itens[0].ID), dto.UpdateItemPedidoRequest{Quantidade: 3}, http.StatusUnprocessableEntity)
+
+	// diminuir não depende do produto estar ativo
+	editarItem(t, router, http.MethodPut, fmt.Sprintf("%s/%d", itensPath, pedido.Itens[0].ID), dto.UpdateItemPedidoRequest{Quantidade: 1}, http.StatusOK)
+	assert.Equal(t, 9, estoque(db, livro.ID))
+	assert.Equal(t, 10, estoque(db, notebook.ID))
+}
+
+func TestPedidoItens_ApenasPendente_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	editarItem(t, router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID), dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, http.StatusUnprocessableEntity)
+	editarItem(t, router, http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d/itens/%d", pedido.ID, pedido.Itens[0].ID), dto.UpdateItemPedidoRequest{Quantidade: 1}, http.StatusUnprocessableEntity)
+	assert.Equal(t, 10, estoque(db, notebook.ID))
+	assert.Equal(t, 8, estoque(db, livro.ID))
+}
+
+func TestPedidoItens_ComPagamentos_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+	itensPath := fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID)
+
+	// um pagamento parcial, mesmo confirmado, mantém o pedido pendente
+	pag := createPagamento(t, router, pedido.ID, dto.CreatePagamentoRequest{Metodo: model.MetodoPix, Valor: money.MustParse("40.00")})
+	editarItem(t, router, http.MethodPost, itensPath, dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, http.StatusConflict)
+
+	notificar(router, pag.Referencia, model.PagamentoConfirmado)
+	assert.Equal(t, model.StatusPendente, findPedido(t, router, pedido.ID).Status)
+	editarItem(t, router, http.MethodDelete, fmt.Sprintf("%s/%d", itensPath, pedido.Itens[0].ID), nil, http.StatusConflict)
+	assert.Equal(t, 10, estoque(db, notebook.ID))
+	assert.Equal(t, 8, estoque(db, livro.ID))
+	assert.Equal(t, pedido.ValorTotal, findPedido(t, router, pedido.ID).ValorTotal)
+}
+
+func TestPedidoItens_RecalculaCupom_Integration(t *testing.T) {
+	router, _, cliente, notebook, livro := setupCupomTestRouter(t)
+	createCupom(t, router, dto.CreateCupomRequest{Codigo: "DEZPORCENTO", Tipo: model.CupomPercentual, Percentual: 10, ValorMinimo: money.MustParse("100.00")})
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		Cupom:     "DEZPORCENTO",
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: livro.ID, Quantidade: 2}},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+	assert.Equal(t, money.MustParse("90.00"), pedido.ValorTotal)
+
+	atual := editarItem(t, router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID), dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, http.StatusCreated)
+	assert.Equal(t, money.MustParse("210.00"), atual.Desconto)
+	assert.Equal(t, money.MustParse("1890.00"), atual.ValorTotal)
+	if assert.Len(t, atual.Descontos, 1) {
+		assert.Equal(t, "DEZPORCENTO", atual.Descontos[0].Codigo)
+	}
+
+	atual = editarItem(t, router, http.MethodPut, fmt.Sprintf("/api/v1/pedidos/%d/itens/%d", pedido.ID, pedido.Itens[0].ID), dto.UpdateItemPedidoRequest{Quantidade: 1}, http.StatusOK)
+	assert.Equal(t, money.MustParse("1845.00"), atual.ValorTotal)
+
+	// abaixo do mínimo do cupom a alteração é recusada
+	editarItem(t, router, http.MethodDelete, fmt.Sprintf("/api/v1/pedidos/%d/itens/%d", pedido.ID, atual.Itens[1].ID), nil, http.StatusUnprocessableEntity)
+	assert.Equal(t, money.MustParse("1845.00"), findPedido(t, router, pedido.ID).ValorTotal)
+}
+
+func TestPedidoItens_IfMatch_Integration(t *testing.T) {
+	router, _, cliente, notebook, livro := setupCupomTestRouter(t)
+	pedido := createPedidoLivros(t, router, cliente.ID, livro.ID)
+
+	rec := sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/pedidos/%d/itens", pedido.ID), dto.CreateItemPedidoRequest{ProdutoID: notebook.ID, Quantidade: 1}, map[string]string{"If-Match": `"999"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
@@ -17,7 +19,7 @@ func setupPedidoRepoTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.Cliente{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}); err != nil {
+	if err := db.AutoMigrate(&model.Cliente{}, &model.Endereco{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}, &model.PedidoDesconto{}, &model.Cupom{}, &model.RegraImposto{}, &model.PedidoItemImposto{}, &model.PedidoStatusHistorico{}, &model.PedidoDevolucao{}, &model.PedidoDevolucaoItem{}); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -32,15 +34,15 @@ func TestPedidoRepository_Create(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	produto := &model.Produto{Nome: "Test Produto", SKU: "TEST-001", Preco: 100.00}
+	produto := &model.Produto{Nome: "Test Produto", SKU: "TEST-001", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	pedido := &model.Pedido{
 		ClienteID:  cliente.ID,
 		Status:     "pendente",
-		ValorTotal: 100.00,
+		ValorTotal: money.MustParse("100.00"),
 		Itens: []model.PedidoProduto{
-			{ProdutoID: produto.ID, Quantidade: 1, PrecoUnitario: 100.00},
+			{ProdutoID: produto.ID, Quantidade: 1, PrecoUnitario: money.MustParse("100.00")},
 		},
 	}
 
@@ -56,12 +58,12 @@ func TestPedidoRepository_FindAll(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: 200.00})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: money.MustParse("200.00")})
 
-	pedidos, err := repo.FindAll(context.Background())
+	pedidos, err := repo.FindAll(context.Background(), repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, pedidos, 2)
+	assert.Len(t, pedidos.Items, 2)
 }
 
 func TestPedidoRepository_FindByID(t *testing.T) {
@@ -71,7 +73,7 @@ func TestPedidoRepository_FindByID(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	created := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00}
+	created := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")}
 	db.Create(created)
 
 	pedido, err := repo.FindByID(context.Background(), created.ID)
@@ -86,7 +88,7 @@ func TestPedidoRepository_FindByID_NotFound(t *testing.T) {
 
 	_, err := repo.FindByID(context.Background(), 9999)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 }
 
 func TestPedidoRepository_FindByID_DBError(t *testing.T) {
@@ -110,13 +112,13 @@ func TestPedidoRepository_FindByClienteID(t *testing.T) {
 	db.Create(cliente1)
 	db.Create(cliente2)
 
-	db.Create(&model.Pedido{ClienteID: cliente1.ID, Status: "pendente", ValorTotal: 100.00})
-	db.Create(&model.Pedido{ClienteID: cliente1.ID, Status: "confirmado", ValorTotal: 200.00})
-	db.Create(&model.Pedido{ClienteID: cliente2.ID, Status: "pendente", ValorTotal: 150.00})
+	db.Create(&model.Pedido{ClienteID: cliente1.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")})
+	db.Create(&model.Pedido{ClienteID: cliente1.ID, Status: "confirmado", ValorTotal: money.MustParse("200.00")})
+	db.Create(&model.Pedido{ClienteID: cliente2.ID, Status: "pendente", ValorTotal: money.MustParse("150.00")})
 
-	pedidos, err := repo.FindByClienteID(context.Background(), cliente1.ID)
+	pedidos, err := repo.FindByClienteID(context.Background(), cliente1.ID, repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, pedidos, 2)
+	assert.Len(t, pedidos.Items, 2)
 }
 
 func TestPedidoRepository_FindByStatus(t *testing.T) {
@@ -126,13 +128,13 @@ func TestPedidoRepository_FindByStatus(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 200.00})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: 150.00})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("200.00")})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: money.MustParse("150.00")})
 
-	pedidos, err := repo.FindByStatus(context.Background(), "pendente")
+	pedidos, err := repo.FindByStatus(context.Background(), "pendente", repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, pedidos, 2)
+	assert.Len(t, pedidos.Items, 2)
 }
 
 func TestPedidoRepository_Update(t *testing.T) {
@@ -142,7 +144,7 @@ func TestPedidoRepository_Update(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00}
+	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")}
 	db.Create(pedido)
 
 	pedido.Status = "confirmado"
@@ -164,7 +166,7 @@ func TestPedidoRepository_Update_NotFound(t *testing.T) {
 	db.Create(cliente)
 
 	// Create a pedido
-	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00}
+	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")}
 	db.Create(pedido)
 
 	// Delete it
@@ -186,7 +188,7 @@ func TestPedidoRepository_Update_DBError(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00}
+	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")}
 	db.Create(pedido)
 
 	// Close database to simulate error
@@ -204,7 +206,7 @@ func TestPedidoRepository_Delete(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00}
+	pedido := &model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")}
 	db.Create(pedido)
 
 	err := repo.Delete(context.Background(), pedido.ID)
@@ -221,7 +223,7 @@ func TestPedidoRepository_Delete_NotFound(t *testing.T) {
 
 	err := repo.Delete(context.Background(), 9999)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 }
 
 func TestPedidoRepository_Delete_DBError(t *testing.T) {
@@ -243,8 +245,8 @@ func TestPedidoRepository_Count(t *testing.T) {
 	cliente := &model.Cliente{Nome: "Test Cliente", Email: "test@test.com", CPF: "12345678901"}
 	db.Create(cliente)
 
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: 100.00})
-	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: 200.00})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "pendente", ValorTotal: money.MustParse("100.00")})
+	db.Create(&model.Pedido{ClienteID: cliente.ID, Status: "confirmado", ValorTotal: money.MustParse("200.00")})
 
 	count, err := repo.Count(context.Background())
 	assert.NoError(t, err)
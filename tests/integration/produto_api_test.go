@@ -3,13 +3,18 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/danmaciel/api/internal/controller"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/middleware"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -24,42 +29,100 @@ func setupProdutoTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Run migrations
-	if err := db.AutoMigrate(&model.Cliente{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}); err != nil {
+	if err := db.AutoMigrate(&model.Cliente{}, &model.Endereco{}, &model.Contato{}, &model.Produto{}, &model.Pedido{}, &model.PedidoProduto{}, &model.PedidoDesconto{}, &model.Cupom{}, &model.RegraImposto{}, &model.PedidoItemImposto{}, &model.FreteZona{}, &model.FreteFaixa{}, &model.Pagamento{}, &model.PagamentoEstorno{}, &model.PedidoDevolucao{}, &model.PedidoDevolucaoItem{}, &model.PedidoStatusHistorico{}, &model.AuditLog{}, &model.AuditCadeia{}); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return db
 }
 
-func setupProdutoTestRouter(db *gorm.DB) (*controller.ClienteController, *controller.ProdutoController, *controller.PedidoController) {
+func setupProdutoTestRouter(db *gorm.DB) controller.Controllers {
+	// Audit log
+	auditRepo := repository.NewAuditRepositorySQLite(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditController := controller.NewAuditController(auditService)
+
 	// Cliente
 	clienteRepo := repository.NewClienteRepositorySQLite(db)
-	clienteService := service.NewClienteService(clienteRepo)
+	clienteService := service.NewClienteService(clienteRepo, auditRepo)
 	clienteController := controller.NewClienteController(clienteService)
 
 	// Produto
 	produtoRepo := repository.NewProdutoRepositorySQLite(db)
-	produtoService := service.NewProdutoService(produtoRepo)
+	produtoService := service.NewProdutoService(produtoRepo, auditRepo)
 	produtoController := controller.NewProdutoController(produtoService)
 
+	// Cupom
+	cupomRepo := repository.NewCupomRepositorySQLite(db)
+	cupomService := service.NewCupomService(cupomRepo)
+	cupomController := controller.NewCupomController(cupomService)
+
+	// Regras de imposto
+	regraImpostoRepo := repository.NewRegraImpostoRepositorySQLite(db)
+	regraImpostoService := service.NewRegraImpostoService(regraImpostoRepo)
+	regraImpostoController := controller.NewRegraImpostoController(regraImpostoService)
+
+	// Endereços
+	enderecoRepo := repository.NewEnderecoRepositorySQLite(db)
+	enderecoService := service.NewEnderecoService(enderecoRepo, clienteRepo)
+	enderecoController := controller.NewEnderecoController(enderecoService)
+
+	// Contatos
+	contatoRepo := repository.NewContatoRepositorySQLite(db)
+	contatoService := service.NewContatoService(contatoRepo, clienteRepo)
+	contatoController := controller.NewContatoController(contatoService)
+
+	// Frete
+	freteRepo := repository.NewFreteRepositorySQLite(db)
+	freteCalculator := frete.NewTabela(freteRepo)
+	freteService := service.NewFreteService(freteRepo, produtoRepo, freteCalculator)
+	freteController := controller.NewFreteController(freteService)
+
 	// Pedido
 	pedidoRepo := repository.NewPedidoRepositorySQLite(db)
-	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo)
+	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, cupomRepo, regraImpostoRepo, enderecoRepo, freteCalculator, auditRepo)
 	pedidoController := controller.NewPedidoController(pedidoService)
 
-	return clienteController, produtoController, pedidoController
+	// Pagamentos
+	pagamentoRepo := repository.NewPagamentoRepositorySQLite(db)
+	pagamentoService := service.NewPagamentoService(pagamentoRepo, pedidoRepo, testGateway, auditRepo)
+	pagamentoController := controller.NewPagamentoController(pagamentoService)
+
+	// Cancelamento e devoluções
+	devolucaoService := service.NewDevolucaoService(pedidoRepo, produtoRepo, cupomRepo, pagamentoRepo, testGateway, auditRepo)
+	devolucaoController := controller.NewDevolucaoController(devolucaoService)
+
+	// API keys
+	apiKeyRepo := repository.NewAPIKeyRepositorySQLite(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+
+	return controller.Controllers{
+		Cliente:      clienteController,
+		Produto:      produtoController,
+		Pedido:       pedidoController,
+		APIKey:       apiKeyController,
+		Cupom:        cupomController,
+		RegraImposto: regraImpostoController,
+		Endereco:     enderecoController,
+		Frete:        freteController,
+		Pagamento:    pagamentoController,
+		Devolucao:    devolucaoController,
+		Contato:      contatoController,
+		Audit:        auditController,
+	}
 }
 
 func TestCreateProduto_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	ativo := true
 	reqBody := dto.CreateProdutoRequest{
 		Nome:      "Notebook Dell",
 		Descricao: "Notebook Dell Inspiron 15",
-		Preco:     2999.99,
+		Preco:     money.MustParse("2999.99"),
 		Estoque:   10,
 		SKU:       "NB-DELL-001",
 		Categoria: "Eletrônicos",
@@ -82,14 +145,74 @@ func TestCreateProduto_Integration(t *testing.T) {
 	assert.NotZero(t, response.ID)
 }
 
+func TestCreateProduto_PrecoFormats_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	tests := []struct {
+		name         string
+		preco        string
+		expectedCode int
+		expected     string
+	}{
+		{"string", `"19.90"`, http.StatusCreated, "19.90"},
+		{"number", `19.9`, http.StatusCreated, "19.90"},
+		{"integer", `20`, http.StatusCreated, "20.00"},
+		{"too many decimals", `"19.999"`, http.StatusBadRequest, ""},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"nome": "Produto", "preco": %s, "sku": "SKU-%03d"}`, tt.preco, i)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/produtos", bytes.NewReader([]byte(body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusCreated {
+				var raw map[string]interface{}
+				json.NewDecoder(rec.Body).Decode(&raw)
+				assert.Equal(t, tt.expected, raw["preco"])
+			}
+		})
+	}
+}
+
+func TestCreateProduto_DuplicateSKU_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99")})
+
+	reqBody := dto.CreateProdutoRequest{
+		Nome:  "Outro Notebook",
+		Preco: money.MustParse("1999.99"),
+		SKU:   "NB-DELL-001",
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/produtos", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
 func TestGetAllProdutos_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: 100.00})
-	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: 200.00})
+	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: money.MustParse("100.00")})
+	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: money.MustParse("200.00")})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos", nil)
 	rec := httptest.NewRecorder()
@@ -98,18 +221,116 @@ func TestGetAllProdutos_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ProdutoResponse
+	var response dto.PageResponse[dto.ProdutoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
+	assert.Len(t, response.Data, 2)
+}
+
+func TestGetAllProdutos_CursorPagination_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	for i := 1; i <= 5; i++ {
+		db.Create(&model.Produto{Nome: fmt.Sprintf("Produto %d", i), SKU: fmt.Sprintf("PROD-%03d", i), Preco: money.MustParse("10.00")})
+	}
+
+	var ids []uint
+	url := "/api/v1/produtos?limit=2"
+	for paginas := 0; url != ""; paginas++ {
+		if paginas > 5 {
+			t.Fatal("paginação não terminou")
+		}
+
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.PageResponse[dto.ProdutoResponse]
+		json.NewDecoder(rec.Body).Decode(&response)
+		assert.Equal(t, int64(5), response.Total)
+		assert.Equal(t, 2, response.Limit)
+		for _, p := range response.Data {
+			ids = append(ids, p.ID)
+		}
+
+		url = ""
+		if response.NextCursor != "" {
+			assert.Contains(t, rec.Header().Get("Link"), `rel="next"`)
+			url = "/api/v1/produtos?limit=2&cursor=" + response.NextCursor
+		} else {
+			assert.NotContains(t, rec.Header().Get("Link"), `rel="next"`)
+		}
+	}
+
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, ids)
+}
+
+func TestGetAllProdutos_PagePagination_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	for i := 1; i <= 5; i++ {
+		db.Create(&model.Produto{Nome: fmt.Sprintf("Produto %d", i), SKU: fmt.Sprintf("PROD-%03d", i), Preco: money.MustParse("10.00")})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos?page=2&page_size=2", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.PageResponse[dto.ProdutoResponse]
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, uint(3), response.Data[0].ID)
+	assert.Equal(t, 2, response.Page)
+	assert.Equal(t, 3, response.NextPage)
+
+	link := rec.Header().Get("Link")
+	assert.Contains(t, link, `</api/v1/produtos?page=3&page_size=2>; rel="next"`)
+	assert.Contains(t, link, `</api/v1/produtos?page=1&page_size=2>; rel="prev"`)
+}
+
+func TestGetAllProdutos_MaxPageSize_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos?limit=100000", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.PageResponse[dto.ProdutoResponse]
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, repository.MaxPageSize, response.Limit)
+}
+
+func TestGetAllProdutos_InvalidPagination_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	for _, query := range []string{"limit=abc", "limit=0", "cursor=naoeumcursor", "page=0", "page=1&cursor=abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos?"+query, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
 }
 
 func TestGetProdutoByID_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	produto := &model.Produto{Nome: "Produto Teste", SKU: "PROD-TEST", Preco: 150.00}
+	produto := &model.Produto{Nome: "Produto Teste", SKU: "PROD-TEST", Preco: money.MustParse("150.00")}
 	db.Create(produto)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/1", nil)
@@ -126,12 +347,12 @@ func TestGetProdutoByID_Integration(t *testing.T) {
 
 func TestCountProdutos_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: 100.00})
-	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: 200.00})
+	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: money.MustParse("100.00")})
+	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: money.MustParse("200.00")})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/count", nil)
 	rec := httptest.NewRecorder()
@@ -147,16 +368,20 @@ func TestCountProdutos_Integration(t *testing.T) {
 
 func TestUpdateProduto_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	produto := &model.Produto{Nome: "Produto Original", SKU: "PROD-ORIG", Preco: 100.00}
+	produto := &model.Produto{Nome: "Produto Original", SKU: "PROD-ORIG", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
+	estoque, ativo := 5, true
 	reqBody := dto.UpdateProdutoRequest{
-		Nome:  "Produto Atualizado",
-		Preco: 150.00,
+		Nome:    "Produto Atualizado",
+		Preco:   money.MustParse("150.00"),
+		Estoque: &estoque,
+		SKU:     "PROD-ORIG",
+		Ativo:   &ativo,
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -171,16 +396,66 @@ func TestUpdateProduto_Integration(t *testing.T) {
 	var response dto.ProdutoResponse
 	json.NewDecoder(rec.Body).Decode(&response)
 	assert.Equal(t, "Produto Atualizado", response.Nome)
-	assert.Equal(t, 150.00, response.Preco)
+	assert.Equal(t, money.MustParse("150.00"), response.Preco)
+}
+
+func TestUpdateProduto_MissingEstoque_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	// Create test data
+	produto := &model.Produto{Nome: "Produto Original", SKU: "PROD-ORIG", Preco: money.MustParse("100.00"), Estoque: 10, Ativo: true}
+	db.Create(produto)
+
+	// PUT é uma substituição completa: sem o estoque a requisição é rejeitada
+	body := `{"nome": "Produto Atualizado", "sku": "PROD-ORIG", "preco": "100.00", "ativo": true}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/produtos/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var atual model.Produto
+	db.First(&atual, produto.ID)
+	assert.Equal(t, 10, atual.Estoque)
+}
+
+func TestPatchProduto_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	// Create test data
+	produto := &model.Produto{Nome: "Produto Original", Descricao: "Descrição", SKU: "PROD-ORIG", Preco: money.MustParse("100.00"), Estoque: 10, Categoria: "Eletrônicos", Ativo: true}
+	db.Create(produto)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/produtos/1", strings.NewReader(`{"nome": "Produto Atualizado", "categoria": null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.ProdutoResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	assert.Equal(t, "Produto Atualizado", response.Nome)
+	assert.Equal(t, 10, response.Estoque)
+	assert.Equal(t, "Descrição", response.Descricao)
+	assert.Empty(t, response.Categoria)
+	assert.True(t, response.Ativo)
 }
 
 func TestDeleteProduto_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	produto := &model.Produto{Nome: "Produto Para Deletar", SKU: "PROD-DEL", Preco: 100.00}
+	produto := &model.Produto{Nome: "Produto Para Deletar", SKU: "PROD-DEL", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/produtos/1", nil)
@@ -193,12 +468,12 @@ func TestDeleteProduto_Integration(t *testing.T) {
 
 func TestFindByNomeProduto_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL", Preco: 2999.99})
-	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-LOG", Preco: 99.99})
+	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL", Preco: money.MustParse("2999.99")})
+	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-LOG", Preco: money.MustParse("99.99")})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/nome/Notebook", nil)
 	rec := httptest.NewRecorder()
@@ -207,21 +482,21 @@ func TestFindByNomeProduto_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ProdutoResponse
+	var response dto.PageResponse[dto.ProdutoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 1)
-	assert.Equal(t, "Notebook Dell", response[0].Nome)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "Notebook Dell", response.Data[0].Nome)
 }
 
 func TestFindByCategoriaProduto_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Create test data
-	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL", Preco: 2999.99, Categoria: "Eletrônicos"})
-	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-LOG", Preco: 99.99, Categoria: "Eletrônicos"})
-	db.Create(&model.Produto{Nome: "Mesa", SKU: "MESA-001", Preco: 500.00, Categoria: "Móveis"})
+	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL", Preco: money.MustParse("2999.99"), Categoria: "Eletrônicos"})
+	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-LOG", Preco: money.MustParse("99.99"), Categoria: "Eletrônicos"})
+	db.Create(&model.Produto{Nome: "Mesa", SKU: "MESA-001", Preco: money.MustParse("500.00"), Categoria: "Móveis"})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/categoria/Eletrônicos", nil)
 	rec := httptest.NewRecorder()
@@ -230,16 +505,16 @@ func TestFindByCategoriaProduto_Integration(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response []dto.ProdutoResponse
+	var response dto.PageResponse[dto.ProdutoResponse]
 	json.NewDecoder(rec.Body).Decode(&response)
-	assert.Len(t, response, 2)
+	assert.Len(t, response.Data, 2)
 }
 
 // Testes de erro para aumentar cobertura
 func TestCreateProduto_InvalidJSON_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/produtos", bytes.NewReader([]byte("invalid")))
 	req.Header.Set("Content-Type", "application/json")
@@ -252,12 +527,12 @@ func TestCreateProduto_InvalidJSON_Integration(t *testing.T) {
 
 func TestCreateProduto_ValidationError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.CreateProdutoRequest{
 		Nome:  "", // Invalid
-		Preco: -10, // Invalid
+		Preco: money.MustParse("-10.00"), // Invalid
 		SKU:   "",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -268,13 +543,13 @@ func TestCreateProduto_ValidationError_Integration(t *testing.T) {
 
 	router.ServeHTTP(rec, req)
 
-	assert.True(t, rec.Code == http.StatusBadRequest || rec.Code == http.StatusInternalServerError)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
 func TestGetProdutoByID_InvalidID_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -286,8 +561,8 @@ func TestGetProdutoByID_InvalidID_Integration(t *testing.T) {
 
 func TestGetProdutoByID_NotFound_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos/9999", nil)
 	rec := httptest.NewRecorder()
@@ -299,8 +574,8 @@ func TestGetProdutoByID_NotFound_Integration(t *testing.T) {
 
 func TestUpdateProduto_InvalidJSON_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/produtos/1", bytes.NewReader([]byte("invalid")))
 	req.Header.Set("Content-Type", "application/json")
@@ -313,8 +588,8 @@ func TestUpdateProduto_InvalidJSON_Integration(t *testing.T) {
 
 func TestUpdateProduto_InvalidID_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	reqBody := dto.UpdateProdutoRequest{Nome: "Test"}
 	body, _ := json.Marshal(reqBody)
@@ -330,10 +605,11 @@ func TestUpdateProduto_InvalidID_Integration(t *testing.T) {
 
 func TestUpdateProduto_NotFound_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
-	reqBody := dto.UpdateProdutoRequest{Nome: "Test"}
+	estoque, ativo := 1, true
+	reqBody := dto.UpdateProdutoRequest{Nome: "Test", Preco: money.MustParse("10.00"), Estoque: &estoque, SKU: "TEST-001", Ativo: &ativo}
 	body, _ := json.Marshal(reqBody)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/produtos/9999", bytes.NewReader(body))
@@ -347,8 +623,8 @@ func TestUpdateProduto_NotFound_Integration(t *testing.T) {
 
 func TestDeleteProduto_InvalidID_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/produtos/invalid", nil)
 	rec := httptest.NewRecorder()
@@ -360,8 +636,8 @@ func TestDeleteProduto_InvalidID_Integration(t *testing.T) {
 
 func TestGetAllProdutos_DBError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -377,8 +653,8 @@ func TestGetAllProdutos_DBError_Integration(t *testing.T) {
 
 func TestCountProdutos_DBError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -394,8 +670,8 @@ func TestCountProdutos_DBError_Integration(t *testing.T) {
 
 func TestFindByNomeProduto_DBError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -411,8 +687,8 @@ func TestFindByNomeProduto_DBError_Integration(t *testing.T) {
 
 func TestFindByCategoriaProduto_DBError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
@@ -428,8 +704,8 @@ func TestFindByCategoriaProduto_DBError_Integration(t *testing.T) {
 
 func TestDeleteProduto_DBError_Integration(t *testing.T) {
 	db := setupProdutoTestDB(t)
-	clienteCtrl, produtoCtrl, pedidoCtrl := setupProdutoTestRouter(db)
-	router := controller.SetupRouter(clienteCtrl, produtoCtrl, pedidoCtrl)
+	controllers := setupProdutoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
 
 	// Close database to trigger error
 	sqlDB, _ := db.DB()
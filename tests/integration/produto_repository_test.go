@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
 	"github.com/danmaciel/api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
@@ -31,7 +33,7 @@ func TestProdutoRepository_Create(t *testing.T) {
 	produto := &model.Produto{
 		Nome:  "Test Produto",
 		SKU:   "TEST-001",
-		Preco: 100.00,
+		Preco: money.MustParse("100.00"),
 	}
 
 	err := repo.Create(context.Background(), produto)
@@ -43,19 +45,19 @@ func TestProdutoRepository_FindAll(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: 100.00})
-	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: 200.00})
+	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: money.MustParse("100.00")})
+	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: money.MustParse("200.00")})
 
-	produtos, err := repo.FindAll(context.Background())
+	produtos, err := repo.FindAll(context.Background(), repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, produtos, 2)
+	assert.Len(t, produtos.Items, 2)
 }
 
 func TestProdutoRepository_FindByID(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	created := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: 100.00}
+	created := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: money.MustParse("100.00")}
 	db.Create(created)
 
 	produto, err := repo.FindByID(context.Background(), created.ID)
@@ -70,7 +72,7 @@ func TestProdutoRepository_FindByID_NotFound(t *testing.T) {
 
 	_, err := repo.FindByID(context.Background(), 9999)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 }
 
 func TestProdutoRepository_FindByID_DBError(t *testing.T) {
@@ -89,20 +91,20 @@ func TestProdutoRepository_FindByName(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-001", Preco: 2999.99})
-	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-001", Preco: 99.99})
+	db.Create(&model.Produto{Nome: "Notebook Dell", SKU: "NB-001", Preco: money.MustParse("2999.99")})
+	db.Create(&model.Produto{Nome: "Mouse Logitech", SKU: "MS-001", Preco: money.MustParse("99.99")})
 
-	produtos, err := repo.FindByName(context.Background(), "Notebook")
+	produtos, err := repo.FindByName(context.Background(), "Notebook", repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, produtos, 1)
-	assert.Equal(t, "Notebook Dell", produtos[0].Nome)
+	assert.Len(t, produtos.Items, 1)
+	assert.Equal(t, "Notebook Dell", produtos.Items[0].Nome)
 }
 
 func TestProdutoRepository_FindBySKU(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	db.Create(&model.Produto{Nome: "Test", SKU: "TEST-001", Preco: 100.00})
+	db.Create(&model.Produto{Nome: "Test", SKU: "TEST-001", Preco: money.MustParse("100.00")})
 
 	produto, err := repo.FindBySKU(context.Background(), "TEST-001")
 	assert.NoError(t, err)
@@ -135,20 +137,20 @@ func TestProdutoRepository_FindByCategoria(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	db.Create(&model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: 2999.99, Categoria: "Eletrônicos"})
-	db.Create(&model.Produto{Nome: "Mouse", SKU: "MS-001", Preco: 99.99, Categoria: "Eletrônicos"})
-	db.Create(&model.Produto{Nome: "Mesa", SKU: "MESA-001", Preco: 500.00, Categoria: "Móveis"})
+	db.Create(&model.Produto{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("2999.99"), Categoria: "Eletrônicos"})
+	db.Create(&model.Produto{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("99.99"), Categoria: "Eletrônicos"})
+	db.Create(&model.Produto{Nome: "Mesa", SKU: "MESA-001", Preco: money.MustParse("500.00"), Categoria: "Móveis"})
 
-	produtos, err := repo.FindByCategoria(context.Background(), "Eletrônicos")
+	produtos, err := repo.FindByCategoria(context.Background(), "Eletrônicos", repository.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, produtos, 2)
+	assert.Len(t, produtos.Items, 2)
 }
 
 func TestProdutoRepository_Update(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	produto := &model.Produto{Nome: "Original", SKU: "TEST-001", Preco: 100.00}
+	produto := &model.Produto{Nome: "Original", SKU: "TEST-001", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	produto.Nome = "Updated"
@@ -160,6 +162,35 @@ func TestProdutoRepository_Update(t *testing.T) {
 	assert.Equal(t, "Updated", updated.Nome)
 }
 
+func TestProdutoRepository_Update_StaleVersion(t *testing.T) {
+	db := setupProdutoRepoTestDB(t)
+	repo := repository.NewProdutoRepositorySQLite(db)
+
+	produto := &model.Produto{Nome: "Original", SKU: "TEST-001", Preco: money.MustParse("100.00"), Estoque: 10}
+	db.Create(produto)
+	assert.Equal(t, uint(1), produto.Versao)
+
+	// duas requisições leem a mesma versão do produto
+	var primeira, segunda model.Produto
+	db.First(&primeira, produto.ID)
+	db.First(&segunda, produto.ID)
+
+	primeira.Estoque = 7
+	assert.NoError(t, repo.Update(context.Background(), &primeira))
+	assert.Equal(t, uint(2), primeira.Versao)
+
+	// a segunda gravação não sobrescreve a primeira
+	segunda.Estoque = 3
+	err := repo.Update(context.Background(), &segunda)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.Equal(t, uint(1), segunda.Versao)
+
+	var atual model.Produto
+	db.First(&atual, produto.ID)
+	assert.Equal(t, 7, atual.Estoque)
+	assert.Equal(t, uint(2), atual.Versao)
+}
+
 func TestProdutoRepository_Update_NotFound(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
@@ -167,7 +198,7 @@ func TestProdutoRepository_Update_NotFound(t *testing.T) {
 	// GORM's Save() is an upsert operation - it will insert if record doesn't exist
 	// To test the "not found" case, we need to ensure the record was previously in DB
 	// Create a record
-	produto := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: 100.00}
+	produto := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	// Now delete it
@@ -186,7 +217,7 @@ func TestProdutoRepository_Update_DBError(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	produto := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: 100.00}
+	produto := &model.Produto{Nome: "Test", SKU: "TEST-001", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	// Close database to simulate error
@@ -201,7 +232,7 @@ func TestProdutoRepository_Delete(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	produto := &model.Produto{Nome: "To Delete", SKU: "DEL-001", Preco: 100.00}
+	produto := &model.Produto{Nome: "To Delete", SKU: "DEL-001", Preco: money.MustParse("100.00")}
 	db.Create(produto)
 
 	err := repo.Delete(context.Background(), produto.ID)
@@ -218,7 +249,7 @@ func TestProdutoRepository_Delete_NotFound(t *testing.T) {
 
 	err := repo.Delete(context.Background(), 9999)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 }
 
 func TestProdutoRepository_Delete_DBError(t *testing.T) {
@@ -237,10 +268,53 @@ func TestProdutoRepository_Count(t *testing.T) {
 	db := setupProdutoRepoTestDB(t)
 	repo := repository.NewProdutoRepositorySQLite(db)
 
-	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: 100.00})
-	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: 200.00})
+	db.Create(&model.Produto{Nome: "Produto 1", SKU: "PROD-001", Preco: money.MustParse("100.00")})
+	db.Create(&model.Produto{Nome: "Produto 2", SKU: "PROD-002", Preco: money.MustParse("200.00")})
 
 	count, err := repo.Count(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), count)
 }
+
+func TestProdutoRepository_DecrementEstoque(t *testing.T) {
+	db := setupProdutoRepoTestDB(t)
+	repo := repository.NewProdutoRepositorySQLite(db)
+
+	produto := &model.Produto{Nome: "Produto", SKU: "SKU-001", Preco: money.MustParse("10.00"), Estoque: 5}
+	db.Create(produto)
+
+	err := repo.DecrementEstoque(context.Background(), produto.ID, 3)
+	assert.NoError(t, err)
+
+	err = repo.DecrementEstoque(context.Background(), produto.ID, 3)
+	assert.ErrorIs(t, err, repository.ErrEstoqueInsuficiente)
+
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 2, atualizado.Estoque)
+	assert.Equal(t, uint(2), atualizado.Versao, "a baixa de estoque invalida a versão lida antes")
+}
+
+func TestProdutoRepository_DecrementEstoque_NotFound(t *testing.T) {
+	db := setupProdutoRepoTestDB(t)
+	repo := repository.NewProdutoRepositorySQLite(db)
+
+	err := repo.DecrementEstoque(context.Background(), 9999, 1)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestProdutoRepository_IncrementEstoque(t *testing.T) {
+	db := setupProdutoRepoTestDB(t)
+	repo := repository.NewProdutoRepositorySQLite(db)
+
+	produto := &model.Produto{Nome: "Produto", SKU: "SKU-001", Preco: money.MustParse("10.00"), Estoque: 5}
+	db.Create(produto)
+
+	err := repo.IncrementEstoque(context.Background(), produto.ID, 4)
+	assert.NoError(t, err)
+
+	var atualizado model.Produto
+	db.First(&atualizado, produto.ID)
+	assert.Equal(t, 9, atualizado.Estoque)
+}
@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupQueryTestRouter(t *testing.T) *chi.Mux {
+	db := setupProdutoTestDB(t)
+	controllers := setupProdutoTestRouter(db)
+
+	produtos := []model.Produto{
+		{Nome: "Teclado", SKU: "TC-001", Preco: money.MustParse("150.00"), Estoque: 5, Categoria: "Periféricos", Ativo: true},
+		{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("80.00"), Estoque: 0, Categoria: "Periféricos", Ativo: true},
+		{Nome: "Monitor", SKU: "MN-001", Preco: money.MustParse("1200.00"), Estoque: 3, Categoria: "Monitores", Ativo: true},
+		{Nome: "Notebook", SKU: "NB-001", Preco: money.MustParse("4500.00"), Estoque: 2, Categoria: "Computadores", Ativo: false},
+		{Nome: "Headset", SKU: "HS-001", Preco: money.MustParse("150.00"), Estoque: 7, Categoria: "Áudio", Ativo: true},
+	}
+	for i := range produtos {
+		db.Create(&produtos[i])
+	}
+	// db.Create ignora o false de Ativo por causa do default:true da coluna
+	db.Model(&model.Produto{}).Where("sku = ?", "NB-001").Update("ativo", false)
+
+	return controller.SetupRouter(controllers, middleware.Anonymous)
+}
+
+func getProdutos(router *chi.Mux, query url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/produtos?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func nomes(page dto.ProdutoPageResponse) []string {
+	result := make([]string, len(page.Data))
+	for i, p := range page.Data {
+		result[i] = p.Nome
+	}
+	return result
+}
+
+func TestListProdutos_FilterAndSort_Integration(t *testing.T) {
+	router := setupQueryTestRouter(t)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []string
+	}{
+		{"faixa de preço", url.Values{"filter[preco][gte]": {"100"}, "filter[preco][lt]": {"2000.00"}}, []string{"Teclado", "Monitor", "Headset"}},
+		{"categoria em lista", url.Values{"filter[categoria][in]": {"Periféricos,Áudio"}}, []string{"Teclado", "Mouse", "Headset"}},
+		{"igualdade sem operador", url.Values{"filter[ativo]": {"false"}}, []string{"Notebook"}},
+		{"texto parcial", url.Values{"filter[nome][like]": {"Mo"}}, []string{"Mouse", "Monitor"}},
+		{"ordem decrescente com desempate", url.Values{"sort": {"-preco,nome"}}, []string{"Notebook", "Monitor", "Headset", "Teclado", "Mouse"}},
+		{"filtro e ordenação", url.Values{"filter[estoque][gt]": {"0"}, "sort": {"estoque"}}, []string{"Notebook", "Monitor", "Teclado", "Headset"}},
+		{"data de criação", url.Values{"filter[created_at][gte]": {"2000-01-01"}, "filter[created_at][lt]": {"2999-01-01T00:00:00Z"}}, []string{"Teclado", "Mouse", "Monitor", "Notebook", "Headset"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getProdutos(router, tt.query)
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			var page dto.ProdutoPageResponse
+			json.NewDecoder(rec.Body).Decode(&page)
+			assert.Equal(t, tt.want, nomes(page))
+			assert.Equal(t, int64(len(tt.want)), page.Total)
+		})
+	}
+}
+
+func TestListProdutos_SortedCursorPagination_Integration(t *testing.T) {
+	router := setupQueryTestRouter(t)
+
+	var vistos []string
+	query := url.Values{"sort": {"-preco,nome"}, "limit": {"2"}}
+	for i := 0; i < 5; i++ {
+		rec := getProdutos(router, query)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var page dto.ProdutoPageResponse
+		json.NewDecoder(rec.Body).Decode(&page)
+		vistos = append(vistos, nomes(page)...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+
+	// o empate de preço entre Headset e Teclado atravessa a página sem repetir nem pular itens
+	assert.Equal(t, []string{"Notebook", "Monitor", "Headset", "Teclado", "Mouse"}, vistos)
+
+	// o cursor não vale para outra ordenação
+	query.Set("sort", "nome")
+	rec := getProdutos(router, query)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestListProdutos_Fields_Integration(t *testing.T) {
+	router := setupQueryTestRouter(t)
+
+	rec := getProdutos(router, url.Values{"fields": {"id,nome,preco"}, "limit": {"1"}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page struct {
+		Data  []map[string]interface{} `json:"data"`
+		Total int64                    `json:"total"`
+	}
+	json.NewDecoder(rec.Body).Decode(&page)
+	assert.Equal(t, int64(5), page.Total)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "nome": "Teclado", "preco": "150.00"}, page.Data[0])
+}
+
+func TestListProdutos_InvalidQuery_Integration(t *testing.T) {
+	router := setupQueryTestRouter(t)
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"campo fora da whitelist", url.Values{"filter[descricao]": {"x"}}},
+		{"operador desconhecido", url.Values{"filter[preco][between]": {"1"}}},
+		{"operador incompatível com o tipo", url.Values{"filter[nome][gt]": {"a"}}},
+		{"valor com tipo errado", url.Values{"filter[estoque][gte]": {"muitos"}}},
+		{"filtro malformado", url.Values{"filter[preco": {"1"}}},
+		{"ordenação desconhecida", url.Values{"sort": {"-senha"}}},
+		{"ordenação repetida", url.Values{"sort": {"nome,-nome"}}},
+		{"campo de resposta desconhecido", url.Values{"fields": {"id,custo"}}},
+		{"injeção no nome do campo", url.Values{"sort": {"nome;DROP TABLE produtos"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getProdutos(router, tt.query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestListPedidos_FilterByStatus_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	controllers := setupPedidoTestRouter(db)
+	router := controller.SetupRouter(controllers, middleware.Anonymous)
+
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678901"}
+	db.Create(cliente)
+	for _, status := range []string{model.StatusPendente, model.StatusPago, model.StatusCancelado} {
+		db.Create(&model.Pedido{ClienteID: cliente.ID, Status: status, ValorTotal: money.MustParse("10.00")})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pedidos?filter[status][in]=pendente,pago&sort=-id", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var page dto.PedidoPageResponse
+	json.NewDecoder(rec.Body).Decode(&page)
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, model.StatusPago, page.Data[0].Status)
+	assert.Equal(t, model.StatusPendente, page.Data[1].Status)
+}
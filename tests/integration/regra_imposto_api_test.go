@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRegraImposto(t *testing.T, router *chi.Mux, req dto.CreateRegraImpostoRequest) dto.RegraImpostoResponse {
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/impostos/regras", req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var regra dto.RegraImpostoResponse
+	json.NewDecoder(rec.Body).Decode(&regra)
+	return regra
+}
+
+func TestRegraImposto_CRUD_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	router := controller.SetupRouter(setupPedidoTestRouter(db), middleware.Anonymous)
+
+	regra := createRegraImposto(t, router, dto.CreateRegraImpostoRequest{
+		Imposto:  "icms",
+		UF:       "sp",
+		Aliquota: money.Aliquota(1800),
+		Modo:     model.ImpostoInclusivo,
+	})
+	assert.Equal(t, "ICMS", regra.Imposto)
+	assert.Equal(t, "SP", regra.UF)
+	assert.Equal(t, money.Aliquota(1800), regra.Aliquota)
+
+	// a alíquota trafega como decimal, igual aos valores monetários
+	rec := sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/impostos/regras/%d", regra.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var raw map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&raw)
+	assert.Equal(t, "18.00", raw["aliquota"])
+
+	// mesma chave (imposto, categoria, UF)
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/impostos/regras", dto.CreateRegraImpostoRequest{
+		Imposto: "ICMS", UF: "SP", Aliquota: money.Aliquota(1200), Modo: model.ImpostoInclusivo,
+	}, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	ativo := false
+	rec = sendWithHeaders(router, http.MethodPut, fmt.Sprintf("/api/v1/impostos/regras/%d", regra.ID), dto.UpdateRegraImpostoRequest{
+		Imposto: "ICMS", UF: "SP", Aliquota: money.Aliquota(1200), Modo: model.ImpostoInclusivo, Ativo: &ativo,
+	}, map[string]string{"If-Match": `"1"`})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/impostos/regras?filter[uf]=SP&filter[ativo]=false", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page dto.RegraImpostoPageResponse
+	json.NewDecoder(rec.Body).Decode(&page)
+	if assert.Len(t, page.Data, 1) {
+		assert.Equal(t, money.Aliquota(1200), page.Data[0].Aliquota)
+	}
+
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/impostos/regras/%d", regra.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/impostos/regras/%d", regra.ID), nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRegraImposto_ValidationError_Integration(t *testing.T) {
+	db := setupPedidoTestDB(t)
+	router := controller.SetupRouter(setupPedidoTestRouter(db), middleware.Anonymous)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/impostos/regras", map[string]interface{}{
+		"imposto": "ICMS", "uf": "ZZ", "aliquota": "150.00", "modo": "inclusivo",
+	}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "uf", problem.Errors[0].Field)
+		assert.Equal(t, "deve ser a sigla de uma UF, por exemplo SP", problem.Errors[0].Message)
+		assert.Equal(t, "aliquota", problem.Errors[1].Field)
+		assert.Equal(t, "deve ser um percentual entre 0 e 100", problem.Errors[1].Message)
+	}
+}
+
+func TestCreatePedido_ComImpostos_Integration(t *testing.T) {
+	router, db, cliente, notebook, livro := setupCupomTestRouter(t)
+
+	createRegraImposto(t, router, dto.CreateRegraImpostoRequest{Imposto: "ICMS", Aliquota: money.Aliquota(1800), Modo: model.ImpostoInclusivo})
+	createRegraImposto(t, router, dto.CreateRegraImpostoRequest{Imposto: "ICMS", UF: "RJ", Aliquota: money.Aliquota(2000), Modo: model.ImpostoInclusivo})
+	createRegraImposto(t, router, dto.CreateRegraImpostoRequest{Imposto: "IPI", Categoria: "Eletrônicos", Aliquota: money.Aliquota(1000), Modo: model.ImpostoExclusivo})
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/pedidos", dto.CreatePedidoRequest{
+		ClienteID: cliente.ID,
+		UFDestino: "rj",
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: notebook.ID, Quantidade: 1},
+			{ProdutoID: livro.ID, Quantidade: 2},
+		},
+	}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var criado dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&criado)
+
+	// os impostos são gravados por item e voltam na consulta
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", criado.ID), nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var pedido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&pedido)
+
+	assert.Equal(t, "RJ", pedido.UFDestino)
+	assert.Equal(t, money.MustParse("2100.00"), pedido.Subtotal)
+	assert.Equal(t, money.MustParse("620.00"), pedido.Imposto)
+	// apenas o IPI é acrescido; o ICMS já está nos preços
+	assert.Equal(t, money.MustParse("2300.00"), pedido.ValorTotal)
+
+	if assert.Len(t, pedido.Itens, 2) {
+		assert.Equal(t, money.MustParse("600.00"), pedido.Itens[0].Imposto)
+		assert.Len(t, pedido.Itens[0].Impostos, 2)
+		assert.Equal(t, money.MustParse("20.00"), pedido.Itens[1].Imposto)
+		if assert.Len(t, pedido.Itens[1].Impostos, 1) {
+			assert.Equal(t, money.Aliquota(2000), pedido.Itens[1].Impostos[0].Aliquota)
+		}
+	}
+	assert.Equal(t, []dto.ImpostoResumoResponse{
+		{Imposto: "ICMS", Modo: model.ImpostoInclusivo, Base: money.MustParse("2100.00"), Valor: money.MustParse("420.00")},
+		{Imposto: "IPI", Modo: model.ImpostoExclusivo, Base: money.MustParse("2000.00"), Valor: money.MustParse("200.00")},
+	}, pedido.Impostos)
+
+	// alterar a regra depois não muda o pedido já criado
+	db.Model(&model.RegraImposto{}).Where("imposto = ?", "IPI").Update("aliquota", money.Aliquota(5000))
+	rec = sendWithHeaders(router, http.MethodGet, fmt.Sprintf("/api/v1/pedidos/%d", criado.ID), nil, nil)
+	var relido dto.PedidoResponse
+	json.NewDecoder(rec.Body).Decode(&relido)
+	assert.Equal(t, money.MustParse("620.00"), relido.Imposto)
+	assert.Equal(t, money.Aliquota(1000), relido.Itens[0].Impostos[1].Aliquota)
+}
+
+func TestRegraImposto_OperadorNaoGerencia_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/impostos/regras", dto.CreateRegraImpostoRequest{
+		Imposto: "ICMS", Aliquota: money.Aliquota(1800), Modo: model.ImpostoInclusivo,
+	}, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/impostos/regras", nil, operador)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupSearchTestRouter monta o roteador com a busca sobre db; o índice FTS5
+// só existe se o teste chamar repository.CreateSearchIndex
+func setupSearchTestRouter(db *gorm.DB) *chi.Mux {
+	controllers := setupProdutoTestRouter(db)
+	searchService := service.NewSearchService(repository.NewSearchRepositorySQLite(db))
+	controllers.Search = controller.NewSearchController(searchService)
+	return controller.SetupRouter(controllers, middleware.Anonymous)
+}
+
+func search(router *chi.Mux, query url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSearch_InvalidQuery_Integration(t *testing.T) {
+	router := setupSearchTestRouter(setupProdutoTestDB(t))
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"sem q", url.Values{}},
+		{"apenas pontuação", url.Values{"q": {"?!-"}}},
+		{"tipo desconhecido", url.Values{"q": {"mouse"}, "tipos": {"pedidos"}}},
+		{"limit inválido", url.Values{"q": {"mouse"}, "limit": {"0"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := search(router, tt.query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestSearch_WithoutIndex_Integration(t *testing.T) {
+	// sem o índice (SQLite sem FTS5) a busca responde 503 e o resto da API segue funcionando
+	router := setupSearchTestRouter(setupProdutoTestDB(t))
+
+	rec := search(router, url.Values{"q": {"mouse"}})
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/problems/service-unavailable")
+}
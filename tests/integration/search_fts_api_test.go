@@ -0,0 +1,139 @@
+//go:build sqlite_fts5
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Estes testes exigem o SQLite com FTS5: go test -tags sqlite_fts5 ./...
+
+func setupFTSTestDB(t *testing.T) *gorm.DB {
+	db := setupProdutoTestDB(t)
+	if err := repository.CreateSearchIndex(db); err != nil {
+		t.Fatalf("Failed to create search index: %v", err)
+	}
+
+	db.Create(&model.Produto{Nome: "Cafeteira Elétrica", Descricao: "Prepara até 30 cafezinhos", SKU: "CF-001", Preco: money.MustParse("199.90"), Categoria: "Cozinha", Ativo: true})
+	db.Create(&model.Produto{Nome: "Café Torrado", Descricao: "Pacote de 500g", SKU: "CF-002", Preco: money.MustParse("25.00"), Categoria: "Alimentos", Ativo: true})
+	db.Create(&model.Produto{Nome: "Mouse Óptico", Descricao: "Ideal para quem toma café no teclado", SKU: "MS-001", Preco: money.MustParse("80.00"), Categoria: "Periféricos", Ativo: true})
+	db.Create(&model.Cliente{Nome: "João da Silva", Email: "joao@example.com", CPF: "12345678901"})
+	db.Create(&model.Cliente{Nome: "Maria Conceição", Email: "maria@example.com", CPF: "98765432100"})
+	return db
+}
+
+func searchResults(t *testing.T, router *chi.Mux, query url.Values) []dto.SearchResult {
+	rec := search(router, query)
+	if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+		return nil
+	}
+	var response dto.SearchResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	return response.Data
+}
+
+func hrefs(results []dto.SearchResult) []string {
+	result := make([]string, len(results))
+	for i, r := range results {
+		result[i] = r.Href
+	}
+	return result
+}
+
+func TestSearch_Integration(t *testing.T) {
+	router := setupSearchTestRouter(setupFTSTestDB(t))
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []string
+	}{
+		{"ignora acentos", url.Values{"q": {"Joao"}}, []string{"/api/v1/clientes/1"}},
+		{"ignora maiúsculas e acentos na consulta", url.Values{"q": {"CONCEIÇAO"}}, []string{"/api/v1/clientes/2"}},
+		{"prefixo para autocomplete", url.Values{"q": {"cafet"}}, []string{"/api/v1/produtos/1"}},
+		{"todos os termos", url.Values{"q": {"café torr"}}, []string{"/api/v1/produtos/2"}},
+		{"sku", url.Values{"q": {"MS-001"}}, []string{"/api/v1/produtos/3"}},
+		{"email", url.Values{"q": {"maria@example"}}, []string{"/api/v1/clientes/2"}},
+		{"filtro por tipo", url.Values{"q": {"ma"}, "tipos": {"clientes"}}, []string{"/api/v1/clientes/2"}},
+		{"sem resultados", url.Values{"q": {"geladeira"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hrefs(searchResults(t, router, tt.query)))
+		})
+	}
+}
+
+func TestSearch_RankingAndHighlight_Integration(t *testing.T) {
+	router := setupSearchTestRouter(setupFTSTestDB(t))
+
+	results := searchResults(t, router, url.Values{"q": {"cafe"}})
+
+	// a palavra inteira vale mais que o prefixo e o nome mais que a descrição:
+	// "Café Torrado", depois o mouse que cita café na descrição e por fim a cafeteira
+	assert.Equal(t, []string{"/api/v1/produtos/2", "/api/v1/produtos/3", "/api/v1/produtos/1"}, hrefs(results))
+	assert.Equal(t, "<mark>Café</mark> Torrado", results[0].Titulo)
+	assert.Contains(t, results[1].Trecho, "<mark>café</mark>")
+	assert.Equal(t, "<mark>Cafeteira</mark> Elétrica", results[2].Titulo)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	assert.Greater(t, results[1].Score, results[2].Score)
+}
+
+func TestSearch_IndexFollowsChanges_Integration(t *testing.T) {
+	db := setupFTSTestDB(t)
+	router := setupSearchTestRouter(db)
+
+	// alteração reindexa o registro
+	db.Model(&model.Produto{}).Where("id = ?", 3).Update("nome", "Mouse Sem Fio")
+	assert.Equal(t, []string{"/api/v1/produtos/3"}, hrefs(searchResults(t, router, url.Values{"q": {"sem fio"}})))
+	assert.Empty(t, searchResults(t, router, url.Values{"q": {"optico"}}))
+
+	// registros excluídos (soft delete) somem da busca
+	db.Delete(&model.Cliente{}, 1)
+	assert.Empty(t, searchResults(t, router, url.Values{"q": {"joao"}}))
+
+	// o índice criado sobre dados existentes é populado e recriar não duplica
+	assert.NoError(t, repository.CreateSearchIndex(db))
+	assert.Equal(t, []string{"/api/v1/clientes/2"}, hrefs(searchResults(t, router, url.Values{"q": {"maria"}})))
+}
+
+func TestSearch_ClientePessoaJuridica_Integration(t *testing.T) {
+	db := setupProdutoTestDB(t)
+
+	// índice criado antes das colunas de PJ, que precisa ser recriado
+	for _, sql := range []string{
+		`CREATE VIRTUAL TABLE clientes_fts USING fts5(nome, email, cpf, telefone, content='clientes', content_rowid='id')`,
+		`CREATE TRIGGER clientes_fts_ai AFTER INSERT ON clientes BEGIN INSERT INTO clientes_fts(rowid, nome, email, cpf, telefone) VALUES (new.id, new.nome, new.email, new.cpf, new.telefone); END`,
+	} {
+		assert.NoError(t, db.Exec(sql).Error)
+	}
+	db.Create(&model.Cliente{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Acme Comércio Ltda", NomeFantasia: "Lojas Acme", Email: "contato@acme.com.br", CNPJ: "11222333000181"})
+
+	assert.NoError(t, repository.CreateSearchIndex(db))
+	db.Create(&model.Cliente{Nome: "João da Silva", Email: "joao@example.com", CPF: "52998224725"})
+	router := setupSearchTestRouter(db)
+
+	// o título de um cliente PJ é a razão social
+	results := searchResults(t, router, url.Values{"q": {"comercio"}})
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "<mark>Acme</mark> Comércio Ltda", searchResults(t, router, url.Values{"q": {"acme"}})[0].Titulo)
+		assert.Equal(t, "Acme <mark>Comércio</mark> Ltda", results[0].Titulo)
+	}
+	assert.Equal(t, []string{"/api/v1/clientes/1"}, hrefs(searchResults(t, router, url.Values{"q": {"lojas"}})))
+	assert.Equal(t, []string{"/api/v1/clientes/1"}, hrefs(searchResults(t, router, url.Values{"q": {"11222333000181"}})))
+	if results := searchResults(t, router, url.Values{"q": {"joao"}}); assert.Len(t, results, 1) {
+		assert.Equal(t, "<mark>João</mark> da Silva", results[0].Titulo)
+	}
+}
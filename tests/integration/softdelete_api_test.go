@@ -0,0 +1,222 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/controller"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupSoftDeleteTestRouter(t *testing.T) (*chi.Mux, *gorm.DB) {
+	db := setupTestDB(t)
+	return controller.SetupRouter(setupTestRouter(db), middleware.Anonymous), db
+}
+
+func createClienteAPI(t *testing.T, router *chi.Mux, req dto.CreateClienteRequest) dto.ClienteResponse {
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/clientes", req, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var cliente dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&cliente)
+	return cliente
+}
+
+func listClientes(t *testing.T, router *chi.Mux, query string) dto.PageResponse[dto.ClienteResponse] {
+	rec := sendWithHeaders(router, http.MethodGet, "/api/v1/clientes"+query, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page dto.PageResponse[dto.ClienteResponse]
+	json.NewDecoder(rec.Body).Decode(&page)
+	return page
+}
+
+func TestCliente_ListDeleted_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	ativo := createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"})
+	excluido := createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "João Souza", Email: "joao@example.com", CPF: "12345678909"})
+
+	rec := sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/clientes/%d", excluido.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	page := listClientes(t, router, "")
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, ativo.ID, page.Data[0].ID)
+	assert.Empty(t, page.Data[0].DeletedAt)
+
+	page = listClientes(t, router, "?deleted=only")
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, excluido.ID, page.Data[0].ID)
+	assert.NotEmpty(t, page.Data[0].DeletedAt)
+
+	page = listClientes(t, router, "?deleted=include&sort=nome")
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, excluido.ID, page.Data[0].ID)
+	assert.Equal(t, ativo.ID, page.Data[1].ID)
+
+	// filtros e paginação continuam valendo sobre os excluídos
+	page = listClientes(t, router, "?deleted=include&filter[email]=maria@example.com")
+	assert.Equal(t, int64(1), page.Total)
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/clientes?deleted=all", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCliente_Restore_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	cliente := createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"})
+	target := fmt.Sprintf("/api/v1/clientes/%d", cliente.ID)
+
+	// um cliente ativo não pode ser restaurado
+	rec := sendWithHeaders(router, http.MethodPost, target+"/restore", nil, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, target+"/restore", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var restaurado dto.ClienteResponse
+	json.NewDecoder(rec.Body).Decode(&restaurado)
+	assert.Equal(t, cliente.Email, restaurado.Email)
+	assert.Empty(t, restaurado.DeletedAt)
+	assert.Equal(t, cliente.Versao+1, restaurado.Versao)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	rec = sendWithHeaders(router, http.MethodGet, target, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/clientes/999/restore", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCliente_RecreateAfterDelete_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	req := dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"}
+	antigo := createClienteAPI(t, router, req)
+
+	rec := sendWithHeaders(router, http.MethodPost, "/api/v1/clientes", req, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// o cliente excluído não impede recadastrar o mesmo email e CPF
+	rec = sendWithHeaders(router, http.MethodDelete, fmt.Sprintf("/api/v1/clientes/%d", antigo.ID), nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	novo := createClienteAPI(t, router, req)
+	assert.NotEqual(t, antigo.ID, novo.ID)
+
+	// mas restaurar o antigo voltaria a duplicá-los
+	rec = sendWithHeaders(router, http.MethodPost, fmt.Sprintf("/api/v1/clientes/%d/restore", antigo.ID), nil, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var problem dto.ProblemDetails
+	json.NewDecoder(rec.Body).Decode(&problem)
+	assert.Equal(t, "cliente com email já cadastrado", problem.Detail)
+}
+
+func TestCliente_Purge_Integration(t *testing.T) {
+	router, db := setupSoftDeleteTestRouter(t)
+
+	cliente := createClienteAPI(t, router, dto.CreateClienteRequest{Nome: "Maria Silva", Email: "maria@example.com", CPF: "52998224725"})
+	db.Create(&model.Endereco{ClienteID: cliente.ID, CEP: "01310100", Logradouro: "Avenida Paulista", Numero: "1000", Bairro: "Bela Vista", Cidade: "São Paulo", UF: "SP", Padrao: true})
+	target := fmt.Sprintf("/api/v1/clientes/%d", cliente.ID)
+
+	rec := sendWithHeaders(router, http.MethodDelete, target+"?purge=sim", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?purge=true", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// nem a listagem dos excluídos nem a restauração encontram o cliente
+	page := listClientes(t, router, "?deleted=include")
+	assert.Equal(t, int64(0), page.Total)
+	rec = sendWithHeaders(router, http.MethodPost, target+"/restore", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var enderecos int64
+	db.Unscoped().Model(&model.Endereco{}).Where("cliente_id = ?", cliente.ID).Count(&enderecos)
+	assert.Equal(t, int64(0), enderecos)
+
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?purge=true", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestProduto_PurgeDeleted_Integration(t *testing.T) {
+	router, db := setupSoftDeleteTestRouter(t)
+
+	produto := &model.Produto{Nome: "Mouse", SKU: "MS-001", Preco: money.MustParse("99.90"), Estoque: 10, Ativo: true}
+	db.Create(produto)
+	target := fmt.Sprintf("/api/v1/produtos/%d", produto.ID)
+
+	rec := sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// o SKU do produto excluído fica livre
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos", dto.CreateProdutoRequest{Nome: "Mouse sem fio", SKU: "MS-001", Preco: money.MustParse("129.90"), Estoque: 5}, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/produtos?deleted=only", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var page dto.PageResponse[dto.ProdutoResponse]
+	json.NewDecoder(rec.Body).Decode(&page)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, produto.ID, page.Data[0].ID)
+	assert.NotNil(t, page.Data[0].DeletedAt)
+
+	// um produto já excluído também pode ser removido definitivamente
+	rec = sendWithHeaders(router, http.MethodDelete, target+"?purge=true", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	var count int64
+	db.Unscoped().Model(&model.Produto{}).Where("id = ?", produto.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestCupom_RestoreDeleted_Integration(t *testing.T) {
+	router, _ := setupSoftDeleteTestRouter(t)
+
+	cupom := createCupom(t, router, dto.CreateCupomRequest{Codigo: "FRETEGRATIS", Tipo: model.CupomFreteGratis})
+	target := fmt.Sprintf("/api/v1/cupons/%d", cupom.ID)
+
+	rec := sendWithHeaders(router, http.MethodDelete, target, nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodPost, target+"/restore", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var restaurado dto.CupomResponse
+	json.NewDecoder(rec.Body).Decode(&restaurado)
+	assert.Equal(t, "FRETEGRATIS", restaurado.Codigo)
+	assert.Nil(t, restaurado.DeletedAt)
+}
+
+func TestRestoreAndPurge_RequireAdmin_Integration(t *testing.T) {
+	router, issue := setupAuthTestRouter(t)
+	operador := map[string]string{"Authorization": issue(auth.RoleOperador)}
+	admin := map[string]string{"Authorization": issue(auth.RoleAdmin)}
+
+	rec := sendWithHeaders(router, http.MethodDelete, "/api/v1/produtos/1?purge=true", nil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos/1/restore", nil, operador)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// leitores veem os excluídos
+	rec = sendWithHeaders(router, http.MethodGet, "/api/v1/produtos?deleted=include", nil, operador)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = sendWithHeaders(router, http.MethodDelete, "/api/v1/produtos/1", nil, admin)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = sendWithHeaders(router, http.MethodPost, "/api/v1/produtos/1/restore", nil, admin)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.APIKey], error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Page[model.APIKey]), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Update(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAPIKeyService_Create_StoresOnlyHash(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	var stored *model.APIKey
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.APIKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*model.APIKey) }).
+		Return(nil)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleAdmin}})
+	req := &dto.CreateAPIKeyRequest{Nome: "Conector ERP", Scopes: []string{"pedidos:write", "produtos:read", "pedidos:write"}}
+
+	result, err := svc.Create(ctx, req)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Key, result.Prefixo+"_"))
+	assert.Equal(t, []string{"pedidos:write", "produtos:read"}, result.Scopes)
+	assert.Equal(t, "admin", result.CriadoPor)
+	assert.Equal(t, sha256Hex(result.Key), stored.Hash)
+	assert.NotContains(t, stored.Hash, result.Key)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Create_InvalidScope(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	tests := []struct {
+		name   string
+		scopes []string
+	}{
+		{"no scopes", nil},
+		{"unknown resource", []string{"usuarios:read"}},
+		{"unknown action", []string{"pedidos:delete"}},
+		{"missing action", []string{"pedidos"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Create(context.Background(), &dto.CreateAPIKeyRequest{Nome: "Conector", Scopes: tt.scopes})
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Nil(t, result)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_VerifyAPIKey_Success(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	key := "ak_1a2b3c4d_segredo"
+	stored := &model.APIKey{ID: 7, Prefixo: "ak_1a2b3c4d", Scopes: "pedidos:write produtos:read"}
+	mockRepo.On("FindByHash", mock.Anything, sha256Hex(key)).Return(stored, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, uint(7), mock.AnythingOfType("time.Time")).Return(nil)
+
+	principal, err := svc.VerifyAPIKey(context.Background(), key)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:ak_1a2b3c4d", principal.Subject)
+	assert.Equal(t, []string{"pedidos:write", "produtos:read"}, principal.Scopes)
+	assert.Empty(t, principal.Roles)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_VerifyAPIKey_RecentlyUsedSkipsTouch(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	usedAt := time.Now().Add(-10 * time.Second)
+	stored := &model.APIKey{ID: 7, Prefixo: "ak_1a2b3c4d", Scopes: "pedidos:read", LastUsedAt: &usedAt}
+	mockRepo.On("FindByHash", mock.Anything, mock.Anything).Return(stored, nil)
+
+	_, err := svc.VerifyAPIKey(context.Background(), "ak_1a2b3c4d_segredo")
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_VerifyAPIKey_Invalid(t *testing.T) {
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name  string
+		key   string
+		setup func(m *MockAPIKeyRepository)
+	}{
+		{"wrong format", "qualquer-coisa", func(m *MockAPIKeyRepository) {}},
+		{"unknown key", "ak_1a2b3c4d_segredo", func(m *MockAPIKeyRepository) {
+			m.On("FindByHash", mock.Anything, mock.Anything).Return(nil, apperror.NotFound("API key não encontrado"))
+		}},
+		{"revoked key", "ak_1a2b3c4d_segredo", func(m *MockAPIKeyRepository) {
+			m.On("FindByHash", mock.Anything, mock.Anything).Return(&model.APIKey{ID: 1, RevokedAt: &revokedAt}, nil)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAPIKeyRepository)
+			tt.setup(mockRepo)
+			svc := service.NewAPIKeyService(mockRepo)
+
+			principal, err := svc.VerifyAPIKey(context.Background(), tt.key)
+
+			assert.ErrorIs(t, err, auth.ErrAPIKeyInvalida)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	stored := &model.APIKey{ID: 3}
+	mockRepo.On("FindByID", mock.Anything, uint(3)).Return(stored, nil)
+	mockRepo.On("Update", mock.Anything, stored).Return(nil).Once()
+
+	assert.NoError(t, svc.Revoke(context.Background(), 3))
+	assert.NotNil(t, stored.RevokedAt)
+
+	// revogar novamente não altera a data original
+	revokedAt := *stored.RevokedAt
+	assert.NoError(t, svc.Revoke(context.Background(), 3))
+	assert.Equal(t, revokedAt, *stored.RevokedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Revoke_NotFound(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(99)).Return(nil, apperror.NotFound("API key não encontrado"))
+
+	err := svc.Revoke(context.Background(), 99)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
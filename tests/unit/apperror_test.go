@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppError_Categories(t *testing.T) {
+	assert.ErrorIs(t, apperror.NotFound("cliente não encontrado"), apperror.ErrNotFound)
+	assert.ErrorIs(t, apperror.Conflict("SKU já cadastrado"), apperror.ErrConflict)
+	assert.ErrorIs(t, apperror.BusinessRule("estoque insuficiente"), apperror.ErrBusinessRule)
+	assert.ErrorIs(t, apperror.Validation("dados inválidos", errors.New("campo")), apperror.ErrValidation)
+	assert.ErrorIs(t, apperror.PreconditionFailed("versão desatualizada"), apperror.ErrPreconditionFailed)
+	assert.ErrorIs(t, apperror.Unavailable("busca indisponível"), apperror.ErrUnavailable)
+
+	assert.NotErrorIs(t, apperror.NotFound("x"), apperror.ErrConflict)
+}
+
+func TestAppError_WrappedKeepsCategory(t *testing.T) {
+	sentinel := apperror.Conflict("transição de status inválida")
+	err := fmt.Errorf("%w: de pendente para entregue", sentinel)
+
+	assert.ErrorIs(t, err, sentinel)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.Equal(t, "transição de status inválida: de pendente para entregue", err.Error())
+}
+
+func TestAppError_ValidationExposesCause(t *testing.T) {
+	type request struct {
+		Nome string `validate:"required"`
+	}
+	cause := validator.New().Struct(request{})
+	err := apperror.Validation("dados do cliente inválidos", cause)
+
+	var validationErrors validator.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Len(t, validationErrors, 1)
+	assert.Contains(t, err.Error(), "dados do cliente inválidos: ")
+}
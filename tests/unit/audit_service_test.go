@@ -0,0 +1,157 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository. Append
+// chains the entries in memory, without expectations, so the services that
+// audit their writes can be tested without setting it up.
+type MockAuditRepository struct {
+	mock.Mock
+	Entries []model.AuditLog
+	Ponta   *model.AuditCadeia
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, entry *model.AuditLog) error {
+	if len(m.Entries) > 0 {
+		entry.HashAnterior = m.Entries[len(m.Entries)-1].Hash
+	}
+	entry.ID = uint(len(m.Entries) + 1)
+	entry.Hash = entry.CalcularHash()
+	m.Entries = append(m.Entries, *entry)
+	m.Ponta = &model.AuditCadeia{ID: 1, Hash: entry.Hash}
+	return nil
+}
+
+func (m *MockAuditRepository) FindPonta(ctx context.Context) (*model.AuditCadeia, error) {
+	if m.Ponta == nil {
+		return nil, apperror.NotFound("ponta do audit log não encontrado")
+	}
+	return m.Ponta, nil
+}
+
+func (m *MockAuditRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.AuditLog], error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Page[model.AuditLog]), args.Error(1)
+}
+
+func (m *MockAuditRepository) ForEach(ctx context.Context, fn func(entry *model.AuditLog) error) error {
+	for i := range m.Entries {
+		if err := fn(&m.Entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithTransaction runs fn directly, since there is no database behind the mock
+func (m *MockAuditRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// Test cases
+func TestAuditService_FindAll_Filters(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	svc := service.NewAuditService(mockRepo)
+
+	mockRepo.On("FindAll", mock.Anything, mock.MatchedBy(func(opts repository.ListOptions) bool {
+		if len(opts.Filters) != 4 {
+			return false
+		}
+		return opts.Filters[0].Column == "entidade" && opts.Filters[0].Values[0] == model.AuditProduto &&
+			opts.Filters[1].Column == "entidade_id" && opts.Filters[1].Values[0] == uint(7) &&
+			opts.Filters[2].Column == "created_at" && opts.Filters[2].Op == repository.OpGte &&
+			opts.Filters[3].Column == "created_at" && opts.Filters[3].Op == repository.OpLt
+	})).Return(&repository.Page[model.AuditLog]{
+		Items: []model.AuditLog{{ID: 1, Entidade: model.AuditProduto, EntidadeID: 7, Acao: model.AuditCreate, Diff: `{"nome":{"depois":"Mouse"}}`}},
+		Total: 1,
+	}, nil)
+
+	result, err := svc.FindAll(context.Background(), dto.AuditFilter{Entity: model.AuditProduto, ID: 7, From: "2026-01-01", To: "2026-01-31"}, dto.PageRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	assert.JSONEq(t, `{"nome":{"depois":"Mouse"}}`, string(result.Data[0].Diff))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_FindAll_InvalidFilter(t *testing.T) {
+	svc := service.NewAuditService(new(MockAuditRepository))
+
+	filtros := []dto.AuditFilter{
+		{Entity: "cupom"},
+		{ID: 1},
+		{From: "ontem"},
+		{Entity: model.AuditCliente, To: "31/01/2026"},
+	}
+	for _, filtro := range filtros {
+		_, err := svc.FindAll(context.Background(), filtro, dto.PageRequest{})
+		assert.ErrorIs(t, err, apperror.ErrValidation, filtro)
+	}
+}
+
+func TestAuditService_Verify(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	svc := service.NewAuditService(mockRepo)
+
+	for i := uint(1); i <= 3; i++ {
+		mockRepo.Append(context.Background(), &model.AuditLog{Entidade: model.AuditCliente, EntidadeID: i, Acao: model.AuditCreate, Ator: "admin", Diff: "{}"})
+	}
+
+	result, err := svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.Entries)
+	assert.Nil(t, result.BrokenAt)
+
+	// alterar uma entrada quebra a cadeia nela
+	mockRepo.Entries[1].Ator = "outro"
+	result, err = svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(1), result.Entries)
+	assert.Equal(t, uint(2), *result.BrokenAt)
+
+	// remover uma entrada quebra a cadeia na seguinte
+	mockRepo.Entries[1].Ator = "admin"
+	mockRepo.Entries = append(mockRepo.Entries[:1], mockRepo.Entries[2:]...)
+	result, err = svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, uint(3), *result.BrokenAt)
+}
+
+func TestAuditService_Verify_UltimasRemovidas(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	svc := service.NewAuditService(mockRepo)
+
+	for i := uint(1); i <= 3; i++ {
+		mockRepo.Append(context.Background(), &model.AuditLog{Entidade: model.AuditCliente, EntidadeID: i, Acao: model.AuditCreate, Ator: "admin", Diff: "{}"})
+	}
+
+	// o que sobra confere, mas a ponta ainda aponta para a última entrada removida
+	mockRepo.Entries = mockRepo.Entries[:1]
+	result, err := svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(1), result.Entries)
+	assert.Nil(t, result.BrokenAt)
+
+	mockRepo.Entries = nil
+	result, err = svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/danmaciel/api/internal/apperror"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,12 +24,12 @@ func (m *MockClienteRepository) Create(ctx context.Context, cliente *model.Clien
 	return args.Error(0)
 }
 
-func (m *MockClienteRepository) FindAll(ctx context.Context) ([]model.Cliente, error) {
-	args := m.Called(ctx)
+func (m *MockClienteRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.Cliente], error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Cliente), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Cliente]), args.Error(1)
 }
 
 func (m *MockClienteRepository) FindByID(ctx context.Context, id uint) (*model.Cliente, error) {
@@ -37,12 +40,12 @@ func (m *MockClienteRepository) FindByID(ctx context.Context, id uint) (*model.C
 	return args.Get(0).(*model.Cliente), args.Error(1)
 }
 
-func (m *MockClienteRepository) FindByName(ctx context.Context, nome string) ([]model.Cliente, error) {
-	args := m.Called(ctx, nome)
+func (m *MockClienteRepository) FindByName(ctx context.Context, nome string, opts repository.ListOptions) (*repository.Page[model.Cliente], error) {
+	args := m.Called(ctx, nome, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Cliente), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Cliente]), args.Error(1)
 }
 
 func (m *MockClienteRepository) Update(ctx context.Context, cliente *model.Cliente) error {
@@ -55,20 +58,40 @@ func (m *MockClienteRepository) Delete(ctx context.Context, id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockClienteRepository) Restore(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) Purge(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) Anonymize(ctx context.Context, cliente *model.Cliente) error {
+	args := m.Called(ctx, cliente)
+	return args.Error(0)
+}
+
 func (m *MockClienteRepository) Count(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockClienteRepository) CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error) {
+	args := m.Called(ctx, id, incluirExcluidos)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Test cases
 func TestClienteService_Create_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	req := &dto.CreateClienteRequest{
 		Nome:     "João Silva",
 		Email:    "joao@example.com",
-		CPF:      "12345678901",
+		CPF:      "12345678909",
 		Telefone: "11999999999",
 	}
 
@@ -85,30 +108,119 @@ func TestClienteService_Create_Success(t *testing.T) {
 
 func TestClienteService_Create_ValidationError(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	req := &dto.CreateClienteRequest{
 		Nome:  "", // Invalid: empty name
 		Email: "joao@example.com",
-		CPF:   "12345678901",
+		CPF:   "12345678909",
 	}
 
 	result, err := svc.Create(context.Background(), req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation error")
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Contains(t, err.Error(), "nome")
+}
+
+func TestClienteService_Create_NormalizesMaskedCPF(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	req := &dto.CreateClienteRequest{
+		Nome:  "João Silva",
+		Email: "joao@example.com",
+		CPF:   "529.982.247-25",
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Cliente) bool {
+		return c.Tipo == model.ClientePessoaFisica && c.CPF == "52998224725" && c.CNPJ == ""
+	})).Return(nil)
+
+	result, err := svc.Create(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "52998224725", result.CPF)
+	assert.Equal(t, model.ClientePessoaFisica, result.Tipo)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClienteService_Create_PessoaJuridica(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	req := &dto.CreateClienteRequest{
+		Tipo:              model.ClientePessoaJuridica,
+		RazaoSocial:       "Acme Comércio Ltda",
+		NomeFantasia:      "Acme",
+		Email:             "contato@acme.com",
+		CNPJ:              "11.222.333/0001-81",
+		InscricaoEstadual: "isento",
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Cliente) bool {
+		return c.Tipo == model.ClientePessoaJuridica && c.CNPJ == "11222333000181" && c.CPF == "" &&
+			c.RazaoSocial == "Acme Comércio Ltda" && c.InscricaoEstadual == "ISENTO"
+	})).Return(nil)
+
+	result, err := svc.Create(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "11222333000181", result.CNPJ)
+	assert.Equal(t, "Acme", result.NomeFantasia)
+	assert.Empty(t, result.Nome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClienteService_Create_InvalidDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   dto.CreateClienteRequest
+		field string
+	}{
+		{"CPF com dígito errado", dto.CreateClienteRequest{Nome: "Cliente", CPF: "52998224724"}, "cpf"},
+		{"CPF com dígitos repetidos", dto.CreateClienteRequest{Nome: "Cliente", CPF: "111.111.111-11"}, "cpf"},
+		{"PF sem CPF", dto.CreateClienteRequest{Nome: "Cliente"}, "cpf"},
+		{"PF sem nome", dto.CreateClienteRequest{CPF: "52998224725"}, "nome"},
+		{"PF com CNPJ", dto.CreateClienteRequest{Nome: "Cliente", CPF: "52998224725", CNPJ: "11222333000181"}, "cnpj"},
+		{"PF com razão social", dto.CreateClienteRequest{Nome: "Cliente", CPF: "52998224725", RazaoSocial: "Cliente Ltda"}, "razao_social"},
+		{"PF com inscrição estadual", dto.CreateClienteRequest{Nome: "Cliente", CPF: "52998224725", InscricaoEstadual: "ISENTO"}, "inscricao_estadual"},
+		{"PJ sem CNPJ", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Cliente Ltda"}, "cnpj"},
+		{"PJ sem razão social", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, CNPJ: "11222333000181"}, "razao_social"},
+		{"PJ com nome", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Cliente Ltda", CNPJ: "11222333000181", Nome: "Cliente"}, "nome"},
+		{"PJ com CPF", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Cliente Ltda", CNPJ: "11222333000181", CPF: "52998224725"}, "cpf"},
+		{"CNPJ com dígito errado", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Cliente Ltda", CNPJ: "11222333000180"}, "cnpj"},
+		{"inscrição estadual inválida", dto.CreateClienteRequest{Tipo: model.ClientePessoaJuridica, RazaoSocial: "Cliente Ltda", CNPJ: "11222333000181", InscricaoEstadual: "12A"}, "inscricao_estadual"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockClienteRepository)
+			svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+			req := tt.req
+			req.Email = "cliente@example.com"
+
+			result, err := svc.Create(context.Background(), &req)
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Contains(t, err.Error(), tt.field)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestClienteService_FindByID_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	expectedCliente := &model.Cliente{
 		ID:       1,
 		Nome:     "João Silva",
 		Email:    "joao@example.com",
-		CPF:      "12345678901",
+		CPF:      "12345678909",
 		Telefone: "11999999999",
 	}
 
@@ -124,20 +236,20 @@ func TestClienteService_FindByID_Success(t *testing.T) {
 
 func TestClienteService_FindByID_NotFound(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), assert.AnError)
+	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), apperror.NotFound("cliente não encontrado"))
 
 	result, err := svc.FindByID(context.Background(), 999)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestClienteService_Count_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("Count", mock.Anything).Return(int64(10), nil)
 
@@ -150,29 +262,29 @@ func TestClienteService_Count_Success(t *testing.T) {
 
 func TestClienteService_FindAll_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	expectedClientes := []model.Cliente{
 		{ID: 1, Nome: "Cliente 1", Email: "c1@example.com"},
 		{ID: 2, Nome: "Cliente 2", Email: "c2@example.com"},
 	}
 
-	mockRepo.On("FindAll", mock.Anything).Return(expectedClientes, nil)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything).Return(&repository.Page[model.Cliente]{Items: expectedClientes, Total: int64(len(expectedClientes))}, nil)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestClienteService_FindAll_Error(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindAll", mock.Anything).Return([]model.Cliente(nil), assert.AnError)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything).Return((*repository.Page[model.Cliente])(nil), assert.AnError)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -181,37 +293,39 @@ func TestClienteService_FindAll_Error(t *testing.T) {
 
 func TestClienteService_FindByName_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	expectedClientes := []model.Cliente{
 		{ID: 1, Nome: "João Silva", Email: "joao@example.com"},
 	}
 
-	mockRepo.On("FindByName", mock.Anything, "João").Return(expectedClientes, nil)
+	mockRepo.On("FindByName", mock.Anything, "João", mock.Anything).Return(&repository.Page[model.Cliente]{Items: expectedClientes, Total: int64(len(expectedClientes))}, nil)
 
-	result, err := svc.FindByName(context.Background(), "João")
+	result, err := svc.FindByName(context.Background(), "João", dto.PageRequest{})
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
+	assert.Len(t, result.Data, 1)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestClienteService_Update_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	existingCliente := &model.Cliente{
 		ID:    1,
 		Nome:  "João Silva",
 		Email: "joao@example.com",
-		CPF:   "12345678901",
+		CPF:   "12345678909",
 	}
 
 	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingCliente, nil)
 	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Cliente")).Return(nil)
 
 	req := &dto.UpdateClienteRequest{
-		Nome: "João Silva Updated",
+		Nome:  "João Silva Updated",
+		Email: "joao@example.com",
+		CPF:   "12345678909",
 	}
 
 	result, err := svc.Update(context.Background(), 1, req)
@@ -224,37 +338,144 @@ func TestClienteService_Update_Success(t *testing.T) {
 
 func TestClienteService_Update_NotFound(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), assert.AnError)
+	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), apperror.NotFound("cliente não encontrado"))
 
 	req := &dto.UpdateClienteRequest{
-		Nome: "Updated Name",
+		Nome:  "Updated Name",
+		Email: "updated@example.com",
+		CPF:   "12345678909",
 	}
 
 	result, err := svc.Update(context.Background(), 999, req)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
 	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestClienteService_Update_PreconditionFailed(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	existingCliente := &model.Cliente{ID: 1, Nome: "João Silva", Email: "joao@example.com", CPF: "12345678909", Versao: 3}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingCliente, nil)
+
+	ctx := etag.WithIfMatch(context.Background(), `"2"`)
+	req := &dto.UpdateClienteRequest{Nome: "João Silva Updated", Email: "joao@example.com", CPF: "12345678909"}
+	result, err := svc.Update(ctx, 1, req)
+
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestClienteService_Update_ClearsOmittedTelefone(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	existingCliente := &model.Cliente{ID: 1, Nome: "João Silva", Email: "joao@example.com", CPF: "12345678909", Telefone: "11999999999"}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingCliente, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Cliente")).Return(nil)
+
+	req := &dto.UpdateClienteRequest{Nome: "João Silva", Email: "joao@example.com", CPF: "12345678909"}
+	result, err := svc.Update(context.Background(), 1, req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Telefone, "PUT substitui o recurso inteiro")
+}
+
+func TestClienteService_Update_MissingRequiredField(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	result, err := svc.Update(context.Background(), 1, &dto.UpdateClienteRequest{Nome: "João Silva"})
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestClienteService_Patch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		wantErr  error
+		nome     string
+		telefone string
+	}{
+		{"altera apenas os campos presentes", `{"nome": "João Souza"}`, nil, "João Souza", "11999999999"},
+		{"null limpa campo opcional", `{"telefone": null}`, nil, "João Silva", ""},
+		{"null em campo obrigatório", `{"nome": null}`, apperror.ErrValidation, "", ""},
+		{"valor inválido", `{"email": "invalido"}`, apperror.ErrValidation, "", ""},
+		{"JSON malformado", `{"nome":`, apperror.ErrValidation, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockClienteRepository)
+			svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+			existingCliente := &model.Cliente{ID: 1, Nome: "João Silva", Email: "joao@example.com", CPF: "12345678909", Telefone: "11999999999"}
+			mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingCliente, nil)
+			mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Cliente")).Return(nil)
+
+			result, err := svc.Patch(context.Background(), 1, []byte(tt.patch))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.nome, result.Nome)
+			assert.Equal(t, tt.telefone, result.Telefone)
+			assert.Equal(t, "joao@example.com", result.Email)
+		})
+	}
+}
+
+func TestClienteService_Delete_PreconditionFailed(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Versao: 2}, nil)
+
+	err := svc.Delete(etag.WithIfMatch(context.Background(), `"1"`), 1)
+
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestClienteService_Delete_Success(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	auditRepo := new(MockAuditRepository)
+	svc := service.NewClienteService(mockRepo, auditRepo)
 
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Nome: "João Silva", Versao: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(0), nil)
 	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
 
 	err := svc.Delete(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+
+	// a exclusão fica no audit log com os dados que o cliente tinha
+	assert.Len(t, auditRepo.Entries, 1)
+	assert.Equal(t, model.AuditDelete, auditRepo.Entries[0].Acao)
+	assert.Equal(t, uint(1), auditRepo.Entries[0].EntidadeID)
+	assert.Contains(t, auditRepo.Entries[0].Diff, `"nome":{"antes":"[omitido]"}`)
+	assert.NotContains(t, auditRepo.Entries[0].Diff, "João Silva")
 }
 
 func TestClienteService_Delete_Error(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
+	mockRepo.On("FindByID", mock.Anything, uint(999)).Return(&model.Cliente{ID: 999, Versao: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(999), false).Return(int64(0), nil)
 	mockRepo.On("Delete", mock.Anything, uint(999)).Return(assert.AnError)
 
 	err := svc.Delete(context.Background(), 999)
@@ -263,9 +484,120 @@ func TestClienteService_Delete_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestClienteService_Restore_Success(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("Restore", mock.Anything, uint(1)).Return(nil)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Nome: "João Silva", Versao: 3}, nil)
+
+	result, err := svc.Restore(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), result.Versao)
+	assert.Empty(t, result.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClienteService_Restore_Conflict(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("Restore", mock.Anything, uint(1)).Return(apperror.Conflict("cliente com email já cadastrado"))
+
+	result, err := svc.Restore(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestClienteService_Purge(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), true).Return(int64(0), nil)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, apperror.ErrNotFound)
+	mockRepo.On("Purge", mock.Anything, uint(1)).Return(nil)
+
+	err := svc.Purge(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClienteService_Delete_ComPedidos(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Versao: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(3), nil)
+	// a exclusão definitiva conta também os pedidos excluídos
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), true).Return(int64(3), nil)
+
+	err := svc.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, map[string]int64{"pedidos": 3}, appErr.Dependents)
+
+	err = svc.Purge(context.Background(), 1)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
+
+func TestClienteService_Deactivate_Anonimiza(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	auditRepo := new(MockAuditRepository)
+	svc := service.NewClienteService(mockRepo, auditRepo)
+
+	cliente := &model.Cliente{ID: 7, Tipo: model.ClientePessoaFisica, Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725", Telefone: "11999999999", Versao: 2}
+	mockRepo.On("FindByID", mock.Anything, uint(7)).Return(cliente, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(7), false).Return(int64(2), nil)
+	mockRepo.On("Anonymize", mock.Anything, cliente).Return(nil)
+
+	err := svc.Deactivate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ClienteAnonimizado, cliente.Nome)
+	assert.Equal(t, "anonimizado-7@anonimizado.invalid", cliente.Email)
+	assert.Empty(t, cliente.CPF)
+	assert.Empty(t, cliente.Telefone)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+
+	// a entrada da anonimização não guarda os dados apagados
+	if assert.Len(t, auditRepo.Entries, 1) {
+		diff := auditRepo.Entries[0].Diff
+		assert.Contains(t, diff, `"cpf":{"antes":"[omitido]"}`)
+		assert.Contains(t, diff, `"telefone":{"antes":"[omitido]","depois":""}`)
+		for _, pessoal := range []string{"João Silva", "joao@example.com", "52998224725", "11999999999"} {
+			assert.NotContains(t, diff, pessoal)
+		}
+	}
+}
+
+func TestClienteService_Deactivate_SemPedidosExclui(t *testing.T) {
+	mockRepo := new(MockClienteRepository)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Nome: "João Silva"}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(0), nil)
+	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+
+	err := svc.Deactivate(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestClienteService_Count_Error(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("Count", mock.Anything).Return(int64(0), assert.AnError)
 
@@ -278,12 +610,12 @@ func TestClienteService_Count_Error(t *testing.T) {
 
 func TestClienteService_Create_RepositoryError(t *testing.T) {
 	mockRepo := new(MockClienteRepository)
-	svc := service.NewClienteService(mockRepo)
+	svc := service.NewClienteService(mockRepo, new(MockAuditRepository))
 
 	req := &dto.CreateClienteRequest{
 		Nome:     "João Silva",
 		Email:    "joao@example.com",
-		CPF:      "12345678901",
+		CPF:      "12345678909",
 		Telefone: "11999999999",
 	}
 
@@ -1,8 +1,14 @@
 package unit
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/danmaciel/api/config"
 	"github.com/stretchr/testify/assert"
@@ -152,3 +158,86 @@ func TestLoad_PartialEnvironmentVariables(t *testing.T) {
 	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
 	assert.Equal(t, "./database/api.db", cfg.Database.FilePath)
 }
+
+func TestLoad_AuthDefaults(t *testing.T) {
+	os.Unsetenv("JWT_ALGORITHM")
+	os.Unsetenv("JWT_ISSUER")
+	os.Unsetenv("JWT_TTL")
+
+	cfg := config.Load()
+
+	assert.Equal(t, "HS256", cfg.Auth.Algorithm)
+	assert.Equal(t, "cliente-api", cfg.Auth.Issuer)
+	assert.Equal(t, time.Hour, cfg.Auth.TokenTTL)
+}
+
+func TestLoad_AuthTTLFromEnvironment(t *testing.T) {
+	os.Setenv("JWT_TTL", "15m")
+	defer os.Unsetenv("JWT_TTL")
+
+	cfg := config.Load()
+
+	assert.Equal(t, 15*time.Minute, cfg.Auth.TokenTTL)
+}
+
+func TestNewJWT_HS256RequiresSecret(t *testing.T) {
+	_, err := config.NewJWT(&config.AuthConfig{Algorithm: "HS256"})
+	assert.Error(t, err)
+
+	jwt, err := config.NewJWT(&config.AuthConfig{Algorithm: "HS256", Secret: "segredo-de-teste-com-pelo-menos-32-bytes"})
+	assert.NoError(t, err)
+	assert.NotNil(t, jwt)
+}
+
+func TestNewJWT_UnsupportedAlgorithm(t *testing.T) {
+	_, err := config.NewJWT(&config.AuthConfig{Algorithm: "none"})
+	assert.Error(t, err)
+}
+
+func TestNewJWT_RS256FromPEMFiles(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0600)
+	os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}), 0644)
+
+	issuer, err := config.NewJWT(&config.AuthConfig{Algorithm: "RS256", PrivateKeyFile: privateFile})
+	assert.NoError(t, err)
+	verifier, err := config.NewJWT(&config.AuthConfig{Algorithm: "RS256", PublicKeyFile: publicFile})
+	assert.NoError(t, err)
+
+	token, err := issuer.Issue("maria", []string{"admin"}, time.Hour)
+	assert.NoError(t, err)
+	principal, err := verifier.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "maria", principal.Subject)
+
+	_, err = config.NewJWT(&config.AuthConfig{Algorithm: "RS256", PublicKeyFile: filepath.Join(dir, "inexistente.pem")})
+	assert.Error(t, err)
+}
+
+func TestLoad_IdempotencyTTL(t *testing.T) {
+	os.Unsetenv("IDEMPOTENCY_TTL")
+	assert.Equal(t, 24*time.Hour, config.Load().Idempotency.TTL)
+
+	os.Setenv("IDEMPOTENCY_TTL", "2h")
+	defer os.Unsetenv("IDEMPOTENCY_TTL")
+	assert.Equal(t, 2*time.Hour, config.Load().Idempotency.TTL)
+}
+
+func TestLoad_PaymentReconcileInterval(t *testing.T) {
+	os.Unsetenv("PAYMENT_RECONCILE_INTERVAL")
+	assert.Equal(t, 5*time.Minute, config.Load().Pagamentos.ConciliacaoIntervalo)
+
+	os.Setenv("PAYMENT_RECONCILE_INTERVAL", "30s")
+	defer os.Unsetenv("PAYMENT_RECONCILE_INTERVAL")
+	assert.Equal(t, 30*time.Second, config.Load().Pagamentos.ConciliacaoIntervalo)
+}
@@ -0,0 +1,186 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockContatoRepository is a mock implementation of ContatoRepository
+type MockContatoRepository struct {
+	mock.Mock
+}
+
+func (m *MockContatoRepository) Create(ctx context.Context, contato *model.Contato) error {
+	args := m.Called(ctx, contato)
+	return args.Error(0)
+}
+
+func (m *MockContatoRepository) FindByClienteID(ctx context.Context, clienteID uint, papel string) ([]model.Contato, error) {
+	args := m.Called(ctx, clienteID, papel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Contato), args.Error(1)
+}
+
+func (m *MockContatoRepository) FindByID(ctx context.Context, id uint) (*model.Contato, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Contato), args.Error(1)
+}
+
+func (m *MockContatoRepository) Update(ctx context.Context, contato *model.Contato) error {
+	args := m.Called(ctx, contato)
+	return args.Error(0)
+}
+
+func (m *MockContatoRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var clientePJ = &model.Cliente{ID: 1, Tipo: model.ClientePessoaJuridica, RazaoSocial: "Acme Comércio Ltda", CNPJ: "11222333000181"}
+
+func contatoRequest() *dto.CreateContatoRequest {
+	return &dto.CreateContatoRequest{
+		Nome:  "Ana Souza",
+		Papel: model.ContatoFinanceiro,
+		Cargo: "Gerente financeira",
+		Email: "ana@acme.com.br",
+	}
+}
+
+func TestContatoService_Create_Success(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewContatoService(mockRepo, mockClienteRepo)
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(clientePJ, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Contato) bool {
+		return c.ClienteID == 1 && c.Papel == model.ContatoFinanceiro
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Contato).ID = 1
+	}).Return(nil)
+
+	result, err := svc.Create(context.Background(), 1, contatoRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	assert.Equal(t, "Ana Souza", result.Nome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestContatoService_Create_ClientePessoaFisica(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewContatoService(mockRepo, mockClienteRepo)
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Tipo: model.ClientePessoaFisica}, nil)
+
+	result, err := svc.Create(context.Background(), 1, contatoRequest())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestContatoService_Create_ValidationError(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   dto.CreateContatoRequest
+		field string
+	}{
+		{"sem email nem telefone", dto.CreateContatoRequest{Nome: "Ana Souza", Papel: model.ContatoCompras}, "email"},
+		{"papel desconhecido", dto.CreateContatoRequest{Nome: "Ana Souza", Papel: "diretoria", Telefone: "11987654321"}, "papel"},
+		{"sem nome", dto.CreateContatoRequest{Papel: model.ContatoCompras, Email: "ana@acme.com.br"}, "nome"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockContatoRepository)
+			mockClienteRepo := new(MockClienteRepository)
+			svc := service.NewContatoService(mockRepo, mockClienteRepo)
+
+			result, err := svc.Create(context.Background(), 1, &tt.req)
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Contains(t, err.Error(), tt.field)
+			mockClienteRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestContatoService_FindByClienteID_FiltraPorPapel(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewContatoService(mockRepo, mockClienteRepo)
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(clientePJ, nil)
+	mockRepo.On("FindByClienteID", mock.Anything, uint(1), model.ContatoFinanceiro).
+		Return([]model.Contato{{ID: 2, ClienteID: 1, Nome: "Ana Souza", Papel: model.ContatoFinanceiro}}, nil)
+
+	result, err := svc.FindByClienteID(context.Background(), 1, "Financeiro")
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, uint(2), result[0].ID)
+	}
+
+	_, err = svc.FindByClienteID(context.Background(), 1, "diretoria")
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func TestContatoService_FindByID_OutroCliente(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	svc := service.NewContatoService(mockRepo, new(MockClienteRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Contato{ID: 1, ClienteID: 2}, nil)
+
+	result, err := svc.FindByID(context.Background(), 1, 1)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestContatoService_Update_PreconditionFailed(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	svc := service.NewContatoService(mockRepo, new(MockClienteRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Contato{ID: 1, ClienteID: 1, Versao: 2}, nil)
+
+	ctx := etag.WithIfMatch(context.Background(), etag.Format(1))
+	result, err := svc.Update(ctx, 1, 1, &dto.UpdateContatoRequest{Nome: "Ana Souza", Papel: model.ContatoFiscal, Telefone: "11987654321"})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestContatoService_Update_Success(t *testing.T) {
+	mockRepo := new(MockContatoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewContatoService(mockRepo, mockClienteRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Contato{ID: 1, ClienteID: 1, Nome: "Ana Souza", Papel: model.ContatoFinanceiro, Email: "ana@acme.com.br", Versao: 1}, nil)
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(clientePJ, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *model.Contato) bool {
+		return c.Papel == model.ContatoFiscal && c.Email == "" && c.Telefone == "11987654321"
+	})).Return(nil)
+
+	result, err := svc.Update(context.Background(), 1, 1, &dto.UpdateContatoRequest{Nome: "Ana Souza", Papel: model.ContatoFiscal, Telefone: "11987654321"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ContatoFiscal, result.Papel)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,170 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCupomRepository is a mock implementation of CupomRepository
+type MockCupomRepository struct {
+	mock.Mock
+}
+
+func (m *MockCupomRepository) Create(ctx context.Context, cupom *model.Cupom) error {
+	args := m.Called(ctx, cupom)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.Cupom], error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Page[model.Cupom]), args.Error(1)
+}
+
+func (m *MockCupomRepository) FindByID(ctx context.Context, id uint) (*model.Cupom, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cupom), args.Error(1)
+}
+
+func (m *MockCupomRepository) FindByCodigo(ctx context.Context, codigo string) (*model.Cupom, error) {
+	args := m.Called(ctx, codigo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cupom), args.Error(1)
+}
+
+func (m *MockCupomRepository) Update(ctx context.Context, cupom *model.Cupom) error {
+	args := m.Called(ctx, cupom)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) Restore(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) Purge(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) IncrementUsos(ctx context.Context, id, clienteID uint) error {
+	args := m.Called(ctx, id, clienteID)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) DecrementUsos(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCupomRepository) CountUsosCliente(ctx context.Context, cupomID, clienteID uint) (int64, error) {
+	args := m.Called(ctx, cupomID, clienteID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestCupomService_Create_Success(t *testing.T) {
+	mockRepo := new(MockCupomRepository)
+	svc := service.NewCupomService(mockRepo)
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Cupom) bool {
+		// o código é normalizado e o valor fixo não se aplica a cupons percentuais
+		return c.Codigo == "BEMVINDO10" && c.Percentual == 10 && c.Valor == 0 && c.Ativo
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Cupom).ID = 1
+	}).Return(nil)
+
+	result, err := svc.Create(context.Background(), &dto.CreateCupomRequest{
+		Codigo:     "bemvindo10",
+		Tipo:       model.CupomPercentual,
+		Percentual: 10,
+		Valor:      money.MustParse("5.00"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	assert.Equal(t, "BEMVINDO10", result.Codigo)
+	assert.Equal(t, money.Money(0), result.Valor)
+	assert.Equal(t, []string{}, result.Categorias)
+	assert.Equal(t, []uint{}, result.ProdutoIDs)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCupomService_Create_ValidationError(t *testing.T) {
+	agora := time.Now()
+	ontem := agora.AddDate(0, 0, -1)
+
+	casos := []struct {
+		name string
+		req  dto.CreateCupomRequest
+	}{
+		{"tipo desconhecido", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: "brinde"}},
+		{"código com espaço", dto.CreateCupomRequest{Codigo: "TEM ESPACO", Tipo: model.CupomFreteGratis}},
+		{"percentual sem valor", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: model.CupomPercentual}},
+		{"percentual acima de 100", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: model.CupomPercentual, Percentual: 101}},
+		{"valor fixo sem valor", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: model.CupomValorFixo}},
+		{"validade invertida", dto.CreateCupomRequest{Codigo: "TESTE", Tipo: model.CupomFreteGratis, ValidoDe: &agora, ValidoAte: &ontem}},
+	}
+
+	for _, tt := range casos {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCupomRepository)
+			svc := service.NewCupomService(mockRepo)
+
+			result, err := svc.Create(context.Background(), &tt.req)
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Nil(t, result)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCupomService_Update_PreconditionFailed(t *testing.T) {
+	mockRepo := new(MockCupomRepository)
+	svc := service.NewCupomService(mockRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cupom{ID: 1, Codigo: "TESTE", Versao: 3}, nil)
+
+	ativo := true
+	ctx := etag.WithIfMatch(context.Background(), `"2"`)
+	result, err := svc.Update(ctx, 1, &dto.UpdateCupomRequest{Codigo: "TESTE", Tipo: model.CupomFreteGratis, Ativo: &ativo})
+
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCupomService_Delete_NotFound(t *testing.T) {
+	mockRepo := new(MockCupomRepository)
+	svc := service.NewCupomService(mockRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, apperror.NotFound("cupom não encontrado"))
+
+	err := svc.Delete(context.Background(), 9)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
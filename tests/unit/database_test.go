@@ -6,7 +6,11 @@ import (
 	"testing"
 
 	"github.com/danmaciel/api/config"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestInitDatabase_Success(t *testing.T) {
@@ -222,8 +226,238 @@ func TestInitDatabase_TablesCreated(t *testing.T) {
 	assert.True(t, db.Migrator().HasTable("produtos"))
 	assert.True(t, db.Migrator().HasTable("pedidos"))
 	assert.True(t, db.Migrator().HasTable("pedido_produtos"))
+	assert.True(t, db.Migrator().HasTable("pedido_status_historico"))
 
 	// Close database
 	sqlDB, _ := db.DB()
 	sqlDB.Close()
 }
+
+func TestInitDatabase_ConvertsLegacyValuesToCentavos(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "legacy.db")
+
+	// Legacy database storing prices and totals as decimal reais
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE produtos (
+		id integer PRIMARY KEY AUTOINCREMENT, nome varchar(200) NOT NULL, descricao text,
+		preco decimal(10,2) NOT NULL, estoque integer NOT NULL DEFAULT 0, sku varchar(50) NOT NULL,
+		categoria varchar(100), ativo numeric DEFAULT true, created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE pedidos (
+		id integer PRIMARY KEY AUTOINCREMENT, cliente_id integer NOT NULL, valor_total decimal(10,2) NOT NULL DEFAULT 0,
+		status varchar(20) NOT NULL DEFAULT 'pendente', data_pedido datetime NOT NULL,
+		created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO produtos (nome, preco, sku) VALUES ('Notebook', 2999.99, 'NB-001'), ('Caneta', 0.1, 'CN-001')`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO pedidos (cliente_id, valor_total, data_pedido) VALUES (1, 5999.98, CURRENT_TIMESTAMP)`).Error)
+	sqlLegacy, _ := legacy.DB()
+	sqlLegacy.Close()
+
+	cfg := &config.DatabaseConfig{Driver: "sqlite", FilePath: dbPath}
+
+	// Run twice to make sure the conversion is applied only once
+	for i := 0; i < 2; i++ {
+		db, err := config.InitDatabase(cfg)
+		assert.NoError(t, err)
+
+		var produtos []model.Produto
+		assert.NoError(t, db.Order("id").Find(&produtos).Error)
+		assert.Len(t, produtos, 2)
+		assert.Equal(t, money.FromCentavos(299999), produtos[0].Preco)
+		assert.Equal(t, money.FromCentavos(10), produtos[1].Preco)
+
+		var pedido model.Pedido
+		assert.NoError(t, db.First(&pedido).Error)
+		assert.Equal(t, money.FromCentavos(599998), pedido.ValorTotal)
+
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}
+}
+
+func TestInitDatabase_CPFUnicoApenasPreenchido(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "legacy.db")
+
+	// Legacy database where every cliente had a CPF with a plain unique index
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE produtos (id integer PRIMARY KEY AUTOINCREMENT, nome varchar(200) NOT NULL)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE schema_migrations (versao varchar(100) PRIMARY KEY, applied_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO schema_migrations (versao, applied_at) VALUES
+		('0001_valores_em_centavos', CURRENT_TIMESTAMP), ('0002_subtotal_dos_pedidos', CURRENT_TIMESTAMP)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE clientes (
+		id integer PRIMARY KEY AUTOINCREMENT, nome varchar(100) NOT NULL, email varchar(100) NOT NULL,
+		cpf varchar(11) NOT NULL, telefone varchar(15), versao integer NOT NULL DEFAULT 1,
+		created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE UNIQUE INDEX idx_clientes_cpf ON clientes(cpf)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO clientes (nome, email, cpf) VALUES ('João Silva', 'joao@example.com', '52998224725')`).Error)
+	sqlLegacy, _ := legacy.DB()
+	sqlLegacy.Close()
+
+	db, err := config.InitDatabase(&config.DatabaseConfig{Driver: "sqlite", FilePath: dbPath})
+	assert.NoError(t, err)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	var existente model.Cliente
+	assert.NoError(t, db.First(&existente).Error)
+	assert.Equal(t, model.ClientePessoaFisica, existente.Tipo)
+
+	// clientes PJ não têm CPF e não colidem entre si
+	assert.NoError(t, db.Create(&model.Cliente{Tipo: model.ClientePessoaJuridica, Nome: "Acme Ltda", Email: "acme@example.com", CNPJ: "11222333000181"}).Error)
+	assert.NoError(t, db.Create(&model.Cliente{Tipo: model.ClientePessoaJuridica, Nome: "Beta S.A.", Email: "beta@example.com", CNPJ: "11444777000161"}).Error)
+	assert.Error(t, db.Create(&model.Cliente{Nome: "Outro João", Email: "outro@example.com", CPF: "52998224725"}).Error)
+}
+
+func TestInitDatabase_MovesNomeOfPJToRazaoSocial(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "legacy.db")
+
+	// Database where clientes PJ were identified by nome, before razao_social existed
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE produtos (id integer PRIMARY KEY AUTOINCREMENT, nome varchar(200) NOT NULL)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE schema_migrations (versao varchar(100) PRIMARY KEY, applied_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO schema_migrations (versao, applied_at) VALUES
+		('0001_valores_em_centavos', CURRENT_TIMESTAMP), ('0002_subtotal_dos_pedidos', CURRENT_TIMESTAMP),
+		('0003_documentos_dos_clientes', CURRENT_TIMESTAMP)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE clientes (
+		id integer PRIMARY KEY AUTOINCREMENT, tipo varchar(2) NOT NULL DEFAULT 'PF', nome varchar(100) NOT NULL,
+		email varchar(100) NOT NULL, cpf varchar(11) NOT NULL, cnpj varchar(14) NOT NULL DEFAULT '', telefone varchar(15),
+		versao integer NOT NULL DEFAULT 1, created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO clientes (tipo, nome, email, cpf, cnpj) VALUES
+		('PF', 'João Silva', 'joao@example.com', '52998224725', ''),
+		('PJ', 'Acme Comércio Ltda', 'acme@example.com', '', '11222333000181')`).Error)
+	sqlLegacy, _ := legacy.DB()
+	sqlLegacy.Close()
+
+	db, err := config.InitDatabase(&config.DatabaseConfig{Driver: "sqlite", FilePath: dbPath})
+	assert.NoError(t, err)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	var clientes []model.Cliente
+	assert.NoError(t, db.Order("id").Find(&clientes).Error)
+	if assert.Len(t, clientes, 2) {
+		assert.Equal(t, "João Silva", clientes[0].Nome)
+		assert.Empty(t, clientes[0].RazaoSocial)
+		assert.Empty(t, clientes[1].Nome)
+		assert.Equal(t, "Acme Comércio Ltda", clientes[1].RazaoSocial)
+	}
+}
+
+func TestInitDatabase_UniqueIndexesIgnoreDeletedRows(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "legacy.db")
+
+	// Current schema, but with the unique indexes that also counted soft-deleted rows
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.AutoMigrate(&model.Cliente{}, &model.Produto{}))
+	assert.NoError(t, legacy.Exec(`DROP INDEX idx_clientes_email`).Error)
+	assert.NoError(t, legacy.Exec(`DROP INDEX idx_clientes_cpf`).Error)
+	assert.NoError(t, legacy.Exec(`DROP INDEX idx_produtos_sku`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE UNIQUE INDEX idx_clientes_email ON clientes(email)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE UNIQUE INDEX idx_clientes_cpf ON clientes(cpf) WHERE cpf <> ''`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE UNIQUE INDEX idx_produtos_sku ON produtos(sku)`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE schema_migrations (versao varchar(100) PRIMARY KEY, applied_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO schema_migrations (versao, applied_at) VALUES
+		('0001_valores_em_centavos', CURRENT_TIMESTAMP), ('0002_subtotal_dos_pedidos', CURRENT_TIMESTAMP),
+		('0003_documentos_dos_clientes', CURRENT_TIMESTAMP), ('0004_razao_social_dos_clientes_pj', CURRENT_TIMESTAMP)`).Error)
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725"}
+	produto := &model.Produto{Nome: "Mouse", Preco: 9990, SKU: "MS-001"}
+	assert.NoError(t, legacy.Create(cliente).Error)
+	assert.NoError(t, legacy.Create(produto).Error)
+	assert.NoError(t, legacy.Delete(cliente).Error)
+	assert.NoError(t, legacy.Delete(produto).Error)
+	sqlLegacy, _ := legacy.DB()
+	sqlLegacy.Close()
+
+	db, err := config.InitDatabase(&config.DatabaseConfig{Driver: "sqlite", FilePath: dbPath})
+	assert.NoError(t, err)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	// os registros excluídos não impedem recadastrar email, CPF e SKU
+	assert.NoError(t, db.Create(&model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725"}).Error)
+	assert.NoError(t, db.Create(&model.Produto{Nome: "Mouse", Preco: 9990, SKU: "MS-001"}).Error)
+
+	// entre os ativos continuam únicos
+	assert.Error(t, db.Create(&model.Cliente{Nome: "Outro João", Email: "joao@example.com", CPF: "12345678909"}).Error)
+	assert.Error(t, db.Create(&model.Cliente{Nome: "Outro João", Email: "outro@example.com", CPF: "52998224725"}).Error)
+	assert.Error(t, db.Create(&model.Produto{Nome: "Mouse", Preco: 9990, SKU: "MS-001"}).Error)
+}
+
+func TestInitDatabase_EnablesForeignKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := config.InitDatabase(&config.DatabaseConfig{Driver: "sqlite", FilePath: filepath.Join(tmpDir, "test.db")})
+	assert.NoError(t, err)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	var foreignKeys int
+	assert.NoError(t, db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error)
+	assert.Equal(t, 1, foreignKeys)
+
+	// endereço de cliente inexistente
+	assert.Error(t, db.Create(&model.Endereco{ClienteID: 999, CEP: "01310100", Logradouro: "Avenida Paulista", Numero: "1000", Bairro: "Bela Vista", Cidade: "São Paulo", UF: "SP"}).Error)
+
+	// cliente com pedido não pode ser removido do banco
+	cliente := &model.Cliente{Nome: "João Silva", Email: "joao@example.com", CPF: "52998224725"}
+	assert.NoError(t, db.Create(cliente).Error)
+	assert.NoError(t, db.Create(&model.Pedido{ClienteID: cliente.ID, Status: model.StatusPendente}).Error)
+	assert.Error(t, db.Unscoped().Delete(cliente).Error)
+}
+
+func TestInitDatabase_MigrationsKeepDependentRows(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "legacy.db")
+
+	// clientes.tipo ainda não tinha default: o AutoMigrate recria a tabela, e
+	// os endereços, que apontam para ela com ON DELETE CASCADE, precisam
+	// sobreviver
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.AutoMigrate(&model.Cliente{}, &model.Endereco{}))
+	assert.NoError(t, legacy.Exec(`CREATE TABLE clientes_legado (
+		id integer PRIMARY KEY AUTOINCREMENT, tipo varchar(2) NOT NULL, nome varchar(100) NOT NULL,
+		razao_social varchar(150) NOT NULL DEFAULT '', nome_fantasia varchar(100) NOT NULL DEFAULT '',
+		email varchar(100) NOT NULL, cpf varchar(11) NOT NULL, cnpj varchar(14) NOT NULL DEFAULT '',
+		inscricao_estadual varchar(14) NOT NULL DEFAULT '', telefone varchar(15),
+		versao integer NOT NULL DEFAULT 1, created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`DROP TABLE clientes`).Error)
+	assert.NoError(t, legacy.Exec(`ALTER TABLE clientes_legado RENAME TO clientes`).Error)
+	assert.NoError(t, legacy.Exec(`CREATE TABLE schema_migrations (versao varchar(100) PRIMARY KEY, applied_at datetime)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO schema_migrations (versao, applied_at) VALUES
+		('0001_valores_em_centavos', CURRENT_TIMESTAMP), ('0002_subtotal_dos_pedidos', CURRENT_TIMESTAMP),
+		('0003_documentos_dos_clientes', CURRENT_TIMESTAMP), ('0004_razao_social_dos_clientes_pj', CURRENT_TIMESTAMP),
+		('0005_unicos_ignoram_excluidos', CURRENT_TIMESTAMP)`).Error)
+	assert.NoError(t, legacy.Exec(`INSERT INTO clientes (tipo, nome, email, cpf) VALUES ('PF', 'João Silva', 'joao@example.com', '52998224725')`).Error)
+	assert.NoError(t, legacy.Create(&model.Endereco{ClienteID: 1, CEP: "01310100", Logradouro: "Avenida Paulista", Numero: "1000", Bairro: "Bela Vista", Cidade: "São Paulo", UF: "SP"}).Error)
+	sqlLegacy, _ := legacy.DB()
+	sqlLegacy.Close()
+
+	db, err := config.InitDatabase(&config.DatabaseConfig{Driver: "sqlite", FilePath: dbPath})
+	assert.NoError(t, err)
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	var enderecos int64
+	assert.NoError(t, db.Model(&model.Endereco{}).Count(&enderecos).Error)
+	assert.Equal(t, int64(1), enderecos)
+
+	var foreignKeys int
+	assert.NoError(t, db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error)
+	assert.Equal(t, 1, foreignKeys)
+}
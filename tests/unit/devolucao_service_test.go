@@ -0,0 +1,269 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDevolucaoService_Cancelar_RestauraEstoque(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockPagamentoRepo := new(MockPagamentoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, nil, mockPagamentoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:     1,
+		Status: "pendente",
+		Itens: []model.PedidoProduto{
+			{ID: 10, ProdutoID: 1, Quantidade: 2},
+			{ID: 11, ProdutoID: 2, Quantidade: 3},
+		},
+	}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pedido) bool {
+		return p.Status == "cancelado" && p.MotivoCancelamento == "Cliente desistiu"
+	})).Return(nil)
+	mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(1), 2).Return(nil)
+	mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(2), 3).Return(nil)
+	mockPagamentoRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{}, nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+
+	result, err := svc.Cancelar(context.Background(), 1, &dto.CancelamentoRequest{Motivo: "Cliente desistiu"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelado", result.Status)
+	assert.Equal(t, "Cliente desistiu", result.MotivoCancelamento)
+	mockPedidoRepo.AssertExpectations(t)
+	mockProdutoRepo.AssertExpectations(t)
+}
+
+func TestDevolucaoService_Cancelar_LiberaCupom(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockCupomRepo := new(MockCupomRepository)
+	mockPagamentoRepo := new(MockPagamentoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, mockCupomRepo, mockPagamentoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	cupomID := uint(5)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:        1,
+		Status:    "pendente",
+		Descontos: []model.PedidoDesconto{{CupomID: &cupomID, Codigo: "TESTE"}},
+	}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+	mockPagamentoRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{}, nil)
+	mockCupomRepo.On("DecrementUsos", mock.Anything, uint(5)).Return(nil)
+
+	result, err := svc.Cancelar(context.Background(), 1, &dto.CancelamentoRequest{Motivo: "Cliente desistiu"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelado", result.Status)
+	mockCupomRepo.AssertExpectations(t)
+}
+
+func TestDevolucaoService_Cancelar_EstornaPagamentos(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockPagamentoRepo := new(MockPagamentoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, nil, mockPagamentoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:         1,
+		Status:     "pago",
+		ValorTotal: money.MustParse("100.00"),
+	}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+	// 60,00 confirmados com 10,00 já estornados, 40,00 confirmados e um
+	// pagamento que falhou, que não é estornado
+	mockPagamentoRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("60.00"), Estornado: money.MustParse("10.00"), Status: model.PagamentoConfirmado},
+		{ID: 2, PedidoID: 1, Valor: money.MustParse("40.00"), Status: model.PagamentoConfirmado},
+		{ID: 3, PedidoID: 1, Valor: money.MustParse("40.00"), Status: model.PagamentoFalhou},
+	}, nil)
+	mockPagamentoRepo.On("AddEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.PagamentoID == 2 && e.Valor == money.MustParse("40.00") && e.Motivo == "Cliente desistiu"
+	})).Return(nil).Once()
+	mockPagamentoRepo.On("AddEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.PagamentoID == 1 && e.Valor == money.MustParse("50.00")
+	})).Return(nil).Once()
+	mockPagamentoRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Status == model.PagamentoEstornado && p.Estornado == p.Valor
+	})).Return(nil).Twice()
+	mockPagamentoRepo.On("UpdateEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Status == model.EstornoConcluido
+	})).Return(nil).Twice()
+
+	result, err := svc.Cancelar(context.Background(), 1, &dto.CancelamentoRequest{Motivo: "Cliente desistiu"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelado", result.Status)
+	mockPagamentoRepo.AssertExpectations(t)
+}
+
+func TestDevolucaoService_Cancelar_SemMotivo(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, nil, nil, nil, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	result, err := svc.Cancelar(context.Background(), 1, &dto.CancelamentoRequest{})
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestDevolucaoService_Cancelar_TransicaoInvalida(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, nil, nil, nil, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "enviado"}, nil)
+
+	result, err := svc.Cancelar(context.Background(), 1, &dto.CancelamentoRequest{Motivo: "Cliente desistiu"})
+
+	assert.ErrorIs(t, err, service.ErrTransicaoStatusInvalida)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDevolucaoService_Devolver_Parcial(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockPagamentoRepo := new(MockPagamentoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, nil, mockPagamentoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	// 4 unidades de 25,00 com 10,00 de desconto; uma já foi devolvida
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:         1,
+		Status:     "entregue",
+		ValorTotal: money.MustParse("90.00"),
+		Itens: []model.PedidoProduto{
+			{ID: 10, ProdutoID: 1, Quantidade: 4, PrecoUnitario: money.MustParse("25.00"), Subtotal: money.MustParse("100.00"), Desconto: money.MustParse("10.00")},
+		},
+		Devolucoes: []model.PedidoDevolucao{
+			{ID: 1, Itens: []model.PedidoDevolucaoItem{{PedidoProdutoID: 10, Quantidade: 1}}},
+		},
+	}, nil)
+	mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(1), 2).Return(nil)
+	mockPagamentoRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("90.00"), Estornado: money.MustParse("22.50"), Status: model.PagamentoConfirmado},
+	}, nil)
+	mockPagamentoRepo.On("AddEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Valor == money.MustParse("45.00") && e.Motivo == "Chegou com defeito"
+	})).Return(nil)
+	mockPagamentoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+	mockPagamentoRepo.On("UpdateEstorno", mock.Anything, mock.AnythingOfType("*model.PagamentoEstorno")).Return(nil)
+	mockPedidoRepo.On("AddDevolucao", mock.Anything, mock.AnythingOfType("*model.PedidoDevolucao")).Return(nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+
+	result, err := svc.Devolver(context.Background(), 1, &dto.CreateDevolucaoRequest{
+		Motivo: "Chegou com defeito",
+		Itens:  []dto.DevolucaoItemRequest{{ItemID: 10, Quantidade: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("45.00"), result.Valor)
+	assert.Equal(t, money.MustParse("45.00"), result.Reembolsado)
+	assert.Len(t, result.Itens, 1)
+	mockProdutoRepo.AssertExpectations(t)
+	mockPagamentoRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestDevolucaoService_Devolver_EstornoRecusado(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockPagamentoRepo := new(MockPagamentoRepository)
+	svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, nil, mockPagamentoRepo, gatewayIndisponivel{pagamento.NewFake("segredo")}, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:         1,
+		Status:     "entregue",
+		ValorTotal: money.MustParse("20.00"),
+		Itens:      []model.PedidoProduto{{ID: 10, ProdutoID: 1, Quantidade: 2, PrecoUnitario: money.MustParse("10.00"), Subtotal: money.MustParse("20.00")}},
+	}, nil)
+	mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+	mockPagamentoRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("20.00"), Status: model.PagamentoConfirmado},
+	}, nil)
+	mockPagamentoRepo.On("AddEstorno", mock.Anything, mock.AnythingOfType("*model.PagamentoEstorno")).Return(nil)
+	mockPagamentoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+	mockPedidoRepo.On("AddDevolucao", mock.Anything, mock.AnythingOfType("*model.PedidoDevolucao")).Return(nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+	// o provedor recusa o estorno: ele fica como falhou e a devolução deixa de contá-lo
+	mockPagamentoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pagamento{ID: 1, PedidoID: 1, Valor: money.MustParse("20.00"), Estornado: money.MustParse("10.00"), Status: model.PagamentoConfirmado}, nil)
+	mockPagamentoRepo.On("UpdateEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Status == model.EstornoFalhou
+	})).Return(nil)
+	mockPedidoRepo.On("UpdateReembolsado", mock.Anything, mock.MatchedBy(func(d *model.PedidoDevolucao) bool {
+		return d.Reembolsado == 0
+	})).Return(nil)
+
+	result, err := svc.Devolver(context.Background(), 1, &dto.CreateDevolucaoRequest{
+		Itens: []dto.DevolucaoItemRequest{{ItemID: 10, Quantidade: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("10.00"), result.Valor)
+	assert.Equal(t, money.Money(0), result.Reembolsado)
+	mockPagamentoRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestDevolucaoService_Devolver_Recusas(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		itens   []dto.DevolucaoItemRequest
+		wantMsg string
+	}{
+		{
+			name:    "pedido pendente",
+			status:  "pendente",
+			itens:   []dto.DevolucaoItemRequest{{ItemID: 10, Quantidade: 1}},
+			wantMsg: "pedido 1 está pendente e não aceita devoluções",
+		},
+		{
+			name:    "item de outro pedido",
+			status:  "pago",
+			itens:   []dto.DevolucaoItemRequest{{ItemID: 99, Quantidade: 1}},
+			wantMsg: "item 99 não pertence ao pedido 1",
+		},
+		{
+			name:    "quantidade acima do restante",
+			status:  "entregue",
+			itens:   []dto.DevolucaoItemRequest{{ItemID: 10, Quantidade: 1}, {ItemID: 10, Quantidade: 2}},
+			wantMsg: "item 10 tem apenas 1 unidade(s) a devolver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockProdutoRepo := new(MockProdutoRepository)
+			svc := service.NewDevolucaoService(mockPedidoRepo, mockProdutoRepo, nil, nil, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+			mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+				ID:     1,
+				Status: tt.status,
+				Itens:  []model.PedidoProduto{{ID: 10, ProdutoID: 1, Quantidade: 2, PrecoUnitario: money.MustParse("10.00"), Subtotal: money.MustParse("20.00")}},
+			}, nil)
+			mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(1), mock.Anything).Return(nil)
+
+			result, err := svc.Devolver(context.Background(), 1, &dto.CreateDevolucaoRequest{Itens: tt.itens})
+
+			assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+			assert.EqualError(t, err, tt.wantMsg)
+			assert.Nil(t, result)
+			mockPedidoRepo.AssertNotCalled(t, "AddDevolucao", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/danmaciel/api/internal/documento"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumento_CPFValido(t *testing.T) {
+	validos := []string{"52998224725", "529.982.247-25", " 123.456.789-09 ", "11144477735"}
+	for _, cpf := range validos {
+		assert.True(t, documento.CPFValido(cpf), cpf)
+	}
+
+	invalidos := []string{
+		"",
+		"52998224724",    // segundo dígito verificador errado
+		"52998224715",    // primeiro dígito verificador errado
+		"11111111111",    // sequência repetida
+		"5299822472",     // curto demais
+		"529982247250",   // longo demais
+		"529.982.247-2a", // letra
+		"11222333000181", // CNPJ
+	}
+	for _, cpf := range invalidos {
+		assert.False(t, documento.CPFValido(cpf), cpf)
+	}
+}
+
+func TestDocumento_CNPJValido(t *testing.T) {
+	validos := []string{"11222333000181", "11.222.333/0001-81", "11444777000161"}
+	for _, cnpj := range validos {
+		assert.True(t, documento.CNPJValido(cnpj), cnpj)
+	}
+
+	invalidos := []string{"", "11222333000180", "11222333000191", "00000000000000", "1122233300018", "52998224725", "11.222.333/0001-8x"}
+	for _, cnpj := range invalidos {
+		assert.False(t, documento.CNPJValido(cnpj), cnpj)
+	}
+}
+
+func TestDocumento_Normalizar(t *testing.T) {
+	assert.Equal(t, "52998224725", documento.Normalizar("529.982.247-25"))
+	assert.Equal(t, "11222333000181", documento.Normalizar(" 11.222.333/0001-81 "))
+	assert.Equal(t, "52998224725", documento.Normalizar("52998224725"))
+	assert.Equal(t, "", documento.Normalizar(""))
+}
+
+func TestDocumento_InscricaoEstadual(t *testing.T) {
+	validas := []string{"110.042.490.114", "110042490114", "ISENTO", "isento", "12"}
+	for _, ie := range validas {
+		assert.True(t, documento.InscricaoEstadualValida(ie), ie)
+	}
+
+	invalidas := []string{"", "1", "123456789012345", "12A", "isenta"}
+	for _, ie := range invalidas {
+		assert.False(t, documento.InscricaoEstadualValida(ie), ie)
+	}
+
+	assert.Equal(t, "110042490114", documento.NormalizarInscricaoEstadual("110.042.490.114"))
+	assert.Equal(t, documento.Isento, documento.NormalizarInscricaoEstadual(" Isento "))
+}
@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEnderecoRepository is a mock implementation of EnderecoRepository
+type MockEnderecoRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnderecoRepository) Create(ctx context.Context, endereco *model.Endereco) error {
+	args := m.Called(ctx, endereco)
+	return args.Error(0)
+}
+
+func (m *MockEnderecoRepository) FindByClienteID(ctx context.Context, clienteID uint) ([]model.Endereco, error) {
+	args := m.Called(ctx, clienteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Endereco), args.Error(1)
+}
+
+func (m *MockEnderecoRepository) FindByID(ctx context.Context, id uint) (*model.Endereco, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Endereco), args.Error(1)
+}
+
+func (m *MockEnderecoRepository) FindPadrao(ctx context.Context, clienteID uint) (*model.Endereco, error) {
+	args := m.Called(ctx, clienteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Endereco), args.Error(1)
+}
+
+func (m *MockEnderecoRepository) Update(ctx context.Context, endereco *model.Endereco) error {
+	args := m.Called(ctx, endereco)
+	return args.Error(0)
+}
+
+func (m *MockEnderecoRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEnderecoRepository) DesmarcarPadrao(ctx context.Context, clienteID, exceto uint) error {
+	args := m.Called(ctx, clienteID, exceto)
+	return args.Error(0)
+}
+
+// WithTransaction runs fn directly, since there is no database behind the mock
+func (m *MockEnderecoRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func enderecoRequest() *dto.CreateEnderecoRequest {
+	return &dto.CreateEnderecoRequest{
+		Apelido:    "Casa",
+		CEP:        "01310-100",
+		Logradouro: "Avenida Paulista",
+		Numero:     "1000",
+		Bairro:     "Bela Vista",
+		Cidade:     "São Paulo",
+		UF:         "sp",
+	}
+}
+
+func TestEnderecoService_Create_PrimeiroEnderecoEhPadrao(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+	mockRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Endereco")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Endereco).ID = 1
+	}).Return(nil)
+
+	result, err := svc.Create(context.Background(), 1, enderecoRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	assert.Equal(t, "01310100", result.CEP)
+	assert.Equal(t, "SP", result.UF)
+	assert.True(t, result.Padrao)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DesmarcarPadrao", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnderecoService_Create_NovoPadrao(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+	mockRepo.On("FindPadrao", mock.Anything, uint(1)).Return(&model.Endereco{ID: 1, ClienteID: 1, Padrao: true}, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Endereco")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Endereco).ID = 2
+	}).Return(nil)
+	mockRepo.On("DesmarcarPadrao", mock.Anything, uint(1), uint(2)).Return(nil)
+
+	req := enderecoRequest()
+	req.Padrao = true
+	result, err := svc.Create(context.Background(), 1, req)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Padrao)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEnderecoService_Create_ValidationError(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	req := enderecoRequest()
+	req.CEP = "1310-100"
+	req.UF = "XX"
+	result, err := svc.Create(context.Background(), 1, req)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	mockClienteRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestEnderecoService_FindByID_OutroCliente(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(3)).Return(&model.Endereco{ID: 3, ClienteID: 2}, nil)
+
+	result, err := svc.FindByID(context.Background(), 1, 3)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrNotFound))
+}
+
+func TestEnderecoService_Update_DesmarcarPadrao(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Endereco{ID: 1, ClienteID: 1, Padrao: true, Versao: 1}, nil)
+
+	padrao := false
+	result, err := svc.Update(context.Background(), 1, 1, &dto.UpdateEnderecoRequest{
+		CEP:        "01310100",
+		Logradouro: "Avenida Paulista",
+		Numero:     "1000",
+		Bairro:     "Bela Vista",
+		Cidade:     "São Paulo",
+		UF:         "SP",
+		Padrao:     &padrao,
+	})
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrBusinessRule))
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestEnderecoService_Delete_PromovePadrao(t *testing.T) {
+	mockRepo := new(MockEnderecoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	svc := service.NewEnderecoService(mockRepo, mockClienteRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Endereco{ID: 1, ClienteID: 1, Padrao: true, Versao: 1}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	mockRepo.On("FindByClienteID", mock.Anything, uint(1)).Return([]model.Endereco{{ID: 2, ClienteID: 1}, {ID: 3, ClienteID: 1}}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *model.Endereco) bool {
+		return e.ID == 2 && e.Padrao
+	})).Return(nil)
+
+	err := svc.Delete(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag_Format(t *testing.T) {
+	assert.Equal(t, `"1"`, etag.Format(1))
+	assert.Equal(t, `"42"`, etag.Format(42))
+}
+
+func TestETag_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"mesma versão", `"3"`, true},
+		{"outra versão", `"2"`, false},
+		{"lista com a versão", `"1", "3"`, true},
+		{"curinga", "*", true},
+		{"prefixo fraco", `W/"3"`, false},
+		{"sem aspas", "3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etag.Matches(tt.header, 3))
+		})
+	}
+}
+
+func TestETag_WeakMatches(t *testing.T) {
+	assert.True(t, etag.WeakMatches(`"3"`, 3))
+	assert.True(t, etag.WeakMatches(`W/"3"`, 3))
+	assert.True(t, etag.WeakMatches(`W/"1", W/"3"`, 3))
+	assert.False(t, etag.WeakMatches(`W/"2"`, 3))
+}
+
+func TestETag_Check(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, etag.HasIfMatch(ctx))
+	assert.NoError(t, etag.Check(ctx, 5), "sem If-Match não há pré-condição")
+
+	ctx = etag.WithIfMatch(ctx, "")
+	assert.False(t, etag.HasIfMatch(ctx), "cabeçalho vazio não cria pré-condição")
+
+	ctx = etag.WithIfMatch(context.Background(), `"5"`)
+	assert.True(t, etag.HasIfMatch(ctx))
+	assert.NoError(t, etag.Check(ctx, 5))
+
+	// If-Match usa a comparação forte: a ETag fraca da mesma versão não vale
+	assert.ErrorIs(t, etag.Check(etag.WithIfMatch(context.Background(), `W/"5"`), 5), apperror.ErrPreconditionFailed)
+
+	err := etag.Check(ctx, 6)
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	assert.Contains(t, err.Error(), `"6"`)
+}
@@ -0,0 +1,244 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/frete"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFreteRepository is a mock implementation of FreteRepository
+type MockFreteRepository struct {
+	mock.Mock
+}
+
+func (m *MockFreteRepository) CreateZona(ctx context.Context, zona *model.FreteZona) error {
+	args := m.Called(ctx, zona)
+	return args.Error(0)
+}
+
+func (m *MockFreteRepository) FindAllZonas(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.FreteZona], error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Page[model.FreteZona]), args.Error(1)
+}
+
+func (m *MockFreteRepository) FindZonaByID(ctx context.Context, id uint) (*model.FreteZona, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FreteZona), args.Error(1)
+}
+
+func (m *MockFreteRepository) FindZonasAtivas(ctx context.Context, cep string) ([]model.FreteZona, error) {
+	args := m.Called(ctx, cep)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FreteZona), args.Error(1)
+}
+
+func (m *MockFreteRepository) UpdateZona(ctx context.Context, zona *model.FreteZona) error {
+	args := m.Called(ctx, zona)
+	return args.Error(0)
+}
+
+func (m *MockFreteRepository) DeleteZona(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockFreteCalculator is a mock implementation of frete.Calculator
+type MockFreteCalculator struct {
+	mock.Mock
+}
+
+func (m *MockFreteCalculator) Cotar(ctx context.Context, pacote frete.Pacote) ([]frete.Opcao, error) {
+	args := m.Called(ctx, pacote)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]frete.Opcao), args.Error(1)
+}
+
+func TestVolume_PesoTaxavel(t *testing.T) {
+	tests := []struct {
+		name   string
+		volume frete.Volume
+		want   int
+	}{
+		{"peso real maior", frete.Volume{PesoGramas: 2000, AlturaCm: 10, LarguraCm: 10, ComprimentoCm: 10, Quantidade: 1}, 2000},
+		// 30 x 40 x 50 cm = 60000 cm³ = 10 kg cubados
+		{"peso cubado maior", frete.Volume{PesoGramas: 2000, AlturaCm: 30, LarguraCm: 40, ComprimentoCm: 50, Quantidade: 1}, 10000},
+		{"multiplica pela quantidade", frete.Volume{PesoGramas: 500, Quantidade: 3}, 1500},
+		{"sem peso nem dimensões", frete.Volume{Quantidade: 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.volume.PesoTaxavel())
+		})
+	}
+}
+
+func TestTabela_Cotar(t *testing.T) {
+	mockRepo := new(MockFreteRepository)
+	calculator := frete.NewTabela(mockRepo)
+
+	mockRepo.On("FindZonasAtivas", mock.Anything, "01310100").Return([]model.FreteZona{
+		{ID: 1, Servico: "Expresso", PrazoDias: 2, Faixas: []model.FreteFaixa{
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("20.00")},
+			{PesoMaximoGramas: 5000, Valor: money.MustParse("35.00")},
+		}},
+		{ID: 2, Servico: "Econômico", PrazoDias: 7, Faixas: []model.FreteFaixa{
+			{PesoMaximoGramas: 5000, Valor: money.MustParse("15.00")},
+		}},
+		// zona sobreposta do mesmo serviço, mais barata
+		{ID: 3, Servico: "Expresso", PrazoDias: 3, Faixas: []model.FreteFaixa{
+			{PesoMaximoGramas: 3000, Valor: money.MustParse("30.00")},
+		}},
+		// não comporta o peso
+		{ID: 4, Servico: "Moto", PrazoDias: 1, Faixas: []model.FreteFaixa{
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("10.00")},
+		}},
+	}, nil)
+
+	opcoes, err := calculator.Cotar(context.Background(), frete.Pacote{
+		CEP:     "01310100",
+		Volumes: []frete.Volume{{PesoGramas: 1200, Quantidade: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []frete.Opcao{
+		{Servico: "Econômico", Valor: money.MustParse("15.00"), PrazoDias: 7, ZonaID: 2},
+		{Servico: "Expresso", Valor: money.MustParse("30.00"), PrazoDias: 3, ZonaID: 3},
+	}, opcoes)
+}
+
+func TestFreteService_Cotar(t *testing.T) {
+	mockRepo := new(MockFreteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockCalculator := new(MockFreteCalculator)
+	svc := service.NewFreteService(mockRepo, mockProdutoRepo, mockCalculator)
+
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID: 1, PesoGramas: 800, AlturaCm: 10, LarguraCm: 20, ComprimentoCm: 30,
+	}, nil)
+	pacote := frete.Pacote{
+		CEP:     "01310100",
+		Volumes: []frete.Volume{{PesoGramas: 800, AlturaCm: 10, LarguraCm: 20, ComprimentoCm: 30, Quantidade: 2}},
+	}
+	mockCalculator.On("Cotar", mock.Anything, pacote).Return([]frete.Opcao{
+		{Servico: "PAC", Valor: money.MustParse("18.50"), PrazoDias: 6},
+	}, nil)
+
+	result, err := svc.Cotar(context.Background(), &dto.CotacaoFreteRequest{
+		CEP:   "01310-100",
+		Itens: []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "01310100", result.CEP)
+	// 10 x 20 x 30 / 6 = 1000 g cubados por unidade
+	assert.Equal(t, 2000, result.PesoGramas)
+	assert.Equal(t, []dto.OpcaoFreteResponse{{Servico: "PAC", Valor: money.MustParse("18.50"), PrazoDias: 6}}, result.Opcoes)
+	mockCalculator.AssertExpectations(t)
+}
+
+func TestFreteService_Cotar_ProdutoNotFound(t *testing.T) {
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewFreteService(nil, mockProdutoRepo, nil)
+
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(99)).Return(nil, apperror.NotFound("produto não encontrado"))
+
+	result, err := svc.Cotar(context.Background(), &dto.CotacaoFreteRequest{
+		CEP:   "01310100",
+		Itens: []dto.CreateItemPedidoRequest{{ProdutoID: 99, Quantidade: 1}},
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Contains(t, err.Error(), "produto 99 não encontrado")
+}
+
+func TestFreteService_CreateZona(t *testing.T) {
+	mockRepo := new(MockFreteRepository)
+	svc := service.NewFreteService(mockRepo, nil, nil)
+
+	mockRepo.On("CreateZona", mock.Anything, mock.MatchedBy(func(z *model.FreteZona) bool {
+		return z.CEPInicio == "01000000" && z.CEPFim == "05999999" && z.Ativo && len(z.Faixas) == 2
+	})).Return(nil)
+
+	result, err := svc.CreateZona(context.Background(), &dto.CreateFreteZonaRequest{
+		Nome:      "Capital SP",
+		Servico:   " Expresso ",
+		CEPInicio: "01000-000",
+		CEPFim:    "05999-999",
+		PrazoDias: 2,
+		Faixas: []dto.FreteFaixaRequest{
+			{PesoMaximoGramas: 1000, Valor: money.MustParse("15.00")},
+			{PesoMaximoGramas: 5000, Valor: money.MustParse("25.90")},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Expresso", result.Servico)
+	assert.Len(t, result.Faixas, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFreteService_CreateZona_Invalida(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.CreateFreteZonaRequest
+		wantMsg string
+	}{
+		{
+			name: "faixa de CEP invertida",
+			req: dto.CreateFreteZonaRequest{
+				Nome: "Zona", Servico: "PAC", CEPInicio: "05999-999", CEPFim: "01000-000",
+				Faixas: []dto.FreteFaixaRequest{{PesoMaximoGramas: 1000}},
+			},
+			wantMsg: "cep_fim deve ser maior ou igual a cep_inicio",
+		},
+		{
+			name: "faixa de peso repetida",
+			req: dto.CreateFreteZonaRequest{
+				Nome: "Zona", Servico: "PAC", CEPInicio: "01000-000", CEPFim: "05999-999",
+				Faixas: []dto.FreteFaixaRequest{{PesoMaximoGramas: 1000}, {PesoMaximoGramas: 1000}},
+			},
+			wantMsg: "faixa de peso de 1000 gramas repetida",
+		},
+		{
+			name: "sem faixas",
+			req: dto.CreateFreteZonaRequest{
+				Nome: "Zona", Servico: "PAC", CEPInicio: "01000-000", CEPFim: "05999-999",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFreteRepository)
+			svc := service.NewFreteService(mockRepo, nil, nil)
+
+			result, err := svc.CreateZona(context.Background(), &tt.req)
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+			mockRepo.AssertNotCalled(t, "CreateZona", mock.Anything, mock.Anything)
+		})
+	}
+}
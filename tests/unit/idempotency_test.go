@@ -0,0 +1,237 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/middleware"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyStore for middleware tests
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	nextID uint
+	keys   map[string]*model.IdempotencyKey
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: map[string]*model.IdempotencyKey{}}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := key.Subject + "|" + key.Chave
+	if existing, ok := s.keys[id]; ok && existing.ExpiresAt.After(time.Now()) &&
+		(!existing.EmAndamento() || existing.ReservadaAte.After(time.Now())) {
+		copied := *existing
+		return &copied, nil
+	}
+	s.nextID++
+	key.ID = s.nextID
+	copied := *key
+	s.keys[id] = &copied
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.keys {
+		if k.ID == key.ID {
+			k.StatusCode = key.StatusCode
+			k.ContentType = key.ContentType
+			k.Cabecalhos = key.Cabecalhos
+			k.Resposta = key.Resposta
+		}
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.keys {
+		if v.ID == id {
+			delete(s.keys, k)
+		}
+	}
+	return nil
+}
+
+func idempotentRequest(path, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Idempotency-Key", key)
+	return req
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("/pedidos", "abc", `{"cliente_id":1}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("/pedidos", "abc", `{"cliente_id":1}`))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_ReplaysHeaders(t *testing.T) {
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/api/v1/pedidos/1")
+		w.Header().Set("ETag", `"1"`)
+		w.Header().Set("X-Outro", "nao")
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/pedidos", "abc", `{}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("/pedidos", "abc", `{}`))
+
+	assert.Equal(t, "/api/v1/pedidos/1", second.Header().Get("Location"))
+	assert.Equal(t, `"1"`, second.Header().Get("ETag"))
+	assert.Empty(t, second.Header().Get("X-Outro"))
+}
+
+func TestIdempotency_MismatchedRequest(t *testing.T) {
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/pedidos", "abc", `{"cliente_id":1}`))
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"different body", idempotentRequest("/pedidos", "abc", `{"cliente_id":2}`)},
+		{"different route", idempotentRequest("/clientes", "abc", `{"cliente_id":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestIdempotency_InFlightDuplicate(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, idempotentRequest("/pedidos", "abc", `{}`))
+		close(done)
+	}()
+	<-started
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("/pedidos", "abc", `{}`))
+	close(finish)
+	<-done
+
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Equal(t, http.StatusCreated, first.Code)
+}
+
+func TestIdempotency_ExpiredReservationIsTakenOver(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	handler := middleware.Idempotency(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	// uma requisição que morreu deixando a reserva, já vencida
+	morta := &model.IdempotencyKey{Subject: "anonimo", Chave: "abc", ReservadaAte: time.Now().Add(-time.Second), ExpiresAt: time.Now().Add(time.Hour)}
+	_, err := store.Reserve(context.Background(), morta)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest("/pedidos", "abc", `{}`))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("/pedidos", "abc", `{}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("/pedidos", "abc", `{}`))
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_IgnoredRequests(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// sem a chave cada POST é executado
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pedidos", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pedidos", nil))
+
+	// a chave só vale para POST
+	put := httptest.NewRequest(http.MethodPut, "/pedidos/1", nil)
+	put.Header.Set("Idempotency-Key", "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), put)
+	handler.ServeHTTP(httptest.NewRecorder(), put)
+
+	assert.Equal(t, 4, calls)
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest("/pedidos", strings.Repeat("a", 256), `{}`))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
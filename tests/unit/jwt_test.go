@@ -0,0 +1,148 @@
+package unit
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "segredo-de-teste-com-pelo-menos-32-bytes"
+
+func newTestHS256(t *testing.T) *auth.JWT {
+	t.Helper()
+	jwt, err := auth.NewHS256([]byte(testSecret), "cliente-api")
+	assert.NoError(t, err)
+	return jwt
+}
+
+func TestJWT_HS256_IssueAndVerify(t *testing.T) {
+	jwt := newTestHS256(t)
+
+	token, err := jwt.Issue("maria", []string{auth.RoleOperador}, time.Hour)
+	assert.NoError(t, err)
+
+	principal, err := jwt.Verify(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "maria", principal.Subject)
+	assert.Equal(t, []string{auth.RoleOperador}, principal.Roles)
+}
+
+func TestJWT_RS256_IssueAndVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	issuer, err := auth.NewRS256(&key.PublicKey, key, "cliente-api")
+	assert.NoError(t, err)
+	verifier, err := auth.NewRS256(&key.PublicKey, nil, "cliente-api")
+	assert.NoError(t, err)
+
+	token, err := issuer.Issue("joao", []string{auth.RoleLeitura}, time.Hour)
+	assert.NoError(t, err)
+
+	principal, err := verifier.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "joao", principal.Subject)
+
+	// sem chave privada não é possível emitir
+	_, err = verifier.Issue("joao", nil, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestJWT_HS256_ShortSecret(t *testing.T) {
+	_, err := auth.NewHS256([]byte("curto"), "")
+	assert.Error(t, err)
+}
+
+func TestJWT_Verify_Expired(t *testing.T) {
+	jwt := newTestHS256(t)
+
+	token, err := jwt.Issue("maria", []string{auth.RoleAdmin}, -time.Hour)
+	assert.NoError(t, err)
+
+	_, err = jwt.Verify(token)
+	assert.ErrorIs(t, err, auth.ErrTokenExpirado)
+}
+
+func TestJWT_Verify_InvalidTokens(t *testing.T) {
+	jwt := newTestHS256(t)
+	token, err := jwt.Issue("maria", []string{auth.RoleLeitura}, time.Hour)
+	assert.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	other, err := auth.NewHS256([]byte("outro-segredo-com-pelo-menos-32-bytes!!"), "cliente-api")
+	assert.NoError(t, err)
+	otherToken, err := other.Issue("maria", []string{auth.RoleAdmin}, time.Hour)
+	assert.NoError(t, err)
+
+	otherIssuer, err := auth.NewHS256([]byte(testSecret), "outro-emissor")
+	assert.NoError(t, err)
+	otherIssuerToken, err := otherIssuer.Issue("maria", []string{auth.RoleAdmin}, time.Hour)
+	assert.NoError(t, err)
+
+	// payload adulterado para promover o usuário a admin
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"maria","roles":["admin"],"iss":"cliente-api","exp":9999999999}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "abc.def"},
+		{"wrong secret", otherToken},
+		{"wrong issuer", otherIssuerToken},
+		{"tampered payload", parts[0] + "." + forgedPayload + "." + parts[2]},
+		{"alg none", noneHeader + "." + forgedPayload + "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwt.Verify(tt.token)
+			assert.ErrorIs(t, err, auth.ErrTokenInvalido)
+		})
+	}
+}
+
+func TestPrincipal_HasRole_Hierarchy(t *testing.T) {
+	admin := &auth.Principal{Roles: []string{auth.RoleAdmin}}
+	operador := &auth.Principal{Roles: []string{auth.RoleOperador}}
+	leitura := &auth.Principal{Roles: []string{auth.RoleLeitura}}
+
+	assert.True(t, admin.HasRole(auth.RoleLeitura))
+	assert.True(t, admin.HasRole(auth.RoleOperador))
+	assert.True(t, operador.HasRole(auth.RoleLeitura))
+	assert.False(t, operador.HasRole(auth.RoleAdmin))
+	assert.False(t, leitura.HasRole(auth.RoleOperador))
+	assert.False(t, (&auth.Principal{Roles: []string{"desconhecido"}}).HasRole(auth.RoleLeitura))
+}
+
+func TestPrincipal_Can_Scopes(t *testing.T) {
+	apiKey := &auth.Principal{Subject: "apikey:ak_1", Scopes: []string{"pedidos:write", "produtos:read"}}
+
+	assert.True(t, apiKey.Can(auth.RecursoPedidos, auth.RoleOperador))
+	assert.True(t, apiKey.Can(auth.RecursoPedidos, auth.RoleLeitura))
+	assert.False(t, apiKey.Can(auth.RecursoPedidos, auth.RoleAdmin))
+	assert.True(t, apiKey.Can(auth.RecursoProdutos, auth.RoleLeitura))
+	assert.False(t, apiKey.Can(auth.RecursoProdutos, auth.RoleOperador))
+	assert.False(t, apiKey.Can(auth.RecursoClientes, auth.RoleLeitura))
+
+	// usuários continuam autorizados pelo papel em qualquer recurso
+	operador := &auth.Principal{Subject: "maria", Roles: []string{auth.RoleOperador}}
+	assert.True(t, operador.Can(auth.RecursoClientes, auth.RoleOperador))
+	assert.False(t, operador.Can(auth.RecursoClientes, auth.RoleAdmin))
+}
+
+func TestValidScope(t *testing.T) {
+	assert.True(t, auth.ValidScope("pedidos:write"))
+	assert.True(t, auth.ValidScope("clientes:read"))
+	assert.True(t, auth.ValidScope("produtos:admin"))
+	assert.False(t, auth.ValidScope("pedidos"))
+	assert.False(t, auth.ValidScope("pedidos:delete"))
+	assert.False(t, auth.ValidScope("usuarios:read"))
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/danmaciel/api/internal/mergepatch"
+	"github.com/stretchr/testify/assert"
+)
+
+// casos do apêndice A da RFC 7396
+func TestMergePatch_Merge(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" + "+tt.patch, func(t *testing.T) {
+			got, err := mergepatch.Merge([]byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestMergePatch_PreservesNumbers(t *testing.T) {
+	got, err := mergepatch.Merge([]byte(`{"id":9007199254740993,"preco":"10.00"}`), []byte(`{"estoque":5}`))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":9007199254740993,"preco":"10.00","estoque":5}`, string(got))
+}
+
+func TestMergePatch_InvalidJSON(t *testing.T) {
+	_, err := mergepatch.Merge([]byte(`{}`), []byte(`{"a":`))
+	assert.Error(t, err)
+}
+
+func TestMergePatch_Apply(t *testing.T) {
+	type recurso struct {
+		Nome     string `json:"nome"`
+		Telefone string `json:"telefone,omitempty"`
+		Estoque  *int   `json:"estoque"`
+	}
+	estoque := 10
+	atual := recurso{Nome: "João", Telefone: "11999999999", Estoque: &estoque}
+
+	var resultado recurso
+	err := mergepatch.Apply(atual, []byte(`{"telefone":null,"nome":"Maria"}`), &resultado)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Maria", resultado.Nome)
+	assert.Empty(t, resultado.Telefone)
+	assert.Equal(t, 10, *resultado.Estoque)
+}
@@ -8,7 +8,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/middleware"
 	"github.com/stretchr/testify/assert"
 )
@@ -82,7 +84,8 @@ func TestRecovery(t *testing.T) {
 	recoveryMiddleware.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Internal Server Error")
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"status":500`)
 
 	logOutput := buf.String()
 	assert.Contains(t, logOutput, "PANIC: test panic")
@@ -200,8 +203,8 @@ func TestMiddlewareChain_WithPanic(t *testing.T) {
 	wrapped.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	// http.Error sets Content-Type to "text/plain; charset=utf-8"
-	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	// Recovery responds with a problem+json body, overriding the default content type
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
 
 	logOutput := buf.String()
 	assert.Contains(t, logOutput, "PANIC: middleware chain panic")
@@ -253,3 +256,85 @@ func TestRecovery_DifferentPanicTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticate(t *testing.T) {
+	jwt, err := auth.NewHS256([]byte(testSecret), "cliente-api")
+	assert.NoError(t, err)
+	token, err := jwt.Issue("maria", []string{auth.RoleOperador}, time.Hour)
+	assert.NoError(t, err)
+	expired, err := jwt.Issue("maria", []string{auth.RoleOperador}, -time.Hour)
+	assert.NoError(t, err)
+
+	var subject string
+	handler := middleware.Authenticate(jwt)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = auth.Actor(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+		expectedDetail string
+	}{
+		{"valid token", "Bearer " + token, http.StatusOK, ""},
+		{"missing header", "", http.StatusUnauthorized, "token de acesso ausente"},
+		{"wrong scheme", "Basic " + token, http.StatusUnauthorized, "token de acesso ausente"},
+		{"invalid token", "Bearer abc.def.ghi", http.StatusUnauthorized, "token de acesso inválido"},
+		{"expired token", "Bearer " + expired, http.StatusUnauthorized, "token de acesso expirado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "maria", subject)
+				return
+			}
+			assert.Empty(t, subject)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			assert.Contains(t, rec.Body.String(), tt.expectedDetail)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	handler := middleware.RequireRole(auth.RoleOperador)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		expectedStatus int
+	}{
+		{"admin inherits operador", &auth.Principal{Subject: "a", Roles: []string{auth.RoleAdmin}}, http.StatusOK},
+		{"operador", &auth.Principal{Subject: "o", Roles: []string{auth.RoleOperador}}, http.StatusOK},
+		{"leitura", &auth.Principal{Subject: "l", Roles: []string{auth.RoleLeitura}}, http.StatusForbidden},
+		{"no principal", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/test", nil)
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/danmaciel/api/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_Parse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected money.Money
+		wantErr  bool
+	}{
+		{"12.34", money.FromCentavos(1234), false},
+		{"12.3", money.FromCentavos(1230), false},
+		{"12", money.FromCentavos(1200), false},
+		{"0.01", money.FromCentavos(1), false},
+		{"-5.50", money.FromCentavos(-550), false},
+		{"12.345", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+		{".50", 0, true},
+		{"1.2.3", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := money.Parse(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, money.ErrValorInvalido)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "12.34", money.FromCentavos(1234).String())
+	assert.Equal(t, "0.05", money.FromCentavos(5).String())
+	assert.Equal(t, "-0.50", money.FromCentavos(-50).String())
+	assert.Equal(t, "0.00", money.Money(0).String())
+}
+
+func TestMoney_SumIsExact(t *testing.T) {
+	// With float64, 0.1 + 0.2 != 0.3
+	var total money.Money
+	total += money.MustParse("0.10")
+	total += money.MustParse("0.20")
+
+	assert.Equal(t, money.MustParse("0.30"), total)
+	assert.Equal(t, money.MustParse("2999.97"), money.MustParse("999.99").Mul(3))
+}
+
+func TestMoney_Percent(t *testing.T) {
+	tests := []struct {
+		valor      string
+		pontosBase int64
+		expected   string
+	}{
+		{"200.00", 1000, "20.00"},
+		{"99.99", 1500, "15.00"}, // 14.9985 arredonda para cima
+		{"10.01", 1250, "1.25"},  // 1.25125 arredonda para baixo
+		{"0.10", 500, "0.01"},    // meio centavo arredonda para longe do zero
+		{"-0.10", 500, "-0.01"},
+		{"123.45", 10000, "123.45"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.valor, func(t *testing.T) {
+			assert.Equal(t, money.MustParse(tt.expected), money.MustParse(tt.valor).Percent(tt.pontosBase))
+		})
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Preco money.Money `json:"preco"`
+	}{money.FromCentavos(299999)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"preco": "2999.99"}`, string(data))
+
+	var fromString, fromNumber money.Money
+	assert.NoError(t, json.Unmarshal([]byte(`"19.90"`), &fromString))
+	assert.NoError(t, json.Unmarshal([]byte(`19.9`), &fromNumber))
+	assert.Equal(t, money.FromCentavos(1990), fromString)
+	assert.Equal(t, money.FromCentavos(1990), fromNumber)
+
+	var invalid money.Money
+	assert.Error(t, json.Unmarshal([]byte(`"19.999"`), &invalid))
+}
+
+func TestAliquota_JSON(t *testing.T) {
+	a, err := money.ParseAliquota("4.65")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(465), a.PontosBase())
+
+	data, err := json.Marshal(struct {
+		Aliquota money.Aliquota `json:"aliquota"`
+	}{a})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"aliquota": "4.65"}`, string(data))
+
+	var fromNumber money.Aliquota
+	assert.NoError(t, json.Unmarshal([]byte(`18`), &fromNumber))
+	assert.Equal(t, money.Aliquota(1800), fromNumber)
+	assert.Equal(t, money.MustParse("180.00"), money.MustParse("1000.00").Percent(fromNumber.PontosBase()))
+
+	var invalid money.Aliquota
+	assert.Error(t, json.Unmarshal([]byte(`"7.125"`), &invalid))
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m money.Money
+
+	assert.NoError(t, m.Scan(int64(1234)))
+	assert.Equal(t, money.FromCentavos(1234), m)
+
+	assert.NoError(t, m.Scan([]byte("550")))
+	assert.Equal(t, money.FromCentavos(550), m)
+
+	assert.NoError(t, m.Scan(nil))
+	assert.Equal(t, money.Money(0), m)
+
+	assert.Error(t, m.Scan(true))
+}
@@ -0,0 +1,512 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/pagamento"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPagamentoRepository is a mock implementation of PagamentoRepository
+type MockPagamentoRepository struct {
+	mock.Mock
+}
+
+func (m *MockPagamentoRepository) Create(ctx context.Context, pagamento *model.Pagamento) error {
+	args := m.Called(ctx, pagamento)
+	return args.Error(0)
+}
+
+func (m *MockPagamentoRepository) FindByID(ctx context.Context, id uint) (*model.Pagamento, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Pagamento), args.Error(1)
+}
+
+func (m *MockPagamentoRepository) FindByReferencia(ctx context.Context, referencia string) (*model.Pagamento, error) {
+	args := m.Called(ctx, referencia)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Pagamento), args.Error(1)
+}
+
+func (m *MockPagamentoRepository) FindByPedidoID(ctx context.Context, pedidoID uint) ([]model.Pagamento, error) {
+	args := m.Called(ctx, pedidoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Pagamento), args.Error(1)
+}
+
+func (m *MockPagamentoRepository) Update(ctx context.Context, pagamento *model.Pagamento) error {
+	args := m.Called(ctx, pagamento)
+	return args.Error(0)
+}
+
+func (m *MockPagamentoRepository) FindProvisorios(ctx context.Context, antes time.Time) ([]model.Pagamento, error) {
+	args := m.Called(ctx, antes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Pagamento), args.Error(1)
+}
+
+func (m *MockPagamentoRepository) FindPedidosAQuitar(ctx context.Context) ([]uint, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockPagamentoRepository) AddEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error {
+	args := m.Called(ctx, estorno)
+	return args.Error(0)
+}
+
+func (m *MockPagamentoRepository) UpdateEstorno(ctx context.Context, estorno *model.PagamentoEstorno) error {
+	args := m.Called(ctx, estorno)
+	return args.Error(0)
+}
+
+func (m *MockPagamentoRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// gatewayIndisponivel is a provider that cannot be reached for charges and refunds
+type gatewayIndisponivel struct {
+	*pagamento.Fake
+}
+
+func (g gatewayIndisponivel) Cobrar(ctx context.Context, cobranca pagamento.Cobranca) (*pagamento.Transacao, error) {
+	return nil, errors.New("provedor indisponível")
+}
+
+func (g gatewayIndisponivel) Consultar(ctx context.Context, referencia string) (*pagamento.Transacao, error) {
+	return nil, errors.New("provedor indisponível")
+}
+
+func (g gatewayIndisponivel) Estornar(ctx context.Context, referencia string, valor money.Money) (string, error) {
+	return "", errors.New("provedor indisponível")
+}
+
+func TestFakeGateway_Webhook(t *testing.T) {
+	gateway := pagamento.NewFake("segredo")
+	payload := []byte(`{"referencia":"fake_pay_1","status":"confirmado"}`)
+
+	evento, err := gateway.Webhook(payload, gateway.Assinar(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, &pagamento.Evento{Referencia: "fake_pay_1", Status: model.PagamentoConfirmado}, evento)
+
+	_, err = gateway.Webhook(payload, pagamento.NewFake("outro").Assinar(payload))
+	assert.ErrorIs(t, err, pagamento.ErrAssinaturaInvalida)
+
+	// sem segredo configurado nenhuma notificação é aceita
+	semSegredo := pagamento.NewFake("")
+	_, err = semSegredo.Webhook(payload, semSegredo.Assinar(payload))
+	assert.ErrorIs(t, err, pagamento.ErrAssinaturaInvalida)
+
+	invalido := []byte(`{"referencia":"fake_pay_1","status":"estornado"}`)
+	_, err = gateway.Webhook(invalido, gateway.Assinar(invalido))
+	assert.ErrorIs(t, err, pagamento.ErrEventoInvalido)
+}
+
+func TestFakeGateway_Cobrar(t *testing.T) {
+	gateway := pagamento.NewFake("segredo")
+
+	cartao, err := gateway.Cobrar(context.Background(), pagamento.Cobranca{PedidoID: 1, Metodo: model.MetodoCartao, Valor: money.MustParse("10.00")})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoConfirmado, cartao.Status)
+
+	pix, err := gateway.Cobrar(context.Background(), pagamento.Cobranca{PedidoID: 1, Metodo: model.MetodoPix, Valor: money.MustParse("10.00")})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoPendente, pix.Status)
+	assert.NotEqual(t, cartao.Referencia, pix.Referencia)
+}
+
+func TestFakeGateway_CobrarIdempotente(t *testing.T) {
+	gateway := pagamento.NewFake("segredo")
+	cobranca := pagamento.Cobranca{Referencia: "pendente_1", PedidoID: 1, Metodo: model.MetodoCartao, Valor: money.MustParse("10.00")}
+
+	_, err := gateway.Consultar(context.Background(), "pendente_1")
+	assert.ErrorIs(t, err, pagamento.ErrCobrancaNaoEncontrada)
+
+	primeira, err := gateway.Cobrar(context.Background(), cobranca)
+	assert.NoError(t, err)
+	repetida, err := gateway.Cobrar(context.Background(), cobranca)
+	assert.NoError(t, err)
+	assert.Equal(t, primeira, repetida)
+
+	consultada, err := gateway.Consultar(context.Background(), "pendente_1")
+	assert.NoError(t, err)
+	assert.Equal(t, primeira, consultada)
+}
+
+func TestPagamentoService_Create_Parcial(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	pedido := &model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(pedido, nil)
+	// 30,00 já confirmados e 20,00 em cobrança
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("30.00"), Status: model.PagamentoConfirmado},
+		{ID: 2, PedidoID: 1, Valor: money.MustParse("20.00"), Status: model.PagamentoPendente},
+		{ID: 3, PedidoID: 1, Valor: money.MustParse("50.00"), Status: model.PagamentoFalhou},
+	}, nil)
+	// a cobrança é gravada com uma referência provisória antes de ir ao provedor
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Valor == money.MustParse("50.00") && p.Status == model.PagamentoPendente && p.Provedor == "fake" &&
+			strings.HasPrefix(p.Referencia, "pendente_")
+	})).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Status == model.PagamentoPendente && strings.HasPrefix(p.Referencia, "fake_pay_")
+	})).Return(nil)
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoPix})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("50.00"), result.Valor)
+	mockRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Create_Recusado(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, gatewayIndisponivel{pagamento.NewFake("segredo")}, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{}, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	// sem resposta não se sabe se houve cobrança: o pagamento continua
+	// pendente com a referência provisória até a conciliação
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Create_QuitacaoFalha(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	// cada leitura devolve um pedido novo, como a releitura de cada transação
+	for range 4 {
+		mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil).Once()
+	}
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{}, nil).Once()
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Status == model.PagamentoConfirmado && strings.HasPrefix(p.Referencia, "fake_pay_")
+	})).Return(nil).Once()
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoConfirmado},
+	}, nil)
+	// a quitação perde todas as corridas pelo pedido
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(apperror.Conflict("pedido alterado"))
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	// a cobrança confirmada continua gravada; a conciliação quita o pedido depois
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoConfirmado, result.Status)
+	mockRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertNumberOfCalls(t, "Update", 3)
+	mockPedidoRepo.AssertNotCalled(t, "AddHistoricoStatus", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Create_QuitacaoRepetida(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	// cada leitura devolve um pedido novo, como a releitura de cada transação
+	for range 3 {
+		mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil).Once()
+	}
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{}, nil).Once()
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pagamento")).Return(nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoConfirmado},
+	}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(apperror.Conflict("pedido alterado")).Once()
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil).Once()
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.StatusNovo == model.StatusPago
+	})).Return(nil)
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoConfirmado, result.Status)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestPagamentoService_Create_ExcedeSaldo(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	pedido := &model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(pedido, nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("60.00"), Status: model.PagamentoPendente},
+	}, nil)
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoPix, Valor: money.MustParse("40.01")})
+
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Create_PedidoNaoPendente(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	pedido := &model.Pedido{ID: 1, Status: model.StatusCancelado, ValorTotal: money.MustParse("100.00")}
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(pedido, nil)
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: model.MetodoCartao})
+
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+}
+
+func TestPagamentoService_Create_InvalidMetodo(t *testing.T) {
+	svc := service.NewPagamentoService(new(MockPagamentoRepository), new(MockPedidoRepository), pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	result, err := svc.Create(context.Background(), 1, &dto.CreatePagamentoRequest{Metodo: "cheque"})
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Nil(t, result)
+}
+
+func TestPagamentoService_Webhook_QuitaPedido(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	gateway := pagamento.NewFake("segredo")
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, gateway, new(MockAuditRepository))
+
+	pendente := &model.Pagamento{ID: 2, PedidoID: 1, Valor: money.MustParse("60.00"), Status: model.PagamentoPendente, Referencia: "fake_pay_2"}
+	mockRepo.On("FindByReferencia", mock.Anything, "fake_pay_2").Return(pendente, nil)
+	mockRepo.On("Update", mock.Anything, pendente).Return(nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("50.00"), Estornado: money.MustParse("10.00"), Status: model.PagamentoConfirmado},
+		{ID: 2, PedidoID: 1, Valor: money.MustParse("60.00"), Status: model.PagamentoConfirmado},
+	}, nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pedido) bool {
+		return p.Status == model.StatusPago
+	})).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.StatusNovo == model.StatusPago && h.Usuario == "gateway:fake"
+	})).Return(nil)
+
+	payload := []byte(`{"referencia":"fake_pay_2","status":"confirmado"}`)
+	err := svc.Webhook(context.Background(), payload, gateway.Assinar(payload))
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoConfirmado, pendente.Status)
+	assert.NotNil(t, pendente.ConfirmadoEm)
+	mockRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestPagamentoService_Webhook_NaoCobreTotal(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	gateway := pagamento.NewFake("segredo")
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, gateway, new(MockAuditRepository))
+
+	pendente := &model.Pagamento{ID: 1, PedidoID: 1, Valor: money.MustParse("40.00"), Status: model.PagamentoPendente, Referencia: "fake_pay_1"}
+	mockRepo.On("FindByReferencia", mock.Anything, "fake_pay_1").Return(pendente, nil)
+	mockRepo.On("Update", mock.Anything, pendente).Return(nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{*pendente}, nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil)
+
+	payload := []byte(`{"referencia":"fake_pay_1","status":"confirmado"}`)
+	err := svc.Webhook(context.Background(), payload, gateway.Assinar(payload))
+
+	assert.NoError(t, err)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Webhook_AssinaturaInvalida(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	err := svc.Webhook(context.Background(), []byte(`{"referencia":"fake_pay_1","status":"confirmado"}`), "assinatura")
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "FindByReferencia", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Webhook_Repetido(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	gateway := pagamento.NewFake("segredo")
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), gateway, new(MockAuditRepository))
+
+	falhou := &model.Pagamento{ID: 1, PedidoID: 1, Status: model.PagamentoFalhou, Referencia: "fake_pay_1"}
+	mockRepo.On("FindByReferencia", mock.Anything, "fake_pay_1").Return(falhou, nil)
+
+	payload := []byte(`{"referencia":"fake_pay_1","status":"falhou"}`)
+	assert.NoError(t, svc.Webhook(context.Background(), payload, gateway.Assinar(payload)))
+
+	// um pagamento que falhou não pode ser confirmado depois
+	payload = []byte(`{"referencia":"fake_pay_1","status":"confirmado"}`)
+	assert.ErrorIs(t, svc.Webhook(context.Background(), payload, gateway.Assinar(payload)), apperror.ErrConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Estornar_Total(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	confirmado := &model.Pagamento{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Estornado: money.MustParse("30.00"), Status: model.PagamentoConfirmado, Referencia: "fake_pay_1"}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(confirmado, nil)
+	// o estorno é gravado pendente e concluído com a referência do provedor depois do commit
+	mockRepo.On("AddEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Valor == money.MustParse("70.00") && e.Status == model.EstornoPendente && e.Referencia == ""
+	})).Return(nil)
+	mockRepo.On("Update", mock.Anything, confirmado).Return(nil)
+	mockRepo.On("UpdateEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Status == model.EstornoConcluido && strings.HasPrefix(e.Referencia, "fake_ref_")
+	})).Return(nil)
+
+	result, err := svc.Estornar(context.Background(), 1, 1, &dto.CreateEstornoRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagamentoEstornado, result.Status)
+	assert.Equal(t, money.MustParse("100.00"), result.Estornado)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPagamentoService_Estornar_Recusado(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), gatewayIndisponivel{pagamento.NewFake("segredo")}, new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pagamento{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoConfirmado, Referencia: "fake_pay_1"}, nil).Once()
+	mockRepo.On("AddEstorno", mock.Anything, mock.AnythingOfType("*model.PagamentoEstorno")).Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Status == model.PagamentoEstornado && p.Estornado == money.MustParse("100.00")
+	})).Return(nil).Once()
+	// a recusa libera o valor reservado e o pagamento volta a ficar confirmado
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pagamento{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Estornado: money.MustParse("100.00"), Status: model.PagamentoEstornado, Referencia: "fake_pay_1"}, nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.Status == model.PagamentoConfirmado && p.Estornado == 0
+	})).Return(nil).Once()
+	mockRepo.On("UpdateEstorno", mock.Anything, mock.MatchedBy(func(e *model.PagamentoEstorno) bool {
+		return e.Status == model.EstornoFalhou
+	})).Return(nil)
+
+	result, err := svc.Estornar(context.Background(), 1, 1, &dto.CreateEstornoRequest{})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPagamentoService_Estornar_OutroPedido(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pagamento{ID: 1, PedidoID: 2, Status: model.PagamentoConfirmado}, nil)
+
+	result, err := svc.Estornar(context.Background(), 1, 1, &dto.CreateEstornoRequest{})
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.Nil(t, result)
+}
+
+func TestPagamentoService_Estornar_Pendente(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), pagamento.NewFake("segredo"), new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pagamento{ID: 1, PedidoID: 1, Status: model.PagamentoPendente}, nil)
+
+	result, err := svc.Estornar(context.Background(), 1, 1, &dto.CreateEstornoRequest{})
+
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "AddEstorno", mock.Anything, mock.Anything)
+}
+
+func TestPagamentoService_Conciliar(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	mockPedidoRepo := new(MockPedidoRepository)
+	gateway := pagamento.NewFake("segredo")
+	svc := service.NewPagamentoService(mockRepo, mockPedidoRepo, gateway, new(MockAuditRepository))
+
+	// o provedor recebeu a primeira cobrança, mas a resposta se perdeu
+	recebida, err := gateway.Cobrar(context.Background(), pagamento.Cobranca{Referencia: "pendente_1", PedidoID: 1, Metodo: model.MetodoCartao, Valor: money.MustParse("100.00")})
+	assert.NoError(t, err)
+	antes := time.Now().Add(-5 * time.Minute)
+	mockRepo.On("FindProvisorios", mock.Anything, antes).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoPendente, Referencia: "pendente_1"},
+		{ID: 2, PedidoID: 2, Valor: money.MustParse("30.00"), Status: model.PagamentoPendente, Referencia: "pendente_2"},
+	}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.ID == 1 && p.Referencia == recebida.Referencia && p.Status == model.PagamentoConfirmado && p.ConfirmadoEm != nil
+	})).Return(nil)
+	// a que o provedor não conhece expira e libera o saldo
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pagamento) bool {
+		return p.ID == 2 && p.Referencia == "pendente_2" && p.Status == model.PagamentoFalhou
+	})).Return(nil)
+	mockRepo.On("FindPedidosAQuitar", mock.Anything).Return([]uint{1}, nil)
+	mockRepo.On("FindByPedidoID", mock.Anything, uint(1)).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoConfirmado},
+	}, nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: model.StatusPendente, ValorTotal: money.MustParse("100.00")}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *model.Pedido) bool {
+		return p.Status == model.StatusPago
+	})).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.StatusNovo == model.StatusPago && h.Usuario == "gateway:fake"
+	})).Return(nil)
+
+	err = svc.Conciliar(context.Background(), antes)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestPagamentoService_Conciliar_ProvedorIndisponivel(t *testing.T) {
+	mockRepo := new(MockPagamentoRepository)
+	gateway := gatewayIndisponivel{pagamento.NewFake("segredo")}
+	svc := service.NewPagamentoService(mockRepo, new(MockPedidoRepository), gateway, new(MockAuditRepository))
+
+	antes := time.Now()
+	mockRepo.On("FindProvisorios", mock.Anything, antes).Return([]model.Pagamento{
+		{ID: 1, PedidoID: 1, Valor: money.MustParse("100.00"), Status: model.PagamentoPendente, Referencia: "pendente_1"},
+	}, nil)
+	mockRepo.On("FindPedidosAQuitar", mock.Anything).Return([]uint{}, nil)
+
+	err := svc.Conciliar(context.Background(), antes)
+
+	// sem resposta do provedor o pagamento continua pendente para a próxima rodada
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
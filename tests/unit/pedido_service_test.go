@@ -5,8 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
+	"github.com/danmaciel/api/internal/frete"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,12 +28,12 @@ func (m *MockPedidoRepository) Create(ctx context.Context, pedido *model.Pedido)
 	return args.Error(0)
 }
 
-func (m *MockPedidoRepository) FindAll(ctx context.Context) ([]model.Pedido, error) {
-	args := m.Called(ctx)
+func (m *MockPedidoRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.Pedido], error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Pedido), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Pedido]), args.Error(1)
 }
 
 func (m *MockPedidoRepository) FindByID(ctx context.Context, id uint) (*model.Pedido, error) {
@@ -38,20 +44,20 @@ func (m *MockPedidoRepository) FindByID(ctx context.Context, id uint) (*model.Pe
 	return args.Get(0).(*model.Pedido), args.Error(1)
 }
 
-func (m *MockPedidoRepository) FindByClienteID(ctx context.Context, clienteID uint) ([]model.Pedido, error) {
-	args := m.Called(ctx, clienteID)
+func (m *MockPedidoRepository) FindByClienteID(ctx context.Context, clienteID uint, opts repository.ListOptions) (*repository.Page[model.Pedido], error) {
+	args := m.Called(ctx, clienteID, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Pedido), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Pedido]), args.Error(1)
 }
 
-func (m *MockPedidoRepository) FindByStatus(ctx context.Context, status string) ([]model.Pedido, error) {
-	args := m.Called(ctx, status)
+func (m *MockPedidoRepository) FindByStatus(ctx context.Context, status string, opts repository.ListOptions) (*repository.Page[model.Pedido], error) {
+	args := m.Called(ctx, status, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Pedido), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Pedido]), args.Error(1)
 }
 
 func (m *MockPedidoRepository) Update(ctx context.Context, pedido *model.Pedido) error {
@@ -69,12 +75,56 @@ func (m *MockPedidoRepository) Count(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockPedidoRepository) AddHistoricoStatus(ctx context.Context, historico *model.PedidoStatusHistorico) error {
+	args := m.Called(ctx, historico)
+	return args.Error(0)
+}
+
+func (m *MockPedidoRepository) FindHistoricoStatus(ctx context.Context, pedidoID uint) ([]model.PedidoStatusHistorico, error) {
+	args := m.Called(ctx, pedidoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PedidoStatusHistorico), args.Error(1)
+}
+
+func (m *MockPedidoRepository) AddDevolucao(ctx context.Context, devolucao *model.PedidoDevolucao) error {
+	args := m.Called(ctx, devolucao)
+	return args.Error(0)
+}
+
+func (m *MockPedidoRepository) UpdateReembolsado(ctx context.Context, devolucao *model.PedidoDevolucao) error {
+	args := m.Called(ctx, devolucao)
+	return args.Error(0)
+}
+
+func (m *MockPedidoRepository) CountPagamentosEmAberto(ctx context.Context, pedidoID uint) (int64, error) {
+	args := m.Called(ctx, pedidoID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPedidoRepository) UpdateItens(ctx context.Context, pedido *model.Pedido) error {
+	args := m.Called(ctx, pedido)
+	return args.Error(0)
+}
+
+// WithTransaction runs fn directly, since there is no database behind the mock
+func (m *MockPedidoRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 // Test cases
 func TestPedidoService_Create_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	mockImpostoRepo := new(MockRegraImpostoRepository)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, mockImpostoRepo, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+	mockImpostoRepo.On("FindAtivas", mock.Anything, "").Return([]model.RegraImposto{}, nil)
+	// cliente sem endereços: o pedido fica sem endereço de entrega
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
 
 	// Mock cliente exists
 	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{
@@ -87,30 +137,34 @@ func TestPedidoService_Create_Success(t *testing.T) {
 	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
 		ID:      1,
 		Nome:    "Notebook",
-		Preco:   2999.99,
+		Preco:   money.MustParse("2999.99"),
 		Estoque: 10,
 		SKU:     "NB-001",
 		Ativo:   true,
 	}, nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 2).Return(nil)
 
 	mockPedidoRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pedido")).Run(func(args mock.Arguments) {
 		pedido := args.Get(1).(*model.Pedido)
 		pedido.ID = 1
 	}).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.PedidoID == 1 && h.StatusAnterior == "" && h.StatusNovo == "pendente"
+	})).Return(nil)
 
 	// Mock FindByID after create to return the complete pedido
 	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
 		ID:         1,
 		ClienteID:  1,
-		ValorTotal: 5999.98,
+		ValorTotal: money.MustParse("5999.98"),
 		Status:     "pendente",
 		DataPedido: time.Now(),
 		Itens: []model.PedidoProduto{
 			{
 				ProdutoID:     1,
 				Quantidade:    2,
-				PrecoUnitario: 2999.99,
-				Subtotal:      5999.98,
+				PrecoUnitario: money.MustParse("2999.99"),
+				Subtotal:      money.MustParse("5999.98"),
 			},
 		},
 	}, nil)
@@ -136,7 +190,7 @@ func TestPedidoService_Create_ValidationError(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	req := &dto.CreatePedidoRequest{
 		ClienteID: 0, // Invalid: missing cliente_id
@@ -147,17 +201,17 @@ func TestPedidoService_Create_ValidationError(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "ClienteID")
+	assert.Contains(t, err.Error(), "cliente_id")
 }
 
 func TestPedidoService_Create_ClienteNotFound(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	// Mock cliente not found - return error
-	mockClienteRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), assert.AnError)
+	mockClienteRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Cliente)(nil), apperror.NotFound("cliente não encontrado"))
 
 	req := &dto.CreatePedidoRequest{
 		ClienteID: 999,
@@ -170,6 +224,7 @@ func TestPedidoService_Create_ClienteNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
 	assert.Contains(t, err.Error(), "não encontrado")
 	mockClienteRepo.AssertExpectations(t)
 }
@@ -178,15 +233,17 @@ func TestPedidoService_Create_ProdutoNotFound(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
 
 	// Mock cliente exists
 	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{
 		ID: 1,
 	}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
 
 	// Mock produto not found - return error
-	mockProdutoRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Produto)(nil), assert.AnError)
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Produto)(nil), apperror.NotFound("produto não encontrado"))
 
 	req := &dto.CreatePedidoRequest{
 		ClienteID: 1,
@@ -199,6 +256,7 @@ func TestPedidoService_Create_ProdutoNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
 	assert.Contains(t, err.Error(), "não encontrado")
 	mockClienteRepo.AssertExpectations(t)
 	mockProdutoRepo.AssertExpectations(t)
@@ -208,20 +266,20 @@ func TestPedidoService_FindAll_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	expectedPedidos := []model.Pedido{
-		{ID: 1, ClienteID: 1, ValorTotal: 299.99, Status: "pendente"},
-		{ID: 2, ClienteID: 2, ValorTotal: 499.99, Status: "pago"},
+		{ID: 1, ClienteID: 1, ValorTotal: money.MustParse("299.99"), Status: "pendente"},
+		{ID: 2, ClienteID: 2, ValorTotal: money.MustParse("499.99"), Status: "pago"},
 	}
 
-	mockPedidoRepo.On("FindAll", mock.Anything).Return(expectedPedidos, nil)
+	mockPedidoRepo.On("FindAll", mock.Anything, mock.Anything).Return(&repository.Page[model.Pedido]{Items: expectedPedidos, Total: int64(len(expectedPedidos))}, nil)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockPedidoRepo.AssertExpectations(t)
 }
 
@@ -229,12 +287,12 @@ func TestPedidoService_FindByID_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	expectedPedido := &model.Pedido{
 		ID:         1,
 		ClienteID:  1,
-		ValorTotal: 299.99,
+		ValorTotal: money.MustParse("299.99"),
 		Status:     "pendente",
 		DataPedido: time.Now(),
 	}
@@ -253,7 +311,7 @@ func TestPedidoService_FindByID_NotFound(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	mockPedidoRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Pedido)(nil), assert.AnError)
 
@@ -268,20 +326,20 @@ func TestPedidoService_FindByClienteID_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	expectedPedidos := []model.Pedido{
-		{ID: 1, ClienteID: 1, ValorTotal: 299.99, Status: "pendente"},
-		{ID: 2, ClienteID: 1, ValorTotal: 199.99, Status: "pago"},
+		{ID: 1, ClienteID: 1, ValorTotal: money.MustParse("299.99"), Status: "pendente"},
+		{ID: 2, ClienteID: 1, ValorTotal: money.MustParse("199.99"), Status: "pago"},
 	}
 
-	mockPedidoRepo.On("FindByClienteID", mock.Anything, uint(1)).Return(expectedPedidos, nil)
+	mockPedidoRepo.On("FindByClienteID", mock.Anything, uint(1), mock.Anything).Return(&repository.Page[model.Pedido]{Items: expectedPedidos, Total: int64(len(expectedPedidos))}, nil)
 
-	result, err := svc.FindByClienteID(context.Background(), 1)
+	result, err := svc.FindByClienteID(context.Background(), 1, dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockPedidoRepo.AssertExpectations(t)
 }
 
@@ -289,20 +347,20 @@ func TestPedidoService_FindByStatus_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	expectedPedidos := []model.Pedido{
-		{ID: 1, ClienteID: 1, ValorTotal: 299.99, Status: "pendente"},
-		{ID: 2, ClienteID: 2, ValorTotal: 199.99, Status: "pendente"},
+		{ID: 1, ClienteID: 1, ValorTotal: money.MustParse("299.99"), Status: "pendente"},
+		{ID: 2, ClienteID: 2, ValorTotal: money.MustParse("199.99"), Status: "pendente"},
 	}
 
-	mockPedidoRepo.On("FindByStatus", mock.Anything, "pendente").Return(expectedPedidos, nil)
+	mockPedidoRepo.On("FindByStatus", mock.Anything, "pendente", mock.Anything).Return(&repository.Page[model.Pedido]{Items: expectedPedidos, Total: int64(len(expectedPedidos))}, nil)
 
-	result, err := svc.FindByStatus(context.Background(), "pendente")
+	result, err := svc.FindByStatus(context.Background(), "pendente", dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockPedidoRepo.AssertExpectations(t)
 }
 
@@ -310,23 +368,28 @@ func TestPedidoService_UpdateStatus_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	existingPedido := &model.Pedido{
 		ID:         1,
 		ClienteID:  1,
-		ValorTotal: 299.99,
+		ValorTotal: money.MustParse("299.99"),
 		Status:     "pendente",
 	}
 
 	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(existingPedido, nil)
 	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.StatusAnterior == "pendente" && h.StatusNovo == "pago" && h.Usuario != ""
+	})).Return(nil)
 
 	req := &dto.UpdatePedidoRequest{
 		Status: "pago",
 	}
 
-	result, err := svc.UpdateStatus(context.Background(), 1, req)
+	// a baixa manual como pago exige admin
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleAdmin}})
+	result, err := svc.UpdateStatus(ctx, 1, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -334,11 +397,45 @@ func TestPedidoService_UpdateStatus_Success(t *testing.T) {
 	mockPedidoRepo.AssertExpectations(t)
 }
 
+func TestPedidoService_UpdateStatus_PagoExigeAdmin(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	existingPedido := &model.Pedido{ID: 1, ClienteID: 1, Status: "pendente"}
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(existingPedido, nil)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "maria", Roles: []string{auth.RoleOperador}})
+	result, err := svc.UpdateStatus(ctx, 1, &dto.UpdatePedidoRequest{Status: "pago"})
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPedidoService_UpdateStatus_PreconditionFailed(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	existingPedido := &model.Pedido{ID: 1, ClienteID: 1, Status: "pendente", Versao: 2}
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(existingPedido, nil)
+
+	ctx := etag.WithIfMatch(context.Background(), `"1"`)
+	result, err := svc.UpdateStatus(ctx, 1, &dto.UpdatePedidoRequest{Status: "pago"})
+
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestPedidoService_UpdateStatus_NotFound(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	mockPedidoRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Pedido)(nil), assert.AnError)
 
@@ -357,7 +454,7 @@ func TestPedidoService_UpdateStatus_ValidationError(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	req := &dto.UpdatePedidoRequest{
 		Status: "invalid_status", // Invalid status
@@ -367,17 +464,18 @@ func TestPedidoService_UpdateStatus_ValidationError(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "Status")
+	assert.Contains(t, err.Error(), "status")
 }
 
 func TestPedidoService_Delete_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	// Mock FindByID to verify pedido exists
-	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1}, nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "cancelado"}, nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(0), nil)
 	mockPedidoRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
 
 	err := svc.Delete(context.Background(), 1)
@@ -390,7 +488,7 @@ func TestPedidoService_Count_Success(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	mockPedidoRepo.On("Count", mock.Anything).Return(int64(50), nil)
 
@@ -405,11 +503,11 @@ func TestPedidoService_FindAll_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
-	mockPedidoRepo.On("FindAll", mock.Anything).Return([]model.Pedido(nil), assert.AnError)
+	mockPedidoRepo.On("FindAll", mock.Anything, mock.Anything).Return((*repository.Page[model.Pedido])(nil), assert.AnError)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -420,11 +518,11 @@ func TestPedidoService_FindByClienteID_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
-	mockPedidoRepo.On("FindByClienteID", mock.Anything, uint(1)).Return([]model.Pedido(nil), assert.AnError)
+	mockPedidoRepo.On("FindByClienteID", mock.Anything, uint(1), mock.Anything).Return((*repository.Page[model.Pedido])(nil), assert.AnError)
 
-	result, err := svc.FindByClienteID(context.Background(), 1)
+	result, err := svc.FindByClienteID(context.Background(), 1, dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -435,11 +533,11 @@ func TestPedidoService_FindByStatus_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
-	mockPedidoRepo.On("FindByStatus", mock.Anything, "pendente").Return([]model.Pedido(nil), assert.AnError)
+	mockPedidoRepo.On("FindByStatus", mock.Anything, "pendente", mock.Anything).Return((*repository.Page[model.Pedido])(nil), assert.AnError)
 
-	result, err := svc.FindByStatus(context.Background(), "pendente")
+	result, err := svc.FindByStatus(context.Background(), "pendente", dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -450,7 +548,7 @@ func TestPedidoService_UpdateStatus_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	existingPedido := &model.Pedido{
 		ID:     1,
@@ -464,7 +562,8 @@ func TestPedidoService_UpdateStatus_Error(t *testing.T) {
 		Status: "pago",
 	}
 
-	result, err := svc.UpdateStatus(context.Background(), 1, req)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleAdmin}})
+	result, err := svc.UpdateStatus(ctx, 1, req)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -475,9 +574,10 @@ func TestPedidoService_Delete_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
-	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1}, nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "cancelado"}, nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(0), nil)
 	mockPedidoRepo.On("Delete", mock.Anything, uint(1)).Return(assert.AnError)
 
 	err := svc.Delete(context.Background(), 1)
@@ -490,7 +590,7 @@ func TestPedidoService_Count_Error(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
 
 	mockPedidoRepo.On("Count", mock.Anything).Return(int64(0), assert.AnError)
 
@@ -505,16 +605,18 @@ func TestPedidoService_Create_EstoqueInsuficiente(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
 
 	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{
 		ID: 1,
 	}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
 
 	// Produto com estoque insuficiente
 	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
 		ID:      1,
-		Preco:   100.00,
+		Preco:   money.MustParse("100.00"),
 		Estoque: 5, // Estoque insuficiente
 		Ativo:   true,
 	}, nil)
@@ -539,16 +641,18 @@ func TestPedidoService_Create_ProdutoInativo(t *testing.T) {
 	mockPedidoRepo := new(MockPedidoRepository)
 	mockClienteRepo := new(MockClienteRepository)
 	mockProdutoRepo := new(MockProdutoRepository)
-	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
 
 	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{
 		ID: 1,
 	}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
 
 	// Produto inativo
 	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
 		ID:      1,
-		Preco:   100.00,
+		Preco:   money.MustParse("100.00"),
 		Estoque: 10,
 		Ativo:   false, // Produto inativo
 	}, nil)
@@ -568,3 +672,614 @@ func TestPedidoService_Create_ProdutoInativo(t *testing.T) {
 	mockClienteRepo.AssertExpectations(t)
 	mockProdutoRepo.AssertExpectations(t)
 }
+
+func TestPedidoService_Create_EstoqueConsumidoConcorrentemente(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{
+		ID: 1,
+	}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
+
+	// Leitura indica saldo suficiente, mas outro pedido consumiu o estoque antes da baixa
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID:      1,
+		Nome:    "Notebook",
+		Preco:   money.MustParse("100.00"),
+		Estoque: 1,
+		Ativo:   true,
+	}, nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(repository.ErrEstoqueInsuficiente)
+
+	req := &dto.CreatePedidoRequest{
+		ClienteID: 1,
+		Itens: []dto.CreateItemPedidoRequest{
+			{ProdutoID: 1, Quantidade: 1},
+		},
+	}
+
+	result, err := svc.Create(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "insuficiente")
+	mockPedidoRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockProdutoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_UpdateStatus_CancelamentoExigeMotivo(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "pendente"}, nil)
+
+	result, err := svc.UpdateStatus(context.Background(), 1, &dto.UpdatePedidoRequest{Status: "cancelado"})
+
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockProdutoRepo.AssertNotCalled(t, "IncrementEstoque", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPedidoService_Delete_RestauraEstoque(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:     1,
+		Status: "pendente",
+		Itens: []model.PedidoProduto{
+			{ProdutoID: 1, Quantidade: 4},
+		},
+	}, nil)
+	mockProdutoRepo.On("IncrementEstoque", mock.Anything, uint(1), 4).Return(nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockPedidoRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+
+	err := svc.Delete(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockPedidoRepo.AssertExpectations(t)
+	mockProdutoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_Delete_Recusado(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		abertos int64
+		wantMsg string
+	}{
+		{"pago", "pago", 1, "pedido 1 está pago e não pode ser excluído; use POST /pedidos/1/cancelamento"},
+		{"entregue", "entregue", 0, "pedido 1 está entregue e não pode ser excluído; use POST /pedidos/1/cancelamento"},
+		{"pendente com cobrança", "pendente", 1, "pedido 1 tem pagamentos pendentes ou confirmados e não pode ser excluído; use POST /pedidos/1/cancelamento"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockProdutoRepo := new(MockProdutoRepository)
+			svc := service.NewPedidoService(mockPedidoRepo, nil, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+			mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+				ID:     1,
+				Status: tt.status,
+				Itens:  []model.PedidoProduto{{ProdutoID: 1, Quantidade: 4}},
+			}, nil)
+			mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(tt.abertos, nil)
+
+			err := svc.Delete(context.Background(), 1)
+
+			assert.ErrorIs(t, err, apperror.ErrConflict)
+			assert.EqualError(t, err, tt.wantMsg)
+			mockPedidoRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			mockProdutoRepo.AssertNotCalled(t, "IncrementEstoque", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPedidoService_UpdateStatus_TransicaoInvalida(t *testing.T) {
+	casos := []struct {
+		de   string
+		para string
+	}{
+		{"entregue", "pendente"},
+		{"cancelado", "pago"},
+		{"enviado", "cancelado"},
+		{"pendente", "entregue"},
+		{"pago", "pago"},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.de+"_"+caso.para, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockClienteRepo := new(MockClienteRepository)
+			mockProdutoRepo := new(MockProdutoRepository)
+			svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+			mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: caso.de}, nil)
+
+			result, err := svc.UpdateStatus(context.Background(), 1, &dto.UpdatePedidoRequest{Status: caso.para})
+
+			assert.ErrorIs(t, err, service.ErrTransicaoStatusInvalida)
+			assert.Nil(t, result)
+			mockPedidoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockPedidoRepo.AssertNotCalled(t, "AddHistoricoStatus", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPedidoService_UpdateStatus_RegistraUsuario(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "pago"}, nil)
+	mockPedidoRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Pedido")).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.MatchedBy(func(h *model.PedidoStatusHistorico) bool {
+		return h.Usuario == "maria" && h.StatusAnterior == "pago" && h.StatusNovo == "enviado"
+	})).Return(nil)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "maria"})
+	result, err := svc.UpdateStatus(ctx, 1, &dto.UpdatePedidoRequest{Status: "enviado"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "enviado", result.Status)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_FindHistorico_Success(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "pago"}, nil)
+	mockPedidoRepo.On("FindHistoricoStatus", mock.Anything, uint(1)).Return([]model.PedidoStatusHistorico{
+		{ID: 1, PedidoID: 1, StatusNovo: "pendente", Usuario: "anonimo"},
+		{ID: 2, PedidoID: 1, StatusAnterior: "pendente", StatusNovo: "pago", Usuario: "anonimo"},
+	}, nil)
+
+	result, err := svc.FindHistorico(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "pago", result[1].StatusNovo)
+	mockPedidoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_FindHistorico_NotFound(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Pedido)(nil), assert.AnError)
+
+	result, err := svc.FindHistorico(context.Background(), 999)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockPedidoRepo.AssertNotCalled(t, "FindHistoricoStatus", mock.Anything, mock.Anything)
+}
+
+func TestPedidoService_Create_ComCupom(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockCupomRepo := new(MockCupomRepository)
+	mockImpostoRepo := new(MockRegraImpostoRepository)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, mockCupomRepo, mockImpostoRepo, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+	mockImpostoRepo.On("FindAtivas", mock.Anything, "").Return([]model.RegraImposto{}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1, Nome: "João Silva"}, nil)
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID: 1, Nome: "Notebook", Preco: money.MustParse("2000.00"), Estoque: 10, Categoria: "Eletrônicos", Ativo: true,
+	}, nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+	mockCupomRepo.On("FindByCodigo", mock.Anything, "BEMVINDO10").Return(&model.Cupom{
+		ID: 5, Codigo: "BEMVINDO10", Tipo: model.CupomPercentual, Percentual: 10, LimitePorCliente: 1, Ativo: true,
+	}, nil)
+	mockCupomRepo.On("CountUsosCliente", mock.Anything, uint(5), uint(1)).Return(int64(0), nil)
+	mockCupomRepo.On("IncrementUsos", mock.Anything, uint(5), uint(1)).Return(nil)
+
+	// o pedido relido após a criação é o mesmo que foi gravado
+	criado := &model.Pedido{}
+	mockPedidoRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pedido")).Run(func(args mock.Arguments) {
+		pedido := args.Get(1).(*model.Pedido)
+		pedido.ID = 1
+		*criado = *pedido
+	}).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(criado, nil)
+
+	result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+		ClienteID: 1,
+		Cupom:     "bemvindo10",
+		Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("2000.00"), result.Subtotal)
+	assert.Equal(t, money.MustParse("200.00"), result.Desconto)
+	assert.Equal(t, money.MustParse("1800.00"), result.ValorTotal)
+	if assert.Len(t, result.Descontos, 1) {
+		assert.Equal(t, "BEMVINDO10", result.Descontos[0].Codigo)
+		assert.Equal(t, money.MustParse("200.00"), result.Descontos[0].Valor)
+	}
+	mockCupomRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_Create_CupomRecusado(t *testing.T) {
+	casos := []struct {
+		name    string
+		setup   func(*MockCupomRepository)
+		wantMsg string
+	}{
+		{
+			name: "não encontrado",
+			setup: func(m *MockCupomRepository) {
+				m.On("FindByCodigo", mock.Anything, "TESTE").Return(nil, nil)
+			},
+			wantMsg: "cupom TESTE não encontrado",
+		},
+		{
+			name: "limite por cliente",
+			setup: func(m *MockCupomRepository) {
+				m.On("FindByCodigo", mock.Anything, "TESTE").Return(&model.Cupom{ID: 5, Codigo: "TESTE", LimitePorCliente: 1, Ativo: true}, nil)
+				m.On("CountUsosCliente", mock.Anything, uint(5), uint(1)).Return(int64(1), nil)
+			},
+			wantMsg: "cupom TESTE já utilizado o máximo de vezes por este cliente",
+		},
+		{
+			// outro pedido do cliente usou o cupom entre a contagem e o registro do uso
+			name: "limite por cliente atingido concorrentemente",
+			setup: func(m *MockCupomRepository) {
+				m.On("FindByCodigo", mock.Anything, "TESTE").Return(&model.Cupom{ID: 5, Codigo: "TESTE", LimitePorCliente: 1, Ativo: true}, nil)
+				m.On("CountUsosCliente", mock.Anything, uint(5), uint(1)).Return(int64(0), nil)
+				m.On("IncrementUsos", mock.Anything, uint(5), uint(1)).Return(repository.ErrCupomLimiteCliente)
+			},
+			wantMsg: "cupom TESTE já utilizado o máximo de vezes por este cliente",
+		},
+		{
+			name: "esgotado",
+			setup: func(m *MockCupomRepository) {
+				m.On("FindByCodigo", mock.Anything, "TESTE").Return(&model.Cupom{ID: 5, Codigo: "TESTE", LimiteUso: 10, Ativo: true}, nil)
+				m.On("IncrementUsos", mock.Anything, uint(5), uint(1)).Return(repository.ErrCupomEsgotado)
+			},
+			wantMsg: "cupom TESTE esgotado",
+		},
+	}
+
+	for _, tt := range casos {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockClienteRepo := new(MockClienteRepository)
+			mockProdutoRepo := new(MockProdutoRepository)
+			mockCupomRepo := new(MockCupomRepository)
+			mockEnderecoRepo := new(MockEnderecoRepository)
+			svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, mockCupomRepo, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+			mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+			mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
+			mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+				ID: 1, Nome: "Notebook", Preco: money.MustParse("100.00"), Estoque: 10, Ativo: true,
+			}, nil)
+			mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+			tt.setup(mockCupomRepo)
+
+			result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+				ClienteID: 1,
+				Cupom:     "teste",
+				Itens:     []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+			})
+
+			assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+			assert.EqualError(t, err, tt.wantMsg)
+			assert.Nil(t, result)
+			mockPedidoRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPedidoService_Create_ComEndereco(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockImpostoRepo := new(MockRegraImpostoRepository)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	mockFreteCalculator := new(MockFreteCalculator)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, mockImpostoRepo, mockEnderecoRepo, mockFreteCalculator, new(MockAuditRepository))
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+	mockEnderecoRepo.On("FindByID", mock.Anything, uint(7)).Return(&model.Endereco{
+		ID: 7, ClienteID: 1, CEP: "20040002", Logradouro: "Rua da Assembleia", Numero: "10",
+		Bairro: "Centro", Cidade: "Rio de Janeiro", UF: "RJ",
+	}, nil)
+	// a UF do endereço escolhe as regras de imposto
+	mockImpostoRepo.On("FindAtivas", mock.Anything, "RJ").Return([]model.RegraImposto{}, nil)
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID: 1, Nome: "Notebook", Preco: money.MustParse("100.00"), Estoque: 10, Ativo: true, PesoGramas: 2500,
+	}, nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+	// o frete é cotado para o CEP do endereço; sem serviço pedido vale o mais barato
+	mockFreteCalculator.On("Cotar", mock.Anything, frete.Pacote{
+		CEP:     "20040002",
+		Volumes: []frete.Volume{{PesoGramas: 2500, Quantidade: 1}},
+	}).Return([]frete.Opcao{
+		{Servico: "Econômico", Valor: money.MustParse("15.00"), PrazoDias: 7},
+		{Servico: "Expresso", Valor: money.MustParse("30.00"), PrazoDias: 2},
+	}, nil)
+
+	criado := &model.Pedido{}
+	mockPedidoRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pedido")).Run(func(args mock.Arguments) {
+		pedido := args.Get(1).(*model.Pedido)
+		pedido.ID = 1
+		*criado = *pedido
+	}).Return(nil)
+	mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(criado, nil)
+
+	enderecoID := uint(7)
+	result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+		ClienteID:  1,
+		EnderecoID: &enderecoID,
+		Itens:      []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "RJ", result.UFDestino)
+	assert.Equal(t, &enderecoID, result.EnderecoID)
+	if assert.NotNil(t, result.Entrega) {
+		assert.Equal(t, "20040002", result.Entrega.CEP)
+		assert.Equal(t, "Rua da Assembleia", result.Entrega.Logradouro)
+	}
+	assert.Equal(t, money.MustParse("15.00"), result.Frete)
+	assert.Equal(t, "Econômico", result.FreteServico)
+	assert.Equal(t, 7, result.FretePrazoDias)
+	assert.Equal(t, money.MustParse("115.00"), result.ValorTotal)
+	mockImpostoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_Create_FreteServico(t *testing.T) {
+	opcoes := []frete.Opcao{
+		{Servico: "Econômico", Valor: money.MustParse("15.00"), PrazoDias: 7},
+		{Servico: "Expresso", Valor: money.MustParse("30.00"), PrazoDias: 2},
+	}
+	casos := []struct {
+		name        string
+		servico     string
+		opcoes      []frete.Opcao
+		wantServico string
+		wantTotal   string
+		wantMsg     string
+	}{
+		{name: "serviço escolhido", servico: "expresso", opcoes: opcoes, wantServico: "Expresso", wantTotal: "130.00"},
+		{name: "serviço indisponível", servico: "Moto", opcoes: opcoes, wantMsg: "serviço de frete Moto indisponível para o CEP 20040002"},
+		{name: "CEP não atendido", opcoes: []frete.Opcao{}, wantMsg: "não há frete disponível para o CEP 20040002"},
+	}
+
+	for _, tt := range casos {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockClienteRepo := new(MockClienteRepository)
+			mockProdutoRepo := new(MockProdutoRepository)
+			mockImpostoRepo := new(MockRegraImpostoRepository)
+			mockEnderecoRepo := new(MockEnderecoRepository)
+			mockFreteCalculator := new(MockFreteCalculator)
+			svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, mockImpostoRepo, mockEnderecoRepo, mockFreteCalculator, new(MockAuditRepository))
+
+			mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+			mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(&model.Endereco{ID: 7, ClienteID: 1, CEP: "20040002", UF: "RJ"}, nil)
+			mockImpostoRepo.On("FindAtivas", mock.Anything, "RJ").Return([]model.RegraImposto{}, nil)
+			mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+				ID: 1, Nome: "Notebook", Preco: money.MustParse("100.00"), Estoque: 10, Ativo: true,
+			}, nil)
+			mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+			mockFreteCalculator.On("Cotar", mock.Anything, mock.AnythingOfType("frete.Pacote")).Return(tt.opcoes, nil)
+
+			criado := &model.Pedido{}
+			mockPedidoRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Pedido")).Run(func(args mock.Arguments) {
+				pedido := args.Get(1).(*model.Pedido)
+				pedido.ID = 1
+				*criado = *pedido
+			}).Return(nil)
+			mockPedidoRepo.On("AddHistoricoStatus", mock.Anything, mock.AnythingOfType("*model.PedidoStatusHistorico")).Return(nil)
+			mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(criado, nil)
+
+			result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+				ClienteID:    1,
+				FreteServico: tt.servico,
+				Itens:        []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+			})
+
+			if tt.wantMsg != "" {
+				assert.Nil(t, result)
+				assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+				assert.Contains(t, err.Error(), tt.wantMsg)
+				mockPedidoRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantServico, result.FreteServico)
+			assert.Equal(t, money.MustParse(tt.wantTotal), result.ValorTotal)
+		})
+	}
+}
+
+func TestPedidoService_Create_FreteServicoSemEndereco(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockImpostoRepo := new(MockRegraImpostoRepository)
+	mockEnderecoRepo := new(MockEnderecoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, mockImpostoRepo, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+	mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+	mockEnderecoRepo.On("FindPadrao", mock.Anything, uint(1)).Return(nil, nil)
+	mockImpostoRepo.On("FindAtivas", mock.Anything, "").Return([]model.RegraImposto{}, nil)
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID: 1, Nome: "Notebook", Preco: money.MustParse("100.00"), Estoque: 10, Ativo: true,
+	}, nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 1).Return(nil)
+
+	result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+		ClienteID:    1,
+		FreteServico: "Expresso",
+		Itens:        []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Contains(t, err.Error(), "frete_servico exige um endereço de entrega")
+}
+
+func TestPedidoService_Create_EnderecoRecusado(t *testing.T) {
+	casos := []struct {
+		name      string
+		ufDestino string
+		endereco  *model.Endereco
+		wantMsg   string
+	}{
+		{
+			name:     "de outro cliente",
+			endereco: &model.Endereco{ID: 7, ClienteID: 2, UF: "RJ"},
+			wantMsg:  "endereço 7 não pertence ao cliente 1",
+		},
+		{
+			name:      "UF divergente",
+			ufDestino: "sp",
+			endereco:  &model.Endereco{ID: 7, ClienteID: 1, UF: "RJ"},
+			wantMsg:   "uf_destino SP diverge da UF do endereço de entrega (RJ)",
+		},
+	}
+
+	for _, tt := range casos {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPedidoRepo := new(MockPedidoRepository)
+			mockClienteRepo := new(MockClienteRepository)
+			mockEnderecoRepo := new(MockEnderecoRepository)
+			svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, nil, nil, nil, mockEnderecoRepo, nil, new(MockAuditRepository))
+
+			mockClienteRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Cliente{ID: 1}, nil)
+			mockEnderecoRepo.On("FindByID", mock.Anything, uint(7)).Return(tt.endereco, nil)
+
+			enderecoID := uint(7)
+			result, err := svc.Create(context.Background(), &dto.CreatePedidoRequest{
+				ClienteID:  1,
+				EnderecoID: &enderecoID,
+				UFDestino:  tt.ufDestino,
+				Itens:      []dto.CreateItemPedidoRequest{{ProdutoID: 1, Quantidade: 1}},
+			})
+
+			assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+			assert.EqualError(t, err, tt.wantMsg)
+			assert.Nil(t, result)
+			mockPedidoRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPedidoService_UpdateItem_ReservaDiferenca(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	mockImpostoRepo := new(MockRegraImpostoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, mockImpostoRepo, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:     1,
+		Status: "pendente",
+		Itens: []model.PedidoProduto{
+			// o preço do item é o da compra, não o atual do produto
+			{ID: 10, ProdutoID: 1, Quantidade: 1, PrecoUnitario: money.MustParse("80.00"), Produto: model.Produto{ID: 1, Preco: money.MustParse("100.00")}},
+		},
+	}, nil)
+	mockProdutoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{
+		ID: 1, Nome: "Notebook", Preco: money.MustParse("100.00"), Estoque: 5, Ativo: true,
+	}, nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockProdutoRepo.On("DecrementEstoque", mock.Anything, uint(1), 2).Return(nil)
+	mockImpostoRepo.On("FindAtivas", mock.Anything, "").Return([]model.RegraImposto{}, nil)
+	mockPedidoRepo.On("UpdateItens", mock.Anything, mock.MatchedBy(func(p *model.Pedido) bool {
+		return p.Itens[0].Quantidade == 3 && p.ValorTotal == money.MustParse("240.00")
+	})).Return(nil)
+
+	result, err := svc.UpdateItem(context.Background(), 1, 10, &dto.UpdateItemPedidoRequest{Quantidade: 3})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockPedidoRepo.AssertExpectations(t)
+	mockProdutoRepo.AssertExpectations(t)
+}
+
+func TestPedidoService_AddItem_PedidoNaoPendente(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{ID: 1, Status: "pago"}, nil)
+
+	result, err := svc.AddItem(context.Background(), 1, &dto.CreateItemPedidoRequest{ProdutoID: 1, Quantidade: 1})
+
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+	mockProdutoRepo.AssertNotCalled(t, "DecrementEstoque", mock.Anything, mock.Anything, mock.Anything)
+	mockPedidoRepo.AssertNotCalled(t, "UpdateItens", mock.Anything, mock.Anything)
+}
+
+func TestPedidoService_UpdateItem_PedidoComPagamentos(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:     1,
+		Status: "pendente",
+		Itens:  []model.PedidoProduto{{ID: 10, ProdutoID: 1, Quantidade: 1}},
+	}, nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(1), nil)
+
+	result, err := svc.UpdateItem(context.Background(), 1, 10, &dto.UpdateItemPedidoRequest{Quantidade: 3})
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.Nil(t, result)
+	mockProdutoRepo.AssertNotCalled(t, "DecrementEstoque", mock.Anything, mock.Anything, mock.Anything)
+	mockPedidoRepo.AssertNotCalled(t, "UpdateItens", mock.Anything, mock.Anything)
+}
+
+func TestPedidoService_RemoveItem_UltimoItem(t *testing.T) {
+	mockPedidoRepo := new(MockPedidoRepository)
+	mockClienteRepo := new(MockClienteRepository)
+	mockProdutoRepo := new(MockProdutoRepository)
+	svc := service.NewPedidoService(mockPedidoRepo, mockClienteRepo, mockProdutoRepo, nil, nil, nil, nil, new(MockAuditRepository))
+
+	mockPedidoRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Pedido{
+		ID:     1,
+		Status: "pendente",
+		Itens:  []model.PedidoProduto{{ID: 10, ProdutoID: 1, Quantidade: 2}},
+	}, nil)
+	mockPedidoRepo.On("CountPagamentosEmAberto", mock.Anything, uint(1)).Return(int64(0), nil)
+
+	result, err := svc.RemoveItem(context.Background(), 1, 10)
+	assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+	assert.Nil(t, result)
+
+	result, err = svc.RemoveItem(context.Background(), 1, 99)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.Nil(t, result)
+	mockProdutoRepo.AssertNotCalled(t, "IncrementEstoque", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,238 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+func itensPricing() []pricing.Item {
+	return []pricing.Item{
+		{ProdutoID: 1, Categoria: "Eletrônicos", Quantidade: 1, PrecoUnitario: money.MustParse("1000.00")},
+		{ProdutoID: 2, Categoria: "Livros", Quantidade: 2, PrecoUnitario: money.MustParse("50.00")},
+	}
+}
+
+func TestPricing_Calcular_SemCupom(t *testing.T) {
+	r, err := pricing.Calcular(pricing.Entrada{Itens: itensPricing(), Agora: time.Now()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("1100.00"), r.Subtotal)
+	assert.Equal(t, money.Money(0), r.Desconto)
+	assert.Equal(t, money.MustParse("1100.00"), r.Total)
+	assert.Empty(t, r.Descontos)
+}
+
+func TestPricing_Calcular_Cupons(t *testing.T) {
+	agora := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ontem := agora.AddDate(0, 0, -1)
+	amanha := agora.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name         string
+		cupom        model.Cupom
+		wantBase     string
+		wantDesconto string
+	}{
+		{"percentual sobre todo o pedido", model.Cupom{Tipo: model.CupomPercentual, Percentual: 10}, "1100.00", "110.00"},
+		{"percentual restrito à categoria", model.Cupom{Tipo: model.CupomPercentual, Percentual: 15, Categorias: []string{"Livros"}}, "100.00", "15.00"},
+		{"valor fixo restrito ao produto", model.Cupom{Tipo: model.CupomValorFixo, Valor: money.MustParse("30.00"), ProdutoIDs: []uint{1}}, "1000.00", "30.00"},
+		{"valor fixo limitado aos itens elegíveis", model.Cupom{Tipo: model.CupomValorFixo, Valor: money.MustParse("500.00"), Categorias: []string{"Livros"}}, "100.00", "100.00"},
+		{"frete grátis", model.Cupom{Tipo: model.CupomFreteGratis}, "0.00", "0.00"},
+		{"dentro da validade e do mínimo", model.Cupom{Tipo: model.CupomPercentual, Percentual: 5, ValidoDe: &ontem, ValidoAte: &amanha, ValorMinimo: money.MustParse("1100.00")}, "1100.00", "55.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cupom := tt.cupom
+			cupom.ID = 7
+			cupom.Codigo = "TESTE"
+			cupom.Ativo = true
+
+			r, err := pricing.Calcular(pricing.Entrada{Itens: itensPricing(), Cupom: &cupom, Agora: agora})
+
+			assert.NoError(t, err)
+			desconto := money.MustParse(tt.wantDesconto)
+			assert.Equal(t, desconto, r.Desconto)
+			assert.Equal(t, money.MustParse("1100.00")-desconto, r.Total)
+			if assert.Len(t, r.Descontos, 1) {
+				linha := r.Descontos[0]
+				assert.Equal(t, uint(7), *linha.CupomID)
+				assert.Equal(t, "TESTE", linha.Codigo)
+				assert.Equal(t, "Cupom TESTE", linha.Descricao)
+				assert.Equal(t, cupom.Tipo, linha.Tipo)
+				assert.Equal(t, money.MustParse(tt.wantBase), linha.Base)
+				assert.Equal(t, desconto, linha.Valor)
+			}
+		})
+	}
+}
+
+func TestPricing_Calcular_Frete(t *testing.T) {
+	r, err := pricing.Calcular(pricing.Entrada{Itens: itensPricing(), Agora: time.Now(), Frete: money.MustParse("25.90")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("25.90"), r.Frete)
+	assert.Equal(t, money.MustParse("1125.90"), r.Total)
+}
+
+func TestPricing_Calcular_FreteGratis(t *testing.T) {
+	cupom := model.Cupom{ID: 7, Codigo: "FRETE", Tipo: model.CupomFreteGratis, Ativo: true}
+
+	r, err := pricing.Calcular(pricing.Entrada{
+		Itens: itensPricing(), Cupom: &cupom, Agora: time.Now(),
+		UF: "RJ", Regras: regrasPricing(), Frete: money.MustParse("25.90"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("25.90"), r.Desconto)
+	if assert.Len(t, r.Descontos, 1) {
+		assert.Equal(t, money.MustParse("25.90"), r.Descontos[0].Base)
+		assert.Equal(t, money.MustParse("25.90"), r.Descontos[0].Valor)
+	}
+	// o frete grátis não é rateado entre os itens nem reduz a base dos impostos
+	for _, item := range r.Itens {
+		assert.Equal(t, money.Money(0), item.Desconto)
+	}
+	// 1100.00 + IPI de 100.00 sobre o notebook; o frete é anulado pelo desconto
+	assert.Equal(t, money.MustParse("1200.00"), r.Total)
+}
+
+func TestPricing_Calcular_CupomRecusado(t *testing.T) {
+	agora := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ontem := agora.AddDate(0, 0, -1)
+	amanha := agora.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name    string
+		cupom   model.Cupom
+		wantMsg string
+	}{
+		{"inativo", model.Cupom{Ativo: false}, "cupom TESTE inativo"},
+		{"ainda não vigente", model.Cupom{Ativo: true, ValidoDe: &amanha}, "cupom TESTE fora do período de validade"},
+		{"expirado", model.Cupom{Ativo: true, ValidoAte: &ontem}, "cupom TESTE fora do período de validade"},
+		{"abaixo do mínimo", model.Cupom{Ativo: true, ValorMinimo: money.MustParse("1100.01")}, "cupom TESTE exige pedido mínimo de 1100.01"},
+		{"sem itens elegíveis", model.Cupom{Ativo: true, Categorias: []string{"Brinquedos"}, ProdutoIDs: []uint{9}}, "cupom TESTE não se aplica aos produtos do pedido"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cupom := tt.cupom
+			cupom.Codigo = "TESTE"
+			cupom.Tipo = model.CupomPercentual
+			cupom.Percentual = 10
+
+			r, err := pricing.Calcular(pricing.Entrada{Itens: itensPricing(), Cupom: &cupom, Agora: agora})
+
+			assert.ErrorIs(t, err, apperror.ErrBusinessRule)
+			assert.EqualError(t, err, tt.wantMsg)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func regrasPricing() []model.RegraImposto {
+	return []model.RegraImposto{
+		{ID: 1, Imposto: "ICMS", Aliquota: 1800, Modo: model.ImpostoInclusivo, Ativo: true},
+		{ID: 2, Imposto: "ICMS", UF: "RJ", Aliquota: 2000, Modo: model.ImpostoInclusivo, Ativo: true},
+		{ID: 3, Imposto: "ICMS", Categoria: "Livros", Aliquota: 0, Modo: model.ImpostoInclusivo, Ativo: true},
+		{ID: 4, Imposto: "IPI", Categoria: "Eletrônicos", Aliquota: 1000, Modo: model.ImpostoExclusivo, Ativo: true},
+		{ID: 5, Imposto: "IPI", Categoria: "Eletrônicos", UF: "RJ", Aliquota: 1500, Modo: model.ImpostoExclusivo, Ativo: false},
+	}
+}
+
+func TestPricing_Calcular_Impostos(t *testing.T) {
+	r, err := pricing.Calcular(pricing.Entrada{Itens: itensPricing(), Agora: time.Now(), UF: "RJ", Regras: regrasPricing()})
+
+	assert.NoError(t, err)
+
+	// notebook: ICMS do RJ (mais específico que o genérico) e IPI da categoria;
+	// a regra inativa do RJ é ignorada
+	notebook := r.Itens[0]
+	if assert.Len(t, notebook.Impostos, 2) {
+		assert.Equal(t, "ICMS", notebook.Impostos[0].Imposto)
+		assert.Equal(t, uint(2), *notebook.Impostos[0].RegraID)
+		assert.Equal(t, money.MustParse("200.00"), notebook.Impostos[0].Valor)
+		assert.Equal(t, "IPI", notebook.Impostos[1].Imposto)
+		assert.Equal(t, uint(4), *notebook.Impostos[1].RegraID)
+		assert.Equal(t, money.MustParse("1000.00"), notebook.Impostos[1].Base)
+		assert.Equal(t, money.MustParse("100.00"), notebook.Impostos[1].Valor)
+	}
+	assert.Equal(t, money.MustParse("300.00"), notebook.Imposto)
+
+	// livros: a regra da categoria (alíquota zero) vence a da UF
+	livros := r.Itens[1]
+	if assert.Len(t, livros.Impostos, 1) {
+		assert.Equal(t, uint(3), *livros.Impostos[0].RegraID)
+		assert.Equal(t, money.Money(0), livros.Impostos[0].Valor)
+	}
+
+	// apenas o IPI (exclusivo) é somado ao total
+	assert.Equal(t, money.MustParse("300.00"), r.Imposto)
+	assert.Equal(t, money.MustParse("1200.00"), r.Total)
+}
+
+func TestPricing_Calcular_ImpostoSobreBaseComDesconto(t *testing.T) {
+	itens := []pricing.Item{
+		{ProdutoID: 1, Categoria: "Eletrônicos", Quantidade: 1, PrecoUnitario: money.MustParse("100.00")},
+		{ProdutoID: 2, Categoria: "Eletrônicos", Quantidade: 1, PrecoUnitario: money.MustParse("200.00")},
+		{ProdutoID: 3, Categoria: "Livros", Quantidade: 1, PrecoUnitario: money.MustParse("50.00")},
+	}
+	cupom := &model.Cupom{ID: 1, Codigo: "ELETRO", Tipo: model.CupomValorFixo, Valor: money.MustParse("10.00"), Categorias: []string{"Eletrônicos"}, Ativo: true}
+	regras := []model.RegraImposto{{ID: 1, Imposto: "IPI", Aliquota: 1000, Modo: model.ImpostoExclusivo, Ativo: true}}
+
+	r, err := pricing.Calcular(pricing.Entrada{Itens: itens, Cupom: cupom, Agora: time.Now(), Regras: regras})
+
+	assert.NoError(t, err)
+	// os 10.00 de desconto são rateados entre os itens elegíveis (1/3 e 2/3),
+	// com a sobra do arredondamento no último
+	assert.Equal(t, money.MustParse("3.33"), r.Itens[0].Desconto)
+	assert.Equal(t, money.MustParse("6.67"), r.Itens[1].Desconto)
+	assert.Equal(t, money.Money(0), r.Itens[2].Desconto)
+
+	assert.Equal(t, money.MustParse("96.67"), r.Itens[0].Impostos[0].Base)
+	assert.Equal(t, money.MustParse("9.67"), r.Itens[0].Impostos[0].Valor)
+	assert.Equal(t, money.MustParse("193.33"), r.Itens[1].Impostos[0].Base)
+	assert.Equal(t, money.MustParse("19.33"), r.Itens[1].Impostos[0].Valor)
+	assert.Equal(t, money.MustParse("5.00"), r.Itens[2].Impostos[0].Valor)
+
+	assert.Equal(t, money.MustParse("34.00"), r.Imposto)
+	assert.Equal(t, money.MustParse("374.00"), r.Total)
+}
+
+func TestPricing_Reembolso(t *testing.T) {
+	// 3 unidades de 33,33 com 9,99 de desconto e IPI exclusivo de 9,00; o ICMS
+	// incluso já está no preço e não soma
+	item := model.PedidoProduto{
+		Quantidade:    3,
+		PrecoUnitario: money.MustParse("33.33"),
+		Subtotal:      money.MustParse("99.99"),
+		Desconto:      money.MustParse("9.99"),
+		Impostos: []model.PedidoItemImposto{
+			{Imposto: "IPI", Modo: model.ImpostoExclusivo, Valor: money.MustParse("9.00")},
+			{Imposto: "ICMS", Modo: model.ImpostoInclusivo, Valor: money.MustParse("16.20")},
+		},
+	}
+
+	assert.Equal(t, money.MustParse("99.00"), pricing.Liquido(item))
+
+	// devoluções parciais somadas nunca passam do líquido
+	primeira := pricing.Reembolso(item, 0, 1)
+	segunda := pricing.Reembolso(item, 1, 1)
+	terceira := pricing.Reembolso(item, 2, 1)
+	assert.Equal(t, money.MustParse("33.00"), primeira)
+	assert.Equal(t, money.MustParse("33.00"), segunda)
+	assert.Equal(t, money.MustParse("33.00"), terceira)
+	assert.Equal(t, pricing.Liquido(item), primeira+segunda+terceira)
+
+	// o arredondamento fica com a última unidade
+	item.Desconto = money.MustParse("10.00")
+	assert.Equal(t, money.MustParse("32.99"), pricing.Reembolso(item, 0, 1))
+	assert.Equal(t, money.MustParse("65.99"), pricing.Reembolso(item, 0, 2))
+	assert.Equal(t, money.MustParse("33.00"), pricing.Reembolso(item, 2, 1))
+}
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblem_New(t *testing.T) {
+	p := problem.New(http.StatusConflict, "SKU já cadastrado")
+	assert.Equal(t, "/problems/conflict", p.Type)
+	assert.Equal(t, http.StatusConflict, p.Status)
+	assert.Equal(t, "SKU já cadastrado", p.Detail)
+
+	// Status without a documented type falls back to about:blank
+	p = problem.New(http.StatusTeapot, "")
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, http.StatusText(http.StatusTeapot), p.Title)
+}
+
+func TestProblem_Write(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+
+	problem.Write(rec, req, problem.New(http.StatusNotFound, "produto não encontrado"))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "/problems/not-found",
+		"title": "Recurso não encontrado",
+		"status": 404,
+		"detail": "produto não encontrado"
+	}`, rec.Body.String())
+}
+
+func TestProblem_FieldErrorMessages(t *testing.T) {
+	type item struct {
+		Quantidade int `validate:"gt=0"`
+	}
+	type request struct {
+		Nome   string `validate:"min=3"`
+		Codigo string `validate:"max=2"`
+		Status string `validate:"oneof=pendente pago"`
+		Idade  int    `validate:"gte=18"`
+		Itens  []item `validate:"min=1"`
+		Outros []item `validate:"dive"`
+	}
+
+	err := validator.New().Struct(request{
+		Nome:   "ab",
+		Codigo: "abc",
+		Status: "x",
+		Idade:  10,
+		Outros: []item{{Quantidade: 0}},
+	})
+
+	fields := problem.FieldErrors(err.(validator.ValidationErrors))
+
+	assert.Equal(t, []dto.FieldError{
+		{Field: "Nome", Rule: "min", Message: "deve ter no mínimo 3 caracteres"},
+		{Field: "Codigo", Rule: "max", Message: "deve ter no máximo 2 caracteres"},
+		{Field: "Status", Rule: "oneof", Message: "deve ser um dos valores: pendente, pago"},
+		{Field: "Idade", Rule: "gte", Message: "deve ser maior ou igual a 18"},
+		{Field: "Itens", Rule: "min", Message: "deve ter no mínimo 1 itens"},
+		{Field: "Outros[0].Quantidade", Rule: "gt", Message: "deve ser maior que 0"},
+	}, fields)
+}
@@ -3,9 +3,15 @@ package unit
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
 	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/etag"
 	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
 	"github.com/danmaciel/api/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,12 +27,12 @@ func (m *MockProdutoRepository) Create(ctx context.Context, produto *model.Produ
 	return args.Error(0)
 }
 
-func (m *MockProdutoRepository) FindAll(ctx context.Context) ([]model.Produto, error) {
-	args := m.Called(ctx)
+func (m *MockProdutoRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.Produto], error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Produto), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Produto]), args.Error(1)
 }
 
 func (m *MockProdutoRepository) FindByID(ctx context.Context, id uint) (*model.Produto, error) {
@@ -37,12 +43,12 @@ func (m *MockProdutoRepository) FindByID(ctx context.Context, id uint) (*model.P
 	return args.Get(0).(*model.Produto), args.Error(1)
 }
 
-func (m *MockProdutoRepository) FindByName(ctx context.Context, nome string) ([]model.Produto, error) {
-	args := m.Called(ctx, nome)
+func (m *MockProdutoRepository) FindByName(ctx context.Context, nome string, opts repository.ListOptions) (*repository.Page[model.Produto], error) {
+	args := m.Called(ctx, nome, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Produto), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Produto]), args.Error(1)
 }
 
 func (m *MockProdutoRepository) FindBySKU(ctx context.Context, sku string) (*model.Produto, error) {
@@ -53,12 +59,12 @@ func (m *MockProdutoRepository) FindBySKU(ctx context.Context, sku string) (*mod
 	return args.Get(0).(*model.Produto), args.Error(1)
 }
 
-func (m *MockProdutoRepository) FindByCategoria(ctx context.Context, categoria string) ([]model.Produto, error) {
-	args := m.Called(ctx, categoria)
+func (m *MockProdutoRepository) FindByCategoria(ctx context.Context, categoria string, opts repository.ListOptions) (*repository.Page[model.Produto], error) {
+	args := m.Called(ctx, categoria, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]model.Produto), args.Error(1)
+	return args.Get(0).(*repository.Page[model.Produto]), args.Error(1)
 }
 
 func (m *MockProdutoRepository) Update(ctx context.Context, produto *model.Produto) error {
@@ -71,21 +77,60 @@ func (m *MockProdutoRepository) Delete(ctx context.Context, id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockProdutoRepository) Restore(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProdutoRepository) Purge(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockProdutoRepository) Count(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockProdutoRepository) CountPedidos(ctx context.Context, id uint, incluirExcluidos bool) (int64, error) {
+	args := m.Called(ctx, id, incluirExcluidos)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProdutoRepository) DecrementEstoque(ctx context.Context, id uint, quantidade int) error {
+	args := m.Called(ctx, id, quantidade)
+	return args.Error(0)
+}
+
+func (m *MockProdutoRepository) IncrementEstoque(ctx context.Context, id uint, quantidade int) error {
+	args := m.Called(ctx, id, quantidade)
+	return args.Error(0)
+}
+
 // Test cases
+// updateProdutoRequest monta um PUT que reenvia todos os campos atuais do produto
+func updateProdutoRequest(p *model.Produto) *dto.UpdateProdutoRequest {
+	estoque, ativo := p.Estoque, p.Ativo
+	return &dto.UpdateProdutoRequest{
+		Nome:      p.Nome,
+		Descricao: p.Descricao,
+		Preco:     p.Preco,
+		Estoque:   &estoque,
+		SKU:       p.SKU,
+		Categoria: p.Categoria,
+		Ativo:     &ativo,
+	}
+}
+
 func TestProdutoService_Create_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	ativo := true
 	req := &dto.CreateProdutoRequest{
 		Nome:      "Notebook Dell",
 		Descricao: "Notebook Dell Inspiron 15",
-		Preco:     2999.99,
+		Preco:     money.MustParse("2999.99"),
 		Estoque:   10,
 		SKU:       "NB-DELL-001",
 		Categoria: "Eletrônicos",
@@ -107,11 +152,11 @@ func TestProdutoService_Create_Success(t *testing.T) {
 
 func TestProdutoService_Create_ValidationError(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	req := &dto.CreateProdutoRequest{
 		Nome:  "", // Invalid: empty name
-		Preco: 100.00,
+		Preco: money.MustParse("100.00"),
 		SKU:   "TEST-001",
 	}
 
@@ -119,16 +164,16 @@ func TestProdutoService_Create_ValidationError(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "Nome")
+	assert.Contains(t, err.Error(), "nome")
 }
 
 func TestProdutoService_Create_ValidationError_InvalidPrice(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	req := &dto.CreateProdutoRequest{
 		Nome:  "Produto Teste",
-		Preco: -10.00, // Invalid: negative price
+		Preco: money.MustParse("-10.00"), // Invalid: negative price
 		SKU:   "TEST-001",
 	}
 
@@ -136,37 +181,37 @@ func TestProdutoService_Create_ValidationError_InvalidPrice(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "Preco")
+	assert.Contains(t, err.Error(), "preco")
 }
 
 func TestProdutoService_FindAll_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	expectedProdutos := []model.Produto{
-		{ID: 1, Nome: "Produto 1", SKU: "PROD-001", Preco: 100.00},
-		{ID: 2, Nome: "Produto 2", SKU: "PROD-002", Preco: 200.00},
+		{ID: 1, Nome: "Produto 1", SKU: "PROD-001", Preco: money.MustParse("100.00")},
+		{ID: 2, Nome: "Produto 2", SKU: "PROD-002", Preco: money.MustParse("200.00")},
 	}
 
-	mockRepo.On("FindAll", mock.Anything).Return(expectedProdutos, nil)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything).Return(&repository.Page[model.Produto]{Items: expectedProdutos, Total: int64(len(expectedProdutos))}, nil)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestProdutoService_FindByID_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	expectedProduto := &model.Produto{
 		ID:        1,
 		Nome:      "Notebook Dell",
 		SKU:       "NB-DELL-001",
-		Preco:     2999.99,
+		Preco:     money.MustParse("2999.99"),
 		Categoria: "Eletrônicos",
 	}
 
@@ -183,7 +228,7 @@ func TestProdutoService_FindByID_Success(t *testing.T) {
 
 func TestProdutoService_FindByID_NotFound(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Produto)(nil), assert.AnError)
 
@@ -194,82 +239,307 @@ func TestProdutoService_FindByID_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProdutoService_FindAll_NextCursor(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	expectedOpts := repository.ListOptions{Limit: 2, AfterID: 7}
+	mockRepo.On("FindAll", mock.Anything, expectedOpts).Return(&repository.Page[model.Produto]{
+		Items:   []model.Produto{{ID: 8}, {ID: 9}},
+		Total:   12,
+		HasMore: true,
+	}, nil)
+
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{Limit: 2, AfterID: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), result.Total)
+	assert.Equal(t, dto.EncodeCursor(9), result.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProdutoService_FindAll_PageMode(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	expectedOpts := repository.ListOptions{Limit: 10, Offset: 20}
+	mockRepo.On("FindAll", mock.Anything, expectedOpts).Return(&repository.Page[model.Produto]{
+		Items: []model.Produto{{ID: 21}},
+		Total: 21,
+	}, nil)
+
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{Limit: 10, Page: 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Page)
+	assert.Zero(t, result.NextPage)
+	assert.Empty(t, result.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProdutoService_FindAll_FiltersAndSort(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	expectedOpts := repository.ListOptions{
+		Limit: repository.DefaultPageSize,
+		Filters: []repository.Filter{
+			{Column: "preco", Op: repository.OpGte, Values: []interface{}{money.MustParse("10.00")}},
+			{Column: "categoria", Op: repository.OpIn, Values: []interface{}{"a", "b"}},
+		},
+		Sort: []repository.Sort{{Column: "created_at", Desc: true}, {Column: "nome"}},
+	}
+	mockRepo.On("FindAll", mock.Anything, expectedOpts).Return(&repository.Page[model.Produto]{
+		Items:    []model.Produto{{ID: 4, Nome: "B"}, {ID: 3, Nome: "A"}},
+		Total:    5,
+		HasMore:  true,
+		LastKeys: []interface{}{time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), "A"},
+	}, nil)
+
+	req := dto.PageRequest{
+		Filters: []dto.FilterParam{{Field: "preco", Op: "gte", Value: "10"}, {Field: "categoria", Op: "in", Value: "a,b"}},
+		Sort:    []dto.SortParam{{Field: "created_at", Desc: true}, {Field: "nome"}},
+	}
+	result, err := svc.FindAll(context.Background(), req)
+
+	assert.NoError(t, err)
+	cursor, err := dto.DecodeCursor(result.NextCursor)
+	assert.NoError(t, err)
+	data, nome := "2026-01-02T03:04:05Z", "A"
+	assert.Equal(t, dto.Cursor{ID: 3, Sort: "-created_at,nome", Keys: []*string{&data, &nome}}, cursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProdutoService_FindAll_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.PageRequest
+	}{
+		{"campo desconhecido", dto.PageRequest{Filters: []dto.FilterParam{{Field: "custo", Op: "eq", Value: "1"}}}},
+		{"operador inválido para texto", dto.PageRequest{Filters: []dto.FilterParam{{Field: "nome", Op: "gte", Value: "a"}}}},
+		{"dinheiro inválido", dto.PageRequest{Filters: []dto.FilterParam{{Field: "preco", Op: "eq", Value: "dez"}}}},
+		{"ordenação desconhecida", dto.PageRequest{Sort: []dto.SortParam{{Field: "custo"}}}},
+		{"cursor sem as chaves da ordenação", dto.PageRequest{AfterID: 3, Sort: []dto.SortParam{{Field: "nome"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProdutoRepository)
+			svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+			result, err := svc.FindAll(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Nil(t, result)
+			mockRepo.AssertNotCalled(t, "FindAll", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestProdutoService_FindByName_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	expectedProdutos := []model.Produto{
-		{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: 2999.99},
+		{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99")},
 	}
 
-	mockRepo.On("FindByName", mock.Anything, "Notebook").Return(expectedProdutos, nil)
+	mockRepo.On("FindByName", mock.Anything, "Notebook", mock.Anything).Return(&repository.Page[model.Produto]{Items: expectedProdutos, Total: int64(len(expectedProdutos))}, nil)
 
-	result, err := svc.FindByName(context.Background(), "Notebook")
+	result, err := svc.FindByName(context.Background(), "Notebook", dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 1)
-	assert.Equal(t, "Notebook Dell", result[0].Nome)
+	assert.Len(t, result.Data, 1)
+	assert.Equal(t, "Notebook Dell", result.Data[0].Nome)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestProdutoService_FindByCategoria_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	expectedProdutos := []model.Produto{
-		{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: 2999.99, Categoria: "Eletrônicos"},
-		{ID: 2, Nome: "Mouse Logitech", SKU: "MS-LOG-001", Preco: 99.99, Categoria: "Eletrônicos"},
+		{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99"), Categoria: "Eletrônicos"},
+		{ID: 2, Nome: "Mouse Logitech", SKU: "MS-LOG-001", Preco: money.MustParse("99.99"), Categoria: "Eletrônicos"},
 	}
 
-	mockRepo.On("FindByCategoria", mock.Anything, "Eletrônicos").Return(expectedProdutos, nil)
+	mockRepo.On("FindByCategoria", mock.Anything, "Eletrônicos", mock.Anything).Return(&repository.Page[model.Produto]{Items: expectedProdutos, Total: int64(len(expectedProdutos))}, nil)
 
-	result, err := svc.FindByCategoria(context.Background(), "Eletrônicos")
+	result, err := svc.FindByCategoria(context.Background(), "Eletrônicos", dto.PageRequest{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Data, 2)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestProdutoService_Update_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	existingProduto := &model.Produto{
 		ID:        1,
 		Nome:      "Notebook Dell",
 		SKU:       "NB-DELL-001",
-		Preco:     2999.99,
+		Preco:     money.MustParse("2999.99"),
 		Categoria: "Eletrônicos",
 	}
 
 	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
 	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Produto")).Return(nil)
 
-	req := &dto.UpdateProdutoRequest{
-		Nome:  "Notebook Dell Atualizado",
-		Preco: 2799.99,
-	}
+	req := updateProdutoRequest(existingProduto)
+	req.Nome = "Notebook Dell Atualizado"
+	req.Preco = money.MustParse("2799.99")
 
-	result, err := svc.Update(context.Background(), 1, req)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleAdmin}})
+	result, err := svc.Update(ctx, 1, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "Notebook Dell Atualizado", result.Nome)
-	assert.Equal(t, 2799.99, result.Preco)
+	assert.Equal(t, money.MustParse("2799.99"), result.Preco)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProdutoService_Update_PriceChangeRequiresAdmin(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	existingProduto := &model.Produto{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99")}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
+
+	req := updateProdutoRequest(existingProduto)
+	req.Preco = money.MustParse("2799.99")
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "joao", Roles: []string{auth.RoleOperador}})
+	result, err := svc.Update(ctx, 1, req)
+
+	assert.ErrorIs(t, err, apperror.ErrForbidden)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProdutoService_Update_SamePriceAllowedForOperador(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	existingProduto := &model.Produto{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99")}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Produto")).Return(nil)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "joao", Roles: []string{auth.RoleOperador}})
+	req := updateProdutoRequest(existingProduto)
+	req.Nome = "Notebook Dell XPS"
+	result, err := svc.Update(ctx, 1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Notebook Dell XPS", result.Nome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProdutoService_Update_PreconditionFailed(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	existingProduto := &model.Produto{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99"), Versao: 4}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
+
+	ctx := etag.WithIfMatch(context.Background(), `"3"`)
+	result, err := svc.Update(ctx, 1, updateProdutoRequest(existingProduto))
+
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProdutoService_Update_MatchingIfMatch(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	existingProduto := &model.Produto{ID: 1, Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99"), Versao: 4}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Produto")).Return(nil)
+
+	req := updateProdutoRequest(existingProduto)
+	req.Nome = "Notebook Dell XPS"
+
+	ctx := etag.WithIfMatch(context.Background(), `"4"`)
+	result, err := svc.Update(ctx, 1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Notebook Dell XPS", result.Nome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProdutoService_Update_MissingEstoque(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	req := updateProdutoRequest(&model.Produto{Nome: "Notebook Dell", SKU: "NB-DELL-001", Preco: money.MustParse("2999.99")})
+	req.Estoque = nil
+
+	result, err := svc.Update(context.Background(), 1, req)
+
+	// omitir o estoque não zera mais o produto: o PUT é rejeitado
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProdutoService_Patch(t *testing.T) {
+	tests := []struct {
+		name      string
+		patch     string
+		wantErr   error
+		estoque   int
+		categoria string
+	}{
+		{"estoque omitido é preservado", `{"nome": "Notebook Dell XPS"}`, nil, 10, "Eletrônicos"},
+		{"estoque zero explícito", `{"estoque": 0}`, nil, 0, "Eletrônicos"},
+		{"null limpa campo opcional", `{"categoria": null, "descricao": null}`, nil, 10, ""},
+		{"null em campo obrigatório", `{"estoque": null}`, apperror.ErrValidation, 0, ""},
+		{"preço sem papel admin", `{"preco": "10.00"}`, apperror.ErrForbidden, 0, ""},
+		{"patch que não é objeto", `[1, 2]`, apperror.ErrValidation, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProdutoRepository)
+			svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+			existingProduto := &model.Produto{
+				ID: 1, Nome: "Notebook Dell", Descricao: "15 polegadas", SKU: "NB-DELL-001",
+				Preco: money.MustParse("2999.99"), Estoque: 10, Categoria: "Eletrônicos", Ativo: true,
+			}
+			mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
+			mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Produto")).Return(nil)
+
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "joao", Roles: []string{auth.RoleOperador}})
+			result, err := svc.Patch(ctx, 1, []byte(tt.patch))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.estoque, result.Estoque)
+			assert.Equal(t, tt.categoria, result.Categoria)
+			assert.Equal(t, money.MustParse("2999.99"), result.Preco)
+			assert.True(t, result.Ativo)
+		})
+	}
+}
+
 func TestProdutoService_Update_NotFound(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("FindByID", mock.Anything, uint(999)).Return((*model.Produto)(nil), assert.AnError)
 
-	req := &dto.UpdateProdutoRequest{
-		Nome: "Produto Atualizado",
-	}
+	req := updateProdutoRequest(&model.Produto{Nome: "Produto Atualizado", SKU: "PROD-001", Preco: money.MustParse("10.00")})
 
 	result, err := svc.Update(context.Background(), 999, req)
 
@@ -280,10 +550,11 @@ func TestProdutoService_Update_NotFound(t *testing.T) {
 
 func TestProdutoService_Delete_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	// Mock FindByID to verify produto exists
 	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{ID: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(0), nil)
 	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
 
 	err := svc.Delete(context.Background(), 1)
@@ -294,7 +565,7 @@ func TestProdutoService_Delete_Success(t *testing.T) {
 
 func TestProdutoService_Count_Success(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("Count", mock.Anything).Return(int64(25), nil)
 
@@ -307,11 +578,11 @@ func TestProdutoService_Count_Success(t *testing.T) {
 
 func TestProdutoService_FindAll_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindAll", mock.Anything).Return([]model.Produto(nil), assert.AnError)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything).Return((*repository.Page[model.Produto])(nil), assert.AnError)
 
-	result, err := svc.FindAll(context.Background())
+	result, err := svc.FindAll(context.Background(), dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -320,11 +591,11 @@ func TestProdutoService_FindAll_Error(t *testing.T) {
 
 func TestProdutoService_FindByName_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindByName", mock.Anything, "Test").Return([]model.Produto(nil), assert.AnError)
+	mockRepo.On("FindByName", mock.Anything, "Test", mock.Anything).Return((*repository.Page[model.Produto])(nil), assert.AnError)
 
-	result, err := svc.FindByName(context.Background(), "Test")
+	result, err := svc.FindByName(context.Background(), "Test", dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -333,11 +604,11 @@ func TestProdutoService_FindByName_Error(t *testing.T) {
 
 func TestProdutoService_FindByCategoria_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
-	mockRepo.On("FindByCategoria", mock.Anything, "Test").Return([]model.Produto(nil), assert.AnError)
+	mockRepo.On("FindByCategoria", mock.Anything, "Test", mock.Anything).Return((*repository.Page[model.Produto])(nil), assert.AnError)
 
-	result, err := svc.FindByCategoria(context.Background(), "Test")
+	result, err := svc.FindByCategoria(context.Background(), "Test", dto.PageRequest{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -346,21 +617,20 @@ func TestProdutoService_FindByCategoria_Error(t *testing.T) {
 
 func TestProdutoService_Update_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	existingProduto := &model.Produto{
 		ID:    1,
 		Nome:  "Produto Teste",
 		SKU:   "TEST-001",
-		Preco: 100.00,
+		Preco: money.MustParse("100.00"),
 	}
 
 	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existingProduto, nil)
 	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Produto")).Return(assert.AnError)
 
-	req := &dto.UpdateProdutoRequest{
-		Nome: "Updated",
-	}
+	req := updateProdutoRequest(existingProduto)
+	req.Nome = "Updated"
 
 	result, err := svc.Update(context.Background(), 1, req)
 
@@ -371,9 +641,10 @@ func TestProdutoService_Update_Error(t *testing.T) {
 
 func TestProdutoService_Delete_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{ID: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(0), nil)
 	mockRepo.On("Delete", mock.Anything, uint(1)).Return(assert.AnError)
 
 	err := svc.Delete(context.Background(), 1)
@@ -382,9 +653,42 @@ func TestProdutoService_Delete_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProdutoService_Delete_ComPedidos(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.Produto{ID: 1}, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(4), nil)
+
+	err := svc.Delete(context.Background(), 1)
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, map[string]int64{"pedidos": 4}, appErr.Dependents)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestProdutoService_Deactivate_Desativa(t *testing.T) {
+	mockRepo := new(MockProdutoRepository)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
+
+	produto := &model.Produto{ID: 1, Nome: "Mouse", Ativo: true}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(produto, nil)
+	mockRepo.On("CountPedidos", mock.Anything, uint(1), false).Return(int64(2), nil)
+	mockRepo.On("Update", mock.Anything, produto).Return(nil)
+
+	err := svc.Deactivate(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.False(t, produto.Ativo)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestProdutoService_Count_Error(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	mockRepo.On("Count", mock.Anything).Return(int64(0), assert.AnError)
 
@@ -397,12 +701,12 @@ func TestProdutoService_Count_Error(t *testing.T) {
 
 func TestProdutoService_Create_RepositoryError(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	ativo := true
 	req := &dto.CreateProdutoRequest{
 		Nome:    "Produto Teste",
-		Preco:   100.00,
+		Preco:   money.MustParse("100.00"),
 		SKU:     "TEST-001",
 		Estoque: 10,
 		Ativo:   &ativo,
@@ -420,12 +724,12 @@ func TestProdutoService_Create_RepositoryError(t *testing.T) {
 
 func TestProdutoService_Create_SKUAlreadyExists(t *testing.T) {
 	mockRepo := new(MockProdutoRepository)
-	svc := service.NewProdutoService(mockRepo)
+	svc := service.NewProdutoService(mockRepo, new(MockAuditRepository))
 
 	ativo := true
 	req := &dto.CreateProdutoRequest{
 		Nome:  "Produto Teste",
-		Preco: 100.00,
+		Preco: money.MustParse("100.00"),
 		SKU:   "TEST-001",
 		Ativo: &ativo,
 	}
@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/model"
+	"github.com/danmaciel/api/internal/money"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRegraImpostoRepository is a mock implementation of RegraImpostoRepository
+type MockRegraImpostoRepository struct {
+	mock.Mock
+}
+
+func (m *MockRegraImpostoRepository) Create(ctx context.Context, regra *model.RegraImposto) error {
+	args := m.Called(ctx, regra)
+	return args.Error(0)
+}
+
+func (m *MockRegraImpostoRepository) FindAll(ctx context.Context, opts repository.ListOptions) (*repository.Page[model.RegraImposto], error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Page[model.RegraImposto]), args.Error(1)
+}
+
+func (m *MockRegraImpostoRepository) FindByID(ctx context.Context, id uint) (*model.RegraImposto, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RegraImposto), args.Error(1)
+}
+
+func (m *MockRegraImpostoRepository) FindByChave(ctx context.Context, imposto, categoria, uf string) (*model.RegraImposto, error) {
+	args := m.Called(ctx, imposto, categoria, uf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RegraImposto), args.Error(1)
+}
+
+func (m *MockRegraImpostoRepository) FindAtivas(ctx context.Context, uf string) ([]model.RegraImposto, error) {
+	args := m.Called(ctx, uf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RegraImposto), args.Error(1)
+}
+
+func (m *MockRegraImpostoRepository) Update(ctx context.Context, regra *model.RegraImposto) error {
+	args := m.Called(ctx, regra)
+	return args.Error(0)
+}
+
+func (m *MockRegraImpostoRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestRegraImpostoService_Create_Success(t *testing.T) {
+	mockRepo := new(MockRegraImpostoRepository)
+	svc := service.NewRegraImpostoService(mockRepo)
+
+	// imposto e UF são normalizados antes da verificação de duplicidade
+	mockRepo.On("FindByChave", mock.Anything, "ICMS", "Eletrônicos", "SP").Return(nil, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.RegraImposto")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.RegraImposto).ID = 1
+	}).Return(nil)
+
+	result, err := svc.Create(context.Background(), &dto.CreateRegraImpostoRequest{
+		Imposto:   "icms",
+		Categoria: "Eletrônicos",
+		UF:        "sp",
+		Aliquota:  money.Aliquota(1800),
+		Modo:      model.ImpostoInclusivo,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	assert.Equal(t, "ICMS", result.Imposto)
+	assert.Equal(t, "SP", result.UF)
+	assert.Equal(t, "18.00", result.Aliquota.String())
+	assert.True(t, result.Ativo)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRegraImpostoService_Create_Duplicada(t *testing.T) {
+	mockRepo := new(MockRegraImpostoRepository)
+	svc := service.NewRegraImpostoService(mockRepo)
+
+	mockRepo.On("FindByChave", mock.Anything, "IPI", "", "").Return(&model.RegraImposto{ID: 4, Imposto: "IPI"}, nil)
+
+	result, err := svc.Create(context.Background(), &dto.CreateRegraImpostoRequest{
+		Imposto:  "IPI",
+		Aliquota: money.Aliquota(500),
+		Modo:     model.ImpostoExclusivo,
+	})
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.EqualError(t, err, "já existe regra de IPI para categoria todas e UF todas (id 4)")
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRegraImpostoService_Create_ValidationError(t *testing.T) {
+	casos := []struct {
+		name string
+		req  dto.CreateRegraImpostoRequest
+	}{
+		{"sem imposto", dto.CreateRegraImpostoRequest{Aliquota: 1800, Modo: model.ImpostoInclusivo}},
+		{"UF inexistente", dto.CreateRegraImpostoRequest{Imposto: "ICMS", UF: "XX", Aliquota: 1800, Modo: model.ImpostoInclusivo}},
+		{"alíquota acima de 100%", dto.CreateRegraImpostoRequest{Imposto: "ICMS", Aliquota: 10001, Modo: model.ImpostoInclusivo}},
+		{"modo desconhecido", dto.CreateRegraImpostoRequest{Imposto: "ICMS", Aliquota: 1800, Modo: "misto"}},
+	}
+
+	for _, tt := range casos {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRegraImpostoRepository)
+			svc := service.NewRegraImpostoService(mockRepo)
+
+			result, err := svc.Create(context.Background(), &tt.req)
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Nil(t, result)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRegraImpostoService_Update_MesmaChave(t *testing.T) {
+	mockRepo := new(MockRegraImpostoRepository)
+	svc := service.NewRegraImpostoService(mockRepo)
+
+	regra := &model.RegraImposto{ID: 2, Imposto: "ICMS", UF: "SP", Aliquota: 1800, Modo: model.ImpostoInclusivo, Ativo: true, Versao: 1}
+	mockRepo.On("FindByID", mock.Anything, uint(2)).Return(regra, nil)
+	// a própria regra ocupa a chave, o que não é conflito
+	mockRepo.On("FindByChave", mock.Anything, "ICMS", "", "SP").Return(regra, nil)
+	mockRepo.On("Update", mock.Anything, regra).Return(nil)
+
+	ativo := true
+	result, err := svc.Update(context.Background(), 2, &dto.UpdateRegraImpostoRequest{
+		Imposto:  "ICMS",
+		UF:       "SP",
+		Aliquota: money.Aliquota(1200),
+		Modo:     model.ImpostoInclusivo,
+		Ativo:    &ativo,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12.00", result.Aliquota.String())
+	mockRepo.AssertExpectations(t)
+}
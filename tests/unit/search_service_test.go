@@ -0,0 +1,152 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/danmaciel/api/internal/apperror"
+	"github.com/danmaciel/api/internal/auth"
+	"github.com/danmaciel/api/internal/dto"
+	"github.com/danmaciel/api/internal/repository"
+	"github.com/danmaciel/api/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchRepository is a mock implementation of SearchRepository
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, opts repository.SearchOptions) ([]repository.SearchHit, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.SearchHit), args.Error(1)
+}
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleAdmin}})
+}
+
+func TestSearchService_Search_Success(t *testing.T) {
+	mockRepo := new(MockSearchRepository)
+	svc := service.NewSearchService(mockRepo)
+
+	expectedOpts := repository.SearchOptions{
+		Termos: []string{"João", "café"},
+		Tipos:  []string{repository.SearchClientes, repository.SearchProdutos},
+		Limit:  20,
+	}
+	mockRepo.On("Search", mock.Anything, expectedOpts).Return([]repository.SearchHit{
+		{Tipo: "produtos", ID: 7, Titulo: "\x02Café\x03 <Premium>", Trecho: "do \x02João\x03 & cia", Rank: -3.5},
+	}, nil)
+
+	result, err := svc.Search(adminContext(), dto.SearchRequest{Q: "  João, café!"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "  João, café!", result.Query)
+	assert.Equal(t, []dto.SearchResult{{
+		Tipo:   "produtos",
+		ID:     7,
+		Titulo: "<mark>Café</mark> &lt;Premium&gt;",
+		Trecho: "do <mark>João</mark> &amp; cia",
+		Score:  3.5,
+		Href:   "/api/v1/produtos/7",
+	}}, result.Data)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_LimitCapped(t *testing.T) {
+	mockRepo := new(MockSearchRepository)
+	svc := service.NewSearchService(mockRepo)
+
+	mockRepo.On("Search", mock.Anything, mock.MatchedBy(func(opts repository.SearchOptions) bool {
+		return opts.Limit == 50
+	})).Return([]repository.SearchHit{}, nil)
+
+	result, err := svc.Search(adminContext(), dto.SearchRequest{Q: "mouse", Limit: 500})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Data)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_TiposByPermission(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		tipos     []string
+		want      []string
+		wantErr   error
+	}{
+		{"todos os tipos legíveis", &auth.Principal{Scopes: []string{"produtos:read"}}, nil, []string{"produtos"}, nil},
+		{"tipo pedido e permitido", &auth.Principal{Roles: []string{auth.RoleLeitura}}, []string{"produtos", "produtos"}, []string{"produtos"}, nil},
+		{"tipo pedido sem permissão", &auth.Principal{Scopes: []string{"produtos:read"}}, []string{"clientes"}, nil, apperror.ErrForbidden},
+		{"nenhum tipo permitido", &auth.Principal{Scopes: []string{"pedidos:read"}}, nil, nil, apperror.ErrForbidden},
+		{"tipo desconhecido", &auth.Principal{Roles: []string{auth.RoleAdmin}}, []string{"pedidos"}, nil, apperror.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockSearchRepository)
+			svc := service.NewSearchService(mockRepo)
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+
+			if tt.wantErr == nil {
+				mockRepo.On("Search", mock.Anything, mock.MatchedBy(func(opts repository.SearchOptions) bool {
+					return assert.ObjectsAreEqual(tt.want, opts.Tipos)
+				})).Return([]repository.SearchHit{}, nil)
+			}
+
+			_, err := svc.Search(ctx, dto.SearchRequest{Q: "mouse", Tipos: tt.tipos})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchService_Search_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+	}{
+		{"vazia", ""},
+		{"apenas símbolos", "--- ?!"},
+		{"longa demais", strings.Repeat("a", 201)},
+		{"termos demais", "a b c d e f g h i j k"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockSearchRepository)
+			svc := service.NewSearchService(mockRepo)
+
+			result, err := svc.Search(adminContext(), dto.SearchRequest{Q: tt.q})
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.Nil(t, result)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSearchService_Search_Unavailable(t *testing.T) {
+	mockRepo := new(MockSearchRepository)
+	svc := service.NewSearchService(mockRepo)
+
+	mockRepo.On("Search", mock.Anything, mock.Anything).Return(nil, apperror.Unavailable("busca textual indisponível"))
+
+	result, err := svc.Search(adminContext(), dto.SearchRequest{Q: "mouse"})
+
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+	assert.Nil(t, result)
+}